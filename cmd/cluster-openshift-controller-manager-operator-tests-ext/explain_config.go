@@ -0,0 +1,79 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
+
+	"github.com/openshift/cluster-openshift-controller-manager-operator/test/framework"
+)
+
+// newExplainConfigCommand returns a command printing the observed config of the operator as YAML, each
+// top-level section preceded by a comment naming the observers which wrote it, followed by the recent
+// changes of the observed config the operator recorded. It only reads from the cluster.
+func newExplainConfigCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "explain-config",
+		Short: "Print the observed config of the operator with the observers responsible for each section.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := framework.NewClientset(nil)
+			if err != nil {
+				return err
+			}
+			sections, err := framework.ExplainObservedConfig(cmd.Context(), client)
+			if err != nil {
+				return err
+			}
+			history, err := framework.ObservedConfigHistory(cmd.Context(), client)
+			if err != nil {
+				return err
+			}
+			if err := writeExplainedConfig(cmd.OutOrStdout(), sections); err != nil {
+				return err
+			}
+			return writeObservedConfigHistory(cmd.OutOrStdout(), history)
+		},
+	}
+}
+
+func writeExplainedConfig(w io.Writer, sections []framework.ObservedConfigSection) error {
+	if len(sections) == 0 {
+		_, err := fmt.Fprintln(w, "# the observed config is empty")
+		return err
+	}
+	for _, section := range sections {
+		observers := "no known observer"
+		if len(section.Observers) > 0 {
+			observers = strings.Join(section.Observers, ", ")
+		}
+		out, err := yaml.Marshal(map[string]interface{}{section.Key: section.Value})
+		if err != nil {
+			return fmt.Errorf("failed to encode section %s: %w", section.Key, err)
+		}
+		if _, err := fmt.Fprintf(w, "# %s: observed by %s\n%s", section.Key, observers, out); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeObservedConfigHistory(w io.Writer, history []framework.ObservedConfigRevision) error {
+	if len(history) == 0 {
+		_, err := fmt.Fprintln(w, "# no recent changes of the observed config were recorded")
+		return err
+	}
+	if _, err := fmt.Fprintln(w, "# recent changes of the observed config, oldest first:"); err != nil {
+		return err
+	}
+	for _, revision := range history {
+		if _, err := fmt.Fprintf(w, "#   %s %s\n", revision.Time.UTC().Format(time.RFC3339), revision.Reason); err != nil {
+			return err
+		}
+	}
+	return nil
+}
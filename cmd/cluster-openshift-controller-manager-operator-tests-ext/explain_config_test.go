@@ -0,0 +1,65 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openshift/cluster-openshift-controller-manager-operator/test/framework"
+)
+
+func TestWriteExplainedConfig(t *testing.T) {
+	out := &bytes.Buffer{}
+	err := writeExplainedConfig(out, []framework.ObservedConfigSection{
+		{Key: "controllers", Value: []interface{}{"*"}, Observers: []string{"Controllers"}},
+		{Key: "legacy", Value: map[string]interface{}{"key": "value"}},
+		{Key: "servingInfo", Value: map[string]interface{}{"minTLSVersion": "VersionTLS12"}, Observers: []string{"TLSSecurityProfile", "NamedCertificates"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `# controllers: observed by Controllers
+controllers:
+- '*'
+# legacy: observed by no known observer
+legacy:
+  key: value
+# servingInfo: observed by TLSSecurityProfile, NamedCertificates
+servingInfo:
+  minTLSVersion: VersionTLS12
+`
+	if out.String() != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, out.String())
+	}
+}
+
+func TestWriteObservedConfigHistory(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	out := &bytes.Buffer{}
+	err := writeObservedConfigHistory(out, []framework.ObservedConfigRevision{
+		{Time: metav1.NewTime(start), Reason: "added controllers"},
+		{Time: metav1.NewTime(start.Add(5 * time.Minute)), Reason: "added servingInfo.cipherSuites; changed servingInfo.minTLSVersion"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `# recent changes of the observed config, oldest first:
+#   2026-01-01T00:00:00Z added controllers
+#   2026-01-01T00:05:00Z added servingInfo.cipherSuites; changed servingInfo.minTLSVersion
+`
+	if out.String() != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, out.String())
+	}
+
+	out.Reset()
+	if err := writeObservedConfigHistory(out, nil); err != nil {
+		t.Fatal(err)
+	}
+	if expected := "# no recent changes of the observed config were recorded\n"; out.String() != expected {
+		t.Errorf("expected %q, got %q", expected, out.String())
+	}
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	oteextensiontests "github.com/openshift-eng/openshift-tests-extension/pkg/extension/extensiontests"
+
+	"k8s.io/klog/v2"
+)
+
+const (
+	// flakyMarker marks a spec as known to be flaky, either in its name or as a label.
+	flakyMarker = "Flaky"
+	// flakyTag is the tag set on the specs which are retried on failure.
+	flakyTag = "flaky"
+)
+
+// isFlakySpec selects the specs whose name contains [Flaky] or which carry the Flaky label.
+func isFlakySpec(spec *oteextensiontests.ExtensionTestSpec) bool {
+	return strings.Contains(spec.Name, "["+flakyMarker+"]") || spec.Labels.Has(flakyMarker)
+}
+
+// markFlakySpecs tags the flaky specs and wraps their run functions so that a failed spec is run
+// again, up to attempts times in total. attempts is only read when a spec runs so it can be bound
+// to a flag that is parsed after the registry is built.
+func markFlakySpecs(specs oteextensiontests.ExtensionTestSpecs, attempts *int) {
+	specs.Select(isFlakySpec).Walk(func(spec *oteextensiontests.ExtensionTestSpec) {
+		if spec.Tags == nil {
+			spec.Tags = map[string]string{}
+		}
+		spec.Tags[flakyTag] = "true"
+		spec.Run = retryOnFailure(spec.Name, spec.Run, attempts)
+		spec.RunParallel = retryOnFailure(spec.Name, spec.RunParallel, attempts)
+	})
+}
+
+func retryOnFailure(name string, run func(ctx context.Context) *oteextensiontests.ExtensionTestResult, attempts *int) func(ctx context.Context) *oteextensiontests.ExtensionTestResult {
+	if run == nil {
+		return nil
+	}
+	return func(ctx context.Context) *oteextensiontests.ExtensionTestResult {
+		result := run(ctx)
+		var previousErrors []oteextensiontests.Details
+		for attempt := 2; attempt <= *attempts && result.Result == oteextensiontests.ResultFailed && ctx.Err() == nil; attempt++ {
+			klog.Warningf("flaky test %q failed, starting attempt %d of %d", name, attempt, *attempts)
+			previousErrors = append(previousErrors, oteextensiontests.Details{
+				Name:  fmt.Sprintf("attempt-%d-error", attempt-1),
+				Value: result.Error,
+			})
+			result = run(ctx)
+		}
+		result.Details = append(result.Details, previousErrors...)
+		return result
+	}
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	oteextensiontests "github.com/openshift-eng/openshift-tests-extension/pkg/extension/extensiontests"
+	"github.com/openshift-eng/openshift-tests-extension/pkg/util/sets"
+)
+
+func TestMarkFlakySpecs(t *testing.T) {
+	specs := oteextensiontests.ExtensionTestSpecs{
+		{Name: "[Operator][Flaky] flaky by name", Labels: sets.New[string]()},
+		{Name: "flaky by label", Labels: sets.New[string](flakyMarker)},
+		{Name: "[Operator] stable", Labels: sets.New[string]()},
+		{Name: "[Operator] stable with tags", Labels: sets.New[string](), Tags: map[string]string{"foo": "bar"}},
+	}
+	attempts := 1
+	markFlakySpecs(specs, &attempts)
+
+	expectedFlaky := map[string]bool{
+		"[Operator][Flaky] flaky by name": true,
+		"flaky by label":                  true,
+	}
+	for _, spec := range specs {
+		if got := spec.Tags[flakyTag] == "true"; got != expectedFlaky[spec.Name] {
+			t.Errorf("spec %q: expected flaky tag to be %v, got tags %v", spec.Name, expectedFlaky[spec.Name], spec.Tags)
+		}
+	}
+	if specs[3].Tags["foo"] != "bar" {
+		t.Errorf("expected existing tags to be kept, got %v", specs[3].Tags)
+	}
+}
+
+func TestRetryOnFailure(t *testing.T) {
+	tests := []struct {
+		name             string
+		attempts         int
+		failures         int
+		expectedRuns     int
+		expectedResult   oteextensiontests.Result
+		expectedAttempts int
+	}{
+		{
+			name:           "default does not retry",
+			attempts:       1,
+			failures:       1,
+			expectedRuns:   1,
+			expectedResult: oteextensiontests.ResultFailed,
+		},
+		{
+			name:           "passes after retry",
+			attempts:       3,
+			failures:       2,
+			expectedRuns:   3,
+			expectedResult: oteextensiontests.ResultPassed,
+		},
+		{
+			name:           "gives up after all attempts",
+			attempts:       2,
+			failures:       5,
+			expectedRuns:   2,
+			expectedResult: oteextensiontests.ResultFailed,
+		},
+		{
+			name:           "passing spec runs once",
+			attempts:       3,
+			expectedRuns:   1,
+			expectedResult: oteextensiontests.ResultPassed,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			runs := 0
+			run := func(ctx context.Context) *oteextensiontests.ExtensionTestResult {
+				runs++
+				if runs <= tc.failures {
+					return &oteextensiontests.ExtensionTestResult{Result: oteextensiontests.ResultFailed, Error: "boom"}
+				}
+				return &oteextensiontests.ExtensionTestResult{Result: oteextensiontests.ResultPassed}
+			}
+
+			result := retryOnFailure("test", run, &tc.attempts)(context.Background())
+			if runs != tc.expectedRuns {
+				t.Errorf("expected %d runs, got %d", tc.expectedRuns, runs)
+			}
+			if result.Result != tc.expectedResult {
+				t.Errorf("expected result %q, got %q", tc.expectedResult, result.Result)
+			}
+			if len(result.Details) != tc.expectedRuns-1 {
+				t.Errorf("expected %d previous attempt details, got %v", tc.expectedRuns-1, result.Details)
+			}
+		})
+	}
+}
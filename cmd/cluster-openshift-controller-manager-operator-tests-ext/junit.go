@@ -0,0 +1,100 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	oteextension "github.com/openshift-eng/openshift-tests-extension/pkg/extension"
+	oteextensiontests "github.com/openshift-eng/openshift-tests-extension/pkg/extension/extensiontests"
+)
+
+// junitPathFlag is the flag of the run commands writing the results as JUnit XML, the same as the one
+// of run-suite.
+const junitPathFlag = "junit-path"
+
+// addJUnitPath lets run-test write its results as JUnit XML like run-suite does, and makes both check
+// that the JUnit file can be written before a spec runs rather than after all of them ran.
+func addJUnitPath(cmds []*cobra.Command, registry *oteextension.Registry) {
+	for _, cmd := range cmds {
+		switch cmd.Name() {
+		case "run-suite":
+			validateJUnitPathBeforeRun(cmd)
+		case "run-test":
+			cmd.Flags().StringP(junitPathFlag, "j", "", "write results to junit XML")
+			validateJUnitPathBeforeRun(cmd)
+			run := cmd.RunE
+			cmd.RunE = func(cmd *cobra.Command, args []string) error {
+				path, err := cmd.Flags().GetString(junitPathFlag)
+				if err != nil {
+					return err
+				}
+				component, err := cmd.Flags().GetString("component")
+				if err != nil {
+					return err
+				}
+				ext := registry.Get(component)
+				if len(path) == 0 || ext == nil {
+					return run(cmd, args)
+				}
+				return runWithJUnit(path, ext, func() error { return run(cmd, args) })
+			}
+		}
+	}
+}
+
+// runWithJUnit runs the specs of ext picked by run and writes their results to the JUnit file at path
+// once they ran.
+func runWithJUnit(path string, ext *oteextension.Extension, run func() error) error {
+	w, err := oteextensiontests.NewJUnitResultWriter(path, ext.Component.Identifier())
+	if err != nil {
+		return fmt.Errorf("couldn't create junit writer: %w", err)
+	}
+	ext.GetSpecs().Walk(func(spec *oteextensiontests.ExtensionTestSpec) {
+		name := spec.Name
+		oteextensiontests.ExtensionTestSpecs{spec}.AddAfterEach(func(res *oteextensiontests.ExtensionTestResult) {
+			res.Name = name
+			w.Write(res)
+		})
+	})
+
+	err = run()
+	if flushErr := w.Flush(); flushErr != nil && err == nil {
+		err = fmt.Errorf("couldn't write junit results to %s: %w", path, flushErr)
+	}
+	return err
+}
+
+// validateJUnitPathBeforeRun fails cmd before it runs anything when its JUnit file cannot be written.
+func validateJUnitPathBeforeRun(cmd *cobra.Command) {
+	preRun := cmd.PreRunE
+	cmd.PreRunE = func(cmd *cobra.Command, args []string) error {
+		path, err := cmd.Flags().GetString(junitPathFlag)
+		if err != nil {
+			return err
+		}
+		if len(path) > 0 {
+			if err := validateJUnitPath(path); err != nil {
+				return err
+			}
+		}
+		if preRun != nil {
+			return preRun(cmd, args)
+		}
+		return nil
+	}
+}
+
+// validateJUnitPath checks that a JUnit file can be created at path by creating and removing a
+// temporary file next to it.
+func validateJUnitPath(path string) error {
+	dir := filepath.Dir(path)
+	file, err := os.CreateTemp(dir, ".junit-*")
+	if err != nil {
+		return fmt.Errorf("--%s %s: directory %s is not writable: %w", junitPathFlag, path, dir, err)
+	}
+	file.Close()
+	return os.Remove(file.Name())
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"context"
+	"encoding/xml"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	otecmd "github.com/openshift-eng/openshift-tests-extension/pkg/cmd"
+	oteextension "github.com/openshift-eng/openshift-tests-extension/pkg/extension"
+	oteextensiontests "github.com/openshift-eng/openshift-tests-extension/pkg/extension/extensiontests"
+	"github.com/openshift-eng/openshift-tests-extension/pkg/junit"
+	"github.com/openshift-eng/openshift-tests-extension/pkg/util/sets"
+)
+
+// newJUnitTestCommand returns a root command with the run commands of a registry with a single passing
+// spec, and a pointer to how many times the spec ran.
+func newJUnitTestCommand(t *testing.T) (*cobra.Command, *int) {
+	t.Helper()
+	// run-test reads the names of the specs from stdin when it is a pipe and prints the results to stdout
+	stdin, stdout := os.Stdin, os.Stdout
+	devNull, err := os.OpenFile(os.DevNull, os.O_RDWR, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.Stdin, os.Stdout = devNull, devNull
+	t.Cleanup(func() {
+		os.Stdin, os.Stdout = stdin, stdout
+		devNull.Close()
+	})
+
+	runs := 0
+	registry := oteextension.NewRegistry()
+	extension := oteextension.NewExtension("openshift", "payload", "test")
+	extension.AddSpecs(oteextensiontests.ExtensionTestSpecs{{
+		Name:   "[Operator] passes",
+		Labels: sets.New[string](),
+		Run: func(ctx context.Context) *oteextensiontests.ExtensionTestResult {
+			runs++
+			return &oteextensiontests.ExtensionTestResult{Result: oteextensiontests.ResultPassed}
+		},
+	}})
+	registry.Register(extension)
+
+	cmd := &cobra.Command{Use: "tests-ext"}
+	extensionCommands := otecmd.DefaultExtensionCommands(registry)
+	addJUnitPath(extensionCommands, registry)
+	cmd.AddCommand(extensionCommands...)
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	return cmd, &runs
+}
+
+func TestRunTestJUnitPath(t *testing.T) {
+	cmd, runs := newJUnitTestCommand(t)
+	path := filepath.Join(t.TempDir(), "junit.xml")
+	cmd.SetArgs([]string{"run-test", "--junit-path", path, "[Operator] passes"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	if *runs != 1 {
+		t.Fatalf("expected the spec to run once, ran %d times", *runs)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("expected the junit file to be written: %v", err)
+	}
+	// junit.TestSuite marshals its test cases but does not unmarshal them
+	suite := &struct {
+		junit.TestSuite
+		TestCases []*junit.TestCase `xml:"testcase"`
+	}{}
+	if err := xml.Unmarshal(data, suite); err != nil {
+		t.Fatalf("expected well-formed junit, got %q: %v", data, err)
+	}
+	if suite.NumTests != 1 || suite.NumFailed != 0 || suite.NumSkipped != 0 {
+		t.Errorf("expected 1 passed test, got %d tests, %d failed, %d skipped", suite.NumTests, suite.NumFailed, suite.NumSkipped)
+	}
+	if len(suite.TestCases) != 1 || suite.TestCases[0].Name != "[Operator] passes" {
+		t.Errorf("expected the test case of the spec, got %q", data)
+	}
+}
+
+func TestRunTestWithoutJUnitPath(t *testing.T) {
+	cmd, runs := newJUnitTestCommand(t)
+	cmd.SetArgs([]string{"run-test", "[Operator] passes"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	if *runs != 1 {
+		t.Fatalf("expected the spec to run once, ran %d times", *runs)
+	}
+}
+
+func TestJUnitPathNotWritable(t *testing.T) {
+	// a directory cannot be created below a file
+	file := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(file, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(file, "junit.xml")
+
+	for _, args := range [][]string{
+		{"run-test", "--junit-path", path, "[Operator] passes"},
+		{"run-suite", "--junit-path", path, "openshift/test/all"},
+	} {
+		t.Run(args[0], func(t *testing.T) {
+			cmd, runs := newJUnitTestCommand(t)
+			cmd.SetArgs(args)
+			if err := cmd.Execute(); err == nil {
+				t.Fatal("expected an error for a junit path which is not writable")
+			}
+			if *runs != 0 {
+				t.Errorf("expected no spec to run, ran %d times", *runs)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	oteextension "github.com/openshift-eng/openshift-tests-extension/pkg/extension"
+)
+
+// suiteProperties are the properties of a suite the release tooling needs to allocate a lane for it.
+type suiteProperties struct {
+	Name        string   `json:"name"`
+	Qualifiers  []string `json:"qualifiers"`
+	Parallelism int      `json:"parallelism"`
+	// Timeout is the per-test timeout as a Go duration string, empty when the suite sets none.
+	Timeout      string                  `json:"timeout,omitempty"`
+	Requirements environmentRequirements `json:"requirements"`
+}
+
+// environmentRequirements are the requirements of a suite on the environment, "any" where it has none.
+type environmentRequirements struct {
+	Platform    string `json:"platform"`
+	Topology    string `json:"topology"`
+	NetworkType string `json:"networkType"`
+}
+
+func newEnvironmentRequirements(requirements suiteRequirements) environmentRequirements {
+	orAny := func(requirement string) string {
+		if len(requirement) == 0 {
+			return anyEnvironment
+		}
+		return requirement
+	}
+	return environmentRequirements{
+		Platform:    orAny(requirements.Platform),
+		Topology:    orAny(requirements.Topology),
+		NetworkType: orAny(requirements.NetworkType),
+	}
+}
+
+// newListSuitesCommand returns a command printing the properties of every registered suite as a JSON
+// array. Unlike "list suites", the timeout is printed as a duration string rather than nanoseconds and
+// the requirements of the suite on the environment are included.
+func newListSuitesCommand(registry *oteextension.Registry, requirements map[string]suiteRequirements) *cobra.Command {
+	return &cobra.Command{
+		Use:   "list-suites",
+		Short: "Print the name, qualifiers, parallelism, timeout and environment requirements of every suite as JSON.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return writeSuites(cmd.OutOrStdout(), registry, requirements)
+		},
+	}
+}
+
+func writeSuites(w io.Writer, registry *oteextension.Registry, requirements map[string]suiteRequirements) error {
+	suites := []suiteProperties{}
+	registry.Walk(func(ext *oteextension.Extension) {
+		for _, suite := range ext.Suites {
+			properties := suiteProperties{
+				Name:         suite.Name,
+				Qualifiers:   suite.Qualifiers,
+				Parallelism:  suite.Parallelism,
+				Requirements: newEnvironmentRequirements(requirements[suite.Name]),
+			}
+			if suite.TestTimeout != nil {
+				properties.Timeout = suite.TestTimeout.String()
+			}
+			suites = append(suites, properties)
+		}
+	})
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	// the qualifiers are CEL expressions, keep their && readable
+	encoder.SetEscapeHTML(false)
+	return encoder.Encode(suites)
+}
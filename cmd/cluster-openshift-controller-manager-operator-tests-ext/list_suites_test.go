@@ -0,0 +1,55 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func TestWriteSuites(t *testing.T) {
+	flakyAttempts := 1
+	registry, requirements := mustPrepareOperatorTestsRegistry(t, &flakyAttempts, "")
+
+	out := &bytes.Buffer{}
+	if err := writeSuites(out, registry, requirements); err != nil {
+		t.Fatal(err)
+	}
+
+	var suites []suiteProperties
+	if err := json.Unmarshal(out.Bytes(), &suites); err != nil {
+		t.Fatalf("expected valid JSON, got %q: %v", out.String(), err)
+	}
+	var serial, all *suiteProperties
+	for i := range suites {
+		switch suites[i].Name {
+		case serialSuiteName:
+			serial = &suites[i]
+		case allSuiteName:
+			all = &suites[i]
+		}
+	}
+	if serial == nil {
+		t.Fatalf("expected suite %q in %s", serialSuiteName, out.String())
+	}
+	if serial.Parallelism != 1 {
+		t.Errorf("expected parallelism 1, got %d", serial.Parallelism)
+	}
+	if serial.Timeout != "30m0s" {
+		t.Errorf("expected timeout 30m0s, got %q", serial.Timeout)
+	}
+	if len(serial.Qualifiers) == 0 {
+		t.Error("expected the serial suite qualifiers to be listed")
+	}
+	expectedSerial := environmentRequirements{Platform: "any", Topology: "ha", NetworkType: "any"}
+	if serial.Requirements != expectedSerial {
+		t.Errorf("expected the serial suite requirements %+v, got %+v", expectedSerial, serial.Requirements)
+	}
+
+	if all == nil {
+		t.Fatalf("expected suite %q in %s", allSuiteName, out.String())
+	}
+	expectedAll := environmentRequirements{Platform: "any", Topology: "any", NetworkType: "any"}
+	if all.Requirements != expectedAll {
+		t.Errorf("expected a suite without requirements to require %+v, got %+v", expectedAll, all.Requirements)
+	}
+}
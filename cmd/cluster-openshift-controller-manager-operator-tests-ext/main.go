@@ -2,8 +2,8 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"os"
-	"time"
 
 	"github.com/spf13/cobra"
 	"k8s.io/component-base/cli"
@@ -14,28 +14,75 @@ import (
 	"github.com/openshift/cluster-openshift-controller-manager-operator/pkg/version"
 
 	_ "github.com/openshift/cluster-openshift-controller-manager-operator/test/e2e"
+	"github.com/openshift/cluster-openshift-controller-manager-operator/test/framework"
 
 	"k8s.io/klog/v2"
 )
 
 func main() {
-	command := newOperatorTestCommand(context.Background())
+	command, err := newOperatorTestCommand(context.Background(), os.Args[1:])
+	if err != nil {
+		klog.Fatal(err)
+	}
 	code := cli.Run(command)
 	os.Exit(code)
 }
 
-func newOperatorTestCommand(ctx context.Context) *cobra.Command {
-	registry := prepareOperatorTestsRegistry()
+// newOperatorTestCommand returns the root command, args are the arguments it is run with, they are only
+// read for the flags the registry is built with.
+func newOperatorTestCommand(ctx context.Context, args []string) (*cobra.Command, error) {
+	// flaky specs are attempted once unless more attempts are requested
+	flakyAttempts := 1
+	specFilter := specFilterFromArgs(args)
+	registry, requirements, err := prepareOperatorTestsRegistry(&flakyAttempts, specFilter)
+	if err != nil {
+		return nil, err
+	}
 
+	var dryRun bool
+	var platform string
+	var suite string
+	extensionCommands := otecmd.DefaultExtensionCommands(registry)
+	addJUnitPath(extensionCommands, registry)
 	cmd := &cobra.Command{
 		Use:   "cluster-openshift-controller-manager-operator-tests-ext",
 		Short: "A binary used to run cluster-openshift-controller-manager-operator tests as part of OTE.",
+		PersistentPreRun: func(cmd *cobra.Command, args []string) {
+			// nobody must miss a run leaving the cluster dirty
+			if framework.SkipCleanup {
+				klog.Warning(framework.SkipCleanupWarning)
+			}
+		},
 		Run: func(cmd *cobra.Command, args []string) {
+			if dryRun {
+				environment, err := platformEnvironment(platform)
+				if err != nil {
+					klog.Fatal(err)
+				}
+				if err := writeSuiteMembership(os.Stdout, registry, environment); err != nil {
+					klog.Fatal(err)
+				}
+				return
+			}
+			if len(suite) > 0 {
+				if err := runSuite(extensionCommands, registry, suite); err != nil {
+					klog.Fatal(err)
+				}
+				return
+			}
 			if err := cmd.Help(); err != nil {
 				klog.Fatal(err)
 			}
 		},
 	}
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Print each suite and the specs its qualifiers claim, one \"suite<TAB>spec\" per line, without running anything.")
+	cmd.Flags().StringVar(&platform, "platform", "", "Platform of the cluster, e.g. \"aws\", to select the specs of --dry-run for instead of detecting it from infrastructures.config.openshift.io/cluster.")
+	cmd.Flags().StringVar(&suite, suiteFlag, "", "Name of a registered suite to run, e.g. \"serial\" or its full name, the same way run-suite runs it.")
+	cmd.MarkFlagsMutuallyExclusive("dry-run", suiteFlag)
+	cmd.PersistentFlags().StringVar(&specFilter, specFilterFlag, specFilter, "Regular expression restricting the specs to the ones whose name matches it, e.g. for quick local runs. All specs are registered when it is empty.")
+	cmd.PersistentFlags().IntVar(&flakyAttempts, "flaky-attempts", flakyAttempts, "Number of times a spec marked [Flaky] is attempted before it is reported as failed.")
+	framework.AddKubeconfigFlag(cmd.PersistentFlags())
+	framework.AddSkipCleanupFlag(cmd.PersistentFlags())
 
 	if v := version.Get().String(); len(v) == 0 {
 		cmd.Version = "<unknown>"
@@ -43,36 +90,63 @@ func newOperatorTestCommand(ctx context.Context) *cobra.Command {
 		cmd.Version = v
 	}
 
-	cmd.AddCommand(otecmd.DefaultExtensionCommands(registry)...)
+	cmd.AddCommand(extensionCommands...)
+	cmd.AddCommand(newVersionCommand())
+	cmd.AddCommand(newListSuitesCommand(registry, requirements))
+	cmd.AddCommand(newExplainConfigCommand())
 
-	return cmd
+	return cmd, nil
 }
 
-func prepareOperatorTestsRegistry() *oteextension.Registry {
+// buildTestSpecs builds the specs from the Ginkgo suite the e2e package registers, tests replace it to
+// build the registry from specs of their own.
+var buildTestSpecs = oteginkgo.BuildExtensionTestSpecsFromOpenShiftGinkgoSuite
+
+// prepareOperatorTestsRegistry returns the registry of the operator specs and suites, along with the
+// requirements of the suites on the environment by suite name. A suite missing from them has none. Only the
+// specs matching specFilter are registered, all of them when it is empty. It fails if the specs cannot be
+// built, carry invalid tags or do not match the suites, or specFilter is not a regular expression.
+func prepareOperatorTestsRegistry(flakyAttempts *int, specFilter string) (*oteextension.Registry, map[string]suiteRequirements, error) {
 	registry := oteextension.NewRegistry()
 	extension := oteextension.NewExtension("openshift", "payload", "cluster-openshift-controller-manager-operator")
 
 	// Build test specs from Ginkgo tests
-	testSpecs, err := oteginkgo.BuildExtensionTestSpecsFromOpenShiftGinkgoSuite()
+	testSpecs, err := buildTestSpecs()
 	if err != nil {
-		klog.Fatalf("failed to build test specs: %v", err)
+		return nil, nil, fmt.Errorf("failed to build test specs: %w", err)
 	}
-
-	testTimeout := 30 * time.Minute
+	if err := validateSpecTags(testSpecs); err != nil {
+		return nil, nil, fmt.Errorf("failed to register the test specs: %w", err)
+	}
+	markFlakySpecs(testSpecs, flakyAttempts)
 
 	// Register serial test suite for tests that must run serially
-	serialSuite := oteextension.Suite{
-		Name: "openshift/cluster-openshift-controller-manager-operator/operator/serial",
-		Qualifiers: []string{
-			`test.Name.Contains("[Serial]") && (test.Name.Contains("[Operator]") || test.Name.Contains("[TLS]") || test.Name.Contains("[Build]") || test.Name.Contains("[Image]"))`,
-		},
-		Parallelism: 1,
-		TestTimeout: &testTimeout,
+	serialSuite, err := newSerialSuite(testSpecs, serialSuiteTags)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to build the serial suite: %w", err)
 	}
 
 	extension.AddSuite(serialSuite)
-	extension.AddSpecs(testSpecs)
+	requirements := map[string]suiteRequirements{
+		serialSuite.Name: serialSuiteRequirements,
+	}
+	// Register parallel test suite for tests that may run alongside others
+	extension.AddSuite(newParallelSuite())
+	// Register the suite of the upgrade lanes
+	extension.AddSuite(newUpgradeSuite())
+	// Register the suite of the API disruption lane
+	apiDisruptionSuite := newAPIDisruptionSuite()
+	extension.AddSuite(apiDisruptionSuite)
+	requirements[apiDisruptionSuite.Name] = apiDisruptionSuiteRequirements
+	// Register a suite running every spec, the specs stay in their other suites too
+	extension.AddSuite(newAllSuite())
+	// the suites are built from all specs, so that a filter does not make their tags look unused
+	filteredSpecs, err := filterSpecs(testSpecs, specFilter)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to filter the test specs: %w", err)
+	}
+	extension.AddSpecs(filteredSpecs)
 
 	registry.Register(extension)
-	return registry
+	return registry, requirements, nil
 }
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	oteextension "github.com/openshift-eng/openshift-tests-extension/pkg/extension"
+	oteextensiontests "github.com/openshift-eng/openshift-tests-extension/pkg/extension/extensiontests"
+)
+
+// mustPrepareOperatorTestsRegistry is prepareOperatorTestsRegistry failing the test on an error.
+func mustPrepareOperatorTestsRegistry(t *testing.T, flakyAttempts *int, specFilter string) (*oteextension.Registry, map[string]suiteRequirements) {
+	t.Helper()
+	registry, requirements, err := prepareOperatorTestsRegistry(flakyAttempts, specFilter)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return registry, requirements
+}
+
+func TestPrepareOperatorTestsRegistrySerialSuite(t *testing.T) {
+	flakyAttempts := 1
+	registry, _ := mustPrepareOperatorTestsRegistry(t, &flakyAttempts, "")
+
+	var serial *oteextension.Suite
+	registry.Walk(func(extension *oteextension.Extension) {
+		for i := range extension.Suites {
+			if extension.Suites[i].Name == serialSuiteName {
+				serial = &extension.Suites[i]
+			}
+		}
+	})
+	if serial == nil {
+		t.Fatalf("expected the suite %q to be registered", serialSuiteName)
+	}
+	if serial.Parallelism != 1 {
+		t.Errorf("expected the serial suite to run one spec at a time, got parallelism %d", serial.Parallelism)
+	}
+	if serial.TestTimeout == nil {
+		t.Error("expected the serial suite to have a test timeout")
+	} else if *serial.TestTimeout != serialSuiteTestTimeout {
+		t.Errorf("expected the serial suite test timeout %s, got %s", serialSuiteTestTimeout, *serial.TestTimeout)
+	}
+	if len(serial.Qualifiers) == 0 {
+		t.Fatal("expected the serial suite to have qualifiers")
+	}
+	for _, qualifier := range serial.Qualifiers {
+		if len(qualifier) == 0 {
+			t.Error("expected no empty qualifier in the serial suite")
+		}
+	}
+	if qualifiers := strings.Join(serial.Qualifiers, " "); !strings.Contains(qualifiers, nameTag(serialMarker)) {
+		t.Errorf("expected the serial suite qualifiers to reference %s, got %q", nameTag(serialMarker), qualifiers)
+	}
+}
+
+// withTestSpecs makes prepareOperatorTestsRegistry build the registry from the specs build returns for the
+// rest of the test.
+func withTestSpecs(t *testing.T, build func(...oteextensiontests.SelectFunction) (oteextensiontests.ExtensionTestSpecs, error)) {
+	original := buildTestSpecs
+	t.Cleanup(func() { buildTestSpecs = original })
+	buildTestSpecs = build
+}
+
+func TestPrepareOperatorTestsRegistryBuildError(t *testing.T) {
+	withTestSpecs(t, func(...oteextensiontests.SelectFunction) (oteextensiontests.ExtensionTestSpecs, error) {
+		return nil, errors.New("duplicate spec name")
+	})
+
+	flakyAttempts := 1
+	registry, requirements, err := prepareOperatorTestsRegistry(&flakyAttempts, "")
+	if err == nil {
+		t.Fatal("expected an error when the specs cannot be built")
+	}
+	if registry != nil || requirements != nil {
+		t.Error("expected no registry and no requirements along with the error")
+	}
+	if expected := "failed to build test specs: duplicate spec name"; err.Error() != expected {
+		t.Errorf("expected the error %q, got %q", expected, err)
+	}
+}
+
+func TestPrepareOperatorTestsRegistrySuiteAssignment(t *testing.T) {
+	withTestSpecs(t, func(...oteextensiontests.SelectFunction) (oteextensiontests.ExtensionTestSpecs, error) {
+		return oteextensiontests.ExtensionTestSpecs{
+			{Name: "[Operator][Serial] serial operator"},
+			{Name: "[TLS][Serial] serial tls"},
+			{Name: "[Operator][Disruptive] disruptive operator"},
+			{Name: "[Operator][Parallel] parallel operator"},
+			{Name: "[TLS][Parallel] parallel tls"},
+			{Name: "[Build][Serial] serial build"},
+			// the serial suite only claims the areas of serialSuiteTags
+			{Name: "[Image][Serial] serial image"},
+			{Name: "[Upgrade][Serial] upgrade"},
+			{Name: "[APIDisruption][Disruptive] api disruption"},
+		}, nil
+	})
+	flakyAttempts := 1
+	registry, _ := mustPrepareOperatorTestsRegistry(t, &flakyAttempts, "")
+
+	expected := map[string][]string{
+		"serial": {
+			"[Operator][Serial] serial operator",
+			"[TLS][Serial] serial tls",
+			"[Operator][Disruptive] disruptive operator",
+			"[Build][Serial] serial build",
+		},
+		"parallel": {
+			"[Operator][Parallel] parallel operator",
+			"[TLS][Parallel] parallel tls",
+		},
+		"upgrade":        {"[Upgrade][Serial] upgrade"},
+		"api-disruption": {"[APIDisruption][Disruptive] api disruption"},
+		"all": {
+			"[Operator][Serial] serial operator",
+			"[TLS][Serial] serial tls",
+			"[Operator][Disruptive] disruptive operator",
+			"[Operator][Parallel] parallel operator",
+			"[TLS][Parallel] parallel tls",
+			"[Build][Serial] serial build",
+			"[Image][Serial] serial image",
+			"[Upgrade][Serial] upgrade",
+			"[APIDisruption][Disruptive] api disruption",
+		},
+	}
+	for suite, expectedNames := range expected {
+		specs, err := suiteSpecs(registry, suite)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(expectedNames, specs.Names()); len(diff) > 0 {
+			t.Errorf("unexpected specs of the %s suite (-want +got):\n%s", suite, diff)
+		}
+	}
+}
+
+func TestPrepareOperatorTestsRegistryInvalidSpecTags(t *testing.T) {
+	withTestSpecs(t, func(...oteextensiontests.SelectFunction) (oteextensiontests.ExtensionTestSpecs, error) {
+		return oteextensiontests.ExtensionTestSpecs{
+			{Name: "[Operator][TLS][Serial] serial"},
+			{Name: "[Operator][Serial][Disruptive] serial and disruptive"},
+		}, nil
+	})
+	flakyAttempts := 1
+	_, _, err := prepareOperatorTestsRegistry(&flakyAttempts, "")
+	if err == nil {
+		t.Fatal("expected an error for a spec with two execution tags")
+	}
+	if expected := `"[Operator][Serial][Disruptive] serial and disruptive": 2 execution tags`; !strings.Contains(err.Error(), expected) {
+		t.Errorf("expected the error to contain %q, got %v", expected, err)
+	}
+}
+
+func TestPrepareOperatorTestsRegistryInvalidSpecFilter(t *testing.T) {
+	flakyAttempts := 1
+	registry, _, err := prepareOperatorTestsRegistry(&flakyAttempts, `[TLS`)
+	if err == nil {
+		t.Fatal("expected an error for an invalid spec filter")
+	}
+	if registry != nil {
+		t.Error("expected no registry along with the error")
+	}
+	if expected := "failed to filter the test specs: invalid --spec-filter"; !strings.Contains(err.Error(), expected) {
+		t.Errorf("expected the error to contain %q, got %v", expected, err)
+	}
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	oteflags "github.com/openshift-eng/openshift-tests-extension/pkg/flags"
+	configv1 "github.com/openshift/api/config/v1"
+)
+
+// knownPlatforms are the platforms the environment selectors of the specs are evaluated for, the platform
+// types of infrastructures.config.openshift.io in lower case like openshift-tests passes them.
+var knownPlatforms = func() map[string]bool {
+	platforms := map[string]bool{}
+	for _, platform := range []configv1.PlatformType{
+		configv1.AWSPlatformType,
+		configv1.AzurePlatformType,
+		configv1.BareMetalPlatformType,
+		configv1.GCPPlatformType,
+		configv1.LibvirtPlatformType,
+		configv1.OpenStackPlatformType,
+		configv1.NonePlatformType,
+		configv1.VSpherePlatformType,
+		configv1.OvirtPlatformType,
+		configv1.IBMCloudPlatformType,
+		configv1.KubevirtPlatformType,
+		configv1.EquinixMetalPlatformType,
+		configv1.PowerVSPlatformType,
+		configv1.AlibabaCloudPlatformType,
+		configv1.NutanixPlatformType,
+		configv1.ExternalPlatformType,
+	} {
+		platforms[strings.ToLower(string(platform))] = true
+	}
+	return platforms
+}()
+
+// platformEnvironment returns the environment the specs are selected for on platform, so that the selection
+// does not need the Infrastructure of a cluster. An empty platform selects every spec.
+func platformEnvironment(platform string) (oteflags.EnvironmentalFlags, error) {
+	if len(platform) == 0 {
+		return oteflags.EnvironmentalFlags{}, nil
+	}
+	if !knownPlatforms[platform] {
+		known := make([]string, 0, len(knownPlatforms))
+		for p := range knownPlatforms {
+			known = append(known, p)
+		}
+		sort.Strings(known)
+		return oteflags.EnvironmentalFlags{}, fmt.Errorf("unknown platform %q, expected one of %s", platform, strings.Join(known, ", "))
+	}
+	return oteflags.EnvironmentalFlags{Platform: platform}, nil
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+
+	"github.com/spf13/pflag"
+
+	oteextensiontests "github.com/openshift-eng/openshift-tests-extension/pkg/extension/extensiontests"
+)
+
+// specFilterFlag is the flag restricting the registered specs to the ones whose name matches a regular
+// expression, for quick local iterations. The registry is built before the command parses its flags, so
+// the flag is read from the arguments up front and only declared on the command for its help.
+const specFilterFlag = "spec-filter"
+
+// specFilterFromArgs returns the value of --spec-filter in the arguments, empty if it is not set. Every
+// other argument is ignored, the command parses and validates them.
+func specFilterFromArgs(args []string) string {
+	flags := pflag.NewFlagSet(specFilterFlag, pflag.ContinueOnError)
+	flags.ParseErrorsWhitelist.UnknownFlags = true
+	flags.SetOutput(io.Discard)
+	flags.Usage = func() {}
+	filter := flags.String(specFilterFlag, "", "")
+	// a malformed command line is reported by the command itself
+	_ = flags.Parse(args)
+	return *filter
+}
+
+// filterSpecs returns the specs whose name matches the filter, all of them for an empty filter.
+func filterSpecs(specs oteextensiontests.ExtensionTestSpecs, filter string) (oteextensiontests.ExtensionTestSpecs, error) {
+	if len(filter) == 0 {
+		return specs, nil
+	}
+	pattern, err := regexp.Compile(filter)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --%s %q: %w", specFilterFlag, filter, err)
+	}
+	return specs.Select(func(spec *oteextensiontests.ExtensionTestSpec) bool {
+		return pattern.MatchString(spec.Name)
+	}), nil
+}
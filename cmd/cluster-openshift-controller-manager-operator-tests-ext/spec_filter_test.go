@@ -0,0 +1,64 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	oteextension "github.com/openshift-eng/openshift-tests-extension/pkg/extension"
+	oteextensiontests "github.com/openshift-eng/openshift-tests-extension/pkg/extension/extensiontests"
+)
+
+func TestSpecFilterFromArgs(t *testing.T) {
+	tests := []struct {
+		args     []string
+		expected string
+	}{
+		{args: nil},
+		{args: []string{"run-suite", "openshift/cluster-openshift-controller-manager-operator/operator/serial"}},
+		{args: []string{"run-test", "--spec-filter", "TLS", "--flaky-attempts=2"}, expected: "TLS"},
+		{args: []string{"list", "tests", "--output=names", "--spec-filter=Operator Restart"}, expected: "Operator Restart"},
+	}
+	for _, tc := range tests {
+		if filter := specFilterFromArgs(tc.args); filter != tc.expected {
+			t.Errorf("expected the filter %q from %q, got %q", tc.expected, tc.args, filter)
+		}
+	}
+}
+
+func TestPrepareOperatorTestsRegistryWithSpecFilter(t *testing.T) {
+	const filter = `\[TLS\].*cipher`
+	flakyAttempts := 1
+	registry, _ := mustPrepareOperatorTestsRegistry(t, &flakyAttempts, filter)
+	specs := registeredSpecs(registry)
+	if len(specs) == 0 {
+		t.Fatalf("expected specs matching %q to be registered", filter)
+	}
+	for _, name := range specs.Names() {
+		if !strings.Contains(name, "[TLS]") || !strings.Contains(name, "cipher") {
+			t.Errorf("expected only specs matching %q, got %q", filter, name)
+		}
+	}
+
+	unfiltered, _ := mustPrepareOperatorTestsRegistry(t, &flakyAttempts, "")
+	if all := registeredSpecs(unfiltered); len(all) <= len(specs) {
+		t.Errorf("expected the filter to leave out specs, %d of %d are registered", len(specs), len(all))
+	}
+}
+
+func registeredSpecs(registry *oteextension.Registry) oteextensiontests.ExtensionTestSpecs {
+	var specs oteextensiontests.ExtensionTestSpecs
+	registry.Walk(func(extension *oteextension.Extension) {
+		specs = append(specs, extension.GetSpecs()...)
+	})
+	return specs
+}
+
+func TestFilterSpecsInvalid(t *testing.T) {
+	_, err := filterSpecs(serialSuiteTestSpecs(), "[TLS")
+	if err == nil {
+		t.Fatal("expected an error for a regular expression which does not compile")
+	}
+	if !strings.Contains(err.Error(), `invalid --spec-filter "[TLS"`) {
+		t.Errorf("expected the error to name the flag and its value, got %v", err)
+	}
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	oteextension "github.com/openshift-eng/openshift-tests-extension/pkg/extension"
+	oteextensiontests "github.com/openshift-eng/openshift-tests-extension/pkg/extension/extensiontests"
+)
+
+// suiteFlag is the flag of the root command running a single registered suite, the same way run-suite
+// does, for reproducing a CI lane locally without the orchestration of openshift-tests.
+const suiteFlag = "suite"
+
+// resolveSuite returns the full name of the registered suite named name, either by its full name or by the
+// last element of it, e.g. "serial". The error lists the valid names.
+func resolveSuite(registry *oteextension.Registry, name string) (string, error) {
+	var resolved string
+	var valid []string
+	registry.Walk(func(extension *oteextension.Extension) {
+		for _, suite := range extension.Suites {
+			short := path.Base(suite.Name)
+			if name == suite.Name || name == short {
+				resolved = suite.Name
+			}
+			valid = append(valid, short)
+		}
+	})
+	if len(resolved) > 0 {
+		return resolved, nil
+	}
+	sort.Strings(valid)
+	return "", fmt.Errorf("unknown --%s %q, valid suites are: %s", suiteFlag, name, strings.Join(valid, ", "))
+}
+
+// suiteSpecs returns the registered specs the qualifiers of the suite named name claim.
+func suiteSpecs(registry *oteextension.Registry, name string) (oteextensiontests.ExtensionTestSpecs, error) {
+	suiteName, err := resolveSuite(registry, name)
+	if err != nil {
+		return nil, err
+	}
+	var specs oteextensiontests.ExtensionTestSpecs
+	var filterErr error
+	registry.Walk(func(extension *oteextension.Extension) {
+		suite, err := extension.GetSuite(suiteName)
+		if err != nil {
+			// the suite belongs to another extension
+			return
+		}
+		selected, err := extension.GetSpecs().Filter(suite.Qualifiers)
+		if err != nil {
+			filterErr = fmt.Errorf("suite %q: %w", suiteName, err)
+			return
+		}
+		specs = append(specs, selected...)
+	})
+	return specs, filterErr
+}
+
+// runSuite runs the registered suite named name with the run-suite command among cmds.
+func runSuite(cmds []*cobra.Command, registry *oteextension.Registry, name string) error {
+	suiteName, err := resolveSuite(registry, name)
+	if err != nil {
+		return err
+	}
+	for _, cmd := range cmds {
+		if cmd.Name() == "run-suite" {
+			if cmd.PreRunE != nil {
+				if err := cmd.PreRunE(cmd, []string{suiteName}); err != nil {
+					return err
+				}
+			}
+			return cmd.RunE(cmd, []string{suiteName})
+		}
+	}
+	return fmt.Errorf("no run-suite command to run suite %q with", suiteName)
+}
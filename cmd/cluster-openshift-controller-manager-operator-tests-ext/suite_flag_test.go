@@ -0,0 +1,62 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestResolveSuite(t *testing.T) {
+	flakyAttempts := 1
+	registry, _ := mustPrepareOperatorTestsRegistry(t, &flakyAttempts, "")
+
+	for _, name := range []string{"serial", serialSuiteName} {
+		resolved, err := resolveSuite(registry, name)
+		if err != nil {
+			t.Fatalf("unexpected error for %q: %v", name, err)
+		}
+		if resolved != serialSuiteName {
+			t.Errorf("expected %q to resolve to %q, got %q", name, serialSuiteName, resolved)
+		}
+	}
+
+	_, err := resolveSuite(registry, "paralel")
+	if err == nil {
+		t.Fatal("expected an error for an unknown suite")
+	}
+	if expected := `unknown --suite "paralel", valid suites are: all, api-disruption, parallel, serial, upgrade`; err.Error() != expected {
+		t.Errorf("expected the error %q, got %q", expected, err)
+	}
+}
+
+func TestSuiteSpecs(t *testing.T) {
+	flakyAttempts := 1
+	registry, _ := mustPrepareOperatorTestsRegistry(t, &flakyAttempts, "")
+
+	serial, err := suiteSpecs(registry, "serial")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(serial) == 0 {
+		t.Fatal("expected the serial suite to claim specs")
+	}
+	for _, name := range serial.Names() {
+		if !strings.Contains(name, nameTag(serialMarker)) && !strings.Contains(name, nameTag(disruptiveMarker)) {
+			t.Errorf("expected only %s or %s specs in the serial suite, got %q", nameTag(serialMarker), nameTag(disruptiveMarker), name)
+		}
+	}
+
+	all, err := suiteSpecs(registry, "all")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != len(registeredSpecs(registry)) {
+		t.Errorf("expected the all suite to claim the %d registered specs, got %d", len(registeredSpecs(registry)), len(all))
+	}
+	if len(serial) >= len(all) {
+		t.Errorf("expected the serial suite to leave out specs, it claims %d of %d", len(serial), len(all))
+	}
+
+	if _, err := suiteSpecs(registry, "paralel"); err == nil {
+		t.Error("expected an error for an unknown suite")
+	}
+}
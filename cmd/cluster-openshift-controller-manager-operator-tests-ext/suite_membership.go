@@ -0,0 +1,51 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	oteextension "github.com/openshift-eng/openshift-tests-extension/pkg/extension"
+	oteflags "github.com/openshift-eng/openshift-tests-extension/pkg/flags"
+)
+
+// writeSuiteMembership writes one "suite<TAB>spec" line for every spec claimed by the qualifiers
+// of every suite in the registry among the specs the environment selects. Nothing is run. Lines are
+// sorted so the output can be diffed across changes to catch qualifier regressions that silently drop
+// tests from a lane.
+func writeSuiteMembership(w io.Writer, registry *oteextension.Registry, environment oteflags.EnvironmentalFlags) error {
+	var lines []string
+	var filterErr error
+	registry.Walk(func(ext *oteextension.Extension) {
+		selected, err := ext.GetSpecs().FilterByEnvironment(environment)
+		if err != nil {
+			if filterErr == nil {
+				filterErr = fmt.Errorf("extension %q: %w", ext.Component.Name, err)
+			}
+			return
+		}
+		for _, suite := range ext.Suites {
+			specs, err := selected.Filter(suite.Qualifiers)
+			if err != nil {
+				if filterErr == nil {
+					filterErr = fmt.Errorf("suite %q: %w", suite.Name, err)
+				}
+				continue
+			}
+			for _, name := range specs.Names() {
+				lines = append(lines, fmt.Sprintf("%s\t%s", suite.Name, name))
+			}
+		}
+	})
+	if filterErr != nil {
+		return filterErr
+	}
+
+	sort.Strings(lines)
+	for _, line := range lines {
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
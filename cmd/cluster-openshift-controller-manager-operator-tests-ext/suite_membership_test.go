@@ -0,0 +1,121 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	oteextension "github.com/openshift-eng/openshift-tests-extension/pkg/extension"
+	oteextensiontests "github.com/openshift-eng/openshift-tests-extension/pkg/extension/extensiontests"
+	oteflags "github.com/openshift-eng/openshift-tests-extension/pkg/flags"
+)
+
+func TestWriteSuiteMembership(t *testing.T) {
+	registry := oteextension.NewRegistry()
+	extension := oteextension.NewExtension("openshift", "payload", "test")
+	extension.AddSuite(oteextension.Suite{
+		Name:       "test/serial",
+		Qualifiers: []string{`name.contains("[Serial]")`},
+	})
+	extension.AddSuite(oteextension.Suite{
+		Name: "test/all",
+	})
+	extension.AddSpecs(oteextensiontests.ExtensionTestSpecs{
+		{Name: "b [Serial]"},
+		{Name: "a [Serial]"},
+		{Name: "c"},
+	})
+	registry.Register(extension)
+
+	out := &bytes.Buffer{}
+	if err := writeSuiteMembership(out, registry, oteflags.EnvironmentalFlags{}); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "test/all\ta [Serial]\n" +
+		"test/all\tb [Serial]\n" +
+		"test/all\tc\n" +
+		"test/serial\ta [Serial]\n" +
+		"test/serial\tb [Serial]\n"
+	if out.String() != expected {
+		t.Errorf("expected:\n%s\ngot:\n%s", expected, out.String())
+	}
+}
+
+func TestWriteSuiteMembershipInvalidQualifier(t *testing.T) {
+	registry := oteextension.NewRegistry()
+	extension := oteextension.NewExtension("openshift", "payload", "test")
+	extension.AddSuite(oteextension.Suite{
+		Name:       "test/broken",
+		Qualifiers: []string{`name.contains(`},
+	})
+	extension.AddSpecs(oteextensiontests.ExtensionTestSpecs{{Name: "a"}})
+	registry.Register(extension)
+
+	if err := writeSuiteMembership(&bytes.Buffer{}, registry, oteflags.EnvironmentalFlags{}); err == nil {
+		t.Fatal("expected an error for an invalid qualifier")
+	}
+}
+
+func TestWriteSuiteMembershipForPlatform(t *testing.T) {
+	registry := oteextension.NewRegistry()
+	extension := oteextension.NewExtension("openshift", "payload", "test")
+	extension.AddSuite(oteextension.Suite{
+		Name:       "test/serial",
+		Qualifiers: []string{`name.contains("[Serial]")`},
+	})
+	awsOnly := &oteextensiontests.ExtensionTestSpec{Name: "aws only [Serial]"}
+	awsOnly.Include(oteextensiontests.PlatformEquals("aws"))
+	notOnMetal := &oteextensiontests.ExtensionTestSpec{Name: "not on metal [Serial]"}
+	notOnMetal.Exclude(oteextensiontests.PlatformEquals("baremetal"))
+	extension.AddSpecs(oteextensiontests.ExtensionTestSpecs{
+		awsOnly,
+		notOnMetal,
+		{Name: "everywhere [Serial]"},
+	})
+	registry.Register(extension)
+
+	tests := []struct {
+		platform string
+		expected string
+	}{
+		{
+			platform: "aws",
+			expected: "test/serial\taws only [Serial]\n" +
+				"test/serial\teverywhere [Serial]\n" +
+				"test/serial\tnot on metal [Serial]\n",
+		},
+		{
+			platform: "baremetal",
+			expected: "test/serial\teverywhere [Serial]\n",
+		},
+		{
+			// without a platform nothing is detected and every spec is selected
+			expected: "test/serial\taws only [Serial]\n" +
+				"test/serial\teverywhere [Serial]\n" +
+				"test/serial\tnot on metal [Serial]\n",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.platform, func(t *testing.T) {
+			environment, err := platformEnvironment(tc.platform)
+			if err != nil {
+				t.Fatal(err)
+			}
+			out := &bytes.Buffer{}
+			if err := writeSuiteMembership(out, registry, environment); err != nil {
+				t.Fatal(err)
+			}
+			if out.String() != tc.expected {
+				t.Errorf("expected:\n%s\ngot:\n%s", tc.expected, out.String())
+			}
+		})
+	}
+}
+
+func TestPlatformEnvironmentUnknownPlatform(t *testing.T) {
+	for _, platform := range []string{"AWS", "metal", "mock"} {
+		if _, err := platformEnvironment(platform); err == nil {
+			t.Errorf("expected an error for the unknown platform %q", platform)
+		}
+	}
+}
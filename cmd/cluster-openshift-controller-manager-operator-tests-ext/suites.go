@@ -0,0 +1,139 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	oteextension "github.com/openshift-eng/openshift-tests-extension/pkg/extension"
+	oteextensiontests "github.com/openshift-eng/openshift-tests-extension/pkg/extension/extensiontests"
+)
+
+const (
+	serialSuiteName        = "openshift/cluster-openshift-controller-manager-operator/operator/serial"
+	parallelSuiteName      = "openshift/cluster-openshift-controller-manager-operator/operator/parallel"
+	allSuiteName           = "openshift/cluster-openshift-controller-manager-operator/operator/all"
+	upgradeSuiteName       = "openshift/cluster-openshift-controller-manager-operator/operator/upgrade"
+	apiDisruptionSuiteName = "openshift/cluster-openshift-controller-manager-operator/operator/api-disruption"
+	// upgradeMarker is the tag of the specs checking the operator across a cluster upgrade.
+	upgradeMarker = "Upgrade"
+	// apiDisruptionMarker is the tag of the specs cutting the operator off the API.
+	apiDisruptionMarker = "APIDisruption"
+	// serialMarker is the tag of the specs which must run one at a time.
+	serialMarker = "Serial"
+	// parallelMarker is the tag of the specs which only read the cluster and may run alongside others.
+	parallelMarker = "Parallel"
+)
+
+// serialSuiteTags are the name tags of which a [Serial] or [Disruptive] spec needs at least one to belong to the
+// serial suite. Every tag must be carried by at least one spec, which is why [Image] is left out until a spec
+// carries it, the serial suite selected it before any did.
+var serialSuiteTags = []string{"Operator", "TLS", "Build"}
+
+// anyEnvironment is what a suite declaring no requirement on a property of the environment requires of it.
+const anyEnvironment = "any"
+
+// suiteRequirements are what a suite requires of the environment it runs in, with the values of the
+// environment flags of openshift-tests. An empty requirement is met by any environment, the aggregator
+// skips a whole suite on clusters not meeting its requirements instead of skipping each of its specs.
+type suiteRequirements struct {
+	Platform    string
+	Topology    string
+	NetworkType string
+}
+
+// serialSuiteTestTimeout is how long a spec of the serial suite may run.
+const serialSuiteTestTimeout = 30 * time.Minute
+
+// serialSuiteRequirements are the requirements of the serial suite: its disruptive specs roll the operand
+// out again, which only a highly available control plane rides out without losing the API.
+var serialSuiteRequirements = suiteRequirements{Topology: "ha"}
+
+// newSerialSuite returns the suite running the [Serial] and [Disruptive] specs carrying any of the given
+// tags one at a time. It fails if a tag is found on none of the specs, as that is most likely a typo.
+func newSerialSuite(specs oteextensiontests.ExtensionTestSpecs, tags []string) (oteextension.Suite, error) {
+	if len(tags) == 0 {
+		return oteextension.Suite{}, fmt.Errorf("suite %q: no tags declared", serialSuiteName)
+	}
+	for _, tag := range append([]string{serialMarker}, tags...) {
+		if len(specs.Select(oteextensiontests.NameContains(nameTag(tag)))) == 0 {
+			return oteextension.Suite{}, fmt.Errorf("suite %q: tag %s is not carried by any spec", serialSuiteName, nameTag(tag))
+		}
+	}
+
+	anyTag := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		anyTag = append(anyTag, nameContains(tag))
+	}
+
+	testTimeout := serialSuiteTestTimeout
+	return oteextension.Suite{
+		Name: serialSuiteName,
+		Qualifiers: []string{
+			fmt.Sprintf("(%s || %s) && (%s)", nameContains(serialMarker), nameContains(disruptiveMarker), strings.Join(anyTag, " || ")),
+		},
+		Parallelism: 1,
+		TestTimeout: &testTimeout,
+	}, nil
+}
+
+// newParallelSuite returns the suite running the [Parallel] specs, as many at once as openshift-tests
+// runs. They do not change the cluster, so they need no dedicated lane.
+func newParallelSuite() oteextension.Suite {
+	testTimeout := 15 * time.Minute
+	return oteextension.Suite{
+		Name:        parallelSuiteName,
+		Qualifiers:  []string{nameContains(parallelMarker)},
+		TestTimeout: &testTimeout,
+	}
+}
+
+// newAllSuite returns the suite running every spec one at a time, so that developers and full-matrix gates
+// can run everything without knowing how the specs are split into suites.
+func newAllSuite() oteextension.Suite {
+	testTimeout := 30 * time.Minute
+	return oteextension.Suite{
+		Name:        allSuiteName,
+		Qualifiers:  []string{"true"},
+		Parallelism: 1,
+		TestTimeout: &testTimeout,
+	}
+}
+
+// newUpgradeSuite returns the suite for the upgrade lanes, running the [Upgrade] specs one at a time while
+// the cluster upgrades. Their timeout leaves room for the whole upgrade to roll out.
+func newUpgradeSuite() oteextension.Suite {
+	testTimeout := 2 * time.Hour
+	return oteextension.Suite{
+		Name:        upgradeSuiteName,
+		Qualifiers:  []string{nameContains(upgradeMarker)},
+		Parallelism: 1,
+		TestTimeout: &testTimeout,
+	}
+}
+
+// apiDisruptionSuiteRequirements are the requirements of the API disruption suite, like the ones of the
+// serial suite.
+var apiDisruptionSuiteRequirements = serialSuiteRequirements
+
+// newAPIDisruptionSuite returns the suite for a dedicated lane, running the [APIDisruption] specs one at a
+// time. They leave the operator without access to the API for a while, which the other specs of a lane
+// sharing the cluster would fail on.
+func newAPIDisruptionSuite() oteextension.Suite {
+	testTimeout := serialSuiteTestTimeout
+	return oteextension.Suite{
+		Name:        apiDisruptionSuiteName,
+		Qualifiers:  []string{nameContains(apiDisruptionMarker)},
+		Parallelism: 1,
+		TestTimeout: &testTimeout,
+	}
+}
+
+func nameTag(tag string) string {
+	return "[" + tag + "]"
+}
+
+// nameContains returns a CEL expression matching the specs carrying tag in their name.
+func nameContains(tag string) string {
+	return fmt.Sprintf("name.contains(%q)", nameTag(tag))
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	oteextension "github.com/openshift-eng/openshift-tests-extension/pkg/extension"
+	oteextensiontests "github.com/openshift-eng/openshift-tests-extension/pkg/extension/extensiontests"
+)
+
+func serialSuiteTestSpecs() oteextensiontests.ExtensionTestSpecs {
+	return oteextensiontests.ExtensionTestSpecs{
+		{Name: "[Operator][TLS][Serial] tls"},
+		{Name: "[Operator][Disruptive] drift"},
+		{Name: "[TLS] parallel"},
+		{Name: "[Serial] untagged"},
+	}
+}
+
+func TestNewSerialSuite(t *testing.T) {
+	specs := serialSuiteTestSpecs()
+	suite, err := newSerialSuite(specs, []string{"Operator", "TLS"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if suite.Parallelism != 1 {
+		t.Errorf("expected parallelism 1, got %d", suite.Parallelism)
+	}
+
+	selected, err := specs.Filter(suite.Qualifiers)
+	if err != nil {
+		t.Fatalf("invalid qualifiers %v: %v", suite.Qualifiers, err)
+	}
+	expected := []string{"[Operator][TLS][Serial] tls", "[Operator][Disruptive] drift"}
+	if got := selected.Names(); strings.Join(got, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("expected suite specs %q, got %q", expected, got)
+	}
+}
+
+func TestNewSerialSuiteUnknownTag(t *testing.T) {
+	_, err := newSerialSuite(serialSuiteTestSpecs(), []string{"Operator", "Buidl"})
+	if err == nil {
+		t.Fatal("expected an error for a tag no spec carries")
+	}
+	if !strings.Contains(err.Error(), "[Buidl]") {
+		t.Errorf("expected the error to name the tag, got %v", err)
+	}
+}
+
+func TestAllSuite(t *testing.T) {
+	specs := serialSuiteTestSpecs()
+	registry := oteextension.NewRegistry()
+	extension := oteextension.NewExtension("openshift", "payload", "test")
+	serialSuite, err := newSerialSuite(specs, []string{"Operator", "TLS"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	extension.AddSuite(serialSuite)
+	extension.AddSuite(newAllSuite())
+	extension.AddSpecs(specs)
+	registry.Register(extension)
+
+	suite, err := extension.GetSuite(allSuiteName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if suite.Parallelism != 1 {
+		t.Errorf("expected parallelism 1, got %d", suite.Parallelism)
+	}
+	claimed, err := extension.GetSpecs().Filter(suite.Qualifiers)
+	if err != nil {
+		t.Fatalf("invalid qualifiers %v: %v", suite.Qualifiers, err)
+	}
+	if len(claimed) != len(specs) {
+		t.Errorf("expected the all suite to claim all %d specs, got %d: %v", len(specs), len(claimed), claimed.Names())
+	}
+}
+
+func TestParallelSuite(t *testing.T) {
+	specs := oteextensiontests.ExtensionTestSpecs{
+		{Name: "[Operator][Parallel] related objects"},
+		{Name: "[Operator][Serial] serial operator"},
+		{Name: "[Operator][Disruptive] disruptive operator"},
+	}
+	suite := newParallelSuite()
+	selected, err := specs.Filter(suite.Qualifiers)
+	if err != nil {
+		t.Fatalf("invalid qualifiers %v: %v", suite.Qualifiers, err)
+	}
+	if got := selected.Names(); len(got) != 1 || got[0] != "[Operator][Parallel] related objects" {
+		t.Errorf("expected the parallel suite to claim only the [Parallel] spec, got %q", got)
+	}
+}
+
+func TestUpgradeSuite(t *testing.T) {
+	specs := oteextensiontests.ExtensionTestSpecs{
+		{Name: "[Upgrade] version reporting"},
+		{Name: "[Operator][TLS][Serial] tls"},
+	}
+	suite := newUpgradeSuite()
+	if suite.Parallelism != 1 {
+		t.Errorf("expected parallelism 1, got %d", suite.Parallelism)
+	}
+	selected, err := specs.Filter(suite.Qualifiers)
+	if err != nil {
+		t.Fatalf("invalid qualifiers %v: %v", suite.Qualifiers, err)
+	}
+	if got := selected.Names(); len(got) != 1 || got[0] != "[Upgrade] version reporting" {
+		t.Errorf("expected the upgrade suite to claim only the [Upgrade] spec, got %q", got)
+	}
+
+	serialSuite, err := newSerialSuite(specs, []string{"TLS"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	serial, err := specs.Filter(serialSuite.Qualifiers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range serial.Names() {
+		if strings.Contains(name, "[Upgrade]") {
+			t.Errorf("expected the serial suite not to claim the upgrade spec %q", name)
+		}
+	}
+}
+
+func TestAPIDisruptionSuite(t *testing.T) {
+	specs := oteextensiontests.ExtensionTestSpecs{
+		{Name: "[APIDisruption][Disruptive] recovery"},
+		{Name: "[Operator][Serial] serial operator"},
+		{Name: "[Operator][Disruptive] disruptive operator"},
+	}
+	suite := newAPIDisruptionSuite()
+	if suite.Parallelism != 1 {
+		t.Errorf("expected parallelism 1, got %d", suite.Parallelism)
+	}
+	selected, err := specs.Filter(suite.Qualifiers)
+	if err != nil {
+		t.Fatalf("invalid qualifiers %v: %v", suite.Qualifiers, err)
+	}
+	if got := selected.Names(); len(got) != 1 || got[0] != "[APIDisruption][Disruptive] recovery" {
+		t.Errorf("expected the api disruption suite to claim only the [APIDisruption] spec, got %q", got)
+	}
+
+	serialSuite, err := newSerialSuite(specs, []string{"Operator"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	serial, err := specs.Filter(serialSuite.Qualifiers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range serial.Names() {
+		if strings.Contains(name, "[APIDisruption]") {
+			t.Errorf("expected the serial suite not to claim the api disruption spec %q", name)
+		}
+	}
+}
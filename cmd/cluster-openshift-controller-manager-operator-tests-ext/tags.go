@@ -0,0 +1,60 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	oteextensiontests "github.com/openshift-eng/openshift-tests-extension/pkg/extension/extensiontests"
+)
+
+// disruptiveMarker is the tag of the specs disrupting the operator or its operand, they run one at a
+// time in the serial suite like the [Serial] specs.
+const disruptiveMarker = "Disruptive"
+
+// areaTags are the name tags of what a spec covers, every spec carries at least one so that the
+// suites selecting by area do not miss it.
+var areaTags = []string{"Operator", "TLS", "Build", "Image", upgradeMarker, apiDisruptionMarker}
+
+// executionTags are the name tags of how a spec runs, every spec carries exactly one.
+var executionTags = []string{serialMarker, parallelMarker, disruptiveMarker}
+
+// validateSpecTags returns an error listing the specs not carrying an area tag or not carrying exactly
+// one execution tag, such specs would silently fall into no suite or into the wrong one.
+func validateSpecTags(specs oteextensiontests.ExtensionTestSpecs) error {
+	var invalid []string
+	for _, spec := range specs {
+		var problems []string
+		if len(carriedTags(spec.Name, areaTags)) == 0 {
+			problems = append(problems, fmt.Sprintf("no area tag of %s", strings.Join(nameTags(areaTags), "")))
+		}
+		if carried := carriedTags(spec.Name, executionTags); len(carried) != 1 {
+			problems = append(problems, fmt.Sprintf("%d execution tags of %s instead of one", len(carried), strings.Join(nameTags(executionTags), "")))
+		}
+		if len(problems) > 0 {
+			invalid = append(invalid, fmt.Sprintf("%q: %s", spec.Name, strings.Join(problems, ", ")))
+		}
+	}
+	if len(invalid) > 0 {
+		return fmt.Errorf("specs with invalid tags:\n  %s", strings.Join(invalid, "\n  "))
+	}
+	return nil
+}
+
+// carriedTags returns the tags carried in the spec name.
+func carriedTags(name string, tags []string) []string {
+	var carried []string
+	for _, tag := range tags {
+		if strings.Contains(name, nameTag(tag)) {
+			carried = append(carried, tag)
+		}
+	}
+	return carried
+}
+
+func nameTags(tags []string) []string {
+	ret := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		ret = append(ret, nameTag(tag))
+	}
+	return ret
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	oteextensiontests "github.com/openshift-eng/openshift-tests-extension/pkg/extension/extensiontests"
+)
+
+func TestValidateSpecTags(t *testing.T) {
+	tests := []struct {
+		name            string
+		specs           oteextensiontests.ExtensionTestSpecs
+		expectedInvalid []string
+	}{
+		{
+			name: "valid",
+			specs: oteextensiontests.ExtensionTestSpecs{
+				{Name: "[Operator][TLS][Serial] tls"},
+				{Name: "[Operator][Disruptive] drift"},
+				{Name: "[Build][Parallel] git proxy"},
+				{Name: "[Upgrade][Serial] version reporting"},
+			},
+		},
+		{
+			name: "mis-tagged",
+			specs: oteextensiontests.ExtensionTestSpecs{
+				{Name: "[Operator][TLS][Serial] tls"},
+				{Name: "[Serial] untagged"},
+				{Name: "[Operator][Serial][Disruptive] drift"},
+				{Name: "[Image] no execution tag"},
+			},
+			expectedInvalid: []string{
+				`"[Serial] untagged": no area tag`,
+				`"[Operator][Serial][Disruptive] drift": 2 execution tags`,
+				`"[Image] no execution tag": 0 execution tags`,
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateSpecTags(tc.specs)
+			if len(tc.expectedInvalid) == 0 {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("expected an error for the mis-tagged specs")
+			}
+			for _, expected := range tc.expectedInvalid {
+				if !strings.Contains(err.Error(), expected) {
+					t.Errorf("expected the error to contain %q, got %v", expected, err)
+				}
+			}
+			if strings.Contains(err.Error(), "tls") {
+				t.Errorf("expected the error not to name the valid spec, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/version"
+
+	operatorversion "github.com/openshift/cluster-openshift-controller-manager-operator/pkg/version"
+)
+
+// newVersionCommand returns a command printing the build metadata of the binary as JSON, so
+// that CI can record which binary produced a set of results. --version stays the human
+// readable short form.
+func newVersionCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "version",
+		Short: "Print the build metadata of this binary as JSON.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return writeVersion(cmd.OutOrStdout(), operatorversion.Get())
+		},
+	}
+}
+
+func writeVersion(w io.Writer, info version.Info) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(info)
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/version"
+)
+
+func TestWriteVersion(t *testing.T) {
+	out := &bytes.Buffer{}
+	info := version.Info{
+		GitVersion: "v4.20.0",
+		GitCommit:  "abcdef0",
+		BuildDate:  "2025-01-01T00:00:00Z",
+		GoVersion:  "go1.23.0",
+	}
+	if err := writeVersion(out, info); err != nil {
+		t.Fatal(err)
+	}
+
+	decoded := map[string]interface{}{}
+	if err := json.Unmarshal(out.Bytes(), &decoded); err != nil {
+		t.Fatalf("expected valid JSON, got %q: %v", out.String(), err)
+	}
+	for _, key := range []string{"major", "minor", "gitVersion", "gitCommit", "gitTreeState", "buildDate", "goVersion", "compiler", "platform"} {
+		if _, ok := decoded[key]; !ok {
+			t.Errorf("expected key %q in %s", key, out.String())
+		}
+	}
+	if decoded["gitCommit"] != "abcdef0" {
+		t.Errorf("expected gitCommit %q, got %v", "abcdef0", decoded["gitCommit"])
+	}
+}
+
+func TestVersionCommand(t *testing.T) {
+	cmd := newVersionCommand()
+	out := &bytes.Buffer{}
+	cmd.SetOut(out)
+	cmd.SetArgs(nil)
+	if err := cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	if !json.Valid(out.Bytes()) {
+		t.Errorf("expected valid JSON, got %q", out.String())
+	}
+}
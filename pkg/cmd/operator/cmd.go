@@ -11,11 +11,19 @@ import (
 )
 
 func NewOperator() *cobra.Command {
+	return newOperatorCommand(operator.NewOptions())
+}
+
+func newOperatorCommand(options *operator.Options) *cobra.Command {
 	cmd := controllercmd.
-		NewControllerCommandConfig("openshift-controller-manager-operator", version.Get(), operator.RunOperator, clock.RealClock{}).
+		NewControllerCommandConfig("openshift-controller-manager-operator", version.Get(), options.RunOperator, clock.RealClock{}).
 		NewCommand()
 	cmd.Use = "operator"
 	cmd.Short = "Start the Cluster openshift-controller-manager Operator"
+	cmd.PreRunE = func(*cobra.Command, []string) error {
+		return options.Validate()
+	}
+	options.AddFlags(cmd.Flags())
 
 	return cmd
 }
@@ -0,0 +1,31 @@
+package operator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/openshift/cluster-openshift-controller-manager-operator/pkg/operator"
+)
+
+func TestOperatorCommandResyncInterval(t *testing.T) {
+	options := operator.NewOptions()
+	cmd := newOperatorCommand(options)
+	if err := cmd.ParseFlags([]string{"--resync-interval=2m"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.PreRunE(cmd, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// the options are the ones the operator is started with
+	if options.ResyncInterval != 2*time.Minute {
+		t.Errorf("expected the resync interval to be 2m, got %v", options.ResyncInterval)
+	}
+
+	cmd = newOperatorCommand(operator.NewOptions())
+	if err := cmd.ParseFlags([]string{"--resync-interval=10s"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.PreRunE(cmd, nil); err == nil {
+		t.Error("expected a resync interval below the minimum to be rejected before the operator starts")
+	}
+}
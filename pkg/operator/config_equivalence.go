@@ -0,0 +1,61 @@
+package operator
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	coreclientv1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"sigs.k8s.io/yaml"
+)
+
+// keepEquivalentConfig keeps the config of the existing configmap in required when both hold the same
+// config written differently, e.g. with the keys in another order after an upgrade of the operator or
+// a manual edit. The operands roll out whenever their configmap changes, recomputing an identical config
+// must not change it.
+func keepEquivalentConfig(client coreclientv1.ConfigMapsGetter, required *corev1.ConfigMap, configKey string) error {
+	existing, err := client.ConfigMaps(required.Namespace).Get(context.TODO(), required.Name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	existingConfig, ok := existing.Data[configKey]
+	if !ok || existingConfig == required.Data[configKey] {
+		return nil
+	}
+	if equivalentConfig(existingConfig, required.Data[configKey]) {
+		required.Data[configKey] = existingConfig
+	}
+	return nil
+}
+
+// equivalentConfig returns whether the JSON or YAML configs a and b are equal once decoded. A config
+// that cannot be decoded is equivalent to none.
+func equivalentConfig(a, b string) bool {
+	decodedA, err := decodeConfig(a)
+	if err != nil {
+		return false
+	}
+	decodedB, err := decodeConfig(b)
+	if err != nil {
+		return false
+	}
+	return reflect.DeepEqual(decodedA, decodedB)
+}
+
+func decodeConfig(config string) (interface{}, error) {
+	configJSON, err := yaml.YAMLToJSON([]byte(config))
+	if err != nil {
+		return nil, err
+	}
+	var decoded interface{}
+	if err := json.Unmarshal(configJSON, &decoded); err != nil {
+		return nil, err
+	}
+	return decoded, nil
+}
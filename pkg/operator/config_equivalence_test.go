@@ -0,0 +1,116 @@
+package operator
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/clock"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/operator/events"
+
+	"github.com/openshift/cluster-openshift-controller-manager-operator/pkg/util"
+)
+
+// reorderedJSON returns the JSON object config with its top level keys in reverse order, the same
+// config written differently.
+func reorderedJSON(t *testing.T, config string) string {
+	t.Helper()
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal([]byte(config), &fields); err != nil {
+		t.Fatal(err)
+	}
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(keys)))
+	var members []string
+	for _, key := range keys {
+		members = append(members, `"`+key+`": `+string(fields[key]))
+	}
+	return "{\n  " + strings.Join(members, ",\n  ") + "\n}"
+}
+
+func TestEquivalentObservedConfigDoesNotRollOut(t *testing.T) {
+	kubeClient := fake.NewSimpleClientset()
+	recorder := events.NewInMemoryRecorder("", clock.RealClock{})
+	operatorConfig := &operatorv1.OpenShiftControllerManager{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+		Spec: operatorv1.OpenShiftControllerManagerSpec{
+			OperatorSpec: operatorv1.OperatorSpec{
+				ObservedConfig: runtime.RawExtension{Raw: []byte(`{"servingInfo": {"minTLSVersion": "VersionTLS12", "cipherSuites": ["TLS_AES_128_GCM_SHA256"]}}`)},
+			},
+		},
+	}
+
+	configMap, _, err := manageRouteControllerManagerConfigMap_v311_00_to_latest(kubeClient, kubeClient.CoreV1(), recorder, operatorConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the stored config has its keys in another order, e.g. as written by an older operator
+	storedConfig := reorderedJSON(t, configMap.Data["config.yaml"])
+	if storedConfig == configMap.Data["config.yaml"] {
+		t.Fatal("expected the stored config to be written differently")
+	}
+	configMap.Data["config.yaml"] = storedConfig
+	if _, err := kubeClient.CoreV1().ConfigMaps(util.RouteControllerTargetNamespace).Update(context.TODO(), configMap, metav1.UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	// the observers recompute the same observed config, its keys in another order
+	operatorConfig.Spec.ObservedConfig.Raw = []byte(`{"servingInfo": {"cipherSuites": ["TLS_AES_128_GCM_SHA256"], "minTLSVersion": "VersionTLS12"}}`)
+	kubeClient.ClearActions()
+	configMap, modified, err := manageRouteControllerManagerConfigMap_v311_00_to_latest(kubeClient, kubeClient.CoreV1(), recorder, operatorConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if modified {
+		t.Error("expected the configmap not to be modified for an equivalent config")
+	}
+	for _, action := range kubeClient.Actions() {
+		if action.GetVerb() == "update" && action.GetResource().Resource == "configmaps" {
+			t.Errorf("expected no configmap update, got %v", action)
+		}
+	}
+	if configMap.Data["config.yaml"] != storedConfig {
+		t.Errorf("expected the stored config to be kept, got %s", configMap.Data["config.yaml"])
+	}
+
+	// a different observed config still rolls out
+	operatorConfig.Spec.ObservedConfig.Raw = []byte(`{"servingInfo": {"cipherSuites": ["TLS_AES_128_GCM_SHA256"], "minTLSVersion": "VersionTLS13"}}`)
+	if _, modified, err = manageRouteControllerManagerConfigMap_v311_00_to_latest(kubeClient, kubeClient.CoreV1(), recorder, operatorConfig); err != nil {
+		t.Fatal(err)
+	}
+	if !modified {
+		t.Error("expected the configmap to be modified for a different config")
+	}
+}
+
+func TestEquivalentConfig(t *testing.T) {
+	tests := []struct {
+		name     string
+		a, b     string
+		expected bool
+	}{
+		{name: "same text", a: `{"a": 1}`, b: `{"a": 1}`, expected: true},
+		{name: "keys in another order", a: `{"a": 1, "b": {"c": "d", "e": "f"}}`, b: `{"b": {"e": "f", "c": "d"}, "a": 1}`, expected: true},
+		{name: "yaml and json", a: "a: 1\nb: [foo, bar]\n", b: `{"b": ["foo", "bar"], "a": 1}`, expected: true},
+		{name: "list order matters", a: `{"a": ["x", "y"]}`, b: `{"a": ["y", "x"]}`},
+		{name: "different value", a: `{"a": 1}`, b: `{"a": 2}`},
+		{name: "undecodable", a: `{"a": `, b: `{"a": `},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := equivalentConfig(tc.a, tc.b); got != tc.expected {
+				t.Errorf("expected %v, got %v", tc.expected, got)
+			}
+		})
+	}
+}
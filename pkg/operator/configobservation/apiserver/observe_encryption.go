@@ -0,0 +1,78 @@
+package apiserver
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/klog/v2"
+
+	configv1 "github.com/openshift/api/config/v1"
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/operator/configobserver"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-openshift-controller-manager-operator/pkg/operator/configobservation"
+)
+
+const (
+	// apiServerEncryptionType is an informational condition, it neither degrades the operator nor makes it
+	// progress.
+	apiServerEncryptionType       = "APIServerEncryption"
+	unknownEncryptionTypeReason   = "UnknownEncryptionType"
+	identityEncryptionReason      = "Identity"
+	encryptionTypeNotFoundMessage = "apiservers.config.openshift.io/cluster does not exist, resources are not encrypted at rest"
+)
+
+// encryptionReasons are the reasons of the APIServerEncryption condition by the encryption types which
+// encrypt the resources at rest.
+var encryptionReasons = map[configv1.EncryptionType]string{
+	configv1.EncryptionTypeAESCBC: "AESCBC",
+	configv1.EncryptionTypeAESGCM: "AESGCM",
+	configv1.EncryptionTypeKMS:    "KMS",
+}
+
+// NewObserveEncryptionFunc returns an observer recording the encryption at rest of the APIServer config in
+// the APIServerEncryption condition, True while resources are encrypted. The operands read and write the
+// resources through the API servers, which encrypt and decrypt them, so nothing is propagated to the
+// observed config. A type unknown to the operator, e.g. one added to the API later, is reported as Unknown
+// rather than failing the observation.
+func NewObserveEncryptionFunc(operatorClient v1helpers.OperatorClient) configobserver.ObserveConfigFunc {
+	return func(genericListers configobserver.Listers, recorder events.Recorder, existingConfig map[string]interface{}) (map[string]interface{}, []error) {
+		listers := genericListers.(configobservation.Listers)
+		observedConfig := map[string]interface{}{}
+
+		condition := operatorv1.OperatorCondition{
+			Type:    apiServerEncryptionType,
+			Status:  operatorv1.ConditionFalse,
+			Reason:  identityEncryptionReason,
+			Message: encryptionTypeNotFoundMessage,
+		}
+		apiServer, err := listers.APIServerLister().Get("cluster")
+		if err != nil && !errors.IsNotFound(err) {
+			return observedConfig, []error{err}
+		}
+		if errors.IsNotFound(err) {
+			klog.V(2).Infof("apiservers.config.openshift.io/cluster: not found")
+		} else {
+			encryptionType := apiServer.Spec.Encryption.Type
+			switch reason, encrypted := encryptionReasons[encryptionType]; {
+			case encrypted:
+				condition.Status = operatorv1.ConditionTrue
+				condition.Reason = reason
+				condition.Message = fmt.Sprintf("resources are encrypted at rest with %s", encryptionType)
+			case len(encryptionType) == 0 || encryptionType == configv1.EncryptionTypeIdentity:
+				condition.Message = "resources are not encrypted at rest"
+			default:
+				condition.Status = operatorv1.ConditionUnknown
+				condition.Reason = unknownEncryptionTypeReason
+				condition.Message = fmt.Sprintf("apiservers.config.openshift.io/cluster spec.encryption.type %q is unknown to the operator", encryptionType)
+			}
+		}
+		if _, _, err := v1helpers.UpdateStatus(context.TODO(), operatorClient, v1helpers.UpdateConditionFn(condition)); err != nil {
+			return observedConfig, []error{err}
+		}
+		return observedConfig, nil
+	}
+}
@@ -0,0 +1,129 @@
+package apiserver
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/utils/clock"
+
+	configv1 "github.com/openshift/api/config/v1"
+	operatorv1 "github.com/openshift/api/operator/v1"
+	configlistersv1 "github.com/openshift/client-go/config/listers/config/v1"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-openshift-controller-manager-operator/pkg/operator/configobservation"
+)
+
+func TestObserveEncryption(t *testing.T) {
+	// the observed config of the other observers is left alone
+	existing := map[string]interface{}{"servingInfo": map[string]interface{}{"minTLSVersion": "VersionTLS12"}}
+
+	tests := []struct {
+		name            string
+		apiServer       *configv1.APIServer
+		expectedStatus  operatorv1.ConditionStatus
+		expectedReason  string
+		expectedMessage string
+	}{
+		{
+			name:            "aescbc",
+			apiServer:       apiServerWithEncryption(configv1.EncryptionTypeAESCBC),
+			expectedStatus:  operatorv1.ConditionTrue,
+			expectedReason:  "AESCBC",
+			expectedMessage: "resources are encrypted at rest with aescbc",
+		},
+		{
+			name:            "aesgcm",
+			apiServer:       apiServerWithEncryption(configv1.EncryptionTypeAESGCM),
+			expectedStatus:  operatorv1.ConditionTrue,
+			expectedReason:  "AESGCM",
+			expectedMessage: "resources are encrypted at rest with aesgcm",
+		},
+		{
+			name:            "KMS",
+			apiServer:       apiServerWithEncryption(configv1.EncryptionTypeKMS),
+			expectedStatus:  operatorv1.ConditionTrue,
+			expectedReason:  "KMS",
+			expectedMessage: "resources are encrypted at rest with KMS",
+		},
+		{
+			name:            "identity",
+			apiServer:       apiServerWithEncryption(configv1.EncryptionTypeIdentity),
+			expectedStatus:  operatorv1.ConditionFalse,
+			expectedReason:  identityEncryptionReason,
+			expectedMessage: "resources are not encrypted at rest",
+		},
+		{
+			name:            "unset",
+			apiServer:       apiServerWithEncryption(""),
+			expectedStatus:  operatorv1.ConditionFalse,
+			expectedReason:  identityEncryptionReason,
+			expectedMessage: "resources are not encrypted at rest",
+		},
+		{
+			name:            "unknown type",
+			apiServer:       apiServerWithEncryption("aes256"),
+			expectedStatus:  operatorv1.ConditionUnknown,
+			expectedReason:  unknownEncryptionTypeReason,
+			expectedMessage: `apiservers.config.openshift.io/cluster spec.encryption.type "aes256" is unknown to the operator`,
+		},
+		{
+			name:            "no APIServer config",
+			expectedStatus:  operatorv1.ConditionFalse,
+			expectedReason:  identityEncryptionReason,
+			expectedMessage: encryptionTypeNotFoundMessage,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+			if tc.apiServer != nil {
+				if err := indexer.Add(tc.apiServer); err != nil {
+					t.Fatal(err)
+				}
+			}
+			listers := configobservation.Listers{
+				APIServerLister_: configlistersv1.NewAPIServerLister(indexer),
+			}
+			operatorClient := v1helpers.NewFakeOperatorClient(&operatorv1.OperatorSpec{}, &operatorv1.OperatorStatus{}, nil)
+			recorder := events.NewInMemoryRecorder("", clock.RealClock{})
+			observe := NewObserveEncryptionFunc(operatorClient)
+
+			// observing again yields the same config
+			for i := 0; i < 2; i++ {
+				observed, errs := observe(listers, recorder, existing)
+				if len(errs) > 0 {
+					t.Fatalf("unexpected errors: %v", errs)
+				}
+				if diff := cmp.Diff(map[string]interface{}{}, observed); len(diff) > 0 {
+					t.Errorf("unexpected observed config (-want +got):\n%s", diff)
+				}
+			}
+
+			_, status, _, err := operatorClient.GetOperatorState()
+			if err != nil {
+				t.Fatal(err)
+			}
+			condition := v1helpers.FindOperatorCondition(status.Conditions, apiServerEncryptionType)
+			if condition == nil {
+				t.Fatalf("expected a %s condition", apiServerEncryptionType)
+			}
+			if condition.Status != tc.expectedStatus || condition.Reason != tc.expectedReason || condition.Message != tc.expectedMessage {
+				t.Errorf("expected %s=%s with reason %q and message %q, got %s with reason %q and message %q",
+					apiServerEncryptionType, tc.expectedStatus, tc.expectedReason, tc.expectedMessage, condition.Status, condition.Reason, condition.Message)
+			}
+		})
+	}
+}
+
+func apiServerWithEncryption(encryptionType configv1.EncryptionType) *configv1.APIServer {
+	return &configv1.APIServer{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+		Spec:       configv1.APIServerSpec{Encryption: configv1.APIServerEncryption{Type: encryptionType}},
+	}
+}
@@ -0,0 +1,101 @@
+package apiserver
+
+import (
+	"fmt"
+	"path"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/klog/v2"
+
+	"github.com/openshift/library-go/pkg/operator/configobserver"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/resourcesynccontroller"
+
+	"github.com/openshift/cluster-openshift-controller-manager-operator/pkg/operator/configobservation"
+	"github.com/openshift/cluster-openshift-controller-manager-operator/pkg/util"
+)
+
+const (
+	// maxNamedCertificates is the number of user-serving-cert-NNN secrets the operand deployments mount.
+	maxNamedCertificates = 10
+	// namedCertificatesDir is where the operand deployments mount the user-serving-cert-NNN secrets.
+	namedCertificatesDir = "/var/run/secrets/named-certs"
+)
+
+var namedCertificatesPath = []string{"servingInfo", "namedCertificates"}
+
+// namedCertificateSecretName is the name of the secret the operand namespaces get a copy of the index-th
+// named certificate in, it must match the projected volumes of the operand deployments.
+func namedCertificateSecretName(index int) string {
+	return fmt.Sprintf("user-serving-cert-%03d", index)
+}
+
+// ObserveNamedCertificates serves the named certificates of the APIServer config for their SNI names. The
+// referenced secrets are synced from openshift-config into a fixed set of secrets in the operand namespaces,
+// up to maxNamedCertificates, and the copies of certificates which are no longer referenced are removed.
+func ObserveNamedCertificates(genericListers configobserver.Listers, recorder events.Recorder, existingConfig map[string]interface{}) (map[string]interface{}, []error) {
+	listers := genericListers.(configobservation.Listers)
+	prevObservedConfig := configobserver.Pruned(existingConfig, namedCertificatesPath)
+
+	var sources []string
+	var namedCertificates []interface{}
+	apiServer, err := listers.APIServerLister().Get("cluster")
+	if err != nil && !errors.IsNotFound(err) {
+		return prevObservedConfig, []error{err}
+	}
+	if errors.IsNotFound(err) {
+		klog.V(2).Infof("apiservers.config.openshift.io/cluster: not found")
+	} else {
+		for _, namedCertificate := range apiServer.Spec.ServingCerts.NamedCertificates {
+			if len(namedCertificate.ServingCertificate.Name) == 0 {
+				continue
+			}
+			if len(sources) == maxNamedCertificates {
+				recorder.Warningf("NamedCertificatesIgnored", "only the first %d named certificates of apiservers.config.openshift.io/cluster are served", maxNamedCertificates)
+				break
+			}
+			dir := path.Join(namedCertificatesDir, namedCertificateSecretName(len(sources)))
+			observed := map[string]interface{}{
+				"certFile": path.Join(dir, "tls.crt"),
+				"keyFile":  path.Join(dir, "tls.key"),
+			}
+			if len(namedCertificate.Names) > 0 {
+				names := make([]interface{}, 0, len(namedCertificate.Names))
+				for _, name := range namedCertificate.Names {
+					names = append(names, name)
+				}
+				observed["names"] = names
+			}
+			sources = append(sources, namedCertificate.ServingCertificate.Name)
+			namedCertificates = append(namedCertificates, observed)
+		}
+	}
+
+	var errs []error
+	for _, namespace := range []string{util.TargetNamespace, util.RouteControllerTargetNamespace} {
+		for i := 0; i < maxNamedCertificates; i++ {
+			// a zero source removes the copy
+			source := resourcesynccontroller.ResourceLocation{}
+			if i < len(sources) {
+				source = resourcesynccontroller.ResourceLocation{Namespace: util.UserSpecifiedGlobalConfigNamespace, Name: sources[i]}
+			}
+			destination := resourcesynccontroller.ResourceLocation{Namespace: namespace, Name: namedCertificateSecretName(i)}
+			if err := listers.ResourceSyncer().SyncSecret(destination, source); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return prevObservedConfig, errs
+	}
+
+	observedConfig := map[string]interface{}{}
+	if len(namedCertificates) == 0 {
+		return observedConfig, nil
+	}
+	if err := unstructured.SetNestedSlice(observedConfig, namedCertificates, namedCertificatesPath...); err != nil {
+		return prevObservedConfig, []error{err}
+	}
+	return observedConfig, nil
+}
@@ -0,0 +1,192 @@
+package apiserver
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/utils/clock"
+
+	configv1 "github.com/openshift/api/config/v1"
+	configlistersv1 "github.com/openshift/client-go/config/listers/config/v1"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/resourcesynccontroller"
+
+	"github.com/openshift/cluster-openshift-controller-manager-operator/pkg/operator/configobservation"
+)
+
+// fakeSecretSyncer records the source of every synced secret by destination, an empty source for a removal.
+type fakeSecretSyncer struct {
+	secrets map[string]string
+	err     error
+}
+
+func (f *fakeSecretSyncer) SyncConfigMap(destination, source resourcesynccontroller.ResourceLocation) error {
+	return nil
+}
+
+func (f *fakeSecretSyncer) SyncSecret(destination, source resourcesynccontroller.ResourceLocation) error {
+	if f.err != nil {
+		return f.err
+	}
+	location := ""
+	if len(source.Name) > 0 {
+		location = source.Namespace + "/" + source.Name
+	}
+	f.secrets[destination.Namespace+"/"+destination.Name] = location
+	return nil
+}
+
+func namedCertificate(secretName string, names ...string) configv1.APIServerNamedServingCert {
+	return configv1.APIServerNamedServingCert{
+		Names:              names,
+		ServingCertificate: configv1.SecretNameReference{Name: secretName},
+	}
+}
+
+func TestObserveNamedCertificates(t *testing.T) {
+	tests := []struct {
+		name              string
+		namedCertificates []configv1.APIServerNamedServingCert
+		expected          map[string]interface{}
+		expectedSources   []string
+	}{
+		{
+			name:     "no named certificates",
+			expected: map[string]interface{}{},
+		},
+		{
+			name:              "one named certificate",
+			namedCertificates: []configv1.APIServerNamedServingCert{namedCertificate("apps-cert", "*.apps.example.com")},
+			expected: map[string]interface{}{
+				"servingInfo": map[string]interface{}{
+					"namedCertificates": []interface{}{
+						map[string]interface{}{
+							"names":    []interface{}{"*.apps.example.com"},
+							"certFile": "/var/run/secrets/named-certs/user-serving-cert-000/tls.crt",
+							"keyFile":  "/var/run/secrets/named-certs/user-serving-cert-000/tls.key",
+						},
+					},
+				},
+			},
+			expectedSources: []string{"openshift-config/apps-cert"},
+		},
+		{
+			name: "multiple named certificates",
+			namedCertificates: []configv1.APIServerNamedServingCert{
+				namedCertificate("apps-cert", "*.apps.example.com", "apps.example.com"),
+				namedCertificate("default-cert"),
+			},
+			expected: map[string]interface{}{
+				"servingInfo": map[string]interface{}{
+					"namedCertificates": []interface{}{
+						map[string]interface{}{
+							"names":    []interface{}{"*.apps.example.com", "apps.example.com"},
+							"certFile": "/var/run/secrets/named-certs/user-serving-cert-000/tls.crt",
+							"keyFile":  "/var/run/secrets/named-certs/user-serving-cert-000/tls.key",
+						},
+						map[string]interface{}{
+							"certFile": "/var/run/secrets/named-certs/user-serving-cert-001/tls.crt",
+							"keyFile":  "/var/run/secrets/named-certs/user-serving-cert-001/tls.key",
+						},
+					},
+				},
+			},
+			expectedSources: []string{"openshift-config/apps-cert", "openshift-config/default-cert"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+			if err := indexer.Add(&configv1.APIServer{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+				Spec: configv1.APIServerSpec{
+					ServingCerts: configv1.APIServerServingCerts{NamedCertificates: tc.namedCertificates},
+				},
+			}); err != nil {
+				t.Fatal(err)
+			}
+			syncer := &fakeSecretSyncer{secrets: map[string]string{}}
+			listers := configobservation.Listers{
+				APIServerLister_: configlistersv1.NewAPIServerLister(indexer),
+				ResourceSync:     syncer,
+			}
+			// a previously observed certificate is removed when it is no longer referenced
+			existingConfig := map[string]interface{}{
+				"servingInfo": map[string]interface{}{
+					"namedCertificates": []interface{}{
+						map[string]interface{}{"certFile": "/stale/tls.crt", "keyFile": "/stale/tls.key"},
+					},
+				},
+			}
+
+			observed, errs := ObserveNamedCertificates(listers, events.NewInMemoryRecorder("", clock.RealClock{}), existingConfig)
+			if len(errs) > 0 {
+				t.Fatalf("unexpected errors: %v", errs)
+			}
+			if !equality.Semantic.DeepEqual(tc.expected, observed) {
+				t.Errorf("unexpected observed config:\n%s", cmp.Diff(tc.expected, observed))
+			}
+
+			expectedSecrets := map[string]string{}
+			for _, namespace := range []string{"openshift-controller-manager", "openshift-route-controller-manager"} {
+				for i := 0; i < maxNamedCertificates; i++ {
+					source := ""
+					if i < len(tc.expectedSources) {
+						source = tc.expectedSources[i]
+					}
+					expectedSecrets[fmt.Sprintf("%s/user-serving-cert-%03d", namespace, i)] = source
+				}
+			}
+			if !equality.Semantic.DeepEqual(expectedSecrets, syncer.secrets) {
+				t.Errorf("unexpected synced secrets:\n%s", cmp.Diff(expectedSecrets, syncer.secrets))
+			}
+		})
+	}
+}
+
+func TestObserveNamedCertificatesSyncError(t *testing.T) {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	if err := indexer.Add(&configv1.APIServer{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+		Spec: configv1.APIServerSpec{
+			ServingCerts: configv1.APIServerServingCerts{
+				NamedCertificates: []configv1.APIServerNamedServingCert{namedCertificate("apps-cert", "*.apps.example.com")},
+			},
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	listers := configobservation.Listers{
+		APIServerLister_: configlistersv1.NewAPIServerLister(indexer),
+		ResourceSync:     &fakeSecretSyncer{err: fmt.Errorf("boom")},
+	}
+	existingConfig := map[string]interface{}{
+		"servingInfo": map[string]interface{}{
+			"minTLSVersion": "VersionTLS12",
+			"namedCertificates": []interface{}{
+				map[string]interface{}{"certFile": "/previous/tls.crt", "keyFile": "/previous/tls.key"},
+			},
+		},
+	}
+
+	observed, errs := ObserveNamedCertificates(listers, events.NewInMemoryRecorder("", clock.RealClock{}), existingConfig)
+	if len(errs) == 0 {
+		t.Fatal("expected the sync error to be returned")
+	}
+	expected := map[string]interface{}{
+		"servingInfo": map[string]interface{}{
+			"namedCertificates": []interface{}{
+				map[string]interface{}{"certFile": "/previous/tls.crt", "keyFile": "/previous/tls.key"},
+			},
+		},
+	}
+	if !equality.Semantic.DeepEqual(expected, observed) {
+		t.Errorf("expected the previous named certificates to be kept:\n%s", cmp.Diff(expected, observed))
+	}
+}
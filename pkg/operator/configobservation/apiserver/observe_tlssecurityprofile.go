@@ -0,0 +1,358 @@
+package apiserver
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/clock"
+
+	configv1 "github.com/openshift/api/config/v1"
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/crypto"
+	"github.com/openshift/library-go/pkg/operator/configobserver"
+	libgoapiserver "github.com/openshift/library-go/pkg/operator/configobserver/apiserver"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-openshift-controller-manager-operator/pkg/operator/configobservation"
+)
+
+const (
+	apiServerConfigDegradedType = "APIServerConfigDegraded"
+	apiServerConfigErrorReason  = "APIServerConfigError"
+
+	// readFailureGracePeriod is how long reading the APIServer config may fail before the operator is
+	// degraded, so that transient API errors do not flap the condition.
+	readFailureGracePeriod = 3 * time.Minute
+
+	tlsSecurityProfileDegradedType = "TLSSecurityProfileDegraded"
+	tlsConfigInvalidReason         = "TLSConfigInvalid"
+
+	// tlsCiphersIgnoredType is an informational condition, it neither degrades the operator nor makes it
+	// progress.
+	tlsCiphersIgnoredType      = "TLSSecurityProfileCiphersIgnored"
+	ciphersNotNegotiableReason = "CiphersNotNegotiable"
+
+	// tlsProfileDebounceWindow is how long a changed TLS config has to be stable before it is observed, so
+	// that rapid successive edits of the profile roll out the operand once.
+	tlsProfileDebounceWindow = 10 * time.Second
+)
+
+var (
+	minTLSVersionPath = []string{"servingInfo", "minTLSVersion"}
+	cipherSuitesPath  = []string{"servingInfo", "cipherSuites"}
+)
+
+type tlsSecurityProfileObserver struct {
+	operatorClient v1helpers.OperatorClient
+	clock          clock.PassiveClock
+	gracePeriod    time.Duration
+	debounceWindow time.Duration
+	// requeueAfter runs the config observer again once the delay passed.
+	requeueAfter func(delay time.Duration)
+	// lastSyncResourceVersion returns the resource version the informer of the APIServer config last
+	// listed or watched, nil if it is unknown.
+	lastSyncResourceVersion func() string
+
+	lock sync.Mutex
+	// readErr is the last error listing or watching the APIServer config, nil while it can be read, and
+	// failingSince is when reading it started to fail. failedResourceVersion is the resource version the
+	// informer had synced when reading last failed.
+	readErr               error
+	failingSince          time.Time
+	failedResourceVersion string
+	// pending is the changed TLS config held back by the debounce window, empty while none is, and
+	// pendingSince is when it was first seen.
+	pending      string
+	pendingSince time.Time
+}
+
+// NewObserveTLSSecurityProfileFunc returns an observer like library-go's ObserveTLSSecurityProfile, which
+// keeps the previously observed TLS config while the APIServer config cannot be read. The lister keeps
+// serving the cached config then, so the failures to list or watch it are taken from apiServerInformer,
+// which must not be started yet. They are only reported after they persisted for a grace period, by
+// setting the APIServerConfigDegraded condition, until the informer receives the config again or, when the
+// config does not exist, syncs another resource version than the one it had when reading failed. A Custom
+// profile listing ciphers unknown to the operator is rejected the same way, but right away: the previously
+// observed TLS config is kept and the TLSSecurityProfileDegraded condition names the unknown ciphers. The
+// ciphers of a Custom profile with the minimum TLS version VersionTLS13 which only TLS 1.2 and earlier
+// negotiate are dropped from the observed cipher suites, the TLSSecurityProfileCiphersIgnored condition
+// names them. A change of the TLS config is only observed once it has been stable for a debounce window,
+// the previous TLS config is kept meanwhile and requeueAfter runs the config observer again once the window
+// passed to observe the change.
+func NewObserveTLSSecurityProfileFunc(operatorClient v1helpers.OperatorClient, apiServerInformer cache.SharedIndexInformer, requeueAfter func(delay time.Duration), clock clock.PassiveClock) configobserver.ObserveConfigFunc {
+	o := newTLSSecurityProfileObserver(operatorClient, requeueAfter, clock)
+	o.lastSyncResourceVersion = apiServerInformer.LastSyncResourceVersion
+	if err := apiServerInformer.SetWatchErrorHandlerWithContext(func(ctx context.Context, r *cache.Reflector, err error) {
+		cache.DefaultWatchErrorHandler(ctx, r, err)
+		o.watchFailed(err)
+	}); err != nil {
+		klog.Errorf("apiservers.config.openshift.io: failures to read the config will not degrade the operator: %v", err)
+	}
+	// the informer delivers the config again once it was listed successfully after a failure
+	if _, err := apiServerInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(interface{}) { o.readSucceeded() },
+		UpdateFunc: func(interface{}, interface{}) { o.readSucceeded() },
+		DeleteFunc: func(interface{}) { o.readSucceeded() },
+	}); err != nil {
+		klog.Errorf("apiservers.config.openshift.io: recoveries from failures to read the config will not be noticed: %v", err)
+	}
+	return o.observe
+}
+
+func newTLSSecurityProfileObserver(operatorClient v1helpers.OperatorClient, requeueAfter func(delay time.Duration), clock clock.PassiveClock) *tlsSecurityProfileObserver {
+	return &tlsSecurityProfileObserver{
+		operatorClient: operatorClient,
+		clock:          clock,
+		gracePeriod:    readFailureGracePeriod,
+		debounceWindow: tlsProfileDebounceWindow,
+		requeueAfter:   requeueAfter,
+	}
+}
+
+func (o *tlsSecurityProfileObserver) observe(genericListers configobserver.Listers, recorder events.Recorder, existingConfig map[string]interface{}) (map[string]interface{}, []error) {
+	listers := genericListers.(configobservation.Listers)
+
+	apiServer, err := listers.APIServerLister().Get("cluster")
+	if err != nil && !errors.IsNotFound(err) {
+		return configobserver.Pruned(existingConfig, minTLSVersionPath, cipherSuitesPath), []error{err}
+	}
+	if failingFor, readErr := o.readFailure(); readErr != nil {
+		prevObservedConfig := configobserver.Pruned(existingConfig, minTLSVersionPath, cipherSuitesPath)
+		if failingFor < o.gracePeriod {
+			klog.Warningf("apiservers.config.openshift.io/cluster: failed to read for %s, will retry: %v", failingFor.Round(time.Second), readErr)
+			return prevObservedConfig, nil
+		}
+		condition := operatorv1.OperatorCondition{
+			Type:    apiServerConfigDegradedType,
+			Status:  operatorv1.ConditionTrue,
+			Reason:  apiServerConfigErrorReason,
+			Message: fmt.Sprintf("failed to read apiservers.config.openshift.io/cluster for %s: %v", failingFor.Round(time.Second), readErr),
+		}
+		if _, _, err := v1helpers.UpdateStatus(context.TODO(), o.operatorClient, v1helpers.UpdateConditionFn(condition)); err != nil {
+			return prevObservedConfig, []error{err}
+		}
+		return prevObservedConfig, nil
+	}
+
+	condition := operatorv1.OperatorCondition{
+		Type:   apiServerConfigDegradedType,
+		Status: operatorv1.ConditionFalse,
+	}
+	profileCondition := operatorv1.OperatorCondition{
+		Type:   tlsSecurityProfileDegradedType,
+		Status: operatorv1.ConditionFalse,
+	}
+	ignoredCondition := operatorv1.OperatorCondition{
+		Type:   tlsCiphersIgnoredType,
+		Status: operatorv1.ConditionFalse,
+	}
+	var unknownCiphers, ignoredCiphers []string
+	if err == nil {
+		unknownCiphers = unknownCustomCiphers(apiServer.Spec.TLSSecurityProfile)
+		ignoredCiphers = tls13IgnoredCustomCiphers(apiServer.Spec.TLSSecurityProfile)
+	}
+	if len(ignoredCiphers) > 0 && len(unknownCiphers) == 0 {
+		ignoredCondition.Status = operatorv1.ConditionTrue
+		ignoredCondition.Reason = ciphersNotNegotiableReason
+		ignoredCondition.Message = fmt.Sprintf("apiservers.config.openshift.io/cluster spec.tlsSecurityProfile.custom.ciphers %s cannot be negotiated with the minimum TLS version %s and are not observed", quoted(ignoredCiphers), configv1.VersionTLS13)
+	}
+	if len(unknownCiphers) > 0 {
+		profileCondition.Status = operatorv1.ConditionTrue
+		profileCondition.Reason = tlsConfigInvalidReason
+		profileCondition.Message = fmt.Sprintf("apiservers.config.openshift.io/cluster spec.tlsSecurityProfile.custom.ciphers lists unknown ciphers %s, keeping the previous TLS config", quoted(unknownCiphers))
+		recorder.Warningf("TLSConfigInvalid", "Rejected the custom TLS security profile, unknown ciphers %s", quoted(unknownCiphers))
+	}
+	if _, _, err := v1helpers.UpdateStatus(context.TODO(), o.operatorClient,
+		v1helpers.UpdateConditionFn(condition), v1helpers.UpdateConditionFn(profileCondition), v1helpers.UpdateConditionFn(ignoredCondition)); err != nil {
+		return configobserver.Pruned(existingConfig, minTLSVersionPath, cipherSuitesPath), []error{err}
+	}
+	if len(unknownCiphers) > 0 {
+		return configobserver.Pruned(existingConfig, minTLSVersionPath, cipherSuitesPath), nil
+	}
+	// library-go records the changes of the TLS config on every observation, they are replayed once the
+	// change is observed
+	changes := events.NewInMemoryRecorder("", o.clock)
+	observedConfig, errs := o.observeTLSConfig(genericListers, changes, existingConfig, ignoredCiphers)
+	if len(errs) == 0 && o.debounced(existingConfig, observedConfig) {
+		return configobserver.Pruned(existingConfig, minTLSVersionPath, cipherSuitesPath), nil
+	}
+	for _, event := range changes.Events() {
+		if event.Type == corev1.EventTypeWarning {
+			recorder.Warning(event.Reason, event.Message)
+		} else {
+			recorder.Event(event.Reason, event.Message)
+		}
+	}
+	return observedConfig, errs
+}
+
+// observeTLSConfig observes the TLS config with library-go, dropping the ignored ciphers from the cipher
+// suites.
+func (o *tlsSecurityProfileObserver) observeTLSConfig(genericListers configobserver.Listers, recorder events.Recorder, existingConfig map[string]interface{}, ignoredCiphers []string) (map[string]interface{}, []error) {
+	observedConfig, errs := libgoapiserver.ObserveTLSSecurityProfile(genericListers, recorder, existingConfig)
+	if len(ignoredCiphers) == 0 || len(errs) > 0 {
+		return observedConfig, errs
+	}
+	cipherSuites, _, err := unstructured.NestedStringSlice(observedConfig, cipherSuitesPath...)
+	if err != nil {
+		return observedConfig, []error{err}
+	}
+	if err := unstructured.SetNestedStringSlice(observedConfig, tls13CipherSuites(cipherSuites), cipherSuitesPath...); err != nil {
+		return observedConfig, []error{err}
+	}
+	return observedConfig, nil
+}
+
+// debounced returns whether the observed TLS config differs from the existing one and has not been stable
+// for the debounce window yet. A change replacing a pending one restarts the window, the config observer is
+// requeued for when it ends. The first TLS config observed is not held back, there is no previous one to
+// keep.
+func (o *tlsSecurityProfileObserver) debounced(existingConfig, observedConfig map[string]interface{}) bool {
+	existing, observed := tlsConfigKey(existingConfig), tlsConfigKey(observedConfig)
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	if existing == observed || len(existing) == 0 {
+		o.pending = ""
+		return false
+	}
+	if observed != o.pending {
+		o.pending = observed
+		o.pendingSince = o.clock.Now()
+		o.requeueAfter(o.debounceWindow)
+	}
+	if stableFor := o.clock.Since(o.pendingSince); stableFor < o.debounceWindow {
+		klog.V(2).Infof("apiservers.config.openshift.io/cluster: TLS config changed %s ago, waiting for it to be stable for %s", stableFor.Round(time.Second), o.debounceWindow)
+		return true
+	}
+	o.pending = ""
+	return false
+}
+
+// tlsConfigKey identifies the TLS config of an observed config, it is empty for a config without one.
+func tlsConfigKey(config map[string]interface{}) string {
+	minTLSVersion, _, _ := unstructured.NestedString(config, minTLSVersionPath...)
+	cipherSuites, _, _ := unstructured.NestedStringSlice(config, cipherSuitesPath...)
+	if len(minTLSVersion) == 0 && len(cipherSuites) == 0 {
+		return ""
+	}
+	return minTLSVersion + "/" + strings.Join(cipherSuites, ",")
+}
+
+// tls13IgnoredCustomCiphers returns the ciphers of a Custom profile with the minimum TLS version
+// VersionTLS13 which cannot be negotiated with TLS 1.3. Those are ignored by the operand, TLS 1.3 has its
+// own cipher suites, but a cipher suite list mixing them would read as if they were in use. Unknown
+// ciphers are left to unknownCustomCiphers.
+func tls13IgnoredCustomCiphers(profile *configv1.TLSSecurityProfile) []string {
+	if profile == nil || profile.Type != configv1.TLSProfileCustomType || profile.Custom == nil ||
+		profile.Custom.MinTLSVersion != configv1.VersionTLS13 {
+		return nil
+	}
+	var ignored []string
+	for _, cipher := range profile.Custom.Ciphers {
+		cipherSuites := crypto.OpenSSLToIANACipherSuites([]string{cipher})
+		if len(cipherSuites) > 0 && len(tls13CipherSuites(cipherSuites)) == 0 {
+			ignored = append(ignored, cipher)
+		}
+	}
+	return ignored
+}
+
+// tls13CipherSuites returns the cipher suites, by their IANA name, which can be negotiated with TLS 1.3.
+func tls13CipherSuites(cipherSuites []string) []string {
+	tls13 := map[string]bool{}
+	for _, suite := range append(tls.CipherSuites(), tls.InsecureCipherSuites()...) {
+		tls13[suite.Name] = slices.Contains(suite.SupportedVersions, tls.VersionTLS13)
+	}
+	filtered := []string{}
+	for _, cipherSuite := range cipherSuites {
+		if tls13[cipherSuite] {
+			filtered = append(filtered, cipherSuite)
+		}
+	}
+	return filtered
+}
+
+// unknownCustomCiphers returns the ciphers of a Custom profile which have no IANA name, library-go drops
+// them from the observed cipher suites silently.
+func unknownCustomCiphers(profile *configv1.TLSSecurityProfile) []string {
+	if profile == nil || profile.Type != configv1.TLSProfileCustomType || profile.Custom == nil {
+		return nil
+	}
+	var unknown []string
+	for _, cipher := range profile.Custom.Ciphers {
+		if len(crypto.OpenSSLToIANACipherSuites([]string{cipher})) == 0 {
+			unknown = append(unknown, cipher)
+		}
+	}
+	return unknown
+}
+
+func quoted(values []string) string {
+	quoted := make([]string, 0, len(values))
+	for _, value := range values {
+		quoted = append(quoted, strconv.Quote(value))
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// watchFailed records a failure to list or watch the APIServer config. A watch closed normally or on an
+// expired resource version is not a failure, the informer lists the config again right away.
+func (o *tlsSecurityProfileObserver) watchFailed(err error) {
+	if err == io.EOF || err == io.ErrUnexpectedEOF || errors.IsResourceExpired(err) || errors.IsGone(err) {
+		return
+	}
+	resourceVersion := o.resourceVersion()
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	if o.readErr == nil {
+		o.failingSince = o.clock.Now()
+	}
+	o.readErr = err
+	o.failedResourceVersion = resourceVersion
+}
+
+// readFailure returns for how long reading the APIServer config fails and the last failure, a nil error
+// while it can be read. The informer delivers no event when it lists again after a failure and finds no
+// config, a failure is over as well once it synced another resource version since.
+func (o *tlsSecurityProfileObserver) readFailure() (time.Duration, error) {
+	resourceVersion := o.resourceVersion()
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	if o.readErr == nil {
+		return 0, nil
+	}
+	if o.lastSyncResourceVersion != nil && resourceVersion != o.failedResourceVersion {
+		klog.V(2).Infof("apiservers.config.openshift.io: synced resource version %q since failing to read at %q", resourceVersion, o.failedResourceVersion)
+		o.readErr = nil
+		o.failingSince = time.Time{}
+		return 0, nil
+	}
+	return o.clock.Since(o.failingSince), o.readErr
+}
+
+func (o *tlsSecurityProfileObserver) resourceVersion() string {
+	if o.lastSyncResourceVersion == nil {
+		return ""
+	}
+	return o.lastSyncResourceVersion()
+}
+
+func (o *tlsSecurityProfileObserver) readSucceeded() {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	o.readErr = nil
+	o.failingSince = time.Time{}
+}
@@ -0,0 +1,576 @@
+package apiserver
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/utils/clock"
+
+	configv1 "github.com/openshift/api/config/v1"
+	operatorv1 "github.com/openshift/api/operator/v1"
+	configlistersv1 "github.com/openshift/client-go/config/listers/config/v1"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-openshift-controller-manager-operator/pkg/operator/configobservation"
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time                  { return c.now }
+func (c *fakeClock) Since(t time.Time) time.Duration { return c.now.Sub(t) }
+
+var _ clock.PassiveClock = &fakeClock{}
+
+// fakeAPIServerInformer keeps the handlers the observer registers, so that tests can fail and recover
+// listing and watching the APIServer config.
+type fakeAPIServerInformer struct {
+	cache.SharedIndexInformer
+	watchErrorHandler cache.WatchErrorHandlerWithContext
+	handlers          []cache.ResourceEventHandler
+	resourceVersion   string
+}
+
+func (i *fakeAPIServerInformer) SetWatchErrorHandlerWithContext(handler cache.WatchErrorHandlerWithContext) error {
+	i.watchErrorHandler = handler
+	return nil
+}
+
+func (i *fakeAPIServerInformer) AddEventHandler(handler cache.ResourceEventHandler) (cache.ResourceEventHandlerRegistration, error) {
+	i.handlers = append(i.handlers, handler)
+	return nil, nil
+}
+
+func (i *fakeAPIServerInformer) LastSyncResourceVersion() string {
+	return i.resourceVersion
+}
+
+// fail fails listing or watching the APIServer config like the reflector of an informer does.
+func (i *fakeAPIServerInformer) fail(err error) {
+	i.watchErrorHandler(context.TODO(), cache.NewReflector(&cache.ListWatch{}, &configv1.APIServer{}, nil, 0), err)
+}
+
+// relist delivers the APIServer config like an informer listing it again.
+func (i *fakeAPIServerInformer) relist(apiServer *configv1.APIServer) {
+	for _, handler := range i.handlers {
+		handler.OnUpdate(apiServer, apiServer)
+	}
+}
+
+// relistWithoutConfig syncs a new resource version like an informer listing again and finding no config,
+// which delivers no event.
+func (i *fakeAPIServerInformer) relistWithoutConfig(resourceVersion string) {
+	i.resourceVersion = resourceVersion
+}
+
+func TestObserveTLSSecurityProfileReadFailures(t *testing.T) {
+	apiServer := &configv1.APIServer{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+		Spec: configv1.APIServerSpec{
+			TLSSecurityProfile: &configv1.TLSSecurityProfile{
+				Type:   configv1.TLSProfileModernType,
+				Modern: &configv1.ModernTLSProfile{},
+			},
+		},
+	}
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	if err := indexer.Add(apiServer); err != nil {
+		t.Fatal(err)
+	}
+	// the lister keeps serving the cached config while the informer fails
+	listers := configobservation.Listers{APIServerLister_: configlistersv1.NewAPIServerLister(indexer)}
+	operatorClient := v1helpers.NewFakeOperatorClient(&operatorv1.OperatorSpec{ManagementState: operatorv1.Managed}, &operatorv1.OperatorStatus{}, nil)
+	clock := &fakeClock{now: time.Now()}
+	informer := &fakeAPIServerInformer{}
+	observe := NewObserveTLSSecurityProfileFunc(operatorClient, informer, noRequeue, clock)
+	recorder := events.NewInMemoryRecorder("", clock)
+
+	existingConfig := map[string]interface{}{
+		"servingInfo": map[string]interface{}{
+			"minTLSVersion": "VersionTLS12",
+			"cipherSuites":  []interface{}{"TLS_AES_128_GCM_SHA256"},
+		},
+	}
+	degraded := func() *operatorv1.OperatorCondition {
+		_, status, _, err := operatorClient.GetOperatorState()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return v1helpers.FindOperatorCondition(status.Conditions, apiServerConfigDegradedType)
+	}
+
+	informer.fail(errors.NewForbidden(configv1.Resource("apiservers"), "", fmt.Errorf("RBAC: access denied")))
+	for _, elapsed := range []time.Duration{0, time.Minute, time.Minute} {
+		clock.now = clock.now.Add(elapsed)
+		observed, errs := observe(listers, recorder, existingConfig)
+		if len(errs) > 0 {
+			t.Fatalf("expected transient failures to not be reported, got %v", errs)
+		}
+		if !equality.Semantic.DeepEqual(existingConfig, observed) {
+			t.Errorf("expected the previous config %v to be kept, got %v", existingConfig, observed)
+		}
+		if condition := degraded(); condition != nil && condition.Status == operatorv1.ConditionTrue {
+			t.Fatalf("expected not to be degraded after failing for less than the grace period, got %v", condition)
+		}
+	}
+
+	clock.now = clock.now.Add(readFailureGracePeriod - 2*time.Minute)
+	observed, errs := observe(listers, recorder, existingConfig)
+	if len(errs) > 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	if !equality.Semantic.DeepEqual(existingConfig, observed) {
+		t.Errorf("expected the previous config %v to be kept, got %v", existingConfig, observed)
+	}
+	condition := degraded()
+	if condition == nil || condition.Status != operatorv1.ConditionTrue || condition.Reason != apiServerConfigErrorReason {
+		t.Fatalf("expected %s=True with reason %s after sustained failures, got %v", apiServerConfigDegradedType, apiServerConfigErrorReason, condition)
+	}
+	if !strings.Contains(condition.Message, "forbidden") {
+		t.Errorf("expected the condition message to contain the read error, got %q", condition.Message)
+	}
+
+	informer.relist(apiServer)
+	observed, errs = observe(listers, recorder, existingConfig)
+	if len(errs) > 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	if condition := degraded(); condition == nil || condition.Status != operatorv1.ConditionFalse {
+		t.Errorf("expected %s=False once the config can be read, got %v", apiServerConfigDegradedType, condition)
+	}
+	// the changed TLS config is observed once it has been stable for the debounce window
+	clock.now = clock.now.Add(tlsProfileDebounceWindow)
+	if observed, errs = observe(listers, recorder, existingConfig); len(errs) > 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	if minTLSVersion := observed["servingInfo"].(map[string]interface{})["minTLSVersion"]; minTLSVersion != "VersionTLS13" {
+		t.Errorf("expected the Modern profile to be observed, got minTLSVersion %v", minTLSVersion)
+	}
+
+	// a watch closed normally is not a failure
+	informer.fail(io.EOF)
+	if _, errs := observe(listers, recorder, existingConfig); len(errs) > 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	if condition := degraded(); condition.Status != operatorv1.ConditionFalse {
+		t.Errorf("expected a watch closed normally to not degrade, got %v", condition)
+	}
+
+	// a new failure starts a new grace period
+	informer.fail(fmt.Errorf("connection refused"))
+	if _, errs := observe(listers, recorder, existingConfig); len(errs) > 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	if condition := degraded(); condition.Status != operatorv1.ConditionFalse {
+		t.Errorf("expected a new failure to not degrade immediately, got %v", condition)
+	}
+}
+
+// TestObserveTLSSecurityProfileReadFailuresWithoutConfig checks a failure to read a missing APIServer
+// config is over once the informer listed again, there is no event for a config which does not exist.
+func TestObserveTLSSecurityProfileReadFailuresWithoutConfig(t *testing.T) {
+	listers := configobservation.Listers{APIServerLister_: configlistersv1.NewAPIServerLister(cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{}))}
+	operatorClient := v1helpers.NewFakeOperatorClient(&operatorv1.OperatorSpec{ManagementState: operatorv1.Managed}, &operatorv1.OperatorStatus{}, nil)
+	clock := &fakeClock{now: time.Now()}
+	informer := &fakeAPIServerInformer{resourceVersion: "1"}
+	observe := NewObserveTLSSecurityProfileFunc(operatorClient, informer, noRequeue, clock)
+	recorder := events.NewInMemoryRecorder("", clock)
+	degradedStatus := func() operatorv1.ConditionStatus {
+		t.Helper()
+		if _, errs := observe(listers, recorder, map[string]interface{}{}); len(errs) > 0 {
+			t.Fatalf("unexpected errors: %v", errs)
+		}
+		_, status, _, err := operatorClient.GetOperatorState()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if condition := v1helpers.FindOperatorCondition(status.Conditions, apiServerConfigDegradedType); condition != nil {
+			return condition.Status
+		}
+		return ""
+	}
+
+	informer.fail(fmt.Errorf("connection refused"))
+	clock.now = clock.now.Add(readFailureGracePeriod)
+	if status := degradedStatus(); status != operatorv1.ConditionTrue {
+		t.Fatalf("expected %s=True after sustained failures, got %q", apiServerConfigDegradedType, status)
+	}
+	// the informer keeps failing without syncing anything
+	clock.now = clock.now.Add(time.Minute)
+	if status := degradedStatus(); status != operatorv1.ConditionTrue {
+		t.Fatalf("expected %s=True while the informer has not listed again, got %q", apiServerConfigDegradedType, status)
+	}
+
+	informer.relistWithoutConfig("2")
+	if status := degradedStatus(); status != operatorv1.ConditionFalse {
+		t.Errorf("expected %s=False once the informer listed again, got %q", apiServerConfigDegradedType, status)
+	}
+	// a later failure starts a new grace period
+	informer.fail(fmt.Errorf("connection refused"))
+	if status := degradedStatus(); status != operatorv1.ConditionFalse {
+		t.Errorf("expected a new failure to not degrade immediately, got %q", status)
+	}
+}
+
+// TestObserveTLSSecurityProfileInformerReadFailures checks the failures of a real informer to list the
+// APIServer config reach the observer, which only reads the cache the informer keeps, and that the observer
+// recovers from them whether the config exists or not.
+func TestObserveTLSSecurityProfileInformerReadFailures(t *testing.T) {
+	tests := []struct {
+		name  string
+		items []configv1.APIServer
+	}{
+		{name: "config exists", items: []configv1.APIServer{{ObjectMeta: metav1.ObjectMeta{Name: "cluster", ResourceVersion: "1"}}}},
+		{name: "config missing"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var forbidden atomic.Bool
+			forbidden.Store(true)
+			informer := cache.NewSharedIndexInformer(&cache.ListWatch{
+				ListFunc: func(metav1.ListOptions) (runtime.Object, error) {
+					if forbidden.Load() {
+						return nil, errors.NewForbidden(configv1.Resource("apiservers"), "", fmt.Errorf("RBAC: access denied"))
+					}
+					return &configv1.APIServerList{ListMeta: metav1.ListMeta{ResourceVersion: "1"}, Items: tc.items}, nil
+				},
+				WatchFunc: func(metav1.ListOptions) (watch.Interface, error) {
+					return watch.NewFake(), nil
+				},
+			}, &configv1.APIServer{}, 0, cache.Indexers{})
+			listers := configobservation.Listers{APIServerLister_: configlistersv1.NewAPIServerLister(informer.GetIndexer())}
+			operatorClient := v1helpers.NewFakeOperatorClient(&operatorv1.OperatorSpec{ManagementState: operatorv1.Managed}, &operatorv1.OperatorStatus{}, nil)
+			clock := &fakeClock{now: time.Now()}
+			observe := NewObserveTLSSecurityProfileFunc(operatorClient, informer, noRequeue, clock)
+			recorder := events.NewInMemoryRecorder("", clock)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			go informer.RunWithContext(ctx)
+
+			degradedStatus := func() (operatorv1.ConditionStatus, error) {
+				// every observation is past the grace period of a failure recorded before it
+				clock.now = clock.now.Add(readFailureGracePeriod)
+				if _, errs := observe(listers, recorder, map[string]interface{}{}); len(errs) > 0 {
+					return "", fmt.Errorf("unexpected errors: %v", errs)
+				}
+				_, status, _, err := operatorClient.GetOperatorState()
+				if err != nil {
+					return "", err
+				}
+				if condition := v1helpers.FindOperatorCondition(status.Conditions, apiServerConfigDegradedType); condition != nil {
+					return condition.Status, nil
+				}
+				return "", nil
+			}
+			waitForDegraded := func(expected operatorv1.ConditionStatus) {
+				t.Helper()
+				err := wait.PollUntilContextTimeout(ctx, 10*time.Millisecond, 30*time.Second, true, func(ctx context.Context) (bool, error) {
+					status, err := degradedStatus()
+					return status == expected, err
+				})
+				if err != nil {
+					t.Fatalf("expected %s=%s: %v", apiServerConfigDegradedType, expected, err)
+				}
+			}
+
+			waitForDegraded(operatorv1.ConditionTrue)
+			forbidden.Store(false)
+			waitForDegraded(operatorv1.ConditionFalse)
+		})
+	}
+}
+
+func TestObserveTLSSecurityProfileUnknownCustomCiphers(t *testing.T) {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	setProfile := func(ciphers ...string) {
+		t.Helper()
+		if err := indexer.Update(&configv1.APIServer{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+			Spec: configv1.APIServerSpec{
+				TLSSecurityProfile: &configv1.TLSSecurityProfile{
+					Type: configv1.TLSProfileCustomType,
+					Custom: &configv1.CustomTLSProfile{TLSProfileSpec: configv1.TLSProfileSpec{
+						MinTLSVersion: configv1.VersionTLS12,
+						Ciphers:       ciphers,
+					}},
+				},
+			},
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	listers := configobservation.Listers{APIServerLister_: configlistersv1.NewAPIServerLister(indexer)}
+	operatorClient := v1helpers.NewFakeOperatorClient(&operatorv1.OperatorSpec{ManagementState: operatorv1.Managed}, &operatorv1.OperatorStatus{}, nil)
+	clock := &fakeClock{now: time.Now()}
+	observe := NewObserveTLSSecurityProfileFunc(operatorClient, &fakeAPIServerInformer{}, noRequeue, clock)
+	recorder := events.NewInMemoryRecorder("", clock)
+	degraded := func() *operatorv1.OperatorCondition {
+		_, status, _, err := operatorClient.GetOperatorState()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return v1helpers.FindOperatorCondition(status.Conditions, tlsSecurityProfileDegradedType)
+	}
+
+	existingConfig := map[string]interface{}{
+		"servingInfo": map[string]interface{}{
+			"minTLSVersion": "VersionTLS13",
+			"cipherSuites":  []interface{}{"TLS_AES_128_GCM_SHA256"},
+		},
+	}
+	setProfile("ECDHE-RSA-AES128-GCM-SHA256", "BOGUS-CIPHER")
+	observed, errs := observe(listers, recorder, existingConfig)
+	if len(errs) > 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	if !equality.Semantic.DeepEqual(existingConfig, observed) {
+		t.Errorf("expected the previous config %v to be kept, got %v", existingConfig, observed)
+	}
+	condition := degraded()
+	if condition == nil || condition.Status != operatorv1.ConditionTrue || condition.Reason != tlsConfigInvalidReason {
+		t.Fatalf("expected %s=True with reason %s, got %v", tlsSecurityProfileDegradedType, tlsConfigInvalidReason, condition)
+	}
+	if !strings.Contains(condition.Message, `"BOGUS-CIPHER"`) || strings.Contains(condition.Message, "ECDHE-RSA-AES128-GCM-SHA256") {
+		t.Errorf("expected the condition message to name only the unknown cipher, got %q", condition.Message)
+	}
+
+	setProfile("ECDHE-RSA-AES128-GCM-SHA256")
+	observed, errs = observe(listers, recorder, existingConfig)
+	if len(errs) > 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	if condition := degraded(); condition == nil || condition.Status != operatorv1.ConditionFalse {
+		t.Errorf("expected %s=False once the ciphers are known, got %v", tlsSecurityProfileDegradedType, condition)
+	}
+	// the changed TLS config is observed once it has been stable for the debounce window
+	clock.now = clock.now.Add(tlsProfileDebounceWindow)
+	if observed, errs = observe(listers, recorder, existingConfig); len(errs) > 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+	expectedConfig := map[string]interface{}{
+		"servingInfo": map[string]interface{}{
+			"minTLSVersion": "VersionTLS12",
+			"cipherSuites":  []interface{}{"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256"},
+		},
+	}
+	if !equality.Semantic.DeepEqual(expectedConfig, observed) {
+		t.Errorf("expected the custom profile %v to be observed, got %v", expectedConfig, observed)
+	}
+}
+
+func TestObserveTLSSecurityProfileCustomMinTLSVersion(t *testing.T) {
+	tests := []struct {
+		name                 string
+		minTLSVersion        configv1.TLSProtocolVersion
+		ciphers              []string
+		expectedCipherSuites []interface{}
+		// expectedIgnored are the ciphers the TLSSecurityProfileCiphersIgnored condition names, none if
+		// the condition is expected to be False
+		expectedIgnored []string
+	}{
+		{
+			name:                 "VersionTLS10",
+			minTLSVersion:        configv1.VersionTLS10,
+			ciphers:              []string{"ECDHE-RSA-AES128-SHA", "ECDHE-RSA-AES128-GCM-SHA256"},
+			expectedCipherSuites: []interface{}{"TLS_ECDHE_RSA_WITH_AES_128_CBC_SHA", "TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256"},
+		},
+		{
+			name:                 "VersionTLS11",
+			minTLSVersion:        configv1.VersionTLS11,
+			ciphers:              []string{"ECDHE-RSA-AES128-SHA", "ECDHE-RSA-AES128-GCM-SHA256"},
+			expectedCipherSuites: []interface{}{"TLS_ECDHE_RSA_WITH_AES_128_CBC_SHA", "TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256"},
+		},
+		{
+			name:                 "VersionTLS12",
+			minTLSVersion:        configv1.VersionTLS12,
+			ciphers:              []string{"TLS_AES_128_GCM_SHA256", "ECDHE-RSA-AES128-GCM-SHA256"},
+			expectedCipherSuites: []interface{}{"TLS_AES_128_GCM_SHA256", "TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256"},
+		},
+		{
+			name:                 "VersionTLS13",
+			minTLSVersion:        configv1.VersionTLS13,
+			ciphers:              []string{"TLS_AES_128_GCM_SHA256", "TLS_CHACHA20_POLY1305_SHA256"},
+			expectedCipherSuites: []interface{}{"TLS_AES_128_GCM_SHA256", "TLS_CHACHA20_POLY1305_SHA256"},
+		},
+		{
+			name:                 "VersionTLS13 with TLS 1.2 ciphers",
+			minTLSVersion:        configv1.VersionTLS13,
+			ciphers:              []string{"TLS_AES_128_GCM_SHA256", "ECDHE-RSA-AES128-GCM-SHA256", "ECDHE-ECDSA-AES256-GCM-SHA384"},
+			expectedCipherSuites: []interface{}{"TLS_AES_128_GCM_SHA256"},
+			expectedIgnored:      []string{"ECDHE-RSA-AES128-GCM-SHA256", "ECDHE-ECDSA-AES256-GCM-SHA384"},
+		},
+		{
+			name:                 "VersionTLS13 with only TLS 1.2 ciphers",
+			minTLSVersion:        configv1.VersionTLS13,
+			ciphers:              []string{"ECDHE-RSA-AES128-GCM-SHA256"},
+			expectedCipherSuites: []interface{}{},
+			expectedIgnored:      []string{"ECDHE-RSA-AES128-GCM-SHA256"},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+			if err := indexer.Add(&configv1.APIServer{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+				Spec: configv1.APIServerSpec{
+					TLSSecurityProfile: &configv1.TLSSecurityProfile{
+						Type: configv1.TLSProfileCustomType,
+						Custom: &configv1.CustomTLSProfile{TLSProfileSpec: configv1.TLSProfileSpec{
+							MinTLSVersion: tc.minTLSVersion,
+							Ciphers:       tc.ciphers,
+						}},
+					},
+				},
+			}); err != nil {
+				t.Fatal(err)
+			}
+			listers := configobservation.Listers{APIServerLister_: configlistersv1.NewAPIServerLister(indexer)}
+			operatorClient := v1helpers.NewFakeOperatorClient(&operatorv1.OperatorSpec{ManagementState: operatorv1.Managed}, &operatorv1.OperatorStatus{}, nil)
+			clock := &fakeClock{now: time.Now()}
+			observe := NewObserveTLSSecurityProfileFunc(operatorClient, &fakeAPIServerInformer{}, noRequeue, clock)
+
+			observed, errs := observe(listers, events.NewInMemoryRecorder("", clock), map[string]interface{}{})
+			if len(errs) > 0 {
+				t.Fatalf("unexpected errors: %v", errs)
+			}
+			expectedConfig := map[string]interface{}{
+				"servingInfo": map[string]interface{}{
+					"minTLSVersion": string(tc.minTLSVersion),
+					"cipherSuites":  tc.expectedCipherSuites,
+				},
+			}
+			if !equality.Semantic.DeepEqual(expectedConfig, observed) {
+				t.Errorf("expected %v to be observed, got %v", expectedConfig, observed)
+			}
+
+			_, status, _, err := operatorClient.GetOperatorState()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if condition := v1helpers.FindOperatorCondition(status.Conditions, tlsSecurityProfileDegradedType); condition == nil || condition.Status != operatorv1.ConditionFalse {
+				t.Errorf("expected %s=False, got %v", tlsSecurityProfileDegradedType, condition)
+			}
+			condition := v1helpers.FindOperatorCondition(status.Conditions, tlsCiphersIgnoredType)
+			if condition == nil {
+				t.Fatalf("expected a %s condition", tlsCiphersIgnoredType)
+			}
+			if len(tc.expectedIgnored) == 0 {
+				if condition.Status != operatorv1.ConditionFalse {
+					t.Errorf("expected %s=False, got %v", tlsCiphersIgnoredType, condition)
+				}
+				return
+			}
+			if condition.Status != operatorv1.ConditionTrue || condition.Reason != ciphersNotNegotiableReason {
+				t.Fatalf("expected %s=True with reason %s, got %v", tlsCiphersIgnoredType, ciphersNotNegotiableReason, condition)
+			}
+			if !strings.Contains(condition.Message, quoted(tc.expectedIgnored)) {
+				t.Errorf("expected the condition message to name %s, got %q", quoted(tc.expectedIgnored), condition.Message)
+			}
+		})
+	}
+}
+
+func TestObserveTLSSecurityProfileDebounce(t *testing.T) {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	setProfile := func(profile *configv1.TLSSecurityProfile) {
+		t.Helper()
+		if err := indexer.Update(&configv1.APIServer{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+			Spec:       configv1.APIServerSpec{TLSSecurityProfile: profile},
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	listers := configobservation.Listers{APIServerLister_: configlistersv1.NewAPIServerLister(indexer)}
+	operatorClient := v1helpers.NewFakeOperatorClient(&operatorv1.OperatorSpec{ManagementState: operatorv1.Managed}, &operatorv1.OperatorStatus{}, nil)
+	clock := &fakeClock{now: time.Now()}
+	var requeues []time.Duration
+	observe := NewObserveTLSSecurityProfileFunc(operatorClient, &fakeAPIServerInformer{}, func(delay time.Duration) { requeues = append(requeues, delay) }, clock)
+	recorder := events.NewInMemoryRecorder("", clock)
+
+	// writes counts the syncs changing the observed config, each of which rolls out the operand
+	writes := 0
+	existingConfig := map[string]interface{}{}
+	sync := func() {
+		t.Helper()
+		observed, errs := observe(listers, recorder, existingConfig)
+		if len(errs) > 0 {
+			t.Fatalf("unexpected errors: %v", errs)
+		}
+		if !equality.Semantic.DeepEqual(existingConfig, observed) {
+			writes++
+			existingConfig = observed
+		}
+	}
+
+	setProfile(&configv1.TLSSecurityProfile{Type: configv1.TLSProfileIntermediateType, Intermediate: &configv1.IntermediateTLSProfile{}})
+	sync()
+	if writes != 1 {
+		t.Fatalf("expected the initial TLS config to be observed right away, got %d writes", writes)
+	}
+	if len(requeues) != 0 {
+		t.Fatalf("expected no requeue for the initial TLS config, got %v", requeues)
+	}
+	initialConfig := existingConfig
+	writes = 0
+
+	profiles := []*configv1.TLSSecurityProfile{
+		{Type: configv1.TLSProfileModernType, Modern: &configv1.ModernTLSProfile{}},
+		{Type: configv1.TLSProfileCustomType, Custom: &configv1.CustomTLSProfile{TLSProfileSpec: configv1.TLSProfileSpec{
+			MinTLSVersion: configv1.VersionTLS12,
+			Ciphers:       []string{"ECDHE-RSA-AES128-GCM-SHA256"},
+		}}},
+		{Type: configv1.TLSProfileOldType, Old: &configv1.OldTLSProfile{}},
+	}
+	for _, profile := range profiles {
+		clock.now = clock.now.Add(tlsProfileDebounceWindow / 4)
+		setProfile(profile)
+		sync()
+		if !equality.Semantic.DeepEqual(initialConfig, existingConfig) {
+			t.Fatalf("expected the previous TLS config %v to be kept within the debounce window, got %v", initialConfig, existingConfig)
+		}
+	}
+	// every edit restarts the window and requeues the config observer for when it ends
+	expectedRequeues := []time.Duration{tlsProfileDebounceWindow, tlsProfileDebounceWindow, tlsProfileDebounceWindow}
+	if !equality.Semantic.DeepEqual(expectedRequeues, requeues) {
+		t.Fatalf("expected the config observer to be requeued after %v, got %v", expectedRequeues, requeues)
+	}
+
+	// the last requeue syncs once the window passed, without any further event
+	clock.now = clock.now.Add(tlsProfileDebounceWindow)
+	sync()
+	sync()
+	if writes != 1 {
+		t.Errorf("expected the edits within the debounce window to be written once, got %d writes", writes)
+	}
+	if minTLSVersion := existingConfig["servingInfo"].(map[string]interface{})["minTLSVersion"]; minTLSVersion != "VersionTLS10" {
+		t.Errorf("expected the last edit, the Old profile, to be observed, got minTLSVersion %v", minTLSVersion)
+	}
+	var changes []string
+	for _, event := range recorder.Events() {
+		if strings.HasPrefix(event.Message, "minTLSVersion changed") {
+			changes = append(changes, event.Message)
+		}
+	}
+	expected := []string{"minTLSVersion changed to VersionTLS12", "minTLSVersion changed to VersionTLS10"}
+	if !equality.Semantic.DeepEqual(expected, changes) {
+		t.Errorf("expected the TLS config change events of the initial and the last edit only %q, got %q", expected, changes)
+	}
+}
+
+func noRequeue(time.Duration) {}
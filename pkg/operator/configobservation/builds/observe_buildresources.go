@@ -0,0 +1,93 @@
+package builds
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/klog/v2"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/operator/configobserver"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-openshift-controller-manager-operator/pkg/operator/configobservation"
+)
+
+const (
+	buildDefaultResourcesDegradedType = "BuildDefaultResourcesDegraded"
+	requestsExceedLimitsReason        = "RequestsExceedLimits"
+)
+
+var buildDefaultResourcesPath = []string{"build", "buildDefaults", "resources"}
+
+// NewObserveBuildDefaultResourcesFunc returns an observer copying the default resource requests and limits
+// of build pods from the cluster-wide build configuration into the observed config. Either may be set
+// without the other. Resources whose request exceeds their limit are not propagated, a build pod with them
+// could not be created: the previously observed resources are kept and the BuildDefaultResourcesDegraded
+// condition names the inconsistent resources.
+func NewObserveBuildDefaultResourcesFunc(operatorClient v1helpers.OperatorClient) configobserver.ObserveConfigFunc {
+	return func(genericListers configobserver.Listers, recorder events.Recorder, existingConfig map[string]interface{}) (map[string]interface{}, []error) {
+		listers := genericListers.(configobservation.Listers)
+		prevObservedConfig := configobserver.Pruned(existingConfig, buildDefaultResourcesPath)
+
+		var resources *corev1.ResourceRequirements
+		buildConfig, err := listers.BuildConfigLister.Get("cluster")
+		if err != nil && !errors.IsNotFound(err) {
+			return prevObservedConfig, []error{err}
+		}
+		if errors.IsNotFound(err) {
+			klog.V(2).Infof("builds.config.openshift.io/cluster: not found")
+		} else {
+			resources = &buildConfig.Spec.BuildDefaults.Resources
+		}
+
+		condition := operatorv1.OperatorCondition{
+			Type:   buildDefaultResourcesDegradedType,
+			Status: operatorv1.ConditionFalse,
+		}
+		var inconsistent []string
+		if resources != nil {
+			inconsistent = requestsExceedingLimits(*resources)
+		}
+		if len(inconsistent) > 0 {
+			condition.Status = operatorv1.ConditionTrue
+			condition.Reason = requestsExceedLimitsReason
+			condition.Message = fmt.Sprintf("builds.config.openshift.io/cluster spec.buildDefaults.resources has requests exceeding their limits: %s", strings.Join(inconsistent, ", "))
+			recorder.Warningf("BuildDefaultResourcesRejected", "%s", condition.Message)
+		}
+		if _, _, err := v1helpers.UpdateStatus(context.TODO(), operatorClient, v1helpers.UpdateConditionFn(condition)); err != nil {
+			return prevObservedConfig, []error{err}
+		}
+		if len(inconsistent) > 0 {
+			return prevObservedConfig, nil
+		}
+
+		observedConfig := map[string]interface{}{}
+		if resources == nil {
+			return observedConfig, nil
+		}
+		if err := configobservation.ObserveField(observedConfig, *resources, strings.Join(buildDefaultResourcesPath, "."), true); err != nil {
+			return prevObservedConfig, []error{fmt.Errorf("failed to observe %s: %v", strings.Join(buildDefaultResourcesPath, "."), err)}
+		}
+		return observedConfig, nil
+	}
+}
+
+// requestsExceedingLimits describes the resources whose request exceeds their limit, sorted by resource
+// name. A resource with only a request or only a limit is consistent.
+func requestsExceedingLimits(resources corev1.ResourceRequirements) []string {
+	var inconsistent []string
+	for name, request := range resources.Requests {
+		limit, ok := resources.Limits[name]
+		if ok && request.Cmp(limit) > 0 {
+			inconsistent = append(inconsistent, fmt.Sprintf("%s request %s exceeds the limit %s", name, request.String(), limit.String()))
+		}
+	}
+	sort.Strings(inconsistent)
+	return inconsistent
+}
@@ -0,0 +1,139 @@
+package builds
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/utils/clock"
+
+	configv1 "github.com/openshift/api/config/v1"
+	operatorv1 "github.com/openshift/api/operator/v1"
+	configlistersv1 "github.com/openshift/client-go/config/listers/config/v1"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-openshift-controller-manager-operator/pkg/operator/configobservation"
+)
+
+func TestObserveBuildDefaultResources(t *testing.T) {
+	previouslyObserved := map[string]interface{}{
+		"build": map[string]interface{}{"buildDefaults": map[string]interface{}{"resources": map[string]interface{}{
+			"limits": map[string]interface{}{"memory": "1Gi"},
+		}}},
+	}
+	resourcesConfig := func(resources map[string]interface{}) map[string]interface{} {
+		return map[string]interface{}{"build": map[string]interface{}{"buildDefaults": map[string]interface{}{"resources": resources}}}
+	}
+
+	tests := []struct {
+		name             string
+		resources        *corev1.ResourceRequirements
+		expected         map[string]interface{}
+		expectedDegraded operatorv1.ConditionStatus
+		expectedMessage  string
+	}{
+		{
+			name:             "no build config",
+			expected:         map[string]interface{}{},
+			expectedDegraded: operatorv1.ConditionFalse,
+		},
+		{
+			name: "requests and limits",
+			resources: &corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("500m"), corev1.ResourceMemory: resource.MustParse("1Gi")},
+				Limits:   corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1"), corev1.ResourceMemory: resource.MustParse("1Gi")},
+			},
+			expected: resourcesConfig(map[string]interface{}{
+				"requests": map[string]interface{}{"cpu": "500m", "memory": "1Gi"},
+				"limits":   map[string]interface{}{"cpu": "1", "memory": "1Gi"},
+			}),
+			expectedDegraded: operatorv1.ConditionFalse,
+		},
+		{
+			name: "requests only",
+			resources: &corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("2Gi")},
+			},
+			expected:         resourcesConfig(map[string]interface{}{"requests": map[string]interface{}{"memory": "2Gi"}}),
+			expectedDegraded: operatorv1.ConditionFalse,
+		},
+		{
+			name: "limits only",
+			resources: &corev1.ResourceRequirements{
+				Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("2Gi")},
+			},
+			expected:         resourcesConfig(map[string]interface{}{"limits": map[string]interface{}{"memory": "2Gi"}}),
+			expectedDegraded: operatorv1.ConditionFalse,
+		},
+		{
+			name: "request of another resource than the limit",
+			resources: &corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("2")},
+				Limits:   corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")},
+			},
+			expected: resourcesConfig(map[string]interface{}{
+				"requests": map[string]interface{}{"cpu": "2"},
+				"limits":   map[string]interface{}{"memory": "1Gi"},
+			}),
+			expectedDegraded: operatorv1.ConditionFalse,
+		},
+		{
+			name: "request exceeding its limit keeps the previous resources",
+			resources: &corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("500m"), corev1.ResourceMemory: resource.MustParse("2Gi")},
+				Limits:   corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1"), corev1.ResourceMemory: resource.MustParse("1Gi")},
+			},
+			expected:         previouslyObserved,
+			expectedDegraded: operatorv1.ConditionTrue,
+			expectedMessage:  "memory request 2Gi exceeds the limit 1Gi",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+			if tc.resources != nil {
+				if err := indexer.Add(&configv1.Build{
+					ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+					Spec:       configv1.BuildSpec{BuildDefaults: configv1.BuildDefaults{Resources: *tc.resources}},
+				}); err != nil {
+					t.Fatal(err)
+				}
+			}
+			listers := configobservation.Listers{BuildConfigLister: configlistersv1.NewBuildLister(indexer)}
+			operatorClient := v1helpers.NewFakeOperatorClient(&operatorv1.OperatorSpec{}, &operatorv1.OperatorStatus{}, nil)
+			recorder := events.NewInMemoryRecorder("", clock.RealClock{})
+
+			observed, errs := NewObserveBuildDefaultResourcesFunc(operatorClient)(listers, recorder, previouslyObserved)
+			if len(errs) > 0 {
+				t.Fatalf("unexpected errors: %v", errs)
+			}
+			if diff := cmp.Diff(tc.expected, observed); len(diff) > 0 {
+				t.Errorf("unexpected observed config (-want +got):\n%s", diff)
+			}
+
+			_, status, _, err := operatorClient.GetOperatorState()
+			if err != nil {
+				t.Fatal(err)
+			}
+			condition := v1helpers.FindOperatorCondition(status.Conditions, buildDefaultResourcesDegradedType)
+			if condition == nil {
+				t.Fatalf("expected a %s condition", buildDefaultResourcesDegradedType)
+			}
+			if condition.Status != tc.expectedDegraded {
+				t.Errorf("expected %s=%s, got %s", buildDefaultResourcesDegradedType, tc.expectedDegraded, condition.Status)
+			}
+			if !strings.Contains(condition.Message, tc.expectedMessage) {
+				t.Errorf("expected the condition message to contain %q, got %q", tc.expectedMessage, condition.Message)
+			}
+			if tc.expectedDegraded == operatorv1.ConditionTrue && strings.Contains(condition.Message, "cpu") {
+				t.Errorf("expected the condition message to name only the inconsistent resource, got %q", condition.Message)
+			}
+		})
+	}
+}
@@ -6,7 +6,6 @@ import (
 	"k8s.io/klog/v2"
 
 	"k8s.io/apimachinery/pkg/api/errors"
-	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 
 	"github.com/openshift/library-go/pkg/operator/configobserver"
 	"github.com/openshift/library-go/pkg/operator/events"
@@ -15,50 +14,13 @@ import (
 )
 
 // ObserveBuildControllerConfig reads the cluster-wide build controller configuration as provided by the cluster admin.
+// The git proxy settings are observed by ObserveGitProxy and ObserveGitNoProxy.
 func ObserveBuildControllerConfig(genericListers configobserver.Listers, recorder events.Recorder, existingConfig map[string]interface{}) (map[string]interface{}, []error) {
 
 	listers := genericListers.(configobservation.Listers)
 	var errs []error
 	prevObservedConfig := map[string]interface{}{}
 
-	// first observe all the existing config values so that if we get any errors
-	// we can at least return those.
-	gitHTTPProxyPath := []string{"build", "buildDefaults", "gitHTTPProxy"}
-	currentGitHTTPProxy, _, err := unstructured.NestedString(existingConfig, gitHTTPProxyPath...)
-	if err != nil {
-		return prevObservedConfig, append(errs, err)
-	}
-	if len(currentGitHTTPProxy) > 0 {
-		err := unstructured.SetNestedField(prevObservedConfig, currentGitHTTPProxy, gitHTTPProxyPath...)
-		if err != nil {
-			return prevObservedConfig, append(errs, err)
-		}
-	}
-
-	gitHTTPSProxyPath := []string{"build", "buildDefaults", "gitHTTPSProxy"}
-	currentGitHTTPSProxy, _, err := unstructured.NestedString(existingConfig, gitHTTPSProxyPath...)
-	if err != nil {
-		return prevObservedConfig, append(errs, err)
-	}
-	if len(currentGitHTTPSProxy) > 0 {
-		err := unstructured.SetNestedField(prevObservedConfig, currentGitHTTPSProxy, gitHTTPSProxyPath...)
-		if err != nil {
-			return prevObservedConfig, append(errs, err)
-		}
-	}
-
-	gitNoProxyPath := []string{"build", "buildDefaults", "gitNoProxy"}
-	currentGitNoProxy, _, err := unstructured.NestedString(existingConfig, gitNoProxyPath...)
-	if err != nil {
-		return prevObservedConfig, append(errs, err)
-	}
-	if len(currentGitNoProxy) > 0 {
-		err := unstructured.SetNestedField(prevObservedConfig, currentGitNoProxy, gitNoProxyPath...)
-		if err != nil {
-			return prevObservedConfig, append(errs, err)
-		}
-	}
-
 	// now gather the cluster config and turn it into the observed config
 	observedConfig := map[string]interface{}{}
 	buildConfig, err := listers.BuildConfigLister.Get("cluster")
@@ -70,8 +32,9 @@ func ObserveBuildControllerConfig(genericListers configobserver.Listers, recorde
 		return prevObservedConfig, append(errs, err)
 	}
 
-	// NOTE proxies are now entirely handled by the build controller itself;
-	// but we still process the other defaults/overrides cluster config for builds here
+	// NOTE proxies are now entirely handled by the build controller itself, apart from the git proxy
+	// observed by ObserveGitProxy and ObserveGitNoProxy; but we still process the other
+	// defaults/overrides cluster config for builds here
 
 	if len(buildConfig.Spec.BuildDefaults.Env) > 0 {
 		if err = configobservation.ObserveField(observedConfig, buildConfig.Spec.BuildDefaults.Env, "build.buildDefaults.env", true); err != nil {
@@ -84,9 +47,7 @@ func ObserveBuildControllerConfig(genericListers configobserver.Listers, recorde
 		}
 	}
 
-	if err = configobservation.ObserveField(observedConfig, buildConfig.Spec.BuildDefaults.Resources, "build.buildDefaults.resources", true); err != nil {
-		return nil, append(errs, fmt.Errorf("failed to observe %s: %v", "build.buildDefaults.resources", err))
-	}
+	// the default resources are observed by NewObserveBuildDefaultResourcesFunc
 
 	// set build overrides
 	if len(buildConfig.Spec.BuildOverrides.ImageLabels) > 0 {
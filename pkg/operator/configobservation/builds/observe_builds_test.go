@@ -350,7 +350,8 @@ func TestObserveBuildControllerConfig(t *testing.T) {
 			expectedEnv := test.buildConfig.Spec.BuildDefaults.Env
 			testNestedField(observed, expectedEnv, "build.buildDefaults.env", false, t)
 			testNestedField(observed, test.buildConfig.Spec.BuildDefaults.ImageLabels, "build.buildDefaults.imageLabels", false, t)
-			testNestedField(observed, test.buildConfig.Spec.BuildDefaults.Resources, "build.buildDefaults.resources", false, t)
+			// the default resources are observed by NewObserveBuildDefaultResourcesFunc
+			testNestedField(observed, nil, "build.buildDefaults.resources", false, t)
 			testNestedField(observed, test.buildConfig.Spec.BuildOverrides.ImageLabels, "build.buildOverrides.imageLabels", false, t)
 			testNestedField(observed, test.buildConfig.Spec.BuildOverrides.NodeSelector, "build.buildOverrides.nodeSelector", false, t)
 			testNestedField(observed, test.buildConfig.Spec.BuildOverrides.Tolerations, "build.buildOverrides.tolerations", false, t)
@@ -476,3 +477,93 @@ func testNestedField(obj map[string]interface{}, expectedVal interface{}, field
 		}
 	}
 }
+
+func TestObserveBuildControllerConfigScheduling(t *testing.T) {
+	tests := []struct {
+		name      string
+		overrides configv1.BuildOverrides
+		expected  map[string]interface{}
+	}{
+		{
+			name:      "empty selector",
+			overrides: configv1.BuildOverrides{NodeSelector: map[string]string{}},
+			expected:  map[string]interface{}{},
+		},
+		{
+			name: "selector referencing labels",
+			overrides: configv1.BuildOverrides{NodeSelector: map[string]string{
+				"node-role.kubernetes.io/builder": "",
+				"topology.kubernetes.io/zone":     "us-east-1a",
+			}},
+			expected: map[string]interface{}{
+				"nodeSelector": map[string]interface{}{
+					"node-role.kubernetes.io/builder": "",
+					"topology.kubernetes.io/zone":     "us-east-1a",
+				},
+			},
+		},
+		{
+			name: "tolerations without a selector",
+			overrides: configv1.BuildOverrides{Tolerations: []corev1.Toleration{
+				{Key: "builds", Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule},
+			}},
+			expected: map[string]interface{}{
+				"tolerations": []interface{}{
+					map[string]interface{}{"key": "builds", "operator": "Exists", "effect": "NoSchedule"},
+				},
+			},
+		},
+		{
+			name: "selector and tolerations",
+			overrides: configv1.BuildOverrides{
+				NodeSelector: map[string]string{"node-role.kubernetes.io/builder": ""},
+				Tolerations: []corev1.Toleration{
+					{Key: "node-role.kubernetes.io/builder", Operator: corev1.TolerationOpEqual, Value: "true", Effect: corev1.TaintEffectNoExecute},
+				},
+			},
+			expected: map[string]interface{}{
+				"nodeSelector": map[string]interface{}{"node-role.kubernetes.io/builder": ""},
+				"tolerations": []interface{}{
+					map[string]interface{}{"key": "node-role.kubernetes.io/builder", "operator": "Equal", "value": "true", "effect": "NoExecute"},
+				},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+			if err := indexer.Add(&configv1.Build{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+				Spec:       configv1.BuildSpec{BuildOverrides: test.overrides},
+			}); err != nil {
+				t.Fatal(err)
+			}
+			listers := configobservation.Listers{
+				BuildConfigLister: configlistersv1.NewBuildLister(indexer),
+			}
+			// the scheduling constraints observed before are replaced, not merged
+			existing := map[string]interface{}{
+				"build": map[string]interface{}{
+					"buildOverrides": map[string]interface{}{
+						"nodeSelector": map[string]interface{}{"old": "selector"},
+					},
+				},
+			}
+
+			observed, errs := ObserveBuildControllerConfig(listers, events.NewInMemoryRecorder("", clock.RealClock{}), existing)
+			if len(errs) > 0 {
+				t.Fatalf("unexpected errors: %v", errs)
+			}
+			overrides, _, err := unstructured.NestedMap(observed, "build", "buildOverrides")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if overrides == nil {
+				overrides = map[string]interface{}{}
+			}
+			if !equality.Semantic.DeepEqual(test.expected, overrides) {
+				t.Errorf("expected the build overrides %v, got %v", test.expected, overrides)
+			}
+		})
+	}
+}
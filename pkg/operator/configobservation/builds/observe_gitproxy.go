@@ -0,0 +1,55 @@
+package builds
+
+import (
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/klog/v2"
+
+	"github.com/openshift/library-go/pkg/operator/configobserver"
+	"github.com/openshift/library-go/pkg/operator/events"
+
+	"github.com/openshift/cluster-openshift-controller-manager-operator/pkg/operator/configobservation"
+)
+
+var (
+	gitHTTPProxyPath  = []string{"build", "buildDefaults", "gitHTTPProxy"}
+	gitHTTPSProxyPath = []string{"build", "buildDefaults", "gitHTTPSProxy"}
+	gitNoProxyPath    = []string{"build", "buildDefaults", "gitNoProxy"}
+)
+
+// ObserveGitProxy reads the proxy used by builds to clone git sources from the cluster-wide build configuration,
+// the keys are removed once the git proxy is cleared. The no-proxy list is observed by ObserveGitNoProxy.
+func ObserveGitProxy(genericListers configobserver.Listers, recorder events.Recorder, existingConfig map[string]interface{}) (map[string]interface{}, []error) {
+	listers := genericListers.(configobservation.Listers)
+	prevObservedConfig := configobserver.Pruned(existingConfig, gitHTTPProxyPath, gitHTTPSProxyPath)
+
+	observedConfig := map[string]interface{}{}
+	buildConfig, err := listers.BuildConfigLister.Get("cluster")
+	if errors.IsNotFound(err) {
+		klog.V(2).Infof("builds.config.openshift.io/cluster: not found")
+		return observedConfig, nil
+	}
+	if err != nil {
+		return prevObservedConfig, []error{err}
+	}
+
+	gitProxy := buildConfig.Spec.BuildDefaults.GitProxy
+	if gitProxy == nil {
+		return observedConfig, nil
+	}
+	for _, field := range []struct {
+		path  []string
+		value string
+	}{
+		{path: gitHTTPProxyPath, value: gitProxy.HTTPProxy},
+		{path: gitHTTPSProxyPath, value: gitProxy.HTTPSProxy},
+	} {
+		if len(field.value) == 0 {
+			continue
+		}
+		if err := unstructured.SetNestedField(observedConfig, field.value, field.path...); err != nil {
+			return prevObservedConfig, []error{err}
+		}
+	}
+	return observedConfig, nil
+}
@@ -0,0 +1,132 @@
+package builds
+
+import (
+	"errors"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/utils/clock"
+
+	configv1 "github.com/openshift/api/config/v1"
+	configlistersv1 "github.com/openshift/client-go/config/listers/config/v1"
+	"github.com/openshift/library-go/pkg/operator/events"
+
+	"github.com/openshift/cluster-openshift-controller-manager-operator/pkg/operator/configobservation"
+)
+
+// erroringBuildLister fails to get the build config.
+type erroringBuildLister struct{}
+
+func (erroringBuildLister) List(labels.Selector) ([]*configv1.Build, error) {
+	return nil, errors.New("list failed")
+}
+
+func (erroringBuildLister) Get(string) (*configv1.Build, error) {
+	return nil, errors.New("get failed")
+}
+
+func TestObserveGitProxy(t *testing.T) {
+	existingConfig := map[string]interface{}{
+		"build": map[string]interface{}{
+			"buildDefaults": map[string]interface{}{
+				"gitHTTPProxy":  "http://old-proxy",
+				"gitHTTPSProxy": "https://old-proxy",
+				"env":           []interface{}{},
+			},
+		},
+	}
+	buildConfig := func(gitProxy *configv1.ProxySpec) *configv1.Build {
+		return &configv1.Build{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+			Spec: configv1.BuildSpec{
+				BuildDefaults: configv1.BuildDefaults{
+					DefaultProxy: &configv1.ProxySpec{HTTPProxy: "http://default-proxy"},
+					GitProxy:     gitProxy,
+				},
+			},
+		}
+	}
+	gitProxyConfig := func(buildDefaults map[string]interface{}) map[string]interface{} {
+		return map[string]interface{}{"build": map[string]interface{}{"buildDefaults": buildDefaults}}
+	}
+
+	tests := []struct {
+		name        string
+		buildConfig *configv1.Build
+		lister      configlistersv1.BuildLister
+		expected    map[string]interface{}
+		expectError bool
+	}{
+		{
+			name:     "no build config",
+			expected: map[string]interface{}{},
+		},
+		{
+			name:        "no git proxy",
+			buildConfig: buildConfig(nil),
+			expected:    map[string]interface{}{},
+		},
+		{
+			name:        "cleared git proxy",
+			buildConfig: buildConfig(&configv1.ProxySpec{}),
+			expected:    map[string]interface{}{},
+		},
+		{
+			name: "full git proxy",
+			buildConfig: buildConfig(&configv1.ProxySpec{
+				HTTPProxy:  "http://my-proxy",
+				HTTPSProxy: "https://my-proxy",
+				NoProxy:    ".cluster.local,10.0.0.0/16",
+			}),
+			expected: gitProxyConfig(map[string]interface{}{
+				"gitHTTPProxy":  "http://my-proxy",
+				"gitHTTPSProxy": "https://my-proxy",
+			}),
+		},
+		{
+			name:        "https proxy only",
+			buildConfig: buildConfig(&configv1.ProxySpec{HTTPSProxy: "https://my-proxy"}),
+			expected:    gitProxyConfig(map[string]interface{}{"gitHTTPSProxy": "https://my-proxy"}),
+		},
+		{
+			name:        "no proxy only is observed by ObserveGitNoProxy",
+			buildConfig: buildConfig(&configv1.ProxySpec{NoProxy: ".cluster.local"}),
+			expected:    map[string]interface{}{},
+		},
+		{
+			name:   "lister error keeps the previous git proxy",
+			lister: erroringBuildLister{},
+			expected: gitProxyConfig(map[string]interface{}{
+				"gitHTTPProxy":  "http://old-proxy",
+				"gitHTTPSProxy": "https://old-proxy",
+			}),
+			expectError: true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			lister := tc.lister
+			if lister == nil {
+				indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+				if tc.buildConfig != nil {
+					if err := indexer.Add(tc.buildConfig); err != nil {
+						t.Fatal(err)
+					}
+				}
+				lister = configlistersv1.NewBuildLister(indexer)
+			}
+			listers := configobservation.Listers{BuildConfigLister: lister}
+
+			observed, errs := ObserveGitProxy(listers, events.NewInMemoryRecorder("", clock.RealClock{}), existingConfig)
+			if tc.expectError != (len(errs) > 0) {
+				t.Errorf("expected error %t, got %v", tc.expectError, errs)
+			}
+			if !equality.Semantic.DeepEqual(tc.expected, observed) {
+				t.Errorf("expected observed config %v, got %v", tc.expected, observed)
+			}
+		})
+	}
+}
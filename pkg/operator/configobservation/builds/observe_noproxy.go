@@ -0,0 +1,55 @@
+package builds
+
+import (
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/klog/v2"
+
+	"github.com/openshift/library-go/pkg/operator/configobserver"
+	"github.com/openshift/library-go/pkg/operator/events"
+
+	"github.com/openshift/cluster-openshift-controller-manager-operator/pkg/operator/configobservation"
+)
+
+// ObserveGitNoProxy reads the list of hosts for which builds clone git sources without a proxy. A no-proxy
+// list set on the git proxy of the cluster-wide build configuration wins, otherwise the list of the cluster
+// proxy is used. The cluster proxy expands its spec with the internal CIDRs, the service network and the
+// API server hostname into its status, so the status is preferred and the spec is only a fallback until
+// the status is populated. The other fields of the cluster proxy, like its readiness endpoints or trusted
+// CA, are consumed elsewhere and never observed.
+func ObserveGitNoProxy(genericListers configobserver.Listers, recorder events.Recorder, existingConfig map[string]interface{}) (map[string]interface{}, []error) {
+	listers := genericListers.(configobservation.Listers)
+	prevObservedConfig := configobserver.Pruned(existingConfig, gitNoProxyPath)
+
+	observedConfig := map[string]interface{}{}
+	buildConfig, err := listers.BuildConfigLister.Get("cluster")
+	if err != nil && !errors.IsNotFound(err) {
+		return prevObservedConfig, []error{err}
+	}
+	if err == nil && buildConfig.Spec.BuildDefaults.GitProxy != nil && len(buildConfig.Spec.BuildDefaults.GitProxy.NoProxy) > 0 {
+		if err := unstructured.SetNestedField(observedConfig, buildConfig.Spec.BuildDefaults.GitProxy.NoProxy, gitNoProxyPath...); err != nil {
+			return prevObservedConfig, []error{err}
+		}
+		return observedConfig, nil
+	}
+
+	proxy, err := listers.ProxyLister.Get("cluster")
+	if errors.IsNotFound(err) {
+		klog.V(2).Infof("proxies.config.openshift.io/cluster: not found")
+		return observedConfig, nil
+	}
+	if err != nil {
+		return prevObservedConfig, []error{err}
+	}
+	noProxy := proxy.Status.NoProxy
+	if len(noProxy) == 0 {
+		noProxy = proxy.Spec.NoProxy
+	}
+	if len(noProxy) == 0 {
+		return observedConfig, nil
+	}
+	if err := unstructured.SetNestedField(observedConfig, noProxy, gitNoProxyPath...); err != nil {
+		return prevObservedConfig, []error{err}
+	}
+	return observedConfig, nil
+}
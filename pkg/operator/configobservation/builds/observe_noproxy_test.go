@@ -0,0 +1,136 @@
+package builds
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/utils/clock"
+
+	configv1 "github.com/openshift/api/config/v1"
+	configlistersv1 "github.com/openshift/client-go/config/listers/config/v1"
+	"github.com/openshift/library-go/pkg/operator/events"
+
+	"github.com/openshift/cluster-openshift-controller-manager-operator/pkg/operator/configobservation"
+)
+
+func TestObserveGitNoProxy(t *testing.T) {
+	existingConfig := map[string]interface{}{
+		"build": map[string]interface{}{
+			"buildDefaults": map[string]interface{}{
+				"gitNoProxy":   ".old.example.com",
+				"gitHTTPProxy": "http://my-proxy",
+			},
+		},
+	}
+	proxy := func(specNoProxy, statusNoProxy string) *configv1.Proxy {
+		return &configv1.Proxy{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+			Spec:       configv1.ProxySpec{HTTPProxy: "http://cluster-proxy", NoProxy: specNoProxy},
+			Status:     configv1.ProxyStatus{HTTPProxy: "http://cluster-proxy", NoProxy: statusNoProxy},
+		}
+	}
+	gitNoProxyConfig := func(noProxy string) map[string]interface{} {
+		return map[string]interface{}{"build": map[string]interface{}{"buildDefaults": map[string]interface{}{"gitNoProxy": noProxy}}}
+	}
+	expandedNoProxy := ".cluster.local,.svc,10.0.0.0/16,172.30.0.0/16,api-int.example.com,example.com,localhost"
+
+	tests := []struct {
+		name        string
+		buildConfig *configv1.Build
+		proxy       *configv1.Proxy
+		buildLister configlistersv1.BuildLister
+		expected    map[string]interface{}
+		expectError bool
+	}{
+		{
+			name:     "no build config and no proxy",
+			expected: map[string]interface{}{},
+		},
+		{
+			name:     "status wins over spec",
+			proxy:    proxy("example.com", expandedNoProxy),
+			expected: gitNoProxyConfig(expandedNoProxy),
+		},
+		{
+			name:     "spec until the status is populated",
+			proxy:    proxy("example.com", ""),
+			expected: gitNoProxyConfig("example.com"),
+		},
+		{
+			name: "all proxy fields populated",
+			proxy: &configv1.Proxy{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+				Spec: configv1.ProxySpec{
+					HTTPProxy:          "http://cluster-proxy",
+					HTTPSProxy:         "https://cluster-proxy",
+					NoProxy:            "example.com",
+					ReadinessEndpoints: []string{"http://www.google.com", "https://www.google.com"},
+					TrustedCA:          configv1.ConfigMapNameReference{Name: "user-ca-bundle"},
+				},
+				Status: configv1.ProxyStatus{
+					HTTPProxy:  "http://cluster-proxy",
+					HTTPSProxy: "https://cluster-proxy",
+					NoProxy:    expandedNoProxy,
+				},
+			},
+			expected: gitNoProxyConfig(expandedNoProxy),
+		},
+		{
+			name:     "proxy without no-proxy list",
+			proxy:    proxy("", ""),
+			expected: map[string]interface{}{},
+		},
+		{
+			name: "git proxy no-proxy list wins over the cluster proxy",
+			buildConfig: &configv1.Build{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+				Spec: configv1.BuildSpec{BuildDefaults: configv1.BuildDefaults{
+					GitProxy: &configv1.ProxySpec{NoProxy: ".git.example.com"},
+				}},
+			},
+			proxy:    proxy("example.com", expandedNoProxy),
+			expected: gitNoProxyConfig(".git.example.com"),
+		},
+		{
+			name:        "build lister error keeps the previous no-proxy list",
+			buildLister: erroringBuildLister{},
+			proxy:       proxy("example.com", expandedNoProxy),
+			expected:    gitNoProxyConfig(".old.example.com"),
+			expectError: true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			buildLister := tc.buildLister
+			if buildLister == nil {
+				indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+				if tc.buildConfig != nil {
+					if err := indexer.Add(tc.buildConfig); err != nil {
+						t.Fatal(err)
+					}
+				}
+				buildLister = configlistersv1.NewBuildLister(indexer)
+			}
+			proxyIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+			if tc.proxy != nil {
+				if err := proxyIndexer.Add(tc.proxy); err != nil {
+					t.Fatal(err)
+				}
+			}
+			listers := configobservation.Listers{
+				BuildConfigLister: buildLister,
+				ProxyLister:       configlistersv1.NewProxyLister(proxyIndexer),
+			}
+
+			observed, errs := ObserveGitNoProxy(listers, events.NewInMemoryRecorder("", clock.RealClock{}), existingConfig)
+			if tc.expectError != (len(errs) > 0) {
+				t.Errorf("expected error %t, got %v", tc.expectError, errs)
+			}
+			if !equality.Semantic.DeepEqual(tc.expected, observed) {
+				t.Errorf("expected observed config %v, got %v", tc.expected, observed)
+			}
+		})
+	}
+}
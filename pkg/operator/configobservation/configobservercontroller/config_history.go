@@ -0,0 +1,196 @@
+package configobservercontroller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+
+	operatorclientv1 "github.com/openshift/client-go/operator/clientset/versioned/typed/operator/v1"
+	"github.com/openshift/library-go/pkg/operator/configobserver"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-openshift-controller-manager-operator/pkg/util"
+)
+
+// observedConfigHistoryLength is how many revisions of the observed config the history keeps.
+const observedConfigHistoryLength = 10
+
+// observedConfigRevision is a change of the observed config, encoded as JSON in the
+// util.ObservedConfigHistoryAnnotation of the operator config.
+type observedConfigRevision struct {
+	Time metav1.Time `json:"time"`
+	// Reason names the dotted paths of the observed config the revision added, changed or removed.
+	Reason string `json:"reason"`
+}
+
+type historyRecordingObserver struct {
+	operatorClient       v1helpers.OperatorClient
+	operatorConfigClient operatorclientv1.OpenShiftControllerManagersGetter
+	observe              configobserver.ObserveConfigFunc
+	size                 int
+	now                  func() time.Time
+
+	lock sync.Mutex
+	// loaded is whether revisions was seeded from the annotation of the operator config, which
+	// carries the history over restarts of the operator.
+	loaded    bool
+	revisions []observedConfigRevision
+	// lastConfig is the observed config last recorded, a config written to the operator config
+	// unsuccessfully is observed again on the next sync and not recorded twice.
+	lastConfig map[string]interface{}
+}
+
+// newHistoryRecordingObserveConfigFunc returns an observer recording the last size changes observe makes
+// to the observed config, with when and why, in a ring buffer persisted to the
+// util.ObservedConfigHistoryAnnotation of the operator config. The history is for debugging the observed
+// config changing, and so the operand restarting, more often than expected. Failing to persist it is
+// logged, never reported.
+func newHistoryRecordingObserveConfigFunc(operatorClient v1helpers.OperatorClient, operatorConfigClient operatorclientv1.OpenShiftControllerManagersGetter, size int, observe configobserver.ObserveConfigFunc) configobserver.ObserveConfigFunc {
+	o := &historyRecordingObserver{
+		operatorClient:       operatorClient,
+		operatorConfigClient: operatorConfigClient,
+		observe:              observe,
+		size:                 size,
+		now:                  time.Now,
+	}
+	return o.observeConfig
+}
+
+func (o *historyRecordingObserver) observeConfig(listers configobserver.Listers, recorder events.Recorder, existingConfig map[string]interface{}) (map[string]interface{}, []error) {
+	observedConfig, errs := o.observe(listers, recorder, existingConfig)
+	if equality.Semantic.DeepEqual(existingConfig, observedConfig) {
+		return observedConfig, errs
+	}
+
+	revisions, recorded := o.record(existingConfig, observedConfig)
+	if !recorded {
+		return observedConfig, errs
+	}
+	if err := setObservedConfigHistory(context.TODO(), o.operatorConfigClient, revisions); err != nil {
+		klog.Warningf("Failed to persist the observed config history: %v", err)
+	}
+	return observedConfig, errs
+}
+
+// record adds the change from existingConfig to observedConfig to the history and returns the history,
+// unless observedConfig was the last config recorded.
+func (o *historyRecordingObserver) record(existingConfig, observedConfig map[string]interface{}) ([]observedConfigRevision, bool) {
+	o.lock.Lock()
+	defer o.lock.Unlock()
+
+	if !o.loaded {
+		o.revisions = o.persistedRevisions()
+		o.loaded = true
+	}
+	if o.lastConfig != nil && equality.Semantic.DeepEqual(o.lastConfig, observedConfig) {
+		return nil, false
+	}
+	o.lastConfig = observedConfig
+
+	o.revisions = append(o.revisions, observedConfigRevision{
+		Time:   metav1.NewTime(o.now()),
+		Reason: configChangeReason(existingConfig, observedConfig),
+	})
+	if len(o.revisions) > o.size {
+		o.revisions = o.revisions[len(o.revisions)-o.size:]
+	}
+	return append([]observedConfigRevision{}, o.revisions...), true
+}
+
+// persistedRevisions returns the history persisted by an earlier run of the operator, none if it cannot
+// be read.
+func (o *historyRecordingObserver) persistedRevisions() []observedConfigRevision {
+	meta, err := o.operatorClient.GetObjectMeta()
+	if err != nil {
+		klog.Warningf("Failed to read the observed config history: %v", err)
+		return nil
+	}
+	value, ok := meta.Annotations[util.ObservedConfigHistoryAnnotation]
+	if !ok {
+		return nil
+	}
+	var revisions []observedConfigRevision
+	if err := json.Unmarshal([]byte(value), &revisions); err != nil {
+		klog.Warningf("Ignoring the malformed observed config history %s: %v", util.ObservedConfigHistoryAnnotation, err)
+		return nil
+	}
+	return revisions
+}
+
+// configChangeReason describes the dotted paths added, changed and removed from existing to observed.
+func configChangeReason(existing, observed map[string]interface{}) string {
+	added, changed, removed := configChanges(existing, observed, nil)
+	var reasons []string
+	for _, change := range []struct {
+		verb  string
+		paths []string
+	}{
+		{verb: "added", paths: added},
+		{verb: "changed", paths: changed},
+		{verb: "removed", paths: removed},
+	} {
+		if len(change.paths) > 0 {
+			sort.Strings(change.paths)
+			reasons = append(reasons, fmt.Sprintf("%s %s", change.verb, strings.Join(change.paths, ", ")))
+		}
+	}
+	return strings.Join(reasons, "; ")
+}
+
+func configChanges(existing, observed map[string]interface{}, prefix []string) (added, changed, removed []string) {
+	for key, value := range observed {
+		path := append(append([]string{}, prefix...), key)
+		existingValue, ok := existing[key]
+		if !ok {
+			added = append(added, strings.Join(path, "."))
+			continue
+		}
+		nested, isMap := value.(map[string]interface{})
+		existingNested, existingIsMap := existingValue.(map[string]interface{})
+		if isMap && existingIsMap {
+			nestedAdded, nestedChanged, nestedRemoved := configChanges(existingNested, nested, path)
+			added = append(added, nestedAdded...)
+			changed = append(changed, nestedChanged...)
+			removed = append(removed, nestedRemoved...)
+			continue
+		}
+		if !equality.Semantic.DeepEqual(existingValue, value) {
+			changed = append(changed, strings.Join(path, "."))
+		}
+	}
+	for key := range existing {
+		if _, ok := observed[key]; !ok {
+			removed = append(removed, strings.Join(append(append([]string{}, prefix...), key), "."))
+		}
+	}
+	return added, changed, removed
+}
+
+func setObservedConfigHistory(ctx context.Context, client operatorclientv1.OpenShiftControllerManagersGetter, revisions []observedConfigRevision) error {
+	history, err := json.Marshal(revisions)
+	if err != nil {
+		return err
+	}
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]string{util.ObservedConfigHistoryAnnotation: string(history)},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	if _, err := client.OpenShiftControllerManagers().Patch(ctx, "cluster", types.MergePatchType, patch, metav1.PatchOptions{}); err != nil {
+		return fmt.Errorf("failed to record the observed config history: %w", err)
+	}
+	return nil
+}
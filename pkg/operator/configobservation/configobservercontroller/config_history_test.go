@@ -0,0 +1,124 @@
+package configobservercontroller
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/clock"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	operatorfake "github.com/openshift/client-go/operator/clientset/versioned/fake"
+	"github.com/openshift/library-go/pkg/operator/configobserver"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-openshift-controller-manager-operator/pkg/operator/configobservation"
+	"github.com/openshift/cluster-openshift-controller-manager-operator/pkg/util"
+)
+
+func TestHistoryRecordingObserveConfigFunc(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	persisted, err := json.Marshal([]observedConfigRevision{{Time: metav1.NewTime(start), Reason: "added build"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	meta := &metav1.ObjectMeta{Name: "cluster", Annotations: map[string]string{util.ObservedConfigHistoryAnnotation: string(persisted)}}
+	operatorClient := v1helpers.NewFakeOperatorClientWithObjectMeta(meta, &operatorv1.OperatorSpec{}, &operatorv1.OperatorStatus{}, nil)
+	operatorConfigClient := operatorfake.NewSimpleClientset(&operatorv1.OpenShiftControllerManager{ObjectMeta: *meta})
+	recorder := events.NewInMemoryRecorder("", clock.RealClock{})
+
+	var observedConfig map[string]interface{}
+	observe := func(configobserver.Listers, events.Recorder, map[string]interface{}) (map[string]interface{}, []error) {
+		return observedConfig, nil
+	}
+	minutes := 0
+	o := &historyRecordingObserver{
+		operatorClient:       operatorClient,
+		operatorConfigClient: operatorConfigClient.OperatorV1(),
+		observe:              observe,
+		size:                 3,
+		now: func() time.Time {
+			minutes++
+			return start.Add(time.Duration(minutes) * time.Minute)
+		},
+	}
+
+	existingConfig := map[string]interface{}{}
+	for _, config := range []map[string]interface{}{
+		{"controllers": []interface{}{"*"}},
+		{"controllers": []interface{}{"*"}, "servingInfo": map[string]interface{}{"minTLSVersion": "VersionTLS12"}},
+		{"controllers": []interface{}{"*"}, "servingInfo": map[string]interface{}{"minTLSVersion": "VersionTLS13", "cipherSuites": []interface{}{"TLS_AES_128_GCM_SHA256"}}},
+		{"servingInfo": map[string]interface{}{"minTLSVersion": "VersionTLS13"}},
+	} {
+		observedConfig = config
+		if _, errs := o.observeConfig(configobservation.Listers{}, recorder, existingConfig); len(errs) > 0 {
+			t.Fatal(errs)
+		}
+		// the config failed to be written and is observed again
+		if _, errs := o.observeConfig(configobservation.Listers{}, recorder, existingConfig); len(errs) > 0 {
+			t.Fatal(errs)
+		}
+		existingConfig = config
+	}
+	// the config is unchanged
+	if _, errs := o.observeConfig(configobservation.Listers{}, recorder, existingConfig); len(errs) > 0 {
+		t.Fatal(errs)
+	}
+
+	// the persisted revision and the first one observed dropped out of the history
+	expected := []observedConfigRevision{
+		{Time: metav1.NewTime(start.Add(2 * time.Minute)), Reason: "added servingInfo"},
+		{Time: metav1.NewTime(start.Add(3 * time.Minute)), Reason: "added servingInfo.cipherSuites; changed servingInfo.minTLSVersion"},
+		{Time: metav1.NewTime(start.Add(4 * time.Minute)), Reason: "removed controllers, servingInfo.cipherSuites"},
+	}
+	if diff := cmp.Diff(expected, o.revisions); len(diff) > 0 {
+		t.Errorf("unexpected history (-want +got):\n%s", diff)
+	}
+
+	operatorConfig, err := operatorConfigClient.OperatorV1().OpenShiftControllerManagers().Get(context.TODO(), "cluster", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var written []observedConfigRevision
+	if err := json.Unmarshal([]byte(operatorConfig.Annotations[util.ObservedConfigHistoryAnnotation]), &written); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(expected, written); len(diff) > 0 {
+		t.Errorf("unexpected persisted history (-want +got):\n%s", diff)
+	}
+}
+
+func TestHistoryRecordingObserveConfigFuncSeedsFromAnnotation(t *testing.T) {
+	persisted := `[{"time":"2026-01-01T00:00:00Z","reason":"added build"}]`
+	meta := &metav1.ObjectMeta{Name: "cluster", Annotations: map[string]string{util.ObservedConfigHistoryAnnotation: persisted}}
+	operatorClient := v1helpers.NewFakeOperatorClientWithObjectMeta(meta, &operatorv1.OperatorSpec{}, &operatorv1.OperatorStatus{}, nil)
+	operatorConfigClient := operatorfake.NewSimpleClientset(&operatorv1.OpenShiftControllerManager{ObjectMeta: *meta})
+	observe := func(configobserver.Listers, events.Recorder, map[string]interface{}) (map[string]interface{}, []error) {
+		return map[string]interface{}{"controllers": []interface{}{"*"}}, nil
+	}
+	o := newHistoryRecordingObserveConfigFunc(operatorClient, operatorConfigClient.OperatorV1(), 3, observe)
+
+	if _, errs := o(configobservation.Listers{}, events.NewInMemoryRecorder("", clock.RealClock{}), map[string]interface{}{}); len(errs) > 0 {
+		t.Fatal(errs)
+	}
+
+	operatorConfig, err := operatorConfigClient.OperatorV1().OpenShiftControllerManagers().Get(context.TODO(), "cluster", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var written []observedConfigRevision
+	if err := json.Unmarshal([]byte(operatorConfig.Annotations[util.ObservedConfigHistoryAnnotation]), &written); err != nil {
+		t.Fatal(err)
+	}
+	var reasons []string
+	for _, revision := range written {
+		reasons = append(reasons, revision.Reason)
+	}
+	if diff := cmp.Diff([]string{"added build", "added controllers"}, reasons); len(diff) > 0 {
+		t.Errorf("unexpected reasons of the persisted history (-want +got):\n%s", diff)
+	}
+}
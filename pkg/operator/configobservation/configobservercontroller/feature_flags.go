@@ -0,0 +1,57 @@
+package configobservercontroller
+
+import (
+	"strconv"
+
+	"k8s.io/klog/v2"
+
+	"github.com/openshift/library-go/pkg/operator/configobserver"
+)
+
+// featureFlagPrefix prefixes the environment variables of the operator enabling experimental observers,
+// e.g. OCM_OPERATOR_ENABLE_NETWORK_OBSERVER=true.
+const featureFlagPrefix = "OCM_OPERATOR_ENABLE_"
+
+// observerRegistration declares an observer of the observed config.
+type observerRegistration struct {
+	name    string
+	observe configobserver.ObserveConfigFunc
+	enabled bool
+	// featureFlag is the name of the environment variable, after featureFlagPrefix, which has to be true
+	// for the observer to be registered. Experimental observers are staged behind a flag until they are
+	// enabled by default, observers without a flag are registered whenever they are enabled.
+	featureFlag string
+	inputs      []observedInput
+	// paths are the dotted paths of the observed config the observer writes, keys outside of the paths of
+	// all registered observers are pruned after an operator upgrade, see newStaleConfigPruningObserveConfigFunc.
+	paths []string
+}
+
+// registeredObservers returns the enabled observers whose feature flag, if any, is set in the environment
+// of the operator. A flag which is not a boolean leaves its observer unregistered.
+func registeredObservers(observers []observerRegistration, lookupEnv func(string) (string, bool)) []observerRegistration {
+	var registered []observerRegistration
+	for _, observer := range observers {
+		if !observer.enabled {
+			continue
+		}
+		if len(observer.featureFlag) > 0 {
+			name := featureFlagPrefix + observer.featureFlag
+			value, ok := lookupEnv(name)
+			if !ok {
+				continue
+			}
+			enabled, err := strconv.ParseBool(value)
+			if err != nil {
+				klog.Warningf("Ignoring %s=%q, not registering the %s observer: %v", name, value, observer.name, err)
+				continue
+			}
+			if !enabled {
+				continue
+			}
+			klog.Infof("Registering the experimental %s observer enabled by %s", observer.name, name)
+		}
+		registered = append(registered, observer)
+	}
+	return registered
+}
@@ -0,0 +1,61 @@
+package configobservercontroller
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRegisteredObservers(t *testing.T) {
+	observers := []observerRegistration{
+		{name: "Stable", enabled: true},
+		{name: "Disabled", enabled: false},
+		{name: "Network", enabled: true, featureFlag: "NETWORK_OBSERVER"},
+		{name: "DisabledNetwork", enabled: false, featureFlag: "NETWORK_OBSERVER"},
+	}
+	tests := []struct {
+		name     string
+		env      map[string]string
+		expected []string
+	}{
+		{
+			name:     "flag unset",
+			expected: []string{"Stable"},
+		},
+		{
+			name:     "flag set",
+			env:      map[string]string{"OCM_OPERATOR_ENABLE_NETWORK_OBSERVER": "true"},
+			expected: []string{"Stable", "Network"},
+		},
+		{
+			name:     "flag false",
+			env:      map[string]string{"OCM_OPERATOR_ENABLE_NETWORK_OBSERVER": "false"},
+			expected: []string{"Stable"},
+		},
+		{
+			name:     "flag not a boolean",
+			env:      map[string]string{"OCM_OPERATOR_ENABLE_NETWORK_OBSERVER": "yes please"},
+			expected: []string{"Stable"},
+		},
+		{
+			name:     "flag of another observer",
+			env:      map[string]string{"OCM_OPERATOR_ENABLE_BUILD_OBSERVER": "true"},
+			expected: []string{"Stable"},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			lookupEnv := func(name string) (string, bool) {
+				value, ok := tc.env[name]
+				return value, ok
+			}
+			var registered []string
+			for _, observer := range registeredObservers(observers, lookupEnv) {
+				registered = append(registered, observer.name)
+			}
+			if diff := cmp.Diff(tc.expected, registered); len(diff) > 0 {
+				t.Errorf("unexpected registered observers (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package configobservercontroller
+
+import (
+	"strings"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"github.com/openshift/library-go/pkg/operator/configobserver"
+	"github.com/openshift/library-go/pkg/operator/events"
+
+	"github.com/openshift/cluster-openshift-controller-manager-operator/pkg/operator/configobservation"
+	"github.com/openshift/cluster-openshift-controller-manager-operator/pkg/util"
+)
+
+// observedInput returns an object an observer reads its config from.
+type observedInput func(listers configobservation.Listers) (metav1.Object, error)
+
+var (
+	imageConfigInput = func(listers configobservation.Listers) (metav1.Object, error) {
+		return listers.ImageConfigLister.Get("cluster")
+	}
+	buildConfigInput = func(listers configobservation.Listers) (metav1.Object, error) {
+		return listers.BuildConfigLister.Get("cluster")
+	}
+	networkConfigInput = func(listers configobservation.Listers) (metav1.Object, error) {
+		return listers.NetworkLister.Get("cluster")
+	}
+	infrastructureInput = func(listers configobservation.Listers) (metav1.Object, error) {
+		return listers.InfrastructureLister.Get("cluster")
+	}
+	proxyConfigInput = func(listers configobservation.Listers) (metav1.Object, error) {
+		return listers.ProxyLister.Get("cluster")
+	}
+	clusterVersionInput = func(listers configobservation.Listers) (metav1.Object, error) {
+		return listers.ClusterVersionLister.Get("version")
+	}
+	imageRegistryOperatorInput = func(listers configobservation.Listers) (metav1.Object, error) {
+		return listers.ClusterOperatorLister.Get("image-registry")
+	}
+	controllerManagerImagesInput = func(listers configobservation.Listers) (metav1.Object, error) {
+		return listers.ConfigMapLister.ConfigMaps(util.OperatorNamespace).Get("openshift-controller-manager-images")
+	}
+)
+
+type cachingObserver struct {
+	inputs  []observedInput
+	observe configobserver.ObserveConfigFunc
+
+	lock sync.Mutex
+	// key is the resource versions of the inputs observedConfig was observed from, empty while nothing
+	// is cached.
+	key            string
+	observedConfig map[string]interface{}
+}
+
+// newCachingObserveConfigFunc returns an observer running observe only when the resource version of any
+// of its inputs changed since the last run, the config observed then is returned otherwise. Resyncs of
+// the config observer unrelated to what an observer reads, which are most of them on large clusters, so
+// do not recompute its config. The observer must not read anything but the inputs, neither the existing
+// config once it succeeded: observations failing are not cached, as they carry the existing config over.
+func newCachingObserveConfigFunc(observe configobserver.ObserveConfigFunc, inputs ...observedInput) configobserver.ObserveConfigFunc {
+	o := &cachingObserver{
+		inputs:  inputs,
+		observe: observe,
+	}
+	return o.observeConfig
+}
+
+func (o *cachingObserver) observeConfig(genericListers configobserver.Listers, recorder events.Recorder, existingConfig map[string]interface{}) (map[string]interface{}, []error) {
+	key, ok := o.inputsKey(genericListers.(configobservation.Listers))
+	if !ok {
+		// the observer reports what it cannot read
+		return o.observe(genericListers, recorder, existingConfig)
+	}
+
+	o.lock.Lock()
+	defer o.lock.Unlock()
+	if o.key == key {
+		return runtime.DeepCopyJSON(o.observedConfig), nil
+	}
+
+	observedConfig, errs := o.observe(genericListers, recorder, existingConfig)
+	if len(errs) > 0 {
+		o.key, o.observedConfig = "", nil
+		return observedConfig, errs
+	}
+	o.key, o.observedConfig = key, runtime.DeepCopyJSON(observedConfig)
+	return observedConfig, nil
+}
+
+// inputsKey returns the resource versions of the inputs, an input which does not exist has an empty one.
+// It returns false if any input cannot be read.
+func (o *cachingObserver) inputsKey(listers configobservation.Listers) (string, bool) {
+	resourceVersions := make([]string, 0, len(o.inputs))
+	for _, input := range o.inputs {
+		obj, err := input(listers)
+		switch {
+		case errors.IsNotFound(err):
+			resourceVersions = append(resourceVersions, "")
+		case err != nil:
+			return "", false
+		default:
+			resourceVersions = append(resourceVersions, obj.GetResourceVersion())
+		}
+	}
+	// the separator keeps a key different from the empty key even when no input exists
+	return "/" + strings.Join(resourceVersions, "/"), true
+}
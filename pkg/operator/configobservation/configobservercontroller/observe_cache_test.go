@@ -0,0 +1,121 @@
+package configobservercontroller
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/utils/clock"
+
+	configv1 "github.com/openshift/api/config/v1"
+	configlistersv1 "github.com/openshift/client-go/config/listers/config/v1"
+	"github.com/openshift/library-go/pkg/operator/configobserver"
+	"github.com/openshift/library-go/pkg/operator/events"
+
+	"github.com/openshift/cluster-openshift-controller-manager-operator/pkg/operator/configobservation"
+)
+
+func TestCachingObserveConfigFunc(t *testing.T) {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	listers := configobservation.Listers{ImageConfigLister: configlistersv1.NewImageLister(indexer)}
+	recorder := events.NewInMemoryRecorder("", clock.RealClock{})
+	setImageConfig := func(resourceVersion, hostname string) {
+		t.Helper()
+		if err := indexer.Update(&configv1.Image{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster", ResourceVersion: resourceVersion},
+			Status:     configv1.ImageStatus{InternalRegistryHostname: hostname},
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	computed := 0
+	var failWith error
+	observe := func(genericListers configobserver.Listers, _ events.Recorder, _ map[string]interface{}) (map[string]interface{}, []error) {
+		computed++
+		if failWith != nil {
+			return map[string]interface{}{}, []error{failWith}
+		}
+		hostname := ""
+		if image, err := genericListers.(configobservation.Listers).ImageConfigLister.Get("cluster"); err == nil {
+			hostname = image.Status.InternalRegistryHostname
+		}
+		return map[string]interface{}{"dockerPullSecret": map[string]interface{}{"internalRegistryHostname": hostname}}, nil
+	}
+	cachingObserve := newCachingObserveConfigFunc(observe, imageConfigInput)
+
+	steps := []struct {
+		name             string
+		change           func()
+		expectedComputed int
+		expectedHostname string
+		expectErr        bool
+	}{
+		{
+			name:             "input missing",
+			expectedComputed: 1,
+		},
+		{
+			name:             "input still missing",
+			expectedComputed: 1,
+		},
+		{
+			name:             "input created",
+			change:           func() { setImageConfig("1", "registry.example.com") },
+			expectedComputed: 2,
+			expectedHostname: "registry.example.com",
+		},
+		{
+			name:             "same resource version",
+			expectedComputed: 2,
+			expectedHostname: "registry.example.com",
+		},
+		{
+			name:             "new resource version",
+			change:           func() { setImageConfig("2", "other.example.com") },
+			expectedComputed: 3,
+			expectedHostname: "other.example.com",
+		},
+		{
+			name: "failing observation",
+			change: func() {
+				setImageConfig("3", "failing.example.com")
+				failWith = fmt.Errorf("failure")
+			},
+			expectedComputed: 4,
+			expectErr:        true,
+		},
+		{
+			name:             "failing observation is not cached",
+			change:           func() { failWith = nil },
+			expectedComputed: 5,
+			expectedHostname: "failing.example.com",
+		},
+	}
+	for _, step := range steps {
+		if step.change != nil {
+			step.change()
+		}
+		observed, errs := cachingObserve(listers, recorder, map[string]interface{}{})
+		if computed != step.expectedComputed {
+			t.Errorf("%s: expected the observer to have computed %d times, got %d", step.name, step.expectedComputed, computed)
+		}
+		if step.expectErr {
+			if len(errs) == 0 {
+				t.Errorf("%s: expected the errors of the observer", step.name)
+			}
+			continue
+		}
+		if len(errs) > 0 {
+			t.Errorf("%s: unexpected errors: %v", step.name, errs)
+		}
+		expected := map[string]interface{}{"dockerPullSecret": map[string]interface{}{"internalRegistryHostname": step.expectedHostname}}
+		if diff := cmp.Diff(expected, observed); len(diff) > 0 {
+			t.Errorf("%s: unexpected observed config (-want +got):\n%s", step.name, diff)
+		}
+		// the merge of the observed configs must not change the cached one
+		observed["dockerPullSecret"].(map[string]interface{})["internalRegistryHostname"] = "modified"
+	}
+}
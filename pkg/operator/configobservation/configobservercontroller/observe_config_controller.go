@@ -1,35 +1,47 @@
 package configobservercontroller
 
 import (
+	"os"
+	"strings"
+
 	"k8s.io/apimachinery/pkg/util/sets"
 	kubeinformers "k8s.io/client-go/informers"
 	"k8s.io/client-go/tools/cache"
+	"k8s.io/utils/clock"
 
 	configv1 "github.com/openshift/api/config/v1"
 	configinformers "github.com/openshift/client-go/config/informers/externalversions"
+	operatorclientv1 "github.com/openshift/client-go/operator/clientset/versioned/typed/operator/v1"
 	operatorv1informers "github.com/openshift/client-go/operator/informers/externalversions"
 	"github.com/openshift/library-go/pkg/controller/factory"
 	"github.com/openshift/library-go/pkg/operator/configobserver"
-	"github.com/openshift/library-go/pkg/operator/configobserver/apiserver"
 	"github.com/openshift/library-go/pkg/operator/configobserver/featuregates"
 	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/resourcesynccontroller"
 	"github.com/openshift/library-go/pkg/operator/v1helpers"
 
 	"github.com/openshift/cluster-openshift-controller-manager-operator/pkg/operator/configobservation"
+	"github.com/openshift/cluster-openshift-controller-manager-operator/pkg/operator/configobservation/apiserver"
 	"github.com/openshift/cluster-openshift-controller-manager-operator/pkg/operator/configobservation/builds"
 	"github.com/openshift/cluster-openshift-controller-manager-operator/pkg/operator/configobservation/controllers"
 	"github.com/openshift/cluster-openshift-controller-manager-operator/pkg/operator/configobservation/deployimages"
 	"github.com/openshift/cluster-openshift-controller-manager-operator/pkg/operator/configobservation/images"
+	"github.com/openshift/cluster-openshift-controller-manager-operator/pkg/operator/configobservation/leaderelection"
 	"github.com/openshift/cluster-openshift-controller-manager-operator/pkg/operator/configobservation/network"
+	"github.com/openshift/cluster-openshift-controller-manager-operator/pkg/operator/configobservation/validation"
+	"github.com/openshift/cluster-openshift-controller-manager-operator/pkg/operator/metrics"
 )
 
 // NewConfigObserver initializes a new configuration observer.
 func NewConfigObserver(
 	operatorClient v1helpers.OperatorClient,
+	operatorConfigClient operatorclientv1.OpenShiftControllerManagersGetter,
+	operatorVersion string,
 	operatorConfigInformers operatorv1informers.SharedInformerFactory,
 	configInformers configinformers.SharedInformerFactory,
 	kubeInformersForOperatorNamespace kubeinformers.SharedInformerFactory,
 	featureGateAccessor featuregates.FeatureGateAccess,
+	resourceSyncer resourcesynccontroller.ResourceSyncer,
 	eventRecorder events.Recorder,
 	buildEnabled bool,
 ) factory.Controller {
@@ -39,6 +51,8 @@ func NewConfigObserver(
 		configInformers.Config().V1().APIServers().Informer().HasSynced,
 		configInformers.Config().V1().ClusterVersions().Informer().HasSynced,
 		configInformers.Config().V1().ClusterOperators().Informer().HasSynced,
+		configInformers.Config().V1().Infrastructures().Informer().HasSynced,
+		configInformers.Config().V1().Proxies().Informer().HasSynced,
 		kubeInformersForOperatorNamespace.Core().V1().ConfigMaps().Informer().HasSynced,
 		operatorConfigInformers.Operator().V1().OpenShiftControllerManagers().Informer().HasSynced,
 	}
@@ -54,28 +68,77 @@ func NewConfigObserver(
 		APIServerLister_:      configInformers.Config().V1().APIServers().Lister(),
 		ClusterVersionLister:  configInformers.Config().V1().ClusterVersions().Lister(),
 		ClusterOperatorLister: configInformers.Config().V1().ClusterOperators().Lister(),
+		InfrastructureLister:  configInformers.Config().V1().Infrastructures().Lister(),
+		ProxyLister:           configInformers.Config().V1().Proxies().Lister(),
 		ConfigMapLister:       kubeInformersForOperatorNamespace.Core().V1().ConfigMaps().Lister(),
+		ResourceSync:          resourceSyncer,
 		PreRunCachesSynced:    informersSynced,
 	}
 
-	observerFuncs := []configobserver.ObserveConfigFunc{
-		images.ObserveInternalRegistryHostname,
-		images.ObserveExternalRegistryHostnames,
-		network.ObserveExternalIPAutoAssignCIDRs,
-		deployimages.ObserveControllerManagerImagesConfig,
-		controllers.ObserveControllers,
-		featuregates.NewObserveFeatureFlagsFunc(
+	if buildEnabled {
+		configObservationListers.BuildConfigLister = configInformers.Config().V1().Builds().Lister()
+	}
+
+	// observers holding back a change requeue the config observer through it once it may be observed
+	requeue := &requeueInformer{}
+
+	// The observers run in the order they are declared in, which keeps the combined observed config stable.
+	// Each observer owns its keys of the observed config and declares them as its paths, see
+	// validation.NewValidatingObserveConfigFunc for how an overlap is resolved. New observers are added to
+	// the group of what they observe.
+	//
+	// The audit profile of the APIServer config is not observed: the controller manager serves no API
+	// requests to audit and OpenShiftControllerManagerConfig has no audit settings to propagate it to.
+	//
+	// Observers reading nothing but cluster config objects declare them as their inputs and are only run
+	// again once any of them changed, see newCachingObserveConfigFunc.
+	//
+	// Experimental observers are gated by a feature flag in the environment of the operator, see
+	// registeredObservers.
+	observers := []observerRegistration{
+		// images
+		{name: "InternalRegistryHostname", observe: images.ObserveInternalRegistryHostname, enabled: true, inputs: []observedInput{imageConfigInput}, paths: []string{"dockerPullSecret.internalRegistryHostname"}},
+		{name: "ExternalRegistryHostnames", observe: images.ObserveExternalRegistryHostnames, enabled: true, inputs: []observedInput{imageConfigInput}, paths: []string{"dockerPullSecret.registryURLs"}},
+		{name: "AdditionalTrustedCA", observe: images.ObserveAdditionalTrustedCA, enabled: true, inputs: []observedInput{imageConfigInput}, paths: []string{"build.additionalTrustedCA"}},
+		// network
+		{name: "ExternalIPAutoAssignCIDRs", observe: network.ObserveExternalIPAutoAssignCIDRs, enabled: true, inputs: []observedInput{networkConfigInput}, paths: []string{"ingress.ingressIPNetworkCIDR"}},
+		// experimental, enabled by OCM_OPERATOR_ENABLE_NETWORK_OBSERVER=true
+		{name: "ClusterNetworks", observe: network.ObserveClusterNetworks, enabled: true, featureFlag: "NETWORK_OBSERVER", inputs: []observedInput{networkConfigInput}, paths: []string{"network.clusterNetworks", "network.serviceNetworkCIDR"}},
+		// controllers
+		{name: "ControllerManagerImagesConfig", observe: deployimages.NewObserveControllerManagerImagesConfigFunc(os.LookupEnv), enabled: true, inputs: []observedInput{controllerManagerImagesInput}, paths: []string{"build.imageTemplateFormat", "deployer.imageTemplateFormat"}},
+		{name: "LeaderElection", observe: leaderelection.ObserveLeaderElection, enabled: true, inputs: []observedInput{infrastructureInput}, paths: []string{"leaderElection"}},
+		{name: "Controllers", observe: controllers.ObserveControllers, enabled: true, inputs: []observedInput{clusterVersionInput, imageRegistryOperatorInput}, paths: []string{"controllers"}},
+		// feature gates
+		{name: "FeatureFlags", observe: featuregates.NewObserveFeatureFlagsFunc(
 			sets.New[configv1.FeatureGateName]("BuildCSIVolumes"),
 			nil,
 			[]string{"featureGates"},
 			featureGateAccessor,
-		),
-		apiserver.ObserveTLSSecurityProfile,
+		), enabled: true, paths: []string{"featureGates"}},
+		// serving
+		{name: "TLSSecurityProfile", observe: apiserver.NewObserveTLSSecurityProfileFunc(operatorClient, configInformers.Config().V1().APIServers().Informer(), requeue.requeueAfter, clock.RealClock{}), enabled: true, paths: []string{"servingInfo.minTLSVersion", "servingInfo.cipherSuites"}},
+		{name: "NamedCertificates", observe: apiserver.ObserveNamedCertificates, enabled: true, paths: []string{"servingInfo.namedCertificates"}},
+		{name: "APIServerEncryption", observe: apiserver.NewObserveEncryptionFunc(operatorClient), enabled: true},
+		// builds
+		{name: "BuildControllerConfig", observe: builds.ObserveBuildControllerConfig, enabled: buildEnabled, inputs: []observedInput{buildConfigInput}, paths: []string{
+			"build.buildDefaults.env", "build.buildDefaults.imageLabels",
+			"build.buildOverrides.imageLabels", "build.buildOverrides.nodeSelector", "build.buildOverrides.tolerations", "build.buildOverrides.forcePull",
+		}},
+		{name: "BuildDefaultResources", observe: builds.NewObserveBuildDefaultResourcesFunc(operatorClient), enabled: buildEnabled, paths: []string{"build.buildDefaults.resources"}},
+		{name: "GitProxy", observe: builds.ObserveGitProxy, enabled: buildEnabled, inputs: []observedInput{buildConfigInput}, paths: []string{"build.buildDefaults.gitHTTPProxy", "build.buildDefaults.gitHTTPSProxy"}},
+		{name: "GitNoProxy", observe: builds.ObserveGitNoProxy, enabled: buildEnabled, inputs: []observedInput{buildConfigInput, proxyConfigInput}, paths: []string{"build.buildDefaults.gitNoProxy"}},
 	}
-
-	if buildEnabled {
-		configObservationListers.BuildConfigLister = configInformers.Config().V1().Builds().Lister()
-		observerFuncs = append(observerFuncs, builds.ObserveBuildControllerConfig)
+	var observerFuncs []configobserver.ObserveConfigFunc
+	var observedPaths [][]string
+	for _, observer := range registeredObservers(observers, os.LookupEnv) {
+		for _, path := range observer.paths {
+			observedPaths = append(observedPaths, strings.Split(path, "."))
+		}
+		observe := observer.observe
+		if len(observer.inputs) > 0 {
+			observe = newCachingObserveConfigFunc(observe, observer.inputs...)
+		}
+		observerFuncs = append(observerFuncs, metrics.InstrumentObserveConfigFunc(observer.name, observe))
 	}
 
 	c := configobserver.NewConfigObserver(
@@ -83,8 +146,13 @@ func NewConfigObserver(
 		operatorClient,
 		eventRecorder,
 		configObservationListers,
-		[]factory.Informer{operatorConfigInformers.Operator().V1().OpenShiftControllerManagers().Informer()},
-		observerFuncs...,
+		[]factory.Informer{operatorConfigInformers.Operator().V1().OpenShiftControllerManagers().Informer(), requeue},
+		// the combined config of all observers is validated before it is written, it is pruned to the paths
+		// of the observers after an operator upgrade to drop keys no observer writes anymore, and its recent
+		// changes are recorded in the operator config
+		newHistoryRecordingObserveConfigFunc(operatorClient, operatorConfigClient, observedConfigHistoryLength,
+			newStaleConfigPruningObserveConfigFunc(operatorClient, operatorConfigClient, operatorVersion, observedPaths,
+				validation.NewValidatingObserveConfigFunc(operatorClient, observerFuncs...))),
 	)
 
 	return c
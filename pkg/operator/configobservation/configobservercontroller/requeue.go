@@ -0,0 +1,42 @@
+package configobservercontroller
+
+import (
+	"sync"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+// requeueInformer is an informer without objects of its own, which lets observers run the config observer
+// again after a while, e.g. once a change they hold back may be observed. The config observer receives no
+// sync context, it is registered as one of its informers instead and enqueues the controller like for an
+// added object.
+type requeueInformer struct {
+	lock     sync.Mutex
+	handlers []cache.ResourceEventHandler
+}
+
+func (i *requeueInformer) AddEventHandler(handler cache.ResourceEventHandler) (cache.ResourceEventHandlerRegistration, error) {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+	i.handlers = append(i.handlers, handler)
+	return nil, nil
+}
+
+func (i *requeueInformer) HasSynced() bool {
+	return true
+}
+
+// requeueAfter enqueues the controller once the delay passed.
+func (i *requeueInformer) requeueAfter(delay time.Duration) {
+	time.AfterFunc(delay, i.requeue)
+}
+
+func (i *requeueInformer) requeue() {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+	for _, handler := range i.handlers {
+		handler.OnAdd(&metav1.PartialObjectMetadata{}, false)
+	}
+}
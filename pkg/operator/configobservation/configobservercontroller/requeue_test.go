@@ -0,0 +1,29 @@
+package configobservercontroller
+
+import (
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/cache"
+)
+
+func TestRequeueInformer(t *testing.T) {
+	informer := &requeueInformer{}
+	requeued := make(chan struct{}, 1)
+	if _, err := informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(interface{}) { requeued <- struct{}{} },
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if !informer.HasSynced() {
+		t.Fatal("expected the informer to be synced right away")
+	}
+
+	informer.requeueAfter(10 * time.Millisecond)
+	select {
+	case <-requeued:
+	case <-time.After(wait.ForeverTestTimeout):
+		t.Fatal("expected the controller to be requeued after the delay")
+	}
+}
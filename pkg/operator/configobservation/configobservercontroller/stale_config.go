@@ -0,0 +1,95 @@
+package configobservercontroller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+
+	operatorclientv1 "github.com/openshift/client-go/operator/clientset/versioned/typed/operator/v1"
+	"github.com/openshift/library-go/pkg/operator/configobserver"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+)
+
+// observedConfigVersionAnnotation is the operator version the observed config of the operator config was
+// last pruned for.
+const observedConfigVersionAnnotation = "openshift-controller-manager.operator.openshift.io/observed-config-version"
+
+// newStaleConfigPruningObserveConfigFunc returns an observer which prunes the observed config to the paths
+// of the current observers after the operator version changed, so that keys they no longer write do not
+// linger from an older schema. Observers carry values of the existing config over, e.g. when they fail, when
+// their source is briefly missing or when the combined config is rejected, so stale keys are not dropped by
+// observing alone. The observers run once against the existing config either way, which keeps their
+// previous values and runs their side effects once per sync.
+//
+// The version is recorded in the observedConfigVersionAnnotation of the operator config only once the
+// pruned config has been written and observed without errors, until then it is pruned on every sync.
+func newStaleConfigPruningObserveConfigFunc(operatorClient v1helpers.OperatorClient, operatorConfigClient operatorclientv1.OpenShiftControllerManagersGetter, version string, paths [][]string, observe configobserver.ObserveConfigFunc) configobserver.ObserveConfigFunc {
+	return func(listers configobserver.Listers, recorder events.Recorder, existingConfig map[string]interface{}) (map[string]interface{}, []error) {
+		observedConfig, errs := observe(listers, recorder, existingConfig)
+		if len(version) == 0 || len(paths) == 0 {
+			return observedConfig, errs
+		}
+		meta, err := operatorClient.GetObjectMeta()
+		if err != nil {
+			return observedConfig, append(errs, err)
+		}
+		if meta.Annotations[observedConfigVersionAnnotation] == version {
+			return observedConfig, errs
+		}
+
+		prunedConfig := configobserver.Pruned(observedConfig, paths...)
+		if stale := staleKeys(observedConfig, prunedConfig, nil); len(stale) > 0 {
+			klog.Infof("Pruning keys of the observed config no longer observed for version %s: %s", version, strings.Join(stale, ", "))
+			recorder.Eventf("ObservedConfigPruned", "Pruned keys of the observed config no longer observed for version %s: %s", version, strings.Join(stale, ", "))
+		}
+		if len(errs) == 0 && equality.Semantic.DeepEqual(existingConfig, prunedConfig) {
+			if err := setObservedConfigVersion(context.TODO(), operatorConfigClient, version); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		return prunedConfig, errs
+	}
+}
+
+// staleKeys returns the sorted dotted paths of the values of existing which are not in observed.
+func staleKeys(existing, observed map[string]interface{}, prefix []string) []string {
+	var stale []string
+	for key, value := range existing {
+		path := append(append([]string{}, prefix...), key)
+		observedValue, ok := observed[key]
+		if !ok {
+			stale = append(stale, strings.Join(path, "."))
+			continue
+		}
+		nested, isMap := value.(map[string]interface{})
+		observedNested, observedIsMap := observedValue.(map[string]interface{})
+		if isMap && observedIsMap {
+			stale = append(stale, staleKeys(nested, observedNested, path)...)
+		}
+	}
+	sort.Strings(stale)
+	return stale
+}
+
+func setObservedConfigVersion(ctx context.Context, client operatorclientv1.OpenShiftControllerManagersGetter, version string) error {
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]string{observedConfigVersionAnnotation: version},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	if _, err := client.OpenShiftControllerManagers().Patch(ctx, "cluster", types.MergePatchType, patch, metav1.PatchOptions{}); err != nil {
+		return fmt.Errorf("failed to record the observed config version %s: %w", version, err)
+	}
+	return nil
+}
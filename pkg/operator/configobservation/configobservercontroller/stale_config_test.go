@@ -0,0 +1,197 @@
+package configobservercontroller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/clock"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	operatorfake "github.com/openshift/client-go/operator/clientset/versioned/fake"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/configobserver"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-openshift-controller-manager-operator/pkg/operator/configobservation"
+)
+
+func TestStaleConfigPruningObserveConfigFunc(t *testing.T) {
+	existingConfig := map[string]interface{}{
+		"controllers": []interface{}{"*"},
+		"build": map[string]interface{}{
+			"additionalTrustedCA": "/ca.crt",
+			// not produced by any current observer
+			"buildDefaults": map[string]interface{}{"removedKey": "stale"},
+		},
+		"removedSection": map[string]interface{}{"key": "stale"},
+	}
+	observe := func(_ configobserver.Listers, _ events.Recorder, existing map[string]interface{}) (map[string]interface{}, []error) {
+		observed := map[string]interface{}{"controllers": []interface{}{"*"}}
+		if build, ok := existing["build"].(map[string]interface{}); ok {
+			// the observer keeps the previous value while its source is missing, like the CA observer does
+			if ca, ok := build["additionalTrustedCA"]; ok {
+				observed["build"] = map[string]interface{}{"additionalTrustedCA": ca}
+			}
+			// and carries over a key no current observer writes, like a rejected combined config does
+			if defaults, ok := build["buildDefaults"]; ok {
+				observed["build"].(map[string]interface{})["buildDefaults"] = defaults
+			}
+		}
+		return observed, nil
+	}
+	paths := [][]string{{"controllers"}, {"build", "additionalTrustedCA"}}
+	validConfig := map[string]interface{}{
+		"controllers": []interface{}{"*"},
+		"build":       map[string]interface{}{"additionalTrustedCA": "/ca.crt"},
+	}
+
+	tests := []struct {
+		name            string
+		recordedVersion string
+		version         string
+		observeErr      error
+		expectedConfig  map[string]interface{}
+		expectRecorded  bool
+	}{
+		{
+			name:            "version changed",
+			recordedVersion: "4.19.0",
+			version:         "4.20.0",
+			expectedConfig:  validConfig,
+			expectRecorded:  true,
+		},
+		{
+			name:           "never pruned",
+			version:        "4.20.0",
+			expectedConfig: validConfig,
+			expectRecorded: true,
+		},
+		{
+			name:            "observer failing",
+			recordedVersion: "4.19.0",
+			version:         "4.20.0",
+			observeErr:      fmt.Errorf("failed"),
+			expectedConfig:  validConfig,
+		},
+		{
+			name:            "version unchanged",
+			recordedVersion: "4.20.0",
+			version:         "4.20.0",
+			expectedConfig: map[string]interface{}{
+				"controllers": []interface{}{"*"},
+				"build": map[string]interface{}{
+					"additionalTrustedCA": "/ca.crt",
+					"buildDefaults":       map[string]interface{}{"removedKey": "stale"},
+				},
+			},
+		},
+		{
+			name:            "version unknown",
+			recordedVersion: "4.19.0",
+			expectedConfig: map[string]interface{}{
+				"controllers": []interface{}{"*"},
+				"build": map[string]interface{}{
+					"additionalTrustedCA": "/ca.crt",
+					"buildDefaults":       map[string]interface{}{"removedKey": "stale"},
+				},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			raw, err := json.Marshal(existingConfig)
+			if err != nil {
+				t.Fatal(err)
+			}
+			meta := &metav1.ObjectMeta{Name: "cluster"}
+			if len(tc.recordedVersion) > 0 {
+				meta.Annotations = map[string]string{observedConfigVersionAnnotation: tc.recordedVersion}
+			}
+			operatorClient := v1helpers.NewFakeOperatorClientWithObjectMeta(
+				meta,
+				&operatorv1.OperatorSpec{ManagementState: operatorv1.Managed, ObservedConfig: runtime.RawExtension{Raw: raw}},
+				&operatorv1.OperatorStatus{},
+				nil,
+			)
+			operatorConfigClient := operatorfake.NewSimpleClientset(&operatorv1.OpenShiftControllerManager{ObjectMeta: *meta})
+			recorder := events.NewInMemoryRecorder("", clock.RealClock{})
+			calls := 0
+			countingObserve := func(listers configobserver.Listers, recorder events.Recorder, existing map[string]interface{}) (map[string]interface{}, []error) {
+				calls++
+				observed, errs := observe(listers, recorder, existing)
+				if tc.observeErr != nil {
+					errs = append(errs, tc.observeErr)
+				}
+				return observed, errs
+			}
+			observer := configobserver.NewConfigObserver("test", operatorClient, recorder, configobservation.Listers{}, nil,
+				newStaleConfigPruningObserveConfigFunc(operatorClient, operatorConfigClient.OperatorV1(), tc.version, paths, countingObserve))
+
+			// the first sync writes the pruned config, the second one finds it written
+			var observedConfig map[string]interface{}
+			for i := 0; i < 2; i++ {
+				if err := observer.Sync(context.TODO(), factory.NewSyncContext("test", recorder)); err != nil && tc.observeErr == nil {
+					t.Fatal(err)
+				}
+				// the observers run once per sync, their side effects must not be repeated
+				if calls != i+1 {
+					t.Fatalf("expected the observers to run %d times, got %d", i+1, calls)
+				}
+				// the fake keeps the written config as an object, serialize it like the API server does
+				spec, _, resourceVersion, err := operatorClient.GetOperatorState()
+				if err != nil {
+					t.Fatal(err)
+				}
+				if written, ok := spec.ObservedConfig.Object.(*unstructured.Unstructured); ok {
+					observedConfig = written.Object
+					if spec.ObservedConfig.Raw, err = json.Marshal(observedConfig); err != nil {
+						t.Fatal(err)
+					}
+					spec.ObservedConfig.Object = nil
+					if _, _, err := operatorClient.UpdateOperatorSpec(context.TODO(), resourceVersion, spec); err != nil {
+						t.Fatal(err)
+					}
+				}
+			}
+			if diff := cmp.Diff(tc.expectedConfig, observedConfig); len(diff) > 0 {
+				t.Errorf("unexpected observed config (-want +got):\n%s", diff)
+			}
+
+			operatorConfig, err := operatorConfigClient.OperatorV1().OpenShiftControllerManagers().Get(context.TODO(), "cluster", metav1.GetOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			recorded := operatorConfig.Annotations[observedConfigVersionAnnotation] == tc.version && tc.version != tc.recordedVersion
+			if recorded != tc.expectRecorded {
+				t.Errorf("expected the version to be recorded %t, got the annotations %v", tc.expectRecorded, operatorConfig.Annotations)
+			}
+		})
+	}
+}
+
+func TestStaleKeys(t *testing.T) {
+	existing := map[string]interface{}{
+		"build": map[string]interface{}{
+			"additionalTrustedCA": "/ca.crt",
+			"buildDefaults":       map[string]interface{}{"removedKey": "stale"},
+		},
+		"removedSection": map[string]interface{}{"key": "stale"},
+		"controllers":    []interface{}{"*"},
+	}
+	observed := map[string]interface{}{
+		"build":       map[string]interface{}{"additionalTrustedCA": "/other.crt", "buildDefaults": map[string]interface{}{}},
+		"controllers": []interface{}{"-openshift.io/build"},
+	}
+	expected := []string{"build.buildDefaults.removedKey", "removedSection"}
+	if diff := cmp.Diff(expected, staleKeys(existing, observed, nil)); len(diff) > 0 {
+		t.Errorf("unexpected stale keys (-want +got):\n%s", diff)
+	}
+}
@@ -1,6 +1,9 @@
 package deployimages
 
 import (
+	"fmt"
+	"regexp"
+
 	"k8s.io/klog/v2"
 
 	"k8s.io/apimachinery/pkg/api/errors"
@@ -12,7 +15,40 @@ import (
 	"github.com/openshift/library-go/pkg/operator/events"
 )
 
+const (
+	// BuilderImageEnv overrides the builder image of the openshift-controller-manager-images configmap.
+	BuilderImageEnv = "BUILDER_IMAGE"
+	// DeployerImageEnv overrides the deployer image of the openshift-controller-manager-images configmap.
+	DeployerImageEnv = "DEPLOYER_IMAGE"
+)
+
+// imageReferenceRegexp matches an image pull spec: an optional registry host with an optional port, a
+// lowercase repository path, an optional tag and an optional digest.
+var imageReferenceRegexp = regexp.MustCompile(`^` +
+	`(?:(?:[a-zA-Z0-9]|[a-zA-Z0-9][a-zA-Z0-9-]*[a-zA-Z0-9])(?:\.(?:[a-zA-Z0-9]|[a-zA-Z0-9][a-zA-Z0-9-]*[a-zA-Z0-9]))*(?::[0-9]+)?/)?` +
+	`[a-z0-9]+(?:(?:[._]|__|-+)[a-z0-9]+)*(?:/[a-z0-9]+(?:(?:[._]|__|-+)[a-z0-9]+)*)*` +
+	`(?::[a-zA-Z0-9_][a-zA-Z0-9_.-]{0,127})?` +
+	`(?:@[A-Za-z][A-Za-z0-9]*(?:[-_+.][A-Za-z][A-Za-z0-9]*)*:[0-9a-fA-F]{32,})?` +
+	`$`)
+
+// ObserveControllerManagerImagesConfig observes the builder and deployer images of the
+// openshift-controller-manager-images configmap.
 func ObserveControllerManagerImagesConfig(genericListers configobserver.Listers, recorder events.Recorder, existingConfig map[string]interface{}) (map[string]interface{}, []error) {
+	return observeControllerManagerImagesConfig(genericListers, existingConfig, func(string) (string, bool) { return "", false })
+}
+
+// NewObserveControllerManagerImagesConfigFunc returns an observer of the builder and deployer images of
+// the openshift-controller-manager-images configmap which the BUILDER_IMAGE and DEPLOYER_IMAGE variables
+// of the operator environment, looked up with lookupEnv, override. Dev and test clusters use them to run
+// their own images. An override that is not an image pull spec is an error, the previously observed
+// images are kept.
+func NewObserveControllerManagerImagesConfigFunc(lookupEnv func(key string) (string, bool)) configobserver.ObserveConfigFunc {
+	return func(genericListers configobserver.Listers, recorder events.Recorder, existingConfig map[string]interface{}) (map[string]interface{}, []error) {
+		return observeControllerManagerImagesConfig(genericListers, existingConfig, lookupEnv)
+	}
+}
+
+func observeControllerManagerImagesConfig(genericListers configobserver.Listers, existingConfig map[string]interface{}, lookupEnv func(key string) (string, bool)) (map[string]interface{}, []error) {
 	listers := genericListers.(configobservation.Listers)
 	var errs []error
 	prevObservedConfig := map[string]interface{}{}
@@ -43,12 +79,32 @@ func ObserveControllerManagerImagesConfig(genericListers configobserver.Listers,
 		}
 	}
 
+	// the overrides are checked first, a malformed one must not replace the images with the defaults
+	overrides := map[string]string{}
+	for _, override := range []struct{ path, env string }{
+		{path: "build.imageTemplateFormat.format", env: BuilderImageEnv},
+		{path: "deployer.imageTemplateFormat.format", env: DeployerImageEnv},
+	} {
+		image, ok := lookupEnv(override.env)
+		if !ok || len(image) == 0 {
+			continue
+		}
+		if !imageReferenceRegexp.MatchString(image) {
+			errs = append(errs, fmt.Errorf("%s=%q is not a valid image reference", override.env, image))
+			continue
+		}
+		overrides[override.path] = image
+	}
+	if len(errs) > 0 {
+		return prevObservedConfig, errs
+	}
+
 	// now gather the cluster config and turn it into the observed config
 	observedConfig := map[string]interface{}{}
 	controllerManagerImagesConfigMap, err := listers.ConfigMapLister.ConfigMaps(util.OperatorNamespace).Get("openshift-controller-manager-images")
 	if errors.IsNotFound(err) {
 		klog.V(2).Infof("configmap/openshift-controller-manager-images: not found")
-		return observedConfig, errs
+		controllerManagerImagesConfigMap, err = nil, nil
 	}
 	if err != nil {
 		return prevObservedConfig, append(errs, err)
@@ -62,6 +118,11 @@ func ObserveControllerManagerImagesConfig(genericListers configobserver.Listers,
 			return nil, append(errs, err)
 		}
 	}
+	for path, image := range overrides {
+		if err = configobservation.ObserveField(observedConfig, image, path, true); err != nil {
+			return nil, append(errs, err)
+		}
+	}
 
 	return observedConfig, errs
 }
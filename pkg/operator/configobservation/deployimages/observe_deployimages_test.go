@@ -116,3 +116,90 @@ func TestObserveClusterConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestObserveImageOverrides(t *testing.T) {
+	imagesConfigMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "openshift-controller-manager-images",
+			Namespace: util.OperatorNamespace,
+		},
+		Data: map[string]string{
+			"builderImage":  "quay.io/sample/origin-builder:v4.0",
+			"deployerImage": "quay.io/sample/origin-deployer:v4.0",
+		},
+	}
+	imagesConfig := func(builderImage, deployerImage string) map[string]interface{} {
+		return map[string]interface{}{
+			"build": map[string]interface{}{
+				"imageTemplateFormat": map[string]interface{}{"format": builderImage},
+			},
+			"deployer": map[string]interface{}{
+				"imageTemplateFormat": map[string]interface{}{"format": deployerImage},
+			},
+		}
+	}
+
+	tests := []struct {
+		name        string
+		cm          *corev1.ConfigMap
+		env         map[string]string
+		existing    map[string]interface{}
+		expect      map[string]interface{}
+		expectError bool
+	}{
+		{
+			name:   "override takes precedence over the configmap",
+			cm:     imagesConfigMap,
+			env:    map[string]string{BuilderImageEnv: "registry.example.com:5000/dev/builder@sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"},
+			expect: imagesConfig("registry.example.com:5000/dev/builder@sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef", "quay.io/sample/origin-deployer:v4.0"),
+		},
+		{
+			name:   "override without configmap",
+			env:    map[string]string{BuilderImageEnv: "quay.io/dev/builder:latest", DeployerImageEnv: "quay.io/dev/deployer:latest"},
+			expect: imagesConfig("quay.io/dev/builder:latest", "quay.io/dev/deployer:latest"),
+		},
+		{
+			name:   "no override keeps the configmap images",
+			cm:     imagesConfigMap,
+			env:    map[string]string{BuilderImageEnv: ""},
+			expect: imagesConfig("quay.io/sample/origin-builder:v4.0", "quay.io/sample/origin-deployer:v4.0"),
+		},
+		{
+			name:        "malformed override is rejected and keeps the previous images",
+			cm:          imagesConfigMap,
+			env:         map[string]string{DeployerImageEnv: "quay.io/Dev/deployer:not a tag"},
+			existing:    imagesConfig("quay.io/previous/builder:v1", "quay.io/previous/deployer:v1"),
+			expect:      imagesConfig("quay.io/previous/builder:v1", "quay.io/previous/deployer:v1"),
+			expectError: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+			if tc.cm != nil {
+				indexer.Add(tc.cm)
+			}
+			listers := configobservation.Listers{
+				ConfigMapLister: corelistersv1.NewConfigMapLister(indexer),
+			}
+			lookupEnv := func(key string) (string, bool) {
+				value, ok := tc.env[key]
+				return value, ok
+			}
+			existing := tc.existing
+			if existing == nil {
+				existing = map[string]interface{}{}
+			}
+
+			observe := NewObserveControllerManagerImagesConfigFunc(lookupEnv)
+			result, errs := observe(listers, events.NewInMemoryRecorder("", clock.RealClock{}), existing)
+			if tc.expectError != (len(errs) > 0) {
+				t.Fatalf("expected error %v, got %v", tc.expectError, errs)
+			}
+			if !reflect.DeepEqual(result, tc.expect) {
+				t.Errorf("expected %v, but got %v", tc.expect, result)
+			}
+		})
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/openshift/library-go/pkg/operator/events"
 
 	"github.com/openshift/cluster-openshift-controller-manager-operator/pkg/operator/configobservation"
+	"github.com/openshift/cluster-openshift-controller-manager-operator/pkg/util"
 )
 
 func ObserveInternalRegistryHostname(genericListers configobserver.Listers, recorder events.Recorder, existingConfig map[string]interface{}) (map[string]interface{}, []error) {
@@ -99,3 +100,45 @@ func ObserveExternalRegistryHostnames(
 	}
 	return observedConfig, errs
 }
+
+// ObserveAdditionalTrustedCA points build.additionalTrustedCA, read by the build controller for the builds
+// pushing and pulling images, to the bundle of additional trusted CAs when the cluster's Image config
+// references any, so that registries behind a custom CA, e.g. the internal registry exposed over a custom
+// route, can be pulled from. The bundle itself is synced into the operand namespace by the user CA
+// observation controller.
+func ObserveAdditionalTrustedCA(genericListers configobserver.Listers, recorder events.Recorder, existingConfig map[string]interface{}) (map[string]interface{}, []error) {
+	listers := genericListers.(configobservation.Listers)
+	var errs []error
+	prevObservedConfig := map[string]interface{}{}
+
+	// first observe all the existing config values so that if we get any errors
+	// we can at least return those.
+	cfgpath := []string{"build", "additionalTrustedCA"}
+	currentAdditionalTrustedCA, _, err := unstructured.NestedString(existingConfig, cfgpath...)
+	if err != nil {
+		return prevObservedConfig, append(errs, err)
+	}
+	if len(currentAdditionalTrustedCA) > 0 {
+		if err := unstructured.SetNestedField(prevObservedConfig, currentAdditionalTrustedCA, cfgpath...); err != nil {
+			return prevObservedConfig, append(errs, err)
+		}
+	}
+
+	observedConfig := map[string]interface{}{}
+	configImage, err := listers.ImageConfigLister.Get("cluster")
+	if errors.IsNotFound(err) {
+		klog.V(2).Infof("images.config.openshift.io/cluster: not found")
+		return observedConfig, errs
+	}
+	if err != nil {
+		return prevObservedConfig, append(errs, err)
+	}
+
+	if len(configImage.Spec.AdditionalTrustedCA.Name) == 0 {
+		return observedConfig, errs
+	}
+	if err := unstructured.SetNestedField(observedConfig, util.AdditionalTrustedCAFile, cfgpath...); err != nil {
+		return prevObservedConfig, append(errs, err)
+	}
+	return observedConfig, errs
+}
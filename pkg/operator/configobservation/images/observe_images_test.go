@@ -223,3 +223,57 @@ func TestObserveRegistryExternalHostnames(t *testing.T) {
 		})
 	}
 }
+
+func TestObserveAdditionalTrustedCA(t *testing.T) {
+	tests := []struct {
+		name        string
+		imageConfig *configv1.Image
+		expected    string
+	}{
+		{
+			name: "not found",
+		},
+		{
+			name: "no additional trusted CA",
+			imageConfig: &configv1.Image{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+			},
+		},
+		{
+			name: "additional trusted CA",
+			imageConfig: &configv1.Image{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+				Spec: configv1.ImageSpec{
+					AdditionalTrustedCA: configv1.ConfigMapNameReference{Name: "registry-cas"},
+				},
+			},
+			expected: "/var/run/configmaps/additional-trusted-ca/ca-bundle.crt",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+			if tc.imageConfig != nil {
+				indexer.Add(tc.imageConfig)
+			}
+			listers := configobservation.Listers{
+				ImageConfigLister: configlistersv1.NewImageLister(indexer),
+			}
+			existingConfig := map[string]interface{}{
+				"build": map[string]interface{}{"additionalTrustedCA": "/stale"},
+			}
+
+			result, errs := ObserveAdditionalTrustedCA(listers, events.NewInMemoryRecorder("", clock.RealClock{}), existingConfig)
+			if len(errs) != 0 {
+				t.Errorf("expected no errors: %v", errs)
+			}
+			additionalTrustedCA, _, err := unstructured.NestedString(result, "build", "additionalTrustedCA")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if additionalTrustedCA != tc.expected {
+				t.Errorf("expected build.additionalTrustedCA %q, got %q", tc.expected, additionalTrustedCA)
+			}
+		})
+	}
+}
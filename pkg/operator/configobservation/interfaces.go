@@ -17,11 +17,14 @@ type Listers struct {
 	APIServerLister_      configlistersv1.APIServerLister
 	ClusterVersionLister  configlistersv1.ClusterVersionLister
 	ClusterOperatorLister configlistersv1.ClusterOperatorLister
+	InfrastructureLister  configlistersv1.InfrastructureLister
+	ProxyLister           configlistersv1.ProxyLister
+	ResourceSync          resourcesynccontroller.ResourceSyncer
 	PreRunCachesSynced    []cache.InformerSynced
 }
 
 func (l Listers) ResourceSyncer() resourcesynccontroller.ResourceSyncer {
-	return nil
+	return l.ResourceSync
 }
 
 func (l Listers) PreRunHasSynced() []cache.InformerSynced {
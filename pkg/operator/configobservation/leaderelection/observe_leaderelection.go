@@ -0,0 +1,101 @@
+package leaderelection
+
+import (
+	"time"
+
+	"k8s.io/klog/v2"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	configv1 "github.com/openshift/api/config/v1"
+	"github.com/openshift/library-go/pkg/operator/configobserver"
+	"github.com/openshift/library-go/pkg/operator/events"
+
+	"github.com/openshift/cluster-openshift-controller-manager-operator/pkg/operator/configobservation"
+)
+
+// The timings below follow the formulas documented in library-go's
+// leaderelection.LeaderElectionDefaulting and leaderelection.LeaderElectionSNOConfig.
+var (
+	// highlyAvailableTimings tolerate 60s of kube-apiserver disruption without losing the lease:
+	// 137s-107s=30s of clock skew, 107s/26s=4 renew retries and a worst graceful lease
+	// acquisition of 26s, which keeps failover between replicas quick.
+	highlyAvailableTimings = leaderElectionTimings{
+		leaseDuration: 137 * time.Second,
+		renewDeadline: 107 * time.Second,
+		retryPeriod:   26 * time.Second,
+	}
+	// singleReplicaTimings tolerate 180s of kube-apiserver disruption. With a single control plane
+	// node there is no other replica to fail over to, so losing the lease during a kubelet or
+	// kube-apiserver hiccup only restarts the operand. We keep 270s-240s=30s of clock skew and
+	// 240s/60s=4 renew retries while reducing the calls made against the kube-apiserver.
+	singleReplicaTimings = leaderElectionTimings{
+		leaseDuration: 270 * time.Second,
+		renewDeadline: 240 * time.Second,
+		retryPeriod:   60 * time.Second,
+	}
+)
+
+type leaderElectionTimings struct {
+	leaseDuration time.Duration
+	renewDeadline time.Duration
+	retryPeriod   time.Duration
+}
+
+var (
+	leaseDurationPath = []string{"leaderElection", "leaseDuration"}
+	renewDeadlinePath = []string{"leaderElection", "renewDeadline"}
+	retryPeriodPath   = []string{"leaderElection", "retryPeriod"}
+)
+
+// ObserveLeaderElection reads the control plane topology from infrastructures.config.openshift.io/cluster
+// and sets the leader election timings of the openshift-controller-manager accordingly.
+// SingleReplica topologies get relaxed timings, every other topology keeps the HA defaults.
+func ObserveLeaderElection(genericListers configobserver.Listers, recorder events.Recorder, existingConfig map[string]interface{}) (map[string]interface{}, []error) {
+	listers := genericListers.(configobservation.Listers)
+	var errs []error
+	prevObservedConfig := map[string]interface{}{}
+
+	// first observe all the existing config values so that if we get any errors
+	// we can at least return those.
+	for _, path := range [][]string{leaseDurationPath, renewDeadlinePath, retryPeriodPath} {
+		current, _, err := unstructured.NestedString(existingConfig, path...)
+		if err != nil {
+			return prevObservedConfig, append(errs, err)
+		}
+		if len(current) > 0 {
+			if err := unstructured.SetNestedField(prevObservedConfig, current, path...); err != nil {
+				return prevObservedConfig, append(errs, err)
+			}
+		}
+	}
+
+	// now gather the cluster config and turn it into the observed config
+	observedConfig := map[string]interface{}{}
+	infra, err := listers.InfrastructureLister.Get("cluster")
+	if errors.IsNotFound(err) {
+		klog.V(2).Infof("infrastructures.config.openshift.io/cluster: not found")
+		return observedConfig, errs
+	}
+	if err != nil {
+		return prevObservedConfig, append(errs, err)
+	}
+
+	timings := highlyAvailableTimings
+	if infra.Status.ControlPlaneTopology == configv1.SingleReplicaTopologyMode {
+		timings = singleReplicaTimings
+	}
+
+	if err := unstructured.SetNestedField(observedConfig, timings.leaseDuration.String(), leaseDurationPath...); err != nil {
+		return prevObservedConfig, append(errs, err)
+	}
+	if err := unstructured.SetNestedField(observedConfig, timings.renewDeadline.String(), renewDeadlinePath...); err != nil {
+		return prevObservedConfig, append(errs, err)
+	}
+	if err := unstructured.SetNestedField(observedConfig, timings.retryPeriod.String(), retryPeriodPath...); err != nil {
+		return prevObservedConfig, append(errs, err)
+	}
+
+	return observedConfig, errs
+}
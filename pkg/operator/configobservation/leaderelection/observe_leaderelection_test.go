@@ -0,0 +1,111 @@
+package leaderelection
+
+import (
+	"reflect"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/utils/clock"
+
+	configv1 "github.com/openshift/api/config/v1"
+	configlistersv1 "github.com/openshift/client-go/config/listers/config/v1"
+	"github.com/openshift/library-go/pkg/operator/events"
+
+	"github.com/openshift/cluster-openshift-controller-manager-operator/pkg/operator/configobservation"
+)
+
+func TestObserveLeaderElection(t *testing.T) {
+	tests := []struct {
+		name           string
+		infra          *configv1.Infrastructure
+		existingConfig map[string]interface{}
+		expected       map[string]interface{}
+	}{
+		{
+			name:     "no infrastructure config",
+			expected: map[string]interface{}{},
+		},
+		{
+			name: "highly available topology",
+			infra: &configv1.Infrastructure{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+				Status: configv1.InfrastructureStatus{
+					ControlPlaneTopology: configv1.HighlyAvailableTopologyMode,
+				},
+			},
+			expected: map[string]interface{}{
+				"leaderElection": map[string]interface{}{
+					"leaseDuration": "2m17s",
+					"renewDeadline": "1m47s",
+					"retryPeriod":   "26s",
+				},
+			},
+		},
+		{
+			name: "single replica topology",
+			infra: &configv1.Infrastructure{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+				Status: configv1.InfrastructureStatus{
+					ControlPlaneTopology: configv1.SingleReplicaTopologyMode,
+				},
+			},
+			expected: map[string]interface{}{
+				"leaderElection": map[string]interface{}{
+					"leaseDuration": "4m30s",
+					"renewDeadline": "4m0s",
+					"retryPeriod":   "1m0s",
+				},
+			},
+		},
+		{
+			name: "single replica topology replaces previously observed HA timings",
+			infra: &configv1.Infrastructure{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+				Status: configv1.InfrastructureStatus{
+					ControlPlaneTopology: configv1.SingleReplicaTopologyMode,
+				},
+			},
+			existingConfig: map[string]interface{}{
+				"leaderElection": map[string]interface{}{
+					"leaseDuration": "2m17s",
+					"renewDeadline": "1m47s",
+					"retryPeriod":   "26s",
+				},
+			},
+			expected: map[string]interface{}{
+				"leaderElection": map[string]interface{}{
+					"leaseDuration": "4m30s",
+					"renewDeadline": "4m0s",
+					"retryPeriod":   "1m0s",
+				},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+			if tc.infra != nil {
+				if err := indexer.Add(tc.infra); err != nil {
+					t.Fatal(err)
+				}
+			}
+			listers := configobservation.Listers{
+				InfrastructureLister: configlistersv1.NewInfrastructureLister(indexer),
+			}
+			existingConfig := tc.existingConfig
+			if existingConfig == nil {
+				existingConfig = map[string]interface{}{}
+			}
+
+			result, errs := ObserveLeaderElection(listers, events.NewInMemoryRecorder("", clock.RealClock{}), existingConfig)
+			if len(errs) != 0 {
+				t.Fatalf("unexpected errors: %v", errs)
+			}
+			if !reflect.DeepEqual(result, tc.expected) {
+				t.Errorf("expected %v, got %v", tc.expected, result)
+			}
+		})
+	}
+}
@@ -2,8 +2,12 @@ package network
 
 import (
 	"fmt"
+	"net"
+	"strings"
 
+	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/klog/v2"
 
 	configv1 "github.com/openshift/api/config/v1"
 	"github.com/openshift/library-go/pkg/operator/configobserver"
@@ -51,3 +55,87 @@ func ObserveExternalIPAutoAssignCIDRs(genericListers configobserver.Listers, rec
 	}
 	return out, nil
 }
+
+var (
+	clusterNetworksPath    = []string{"network", "clusterNetworks"}
+	serviceNetworkCIDRPath = []string{"network", "serviceNetworkCIDR"}
+)
+
+// ObserveClusterNetworks watches the config.openshift.io/v1/Network.Status.ClusterNetwork and
+// Status.ServiceNetwork fields and configures the pod and service network CIDRs of the
+// openshift-controller-manager accordingly. On dual-stack clusters the order of the CIDRs is
+// preserved, multiple service networks are joined by commas.
+// The network status is populated by the network operator during bootstrap. Until then, the
+// previously observed values are kept.
+func ObserveClusterNetworks(genericListers configobserver.Listers, recorder events.Recorder, existingConfig map[string]interface{}) (map[string]interface{}, []error) {
+	listers := genericListers.(configobservation.Listers)
+	prevObservedConfig := map[string]interface{}{}
+
+	// Preserve the existing values, so we can return them if we encounter an error
+	currentClusterNetworks, _, err := unstructured.NestedSlice(existingConfig, clusterNetworksPath...)
+	if err != nil {
+		return prevObservedConfig, []error{err}
+	}
+	if len(currentClusterNetworks) > 0 {
+		if err := unstructured.SetNestedSlice(prevObservedConfig, currentClusterNetworks, clusterNetworksPath...); err != nil {
+			return prevObservedConfig, []error{err}
+		}
+	}
+	currentServiceNetworkCIDR, _, err := unstructured.NestedString(existingConfig, serviceNetworkCIDRPath...)
+	if err != nil {
+		return prevObservedConfig, []error{err}
+	}
+	if len(currentServiceNetworkCIDR) > 0 {
+		if err := unstructured.SetNestedField(prevObservedConfig, currentServiceNetworkCIDR, serviceNetworkCIDRPath...); err != nil {
+			return prevObservedConfig, []error{err}
+		}
+	}
+
+	networkConfig, err := listers.NetworkLister.Get("cluster")
+	if errors.IsNotFound(err) {
+		klog.V(2).Infof("networks.%s/cluster: not found", configv1.GroupName)
+		return prevObservedConfig, nil
+	}
+	if err != nil {
+		return prevObservedConfig, []error{err}
+	}
+	if len(networkConfig.Status.ClusterNetwork) == 0 && len(networkConfig.Status.ServiceNetwork) == 0 {
+		klog.V(2).Infof("networks.%s/cluster: status not populated yet", configv1.GroupName)
+		return prevObservedConfig, nil
+	}
+
+	observedConfig := map[string]interface{}{}
+	var clusterNetworks []interface{}
+	for _, clusterNetwork := range networkConfig.Status.ClusterNetwork {
+		_, ipNet, err := net.ParseCIDR(clusterNetwork.CIDR)
+		if err != nil {
+			return prevObservedConfig, []error{fmt.Errorf("error reading networks.%s/cluster Status.ClusterNetwork: %v", configv1.GroupName, err)}
+		}
+		entry := map[string]interface{}{
+			"cidr": clusterNetwork.CIDR,
+		}
+		// hostSubnetLength is the number of host bits of the subnet assigned to each node
+		if _, bits := ipNet.Mask.Size(); clusterNetwork.HostPrefix > 0 && int(clusterNetwork.HostPrefix) <= bits {
+			entry["hostSubnetLength"] = int64(bits - int(clusterNetwork.HostPrefix))
+		}
+		clusterNetworks = append(clusterNetworks, entry)
+	}
+	if len(clusterNetworks) > 0 {
+		if err := unstructured.SetNestedSlice(observedConfig, clusterNetworks, clusterNetworksPath...); err != nil {
+			return prevObservedConfig, []error{err}
+		}
+	}
+
+	for _, cidr := range networkConfig.Status.ServiceNetwork {
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			return prevObservedConfig, []error{fmt.Errorf("error reading networks.%s/cluster Status.ServiceNetwork: %v", configv1.GroupName, err)}
+		}
+	}
+	if len(networkConfig.Status.ServiceNetwork) > 0 {
+		if err := unstructured.SetNestedField(observedConfig, strings.Join(networkConfig.Status.ServiceNetwork, ","), serviceNetworkCIDRPath...); err != nil {
+			return prevObservedConfig, []error{err}
+		}
+	}
+
+	return observedConfig, nil
+}
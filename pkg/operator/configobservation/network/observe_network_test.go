@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/openshift/library-go/pkg/operator/events"
+	"k8s.io/apimachinery/pkg/api/equality"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/client-go/tools/cache"
@@ -99,3 +100,112 @@ func TestObserveExternalIPAutoAssignCIDRs(t *testing.T) {
 	}
 	expectValue("1.2.3.0/24", result)
 }
+
+func TestObserveClusterNetworks(t *testing.T) {
+	previouslyObserved := map[string]interface{}{
+		"network": map[string]interface{}{
+			"clusterNetworks": []interface{}{
+				map[string]interface{}{"cidr": "10.128.0.0/14", "hostSubnetLength": int64(9)},
+			},
+			"serviceNetworkCIDR": "172.30.0.0/16",
+		},
+	}
+
+	tests := []struct {
+		name           string
+		network        *configv1.Network
+		existingConfig map[string]interface{}
+		expected       map[string]interface{}
+		expectErrors   bool
+	}{
+		{
+			name:           "no network config keeps previous values",
+			existingConfig: previouslyObserved,
+			expected:       previouslyObserved,
+		},
+		{
+			name: "unpopulated status keeps previous values",
+			network: &configv1.Network{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+				Spec: configv1.NetworkSpec{
+					ServiceNetwork: []string{"172.31.0.0/16"},
+				},
+			},
+			existingConfig: previouslyObserved,
+			expected:       previouslyObserved,
+		},
+		{
+			name: "single stack",
+			network: &configv1.Network{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+				Status: configv1.NetworkStatus{
+					ClusterNetwork: []configv1.ClusterNetworkEntry{
+						{CIDR: "10.128.0.0/14", HostPrefix: 23},
+					},
+					ServiceNetwork: []string{"172.30.0.0/16"},
+				},
+			},
+			existingConfig: map[string]interface{}{},
+			expected:       previouslyObserved,
+		},
+		{
+			name: "dual stack preserves order",
+			network: &configv1.Network{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+				Status: configv1.NetworkStatus{
+					ClusterNetwork: []configv1.ClusterNetworkEntry{
+						{CIDR: "fd01::/48", HostPrefix: 64},
+						{CIDR: "10.128.0.0/14", HostPrefix: 23},
+					},
+					ServiceNetwork: []string{"fd02::/112", "172.30.0.0/16"},
+				},
+			},
+			existingConfig: previouslyObserved,
+			expected: map[string]interface{}{
+				"network": map[string]interface{}{
+					"clusterNetworks": []interface{}{
+						map[string]interface{}{"cidr": "fd01::/48", "hostSubnetLength": int64(64)},
+						map[string]interface{}{"cidr": "10.128.0.0/14", "hostSubnetLength": int64(9)},
+					},
+					"serviceNetworkCIDR": "fd02::/112,172.30.0.0/16",
+				},
+			},
+		},
+		{
+			name: "invalid cidr keeps previous values",
+			network: &configv1.Network{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+				Status: configv1.NetworkStatus{
+					ClusterNetwork: []configv1.ClusterNetworkEntry{
+						{CIDR: "invalid", HostPrefix: 23},
+					},
+				},
+			},
+			existingConfig: previouslyObserved,
+			expected:       previouslyObserved,
+			expectErrors:   true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+			if tc.network != nil {
+				if err := indexer.Add(tc.network); err != nil {
+					t.Fatal(err)
+				}
+			}
+			listers := configobservation.Listers{
+				NetworkLister: configlistersv1.NewNetworkLister(indexer),
+			}
+
+			result, errs := ObserveClusterNetworks(listers, events.NewInMemoryRecorder("", clock.RealClock{}), tc.existingConfig)
+			if tc.expectErrors != (len(errs) > 0) {
+				t.Fatalf("expected errors: %v, got %v", tc.expectErrors, errs)
+			}
+			if !equality.Semantic.DeepEqual(tc.expected, result) {
+				t.Errorf("expected %v, got %v", tc.expected, result)
+			}
+		})
+	}
+}
@@ -0,0 +1,166 @@
+package validation
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/imdario/mergo"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/klog/v2"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/crypto"
+	"github.com/openshift/library-go/pkg/operator/configobserver"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+)
+
+const (
+	observedConfigInvalidDegradedType = "ObservedConfigInvalidDegraded"
+	inconsistentObservedConfigReason  = "InconsistentObservedConfig"
+)
+
+// observedConfigValidator returns the reasons why a combined observed config is inconsistent, none if it is
+// consistent.
+type observedConfigValidator func(observedConfig map[string]interface{}) []string
+
+var validators = []observedConfigValidator{
+	validateServingInfoTLS,
+}
+
+// NewValidatingObserveConfigFunc returns an observer which runs the given observers in order and validates
+// their combined config. An inconsistent combined config is rejected: the existing config is kept and a
+// single ObservedConfigInvalidDegraded condition lists every inconsistency found.
+//
+// The observers are expected to write disjoint keys, then the combined config does not depend on their
+// order. Where two observers write the same key anyway, the value of the one given first is kept and the
+// conflict is logged, so that the combined config is still the same on every run.
+func NewValidatingObserveConfigFunc(operatorClient v1helpers.OperatorClient, observers ...configobserver.ObserveConfigFunc) configobserver.ObserveConfigFunc {
+	return func(listers configobserver.Listers, recorder events.Recorder, existingConfig map[string]interface{}) (map[string]interface{}, []error) {
+		var errs []error
+		observedConfig := map[string]interface{}{}
+		for i, observe := range observers {
+			config, currErrs := observe(listers, recorder, existingConfig)
+			errs = append(errs, currErrs...)
+			if normalized, err := normalizeConfig(config); err != nil {
+				errs = append(errs, fmt.Errorf("observer %d returned a config which is not JSON: %w", i, err))
+			} else {
+				config = normalized
+			}
+			if conflicts := conflictingKeys(observedConfig, config, nil); len(conflicts) > 0 {
+				klog.Warningf("observer %d writes keys of the observed config an earlier observer wrote, keeping the earlier values of %s", i, strings.Join(conflicts, ", "))
+			}
+			if err := mergo.Merge(&observedConfig, config); err != nil {
+				klog.Warningf("merging observed config failed: %v", err)
+			}
+		}
+
+		var reasons []string
+		for _, validate := range validators {
+			reasons = append(reasons, validate(observedConfig)...)
+		}
+
+		condition := operatorv1.OperatorCondition{
+			Type:   observedConfigInvalidDegradedType,
+			Status: operatorv1.ConditionFalse,
+		}
+		if len(reasons) > 0 {
+			condition.Status = operatorv1.ConditionTrue
+			condition.Reason = inconsistentObservedConfigReason
+			condition.Message = fmt.Sprintf("rejected the observed config:\n%s", strings.Join(reasons, "\n"))
+			recorder.Warningf("ObservedConfigRejected", "Rejected the observed config: %s", strings.Join(reasons, "; "))
+			observedConfig = runtime.DeepCopyJSON(existingConfig)
+		}
+		if _, _, err := v1helpers.UpdateStatus(context.TODO(), operatorClient, v1helpers.UpdateConditionFn(condition)); err != nil {
+			errs = append(errs, err)
+		}
+		return observedConfig, errs
+	}
+}
+
+// normalizeConfig returns a copy of the config of an observer as it is written, so that merging it never
+// modifies what the observer returned, e.g. a cached value, and its values compare equal to the existing
+// config, which is decoded from JSON.
+func normalizeConfig(config map[string]interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(config)
+	if err != nil {
+		return nil, err
+	}
+	normalized := map[string]interface{}{}
+	if err := json.Unmarshal(data, &normalized); err != nil {
+		return nil, err
+	}
+	return normalized, nil
+}
+
+// conflictingKeys returns the sorted dotted paths of the values config sets differently than merged.
+func conflictingKeys(merged, config map[string]interface{}, prefix []string) []string {
+	var conflicts []string
+	for key, value := range config {
+		mergedValue, ok := merged[key]
+		if !ok {
+			continue
+		}
+		path := append(append([]string{}, prefix...), key)
+		mergedNested, mergedIsMap := mergedValue.(map[string]interface{})
+		nested, isMap := value.(map[string]interface{})
+		if mergedIsMap && isMap {
+			conflicts = append(conflicts, conflictingKeys(mergedNested, nested, path)...)
+			continue
+		}
+		if !equality.Semantic.DeepEqual(mergedValue, value) {
+			conflicts = append(conflicts, strings.Join(path, "."))
+		}
+	}
+	sort.Strings(conflicts)
+	return conflicts
+}
+
+// validateServingInfoTLS checks that the observed minimum TLS version and cipher suites are known and that at
+// least one of the cipher suites can be negotiated with the minimum TLS version or a later one.
+func validateServingInfoTLS(observedConfig map[string]interface{}) []string {
+	minTLSVersionName, _, err := unstructured.NestedString(observedConfig, "servingInfo", "minTLSVersion")
+	if err != nil {
+		return []string{fmt.Sprintf("servingInfo.minTLSVersion: %v", err)}
+	}
+	cipherSuites, _, err := unstructured.NestedStringSlice(observedConfig, "servingInfo", "cipherSuites")
+	if err != nil {
+		return []string{fmt.Sprintf("servingInfo.cipherSuites: %v", err)}
+	}
+
+	var reasons []string
+	var minTLSVersion uint16
+	if len(minTLSVersionName) > 0 {
+		if minTLSVersion, err = crypto.TLSVersion(minTLSVersionName); err != nil {
+			reasons = append(reasons, fmt.Sprintf("servingInfo.minTLSVersion: unknown TLS version %q", minTLSVersionName))
+		}
+	}
+
+	supportedVersions := map[string][]uint16{}
+	for _, suite := range append(tls.CipherSuites(), tls.InsecureCipherSuites()...) {
+		supportedVersions[suite.Name] = suite.SupportedVersions
+	}
+	negotiable := false
+	for _, cipherSuite := range cipherSuites {
+		versions, ok := supportedVersions[cipherSuite]
+		if !ok {
+			reasons = append(reasons, fmt.Sprintf("servingInfo.cipherSuites: unknown cipher suite %q", cipherSuite))
+			continue
+		}
+		for _, version := range versions {
+			if version >= minTLSVersion {
+				negotiable = true
+			}
+		}
+	}
+	if len(cipherSuites) > 0 && minTLSVersion != 0 && !negotiable {
+		reasons = append(reasons, fmt.Sprintf("servingInfo.minTLSVersion: %s is higher than any of the cipher suites %v supports", minTLSVersionName, cipherSuites))
+	}
+	return reasons
+}
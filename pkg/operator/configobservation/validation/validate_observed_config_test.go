@@ -0,0 +1,237 @@
+package validation
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/utils/clock"
+
+	configv1 "github.com/openshift/api/config/v1"
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/crypto"
+	"github.com/openshift/library-go/pkg/operator/configobserver"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-openshift-controller-manager-operator/pkg/operator/configobservation"
+)
+
+func staticObserver(config map[string]interface{}) configobserver.ObserveConfigFunc {
+	return func(configobserver.Listers, events.Recorder, map[string]interface{}) (map[string]interface{}, []error) {
+		return config, nil
+	}
+}
+
+func TestValidatingObserveConfigFunc(t *testing.T) {
+	existingConfig := map[string]interface{}{
+		"servingInfo": map[string]interface{}{
+			"minTLSVersion": "VersionTLS12",
+			"cipherSuites":  []interface{}{"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256"},
+		},
+	}
+	buildConfig := map[string]interface{}{
+		"build": map[string]interface{}{"buildDefaults": map[string]interface{}{}},
+	}
+
+	tests := []struct {
+		name            string
+		servingInfo     map[string]interface{}
+		expectedConfig  map[string]interface{}
+		expectedReasons []string
+	}{
+		{
+			name: "consistent config",
+			servingInfo: map[string]interface{}{
+				"servingInfo": map[string]interface{}{
+					"minTLSVersion": "VersionTLS13",
+					"cipherSuites":  []interface{}{"TLS_AES_128_GCM_SHA256", "TLS_AES_256_GCM_SHA384"},
+				},
+			},
+			expectedConfig: map[string]interface{}{
+				"servingInfo": map[string]interface{}{
+					"minTLSVersion": "VersionTLS13",
+					"cipherSuites":  []interface{}{"TLS_AES_128_GCM_SHA256", "TLS_AES_256_GCM_SHA384"},
+				},
+				"build": map[string]interface{}{"buildDefaults": map[string]interface{}{}},
+			},
+		},
+		{
+			name: "inconsistent config",
+			servingInfo: map[string]interface{}{
+				"servingInfo": map[string]interface{}{
+					"minTLSVersion": "VersionTLS13",
+					"cipherSuites":  []interface{}{"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256", "TLS_NOT_A_CIPHER"},
+				},
+			},
+			expectedConfig: existingConfig,
+			expectedReasons: []string{
+				`unknown cipher suite "TLS_NOT_A_CIPHER"`,
+				"VersionTLS13 is higher than any of the cipher suites",
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			operatorClient := v1helpers.NewFakeOperatorClient(&operatorv1.OperatorSpec{ManagementState: operatorv1.Managed}, &operatorv1.OperatorStatus{}, nil)
+			recorder := events.NewInMemoryRecorder("", clock.RealClock{})
+			observe := NewValidatingObserveConfigFunc(operatorClient, staticObserver(tc.servingInfo), staticObserver(buildConfig))
+
+			observedConfig, errs := observe(configobservation.Listers{}, recorder, existingConfig)
+			if len(errs) > 0 {
+				t.Fatalf("unexpected errors: %v", errs)
+			}
+			if !equality.Semantic.DeepEqual(observedConfig, tc.expectedConfig) {
+				t.Errorf("expected config %v, got %v", tc.expectedConfig, observedConfig)
+			}
+
+			_, status, _, err := operatorClient.GetOperatorState()
+			if err != nil {
+				t.Fatal(err)
+			}
+			condition := v1helpers.FindOperatorCondition(status.Conditions, observedConfigInvalidDegradedType)
+			if condition == nil {
+				t.Fatalf("expected condition %s to be set", observedConfigInvalidDegradedType)
+			}
+			if len(tc.expectedReasons) == 0 {
+				if condition.Status != operatorv1.ConditionFalse {
+					t.Errorf("expected condition %s to be False, got %s: %s", condition.Type, condition.Status, condition.Message)
+				}
+				return
+			}
+			if condition.Status != operatorv1.ConditionTrue || condition.Reason != inconsistentObservedConfigReason {
+				t.Errorf("expected condition %s to be True with reason %s, got %s with reason %s", condition.Type, inconsistentObservedConfigReason, condition.Status, condition.Reason)
+			}
+			for _, reason := range tc.expectedReasons {
+				if !strings.Contains(condition.Message, reason) {
+					t.Errorf("expected condition message to contain %q, got %q", reason, condition.Message)
+				}
+			}
+		})
+	}
+}
+
+func TestValidatingObserveConfigFuncIsDeterministic(t *testing.T) {
+	// the observers return the same maps on every run, like observers returning a cached config
+	observers := []configobserver.ObserveConfigFunc{
+		staticObserver(map[string]interface{}{
+			"build": map[string]interface{}{"buildDefaults": map[string]interface{}{"gitHTTPProxy": "http://proxy"}},
+			"network": map[string]interface{}{"clusterNetworks": []interface{}{
+				map[string]interface{}{"cidr": "10.128.0.0/14", "hostSubnetLength": int64(9)},
+			}},
+		}),
+		staticObserver(map[string]interface{}{
+			"build":        map[string]interface{}{"buildDefaults": map[string]interface{}{"gitNoProxy": ".cluster.local"}},
+			"featureGates": []interface{}{"BuildCSIVolumes=true"},
+		}),
+		staticObserver(map[string]interface{}{
+			"servingInfo": map[string]interface{}{"minTLSVersion": "VersionTLS12"},
+		}),
+	}
+	run := func() []byte {
+		t.Helper()
+		operatorClient := v1helpers.NewFakeOperatorClient(&operatorv1.OperatorSpec{ManagementState: operatorv1.Managed}, &operatorv1.OperatorStatus{}, nil)
+		observe := NewValidatingObserveConfigFunc(operatorClient, observers...)
+		observedConfig, errs := observe(configobservation.Listers{}, events.NewInMemoryRecorder("", clock.RealClock{}), map[string]interface{}{})
+		if len(errs) > 0 {
+			t.Fatalf("unexpected errors: %v", errs)
+		}
+		data, err := json.Marshal(observedConfig)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return data
+	}
+
+	first, second := run(), run()
+	if !bytes.Equal(first, second) {
+		t.Errorf("expected the same observed config on every run, got\n%s\nthen\n%s", first, second)
+	}
+	// merging the second observer must not have changed the map the first one returns
+	firstConfig, _ := observers[0](nil, nil, nil)
+	if _, ok := firstConfig["build"].(map[string]interface{})["buildDefaults"].(map[string]interface{})["gitNoProxy"]; ok {
+		t.Errorf("expected the config of the first observer to be unchanged, got %v", firstConfig)
+	}
+}
+
+func TestValidatingObserveConfigFuncObserverOrder(t *testing.T) {
+	gitProxy := staticObserver(map[string]interface{}{
+		"build": map[string]interface{}{"buildDefaults": map[string]interface{}{"gitHTTPProxy": "http://proxy"}},
+	})
+	gitNoProxy := staticObserver(map[string]interface{}{
+		"build": map[string]interface{}{"buildDefaults": map[string]interface{}{"gitNoProxy": ".cluster.local"}},
+	})
+	otherGitProxy := staticObserver(map[string]interface{}{
+		"build": map[string]interface{}{"buildDefaults": map[string]interface{}{"gitHTTPProxy": "http://other-proxy"}},
+	})
+	run := func(observers ...configobserver.ObserveConfigFunc) string {
+		t.Helper()
+		operatorClient := v1helpers.NewFakeOperatorClient(&operatorv1.OperatorSpec{ManagementState: operatorv1.Managed}, &operatorv1.OperatorStatus{}, nil)
+		observedConfig, errs := NewValidatingObserveConfigFunc(operatorClient, observers...)(configobservation.Listers{}, events.NewInMemoryRecorder("", clock.RealClock{}), map[string]interface{}{})
+		if len(errs) > 0 {
+			t.Fatalf("unexpected errors: %v", errs)
+		}
+		data, err := json.Marshal(observedConfig)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(data)
+	}
+
+	expected := `{"build":{"buildDefaults":{"gitHTTPProxy":"http://proxy","gitNoProxy":".cluster.local"}}}`
+	if observed := run(gitProxy, gitNoProxy); observed != expected {
+		t.Errorf("expected %s, got %s", expected, observed)
+	}
+	if observed := run(gitNoProxy, gitProxy); observed != expected {
+		t.Errorf("expected observers of disjoint keys to not depend on their order, got %s", observed)
+	}
+
+	// of observers writing the same key, the one declared first wins
+	if observed := run(gitProxy, otherGitProxy); !strings.Contains(observed, `"gitHTTPProxy":"http://proxy"`) {
+		t.Errorf("expected the value of the first observer, got %s", observed)
+	}
+	if observed := run(otherGitProxy, gitProxy); !strings.Contains(observed, `"gitHTTPProxy":"http://other-proxy"`) {
+		t.Errorf("expected the value of the first observer, got %s", observed)
+	}
+}
+
+func TestConflictingKeys(t *testing.T) {
+	merged := map[string]interface{}{
+		"build":       map[string]interface{}{"buildDefaults": map[string]interface{}{"gitHTTPProxy": "http://proxy", "gitNoProxy": ".cluster.local"}},
+		"controllers": []interface{}{"*"},
+	}
+	config := map[string]interface{}{
+		"build":       map[string]interface{}{"buildDefaults": map[string]interface{}{"gitHTTPProxy": "http://other-proxy", "gitNoProxy": ".cluster.local"}},
+		"controllers": []interface{}{"*", "-openshift.io/build"},
+		"deployer":    map[string]interface{}{"imageTemplateFormat": map[string]interface{}{"format": "quay.io/deployer"}},
+	}
+	expected := []string{"build.buildDefaults.gitHTTPProxy", "controllers"}
+	if conflicts := conflictingKeys(merged, config, nil); !equality.Semantic.DeepEqual(conflicts, expected) {
+		t.Errorf("expected conflicts %v, got %v", expected, conflicts)
+	}
+}
+
+func TestValidateServingInfoTLSProfiles(t *testing.T) {
+	for profileType, profile := range configv1.TLSProfiles {
+		observedConfig := map[string]interface{}{
+			"servingInfo": map[string]interface{}{
+				"minTLSVersion": string(profile.MinTLSVersion),
+				"cipherSuites":  stringsToInterfaces(crypto.OpenSSLToIANACipherSuites(profile.Ciphers)),
+			},
+		}
+		if reasons := validateServingInfoTLS(observedConfig); len(reasons) > 0 {
+			t.Errorf("expected the %s TLS profile to be consistent, got %v", profileType, reasons)
+		}
+	}
+}
+
+func stringsToInterfaces(in []string) []interface{} {
+	out := make([]interface{}, 0, len(in))
+	for _, s := range in {
+		out = append(out, s)
+	}
+	return out
+}
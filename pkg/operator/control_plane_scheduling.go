@@ -0,0 +1,42 @@
+package operator
+
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	masterNodeRoleLabel       = "node-role.kubernetes.io/master"
+	controlPlaneNodeRoleLabel = "node-role.kubernetes.io/control-plane"
+)
+
+// controlPlaneTolerations are the taints of the control-plane nodes the operand pods must tolerate. Nodes are
+// tainted with the master role, the control-plane role or both.
+var controlPlaneTolerations = []corev1.Toleration{
+	{Key: masterNodeRoleLabel, Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule},
+	{Key: controlPlaneNodeRoleLabel, Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule},
+}
+
+// ensureControlPlaneScheduling makes the pods land on the control-plane nodes: they select the master nodes and
+// tolerate the control-plane taints. The cluster default node selector of the scheduler config does not apply,
+// it targets the workload nodes and the operand namespaces opt out of it.
+func ensureControlPlaneScheduling(spec *corev1.PodSpec) {
+	if spec.NodeSelector == nil {
+		spec.NodeSelector = map[string]string{}
+	}
+	if _, ok := spec.NodeSelector[masterNodeRoleLabel]; !ok {
+		spec.NodeSelector[masterNodeRoleLabel] = ""
+	}
+
+	for _, required := range controlPlaneTolerations {
+		tolerated := false
+		for _, existing := range spec.Tolerations {
+			if existing.MatchToleration(&required) {
+				tolerated = true
+				break
+			}
+		}
+		if !tolerated {
+			spec.Tolerations = append(spec.Tolerations, required)
+		}
+	}
+}
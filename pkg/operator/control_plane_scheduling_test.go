@@ -0,0 +1,136 @@
+package operator
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/utils/clock"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	configlistersv1 "github.com/openshift/client-go/config/listers/config/v1"
+	workloadcontroller "github.com/openshift/library-go/pkg/operator/apiserver/controller/workload"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/resource/resourceread"
+
+	"github.com/openshift/cluster-openshift-controller-manager-operator/bindata"
+)
+
+func TestControlPlaneScheduling(t *testing.T) {
+	countNodes := func(nodeSelector map[string]string) (*int32, error) {
+		result := int32(3)
+		return &result, nil
+	}
+	recorder := events.NewInMemoryRecorder("", clock.RealClock{})
+	options := &operatorv1.OpenShiftControllerManager{ObjectMeta: metav1.ObjectMeta{Name: "cluster"}}
+
+	ocmDeployment, _, err := manageOpenShiftControllerManagerDeployment_v311_00_to_latest(
+		bindata.MustAsset,
+		fake.NewSimpleClientset().AppsV1(),
+		countNodes,
+		workloadcontroller.EnsureAtMostOnePodPerNode,
+		recorder,
+		options,
+		"my.co/repo/img:latest",
+		nil,
+		configlistersv1.NewProxyLister(cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})),
+		map[string]string{},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rcmDeployment, _, err := manageRouteControllerManagerDeployment_v311_00_to_latest(
+		fake.NewSimpleClientset().AppsV1(),
+		countNodes,
+		workloadcontroller.EnsureAtMostOnePodPerNode,
+		recorder,
+		options,
+		"my.co/repo/img:latest",
+		nil,
+		map[string]string{},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, spec := range map[string]corev1.PodSpec{
+		"controller-manager":       ocmDeployment.Spec.Template.Spec,
+		"route-controller-manager": rcmDeployment.Spec.Template.Spec,
+	} {
+		t.Run(name, func(t *testing.T) {
+			if value, ok := spec.NodeSelector[masterNodeRoleLabel]; !ok || value != "" || len(spec.NodeSelector) != 1 {
+				t.Errorf("expected the pods to select only the %s nodes, got nodeSelector %v", masterNodeRoleLabel, spec.NodeSelector)
+			}
+			assertToleratesControlPlane(t, spec)
+
+			// the default eviction tolerations of the asset are kept
+			for _, key := range []string{"node.kubernetes.io/unreachable", "node.kubernetes.io/not-ready"} {
+				found := false
+				for _, toleration := range spec.Tolerations {
+					if toleration.Key == key && toleration.Effect == corev1.TaintEffectNoExecute {
+						found = true
+					}
+				}
+				if !found {
+					t.Errorf("expected the %s toleration to be kept, got %v", key, spec.Tolerations)
+				}
+			}
+		})
+	}
+}
+
+// TestOperandNamespacesIgnoreDefaultNodeSelector checks the operand namespaces opt out of the cluster default
+// node selector, which the project node selector admission would otherwise add to the operand pods.
+func TestOperandNamespacesIgnoreDefaultNodeSelector(t *testing.T) {
+	for _, asset := range operandNamespaceAssets {
+		namespace := resourceread.ReadNamespaceV1OrDie(bindata.MustAsset(asset))
+		if value, ok := namespace.Annotations["openshift.io/node-selector"]; !ok || value != "" {
+			t.Errorf("expected namespace/%s to have an empty openshift.io/node-selector annotation, got %v", namespace.Name, namespace.Annotations)
+		}
+	}
+}
+
+// TestOperandNamespacesPodSecurityLabels checks the operand namespaces admit their pods at the privileged
+// pod security level, like the other control-plane namespaces.
+func TestOperandNamespacesPodSecurityLabels(t *testing.T) {
+	for _, asset := range operandNamespaceAssets {
+		namespace := resourceread.ReadNamespaceV1OrDie(bindata.MustAsset(asset))
+		for _, mode := range []string{"enforce", "audit", "warn"} {
+			if level := namespace.Labels["pod-security.kubernetes.io/"+mode]; level != "privileged" {
+				t.Errorf("expected namespace/%s to %s the privileged pod security level, got %q", namespace.Name, mode, level)
+			}
+		}
+	}
+}
+
+func TestEnsureControlPlaneSchedulingIsIdempotent(t *testing.T) {
+	spec := &corev1.PodSpec{}
+	ensureControlPlaneScheduling(spec)
+	ensureControlPlaneScheduling(spec)
+
+	assertToleratesControlPlane(t, *spec)
+	if len(spec.Tolerations) != len(controlPlaneTolerations) {
+		t.Errorf("expected %d tolerations, got %v", len(controlPlaneTolerations), spec.Tolerations)
+	}
+	if len(spec.NodeSelector) != 1 {
+		t.Errorf("expected only the %s node selector, got %v", masterNodeRoleLabel, spec.NodeSelector)
+	}
+}
+
+func assertToleratesControlPlane(t *testing.T, spec corev1.PodSpec) {
+	t.Helper()
+	for _, key := range []string{masterNodeRoleLabel, controlPlaneNodeRoleLabel} {
+		taint := &corev1.Taint{Key: key, Effect: corev1.TaintEffectNoSchedule}
+		tolerated := false
+		for _, toleration := range spec.Tolerations {
+			if toleration.ToleratesTaint(taint) {
+				tolerated = true
+			}
+		}
+		if !tolerated {
+			t.Errorf("expected the %s taint to be tolerated, got %v", key, spec.Tolerations)
+		}
+	}
+}
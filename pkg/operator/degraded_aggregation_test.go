@@ -0,0 +1,131 @@
+package operator
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+
+	configv1 "github.com/openshift/api/config/v1"
+	operatorv1 "github.com/openshift/api/operator/v1"
+	configlistersv1 "github.com/openshift/client-go/config/listers/config/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/configobserver"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/status"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-openshift-controller-manager-operator/pkg/operator/configobservation"
+	"github.com/openshift/cluster-openshift-controller-manager-operator/pkg/operator/configobservation/apiserver"
+	"github.com/openshift/cluster-openshift-controller-manager-operator/pkg/operator/configobservation/builds"
+	"github.com/openshift/cluster-openshift-controller-manager-operator/pkg/operator/configobservation/validation"
+)
+
+// TestDegradedAggregatesSubConditions drives the config observer as it is wired in the operator into two
+// degradations at once, build default resources whose requests exceed their limits and a TLS profile
+// with an unknown minimum TLS version, and checks the Degraded condition the ClusterOperator reports lists both and keeps reporting the
+// one left when the other clears.
+func TestDegradedAggregatesSubConditions(t *testing.T) {
+	invalidProfile := &configv1.TLSSecurityProfile{
+		Type: configv1.TLSProfileCustomType,
+		Custom: &configv1.CustomTLSProfile{TLSProfileSpec: configv1.TLSProfileSpec{
+			MinTLSVersion: "VersionTLS14",
+			Ciphers:       []string{"ECDHE-RSA-AES128-GCM-SHA256"},
+		}},
+	}
+	inconsistentResources := corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("2Gi")},
+		Limits:   corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")},
+	}
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	setAPIServer := func(spec configv1.APIServerSpec) {
+		t.Helper()
+		if err := indexer.Update(&configv1.APIServer{ObjectMeta: metav1.ObjectMeta{Name: "cluster"}, Spec: spec}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	buildIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	setBuildDefaultResources := func(resources corev1.ResourceRequirements) {
+		t.Helper()
+		build := &configv1.Build{ObjectMeta: metav1.ObjectMeta{Name: "cluster"}}
+		build.Spec.BuildDefaults.Resources = resources
+		if err := buildIndexer.Update(build); err != nil {
+			t.Fatal(err)
+		}
+	}
+	setAPIServer(configv1.APIServerSpec{TLSSecurityProfile: invalidProfile})
+	setBuildDefaultResources(inconsistentResources)
+
+	operatorClient := v1helpers.NewFakeOperatorClient(&operatorv1.OperatorSpec{ManagementState: operatorv1.Managed}, &operatorv1.OperatorStatus{}, nil)
+	clock := &fakePassiveClock{now: time.Now()}
+	recorder := events.NewInMemoryRecorder("", clock)
+	observer := configobserver.NewConfigObserver(
+		"openshift-controller-manager",
+		operatorClient,
+		recorder,
+		configobservation.Listers{
+			APIServerLister_:  configlistersv1.NewAPIServerLister(indexer),
+			BuildConfigLister: configlistersv1.NewBuildLister(buildIndexer),
+		},
+		nil,
+		validation.NewValidatingObserveConfigFunc(operatorClient,
+			apiserver.NewObserveTLSSecurityProfileFunc(operatorClient, &flakyAPIServerInformer{}, func(time.Duration) {}, clock),
+			builds.NewObserveBuildDefaultResourcesFunc(operatorClient),
+		),
+	)
+	sync := func() {
+		t.Helper()
+		if err := observer.Sync(context.TODO(), factory.NewSyncContext("test", recorder)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	clusterDegraded := func() configv1.ClusterOperatorStatusCondition {
+		t.Helper()
+		_, operatorStatus, _, err := operatorClient.GetOperatorState()
+		if err != nil {
+			t.Fatal(err)
+		}
+		// the ClusterOperator status controller aggregates the *Degraded conditions the same way
+		return status.UnionClusterCondition(configv1.OperatorDegraded, operatorv1.ConditionFalse, nil, operatorStatus.Conditions...)
+	}
+
+	sync()
+	degraded := clusterDegraded()
+	if degraded.Status != configv1.ConditionTrue {
+		t.Fatalf("expected Degraded=True, got %v", degraded)
+	}
+	if expected := "BuildDefaultResources_RequestsExceedLimits::ObservedConfigInvalid_InconsistentObservedConfig"; degraded.Reason != expected {
+		t.Errorf("expected reason %q, got %q", expected, degraded.Reason)
+	}
+	for _, expected := range []string{"BuildDefaultResourcesDegraded: ", "ObservedConfigInvalidDegraded: "} {
+		if !strings.Contains(degraded.Message, expected) {
+			t.Errorf("expected the message to contain %q, got %q", expected, degraded.Message)
+		}
+	}
+	for _, line := range strings.Split(degraded.Message, "\n") {
+		if strings.HasSuffix(line, ": ") {
+			t.Errorf("expected no empty sub-condition message, got line %q in %q", line, degraded.Message)
+		}
+	}
+
+	setBuildDefaultResources(corev1.ResourceRequirements{Limits: inconsistentResources.Limits})
+	sync()
+	degraded = clusterDegraded()
+	if degraded.Status != configv1.ConditionTrue || degraded.Reason != "ObservedConfigInvalid_InconsistentObservedConfig" {
+		t.Fatalf("expected Degraded=True with reason ObservedConfigInvalid_InconsistentObservedConfig once the build default resources are consistent, got %v", degraded)
+	}
+	if strings.Contains(degraded.Message, "BuildDefaultResourcesDegraded") {
+		t.Errorf("expected the cleared build default resources degradation not to be reported, got %q", degraded.Message)
+	}
+
+	setAPIServer(configv1.APIServerSpec{})
+	sync()
+	if degraded := clusterDegraded(); degraded.Status != configv1.ConditionFalse {
+		t.Errorf("expected Degraded to clear once both are fixed, got %v", degraded)
+	}
+}
@@ -0,0 +1,247 @@
+package operator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/cache"
+
+	configv1 "github.com/openshift/api/config/v1"
+	operatorv1 "github.com/openshift/api/operator/v1"
+	configlistersv1 "github.com/openshift/client-go/config/listers/config/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/configobserver"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/status"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-openshift-controller-manager-operator/pkg/operator/configobservation"
+	"github.com/openshift/cluster-openshift-controller-manager-operator/pkg/operator/configobservation/apiserver"
+	"github.com/openshift/cluster-openshift-controller-manager-operator/pkg/operator/configobservation/validation"
+)
+
+type fakePassiveClock struct {
+	now time.Time
+}
+
+func (c *fakePassiveClock) Now() time.Time                  { return c.now }
+func (c *fakePassiveClock) Since(t time.Time) time.Duration { return c.now.Sub(t) }
+
+// flakyAPIServerInformer keeps the handlers the TLS security profile observer registers, so that tests
+// can fail listing and watching the APIServer config like a transient API error, and recover from it.
+type flakyAPIServerInformer struct {
+	cache.SharedIndexInformer
+	watchErrorHandler cache.WatchErrorHandlerWithContext
+	handlers          []cache.ResourceEventHandler
+}
+
+func (i *flakyAPIServerInformer) SetWatchErrorHandlerWithContext(handler cache.WatchErrorHandlerWithContext) error {
+	i.watchErrorHandler = handler
+	return nil
+}
+
+func (i *flakyAPIServerInformer) AddEventHandler(handler cache.ResourceEventHandler) (cache.ResourceEventHandlerRegistration, error) {
+	i.handlers = append(i.handlers, handler)
+	return nil, nil
+}
+
+// LastSyncResourceVersion never changes, the observer only recovers from the events of the informer.
+func (i *flakyAPIServerInformer) LastSyncResourceVersion() string {
+	return ""
+}
+
+func (i *flakyAPIServerInformer) fail(err error) {
+	i.watchErrorHandler(context.TODO(), cache.NewReflector(&cache.ListWatch{}, &configv1.APIServer{}, nil, 0), err)
+}
+
+// recover delivers the APIServer config of the indexer like the informer listing it again.
+func (i *flakyAPIServerInformer) recover(t *testing.T, indexer cache.Indexer) {
+	t.Helper()
+	apiServer, exists, err := indexer.GetByKey("cluster")
+	if err != nil || !exists {
+		t.Fatalf("expected the APIServer config in the cache: %v", err)
+	}
+	for _, handler := range i.handlers {
+		handler.OnUpdate(apiServer, apiServer)
+	}
+}
+
+// TestDegradedClearsAfterAPIServerReadErrorRecovers drives the config observer as it is wired in the operator
+// through a sustained failure to read the APIServer config and its recovery, and checks the Degraded
+// condition the ClusterOperator reports trips with the APIServer config reason and clears afterwards.
+func TestDegradedClearsAfterAPIServerReadErrorRecovers(t *testing.T) {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	if err := indexer.Add(&configv1.APIServer{ObjectMeta: metav1.ObjectMeta{Name: "cluster"}}); err != nil {
+		t.Fatal(err)
+	}
+	informer := &flakyAPIServerInformer{}
+	operatorClient := v1helpers.NewFakeOperatorClient(&operatorv1.OperatorSpec{ManagementState: operatorv1.Managed}, &operatorv1.OperatorStatus{}, nil)
+	clock := &fakePassiveClock{now: time.Now()}
+	recorder := events.NewInMemoryRecorder("", clock)
+	observer := configobserver.NewConfigObserver(
+		"openshift-controller-manager",
+		operatorClient,
+		recorder,
+		configobservation.Listers{APIServerLister_: configlistersv1.NewAPIServerLister(indexer)},
+		nil,
+		validation.NewValidatingObserveConfigFunc(operatorClient, apiserver.NewObserveTLSSecurityProfileFunc(operatorClient, informer, func(time.Duration) {}, clock)),
+	)
+
+	sync := func() {
+		t.Helper()
+		if err := observer.Sync(context.TODO(), factory.NewSyncContext("test", recorder)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	clusterDegraded := func() configv1.ClusterOperatorStatusCondition {
+		t.Helper()
+		_, operatorStatus, _, err := operatorClient.GetOperatorState()
+		if err != nil {
+			t.Fatal(err)
+		}
+		// the ClusterOperator status controller aggregates the *Degraded conditions the same way
+		return status.UnionClusterCondition(configv1.OperatorDegraded, operatorv1.ConditionFalse, nil, operatorStatus.Conditions...)
+	}
+
+	sync()
+	if degraded := clusterDegraded(); degraded.Status != configv1.ConditionFalse {
+		t.Fatalf("expected not to be degraded while the APIServer config can be read, got %v", degraded)
+	}
+
+	informer.fail(fmt.Errorf("the server is currently unable to handle the request"))
+	sync()
+	if degraded := clusterDegraded(); degraded.Status != configv1.ConditionFalse {
+		t.Fatalf("expected a transient error not to degrade, got %v", degraded)
+	}
+
+	clock.now = clock.now.Add(5 * time.Minute)
+	sync()
+	degraded := clusterDegraded()
+	if degraded.Status != configv1.ConditionTrue || degraded.Reason != "APIServerConfig_APIServerConfigError" {
+		t.Fatalf("expected Degraded=True with reason APIServerConfig_APIServerConfigError after a sustained error, got %v", degraded)
+	}
+
+	informer.recover(t, indexer)
+	sync()
+	if degraded := clusterDegraded(); degraded.Status != configv1.ConditionFalse {
+		t.Errorf("expected Degraded to clear once the APIServer config can be read again, got %v", degraded)
+	}
+	_, operatorStatus, _, err := operatorClient.GetOperatorState()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, conditionType := range []string{"APIServerConfigDegraded", "ConfigObservationDegraded", "ObservedConfigInvalidDegraded"} {
+		if condition := v1helpers.FindOperatorCondition(operatorStatus.Conditions, conditionType); condition == nil || condition.Status != operatorv1.ConditionFalse {
+			t.Errorf("expected %s=False after the recovery, got %v", conditionType, condition)
+		}
+	}
+}
+
+// TestObservedConfigRecoversAfterAPIServerDisruption drives the config observer as it is wired in the
+// operator through a disruption of the API while the TLS security profile is changed, and checks the
+// previous TLS config is kept while the APIServer config cannot be read, without degrading within the grace
+// period, and the changed profile is observed once it can be read again instead of a stale config.
+func TestObservedConfigRecoversAfterAPIServerDisruption(t *testing.T) {
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	setProfile := func(profile *configv1.TLSSecurityProfile) {
+		t.Helper()
+		if err := indexer.Update(&configv1.APIServer{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+			Spec:       configv1.APIServerSpec{TLSSecurityProfile: profile},
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	setProfile(&configv1.TLSSecurityProfile{Type: configv1.TLSProfileIntermediateType, Intermediate: &configv1.IntermediateTLSProfile{}})
+	informer := &flakyAPIServerInformer{}
+	operatorClient := v1helpers.NewFakeOperatorClient(&operatorv1.OperatorSpec{ManagementState: operatorv1.Managed}, &operatorv1.OperatorStatus{}, nil)
+	clock := &fakePassiveClock{now: time.Now()}
+	recorder := events.NewInMemoryRecorder("", clock)
+	observer := configobserver.NewConfigObserver(
+		"openshift-controller-manager",
+		operatorClient,
+		recorder,
+		configobservation.Listers{APIServerLister_: configlistersv1.NewAPIServerLister(indexer)},
+		nil,
+		validation.NewValidatingObserveConfigFunc(operatorClient, apiserver.NewObserveTLSSecurityProfileFunc(operatorClient, informer, func(time.Duration) {}, clock)),
+	)
+
+	sync := func() {
+		t.Helper()
+		if err := observer.Sync(context.TODO(), factory.NewSyncContext("test", recorder)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	clusterDegraded := func() configv1.ClusterOperatorStatusCondition {
+		t.Helper()
+		_, operatorStatus, _, err := operatorClient.GetOperatorState()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return status.UnionClusterCondition(configv1.OperatorDegraded, operatorv1.ConditionFalse, nil, operatorStatus.Conditions...)
+	}
+	observedMinTLSVersion := func() string {
+		t.Helper()
+		spec, _, _, err := operatorClient.GetOperatorState()
+		if err != nil {
+			t.Fatal(err)
+		}
+		raw := spec.ObservedConfig.Raw
+		if len(raw) == 0 {
+			// the fake operator client keeps the written observed config as an object
+			if raw, err = json.Marshal(spec.ObservedConfig.Object); err != nil {
+				t.Fatal(err)
+			}
+		}
+		observedConfig := map[string]interface{}{}
+		if err := json.Unmarshal(raw, &observedConfig); err != nil {
+			t.Fatal(err)
+		}
+		minTLSVersion, _, err := unstructured.NestedString(observedConfig, "servingInfo", "minTLSVersion")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return minTLSVersion
+	}
+
+	sync()
+	if minTLSVersion := observedMinTLSVersion(); minTLSVersion != "VersionTLS12" {
+		t.Fatalf("expected the Intermediate profile to be observed, got minTLSVersion %q", minTLSVersion)
+	}
+
+	informer.fail(fmt.Errorf("the server is currently unable to handle the request"))
+	setProfile(&configv1.TLSSecurityProfile{Type: configv1.TLSProfileModernType, Modern: &configv1.ModernTLSProfile{}})
+	for i := 0; i < 2; i++ {
+		sync()
+		if degraded := clusterDegraded(); degraded.Status != configv1.ConditionFalse {
+			t.Fatalf("expected a disruption within the grace period not to degrade, got %v", degraded)
+		}
+		if minTLSVersion := observedMinTLSVersion(); minTLSVersion != "VersionTLS12" {
+			t.Fatalf("expected the previous TLS config to be kept during the disruption, got minTLSVersion %q", minTLSVersion)
+		}
+		clock.now = clock.now.Add(time.Minute)
+	}
+
+	clock.now = clock.now.Add(5 * time.Minute)
+	sync()
+	if degraded := clusterDegraded(); degraded.Status != configv1.ConditionTrue {
+		t.Fatalf("expected a disruption past the grace period to degrade, got %v", degraded)
+	}
+
+	informer.recover(t, indexer)
+	sync()
+	if degraded := clusterDegraded(); degraded.Status != configv1.ConditionFalse {
+		t.Errorf("expected Degraded to clear once the APIServer config can be read again, got %v", degraded)
+	}
+	// the changed TLS config is observed once stable, on the next resync of the config observer
+	clock.now = clock.now.Add(time.Minute)
+	sync()
+	if minTLSVersion := observedMinTLSVersion(); minTLSVersion != "VersionTLS13" {
+		t.Errorf("expected the profile changed during the disruption to be observed, got minTLSVersion %q", minTLSVersion)
+	}
+}
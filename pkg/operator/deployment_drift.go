@@ -0,0 +1,113 @@
+package operator
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	appsclientv1 "k8s.io/client-go/kubernetes/typed/apps/v1"
+
+	operatorapiv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/resource/resourceapply"
+	"github.com/openshift/library-go/pkg/operator/resource/resourcemerge"
+)
+
+// specHashAnnotation is the annotation resourceapply.ApplyDeployment stores the hash of the required spec in.
+const specHashAnnotation = "operator.openshift.io/spec-hash"
+
+// applyDeploymentRevertingDrift applies the required deployment like resourceapply.ApplyDeployment, but
+// additionally forces the required spec to be written when the fields owned by the operator were edited
+// out-of-band, e.g. by an admin, while the operator's desired state did not change. The reversion is
+// reported in an event.
+func applyDeploymentRevertingDrift(
+	client appsclientv1.DeploymentsGetter,
+	recorder events.Recorder,
+	required *appsv1.Deployment,
+	generationStatus []operatorapiv1.GenerationStatus,
+) (*appsv1.Deployment, bool, error) {
+	expectedGeneration := resourcemerge.ExpectedDeploymentGeneration(required, generationStatus)
+
+	existing, err := client.Deployments(required.Namespace).Get(context.TODO(), required.Name, metav1.GetOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return nil, false, err
+	}
+	if err == nil {
+		hashed := required.DeepCopy()
+		if err := resourceapply.SetSpecHashAnnotation(&hashed.ObjectMeta, hashed.Spec); err != nil {
+			return nil, false, err
+		}
+		// a different hash means the operator itself wants to change the deployment, which is not drift
+		if existing.Annotations[specHashAnnotation] == hashed.Annotations[specHashAnnotation] {
+			if drift := deploymentDrift(required, existing); len(drift) > 0 {
+				recorder.Warningf("DeploymentDriftDetected", "Reverting out-of-band changes to deployment/%s -n %s: %s",
+					required.Name, required.Namespace, strings.Join(drift, ", "))
+				// a generation that never matches makes ApplyDeployment write the required spec
+				expectedGeneration = -1
+			}
+		}
+	}
+
+	return resourceapply.ApplyDeployment(context.Background(), client, recorder, required, expectedGeneration)
+}
+
+// deploymentDrift returns a description of every operator owned field of the existing deployment whose
+// value differs from the required deployment. Fields defaulted by the API server are not compared.
+func deploymentDrift(required, existing *appsv1.Deployment) []string {
+	var drift []string
+
+	if required.Spec.Replicas != nil && (existing.Spec.Replicas == nil || *existing.Spec.Replicas != *required.Spec.Replicas) {
+		drift = append(drift, "spec.replicas")
+	}
+
+	existingContainers := map[string]corev1.Container{}
+	for _, c := range existing.Spec.Template.Spec.Containers {
+		existingContainers[c.Name] = c
+	}
+	for _, requiredContainer := range required.Spec.Template.Spec.Containers {
+		path := fmt.Sprintf("container/%s", requiredContainer.Name)
+		existingContainer, ok := existingContainers[requiredContainer.Name]
+		if !ok {
+			drift = append(drift, path)
+			continue
+		}
+		if existingContainer.Image != requiredContainer.Image {
+			drift = append(drift, path+" image")
+		}
+		if !equality.Semantic.DeepEqual(existingContainer.Command, requiredContainer.Command) {
+			drift = append(drift, path+" command")
+		}
+		if !equality.Semantic.DeepEqual(existingContainer.Args, requiredContainer.Args) {
+			drift = append(drift, path+" args")
+		}
+		if !equality.Semantic.DeepEqual(envVarKeys(existingContainer.Env), envVarKeys(requiredContainer.Env)) {
+			drift = append(drift, path+" env")
+		}
+		if !equality.Semantic.DeepEqual(existingContainer.Resources, requiredContainer.Resources) {
+			drift = append(drift, path+" resources")
+		}
+	}
+	return drift
+}
+
+// envVarKeys reduces env vars to their name and value, or the field path they are sourced from,
+// so that API server defaulting of the value sources does not count as drift.
+func envVarKeys(env []corev1.EnvVar) []string {
+	keys := make([]string, 0, len(env))
+	for _, e := range env {
+		switch {
+		case e.ValueFrom != nil && e.ValueFrom.FieldRef != nil:
+			keys = append(keys, fmt.Sprintf("%s<-%s", e.Name, e.ValueFrom.FieldRef.FieldPath))
+		case e.ValueFrom != nil:
+			keys = append(keys, fmt.Sprintf("%s<-%s", e.Name, e.ValueFrom.String()))
+		default:
+			keys = append(keys, fmt.Sprintf("%s=%s", e.Name, e.Value))
+		}
+	}
+	return keys
+}
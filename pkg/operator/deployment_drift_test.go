@@ -0,0 +1,164 @@
+package operator
+
+import (
+	"context"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/clock"
+	"k8s.io/utils/ptr"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/resource/resourcemerge"
+)
+
+func driftTestDeployment() *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "controller-manager",
+			Namespace: "openshift-controller-manager",
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: ptr.To[int32](3),
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{
+							Name:  "controller-manager",
+							Image: "my.co/repo/img:latest",
+							Args:  []string{"--config=/var/run/configmaps/config/config.yaml", "-v=2"},
+							Env: []corev1.EnvVar{
+								{
+									Name: "POD_NAME",
+									ValueFrom: &corev1.EnvVarSource{
+										FieldRef: &corev1.ObjectFieldSelector{FieldPath: "metadata.name"},
+									},
+								},
+							},
+							Resources: corev1.ResourceRequirements{
+								Requests: corev1.ResourceList{
+									corev1.ResourceMemory: resource.MustParse("100Mi"),
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestDeploymentDrift(t *testing.T) {
+	tests := []struct {
+		name     string
+		edit     func(*appsv1.Deployment)
+		expected []string
+	}{
+		{
+			name: "no drift",
+			edit: func(*appsv1.Deployment) {},
+		},
+		{
+			name: "defaulted field ref api version is not drift",
+			edit: func(d *appsv1.Deployment) {
+				d.Spec.Template.Spec.Containers[0].Env[0].ValueFrom.FieldRef.APIVersion = "v1"
+				d.Spec.Template.Spec.Containers[0].TerminationMessagePath = "/dev/termination-log"
+			},
+		},
+		{
+			name: "replicas scaled",
+			edit: func(d *appsv1.Deployment) {
+				d.Spec.Replicas = ptr.To[int32](1)
+			},
+			expected: []string{"spec.replicas"},
+		},
+		{
+			name: "env var and image edited",
+			edit: func(d *appsv1.Deployment) {
+				d.Spec.Template.Spec.Containers[0].Image = "my.co/repo/other:latest"
+				d.Spec.Template.Spec.Containers[0].Env = append(d.Spec.Template.Spec.Containers[0].Env, corev1.EnvVar{Name: "FOO", Value: "bar"})
+			},
+			expected: []string{"container/controller-manager image", "container/controller-manager env"},
+		},
+		{
+			name: "resources edited",
+			edit: func(d *appsv1.Deployment) {
+				d.Spec.Template.Spec.Containers[0].Resources.Requests[corev1.ResourceMemory] = resource.MustParse("1Gi")
+			},
+			expected: []string{"container/controller-manager resources"},
+		},
+		{
+			name: "container removed",
+			edit: func(d *appsv1.Deployment) {
+				d.Spec.Template.Spec.Containers = nil
+			},
+			expected: []string{"container/controller-manager"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			existing := driftTestDeployment()
+			tc.edit(existing)
+			drift := deploymentDrift(driftTestDeployment(), existing)
+			if !equality.Semantic.DeepEqual(drift, tc.expected) {
+				t.Errorf("expected drift %v, got %v", tc.expected, drift)
+			}
+		})
+	}
+}
+
+func TestApplyDeploymentRevertingDrift(t *testing.T) {
+	kubeClient := fake.NewSimpleClientset()
+	recorder := events.NewInMemoryRecorder("", clock.RealClock{})
+
+	applied, _, err := applyDeploymentRevertingDrift(kubeClient.AppsV1(), recorder, driftTestDeployment(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var generations []operatorv1.GenerationStatus
+	resourcemerge.SetDeploymentGeneration(&generations, applied)
+
+	// an unchanged deployment must not be written again
+	_, modified, err := applyDeploymentRevertingDrift(kubeClient.AppsV1(), recorder, driftTestDeployment(), generations)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if modified {
+		t.Fatal("expected no update without drift")
+	}
+
+	// edit the deployment out-of-band without touching its generation
+	edited := applied.DeepCopy()
+	edited.Spec.Template.Spec.Containers[0].Env = append(edited.Spec.Template.Spec.Containers[0].Env, corev1.EnvVar{Name: "FOO", Value: "bar"})
+	if _, err := kubeClient.AppsV1().Deployments(edited.Namespace).Update(context.TODO(), edited, metav1.UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	reverted, modified, err := applyDeploymentRevertingDrift(kubeClient.AppsV1(), recorder, driftTestDeployment(), generations)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !modified {
+		t.Fatal("expected the drifted deployment to be updated")
+	}
+	if drift := deploymentDrift(driftTestDeployment(), reverted); len(drift) > 0 {
+		t.Errorf("expected drift to be reverted, still drifted: %v", drift)
+	}
+
+	found := false
+	for _, event := range recorder.Events() {
+		if event.Reason == "DeploymentDriftDetected" {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("expected a DeploymentDriftDetected event")
+	}
+}
@@ -0,0 +1,23 @@
+package operator
+
+import (
+	"time"
+
+	"k8s.io/client-go/tools/cache"
+)
+
+// deploymentEventDelay is how long the sync triggered by an update of an operand deployment waits for
+// further updates. The status of a deployment is updated for every replica during a rollout, the queue
+// collapses the updates within the delay into a single sync.
+const deploymentEventDelay = time.Second
+
+// deploymentEventHandler queues the operator to check the operand deployments. The updates are queued
+// after deploymentEventDelay, the queue keeps the earliest of the pending requeues of the single key so a
+// burst of updates does not postpone the sync indefinitely. Additions and deletions are queued right away.
+func (c *OpenShiftControllerManagerOperator) deploymentEventHandler() cache.ResourceEventHandler {
+	return cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(obj interface{}) { c.queue.Add(workQueueKey) },
+		UpdateFunc: func(old, new interface{}) { c.queue.AddAfter(workQueueKey, deploymentEventDelay) },
+		DeleteFunc: func(obj interface{}) { c.queue.Add(workQueueKey) },
+	}
+}
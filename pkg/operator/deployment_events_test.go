@@ -0,0 +1,60 @@
+package operator
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/workqueue"
+)
+
+func TestDeploymentEventHandlerCoalescesUpdates(t *testing.T) {
+	c := &OpenShiftControllerManagerOperator{
+		queue: workqueue.NewNamedRateLimitingQueue(newRequeueRateLimiter(), "test"),
+	}
+	defer c.queue.ShutDown()
+
+	var syncs atomic.Int32
+	go func() {
+		for {
+			key, quit := c.queue.Get()
+			if quit {
+				return
+			}
+			syncs.Add(1)
+			c.queue.Done(key)
+		}
+	}()
+
+	// a rollout updates the status of the deployment for every replica
+	handler := c.deploymentEventHandler()
+	for i := 0; i < 50; i++ {
+		old := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "controller-manager", ResourceVersion: "1"}}
+		updated := old.DeepCopy()
+		updated.Status.UpdatedReplicas = int32(i)
+		handler.OnUpdate(old, updated)
+		time.Sleep(5 * time.Millisecond)
+	}
+	if got := syncs.Load(); got != 0 {
+		t.Errorf("expected the updates to wait for %s, got %d syncs", deploymentEventDelay, got)
+	}
+
+	time.Sleep(deploymentEventDelay + 500*time.Millisecond)
+	if got := syncs.Load(); got != 1 {
+		t.Errorf("expected the updates to be collapsed into a single sync, got %d syncs", got)
+	}
+}
+
+func TestDeploymentEventHandlerDeletion(t *testing.T) {
+	c := &OpenShiftControllerManagerOperator{
+		queue: workqueue.NewNamedRateLimitingQueue(newRequeueRateLimiter(), "test"),
+	}
+	defer c.queue.ShutDown()
+
+	c.deploymentEventHandler().OnDelete(&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "controller-manager"}})
+	if got := c.queue.Len(); got != 1 {
+		t.Errorf("expected the deletion to be queued right away, got %d queued items", got)
+	}
+}
@@ -0,0 +1,51 @@
+package operator
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+)
+
+const (
+	// rolloutMinReadySeconds is how long a new pod must be ready before the rollout stops the next old one, so
+	// that it has taken over the controllers by then.
+	rolloutMinReadySeconds = 30
+	// preStopSleepSeconds delays the termination of an old pod for the in-flight syncs of its controllers to
+	// complete.
+	preStopSleepSeconds = 15
+	// terminationGracePeriodSeconds is how long an old pod may take to shut down its controllers, including
+	// the pre-stop sleep.
+	terminationGracePeriodSeconds = 90
+)
+
+// ensureGracefulRollout configures the deployment to replace its pods one at a time, each old pod being stopped
+// only after its replacement settled and given the time to finish its in-flight work, so that a config change
+// does not interrupt the build or route controllers mid-operation.
+func ensureGracefulRollout(spec *appsv1.DeploymentSpec, containerName string) {
+	// a surged pod could not be scheduled, the anti-affinity allows a single pod per master node
+	maxSurge := intstr.FromInt32(0)
+	maxUnavailable := intstr.FromInt32(1)
+	spec.Strategy = appsv1.DeploymentStrategy{
+		Type: appsv1.RollingUpdateDeploymentStrategyType,
+		RollingUpdate: &appsv1.RollingUpdateDeployment{
+			MaxSurge:       &maxSurge,
+			MaxUnavailable: &maxUnavailable,
+		},
+	}
+	spec.MinReadySeconds = rolloutMinReadySeconds
+	spec.Template.Spec.TerminationGracePeriodSeconds = ptr.To[int64](terminationGracePeriodSeconds)
+
+	for i := range spec.Template.Spec.Containers {
+		container := &spec.Template.Spec.Containers[i]
+		if container.Name != containerName {
+			continue
+		}
+		if container.Lifecycle == nil {
+			container.Lifecycle = &corev1.Lifecycle{}
+		}
+		container.Lifecycle.PreStop = &corev1.LifecycleHandler{
+			Sleep: &corev1.SleepAction{Seconds: preStopSleepSeconds},
+		}
+	}
+}
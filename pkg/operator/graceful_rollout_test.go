@@ -0,0 +1,92 @@
+package operator
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/utils/clock"
+	"k8s.io/utils/ptr"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	configlistersv1 "github.com/openshift/client-go/config/listers/config/v1"
+	workloadcontroller "github.com/openshift/library-go/pkg/operator/apiserver/controller/workload"
+	"github.com/openshift/library-go/pkg/operator/events"
+
+	"github.com/openshift/cluster-openshift-controller-manager-operator/bindata"
+)
+
+func TestGracefulRolloutStrategy(t *testing.T) {
+	countNodes := func(nodeSelector map[string]string) (*int32, error) {
+		result := int32(3)
+		return &result, nil
+	}
+	ocmDeployment, _, err := manageOpenShiftControllerManagerDeployment_v311_00_to_latest(
+		bindata.MustAsset,
+		fake.NewSimpleClientset().AppsV1(),
+		countNodes,
+		workloadcontroller.EnsureAtMostOnePodPerNode,
+		events.NewInMemoryRecorder("", clock.RealClock{}),
+		&operatorv1.OpenShiftControllerManager{ObjectMeta: metav1.ObjectMeta{Name: "cluster"}},
+		"my.co/repo/img:latest",
+		nil,
+		configlistersv1.NewProxyLister(cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})),
+		map[string]string{},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rcmDeployment, _, err := manageRouteControllerManagerDeployment_v311_00_to_latest(
+		fake.NewSimpleClientset().AppsV1(),
+		countNodes,
+		workloadcontroller.EnsureAtMostOnePodPerNode,
+		events.NewInMemoryRecorder("", clock.RealClock{}),
+		&operatorv1.OpenShiftControllerManager{ObjectMeta: metav1.ObjectMeta{Name: "cluster"}},
+		"my.co/repo/img:latest",
+		nil,
+		map[string]string{},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	maxSurge := intstr.FromInt32(0)
+	maxUnavailable := intstr.FromInt32(1)
+	expectedStrategy := appsv1.DeploymentStrategy{
+		Type: appsv1.RollingUpdateDeploymentStrategyType,
+		RollingUpdate: &appsv1.RollingUpdateDeployment{
+			MaxSurge:       &maxSurge,
+			MaxUnavailable: &maxUnavailable,
+		},
+	}
+	expectedPreStop := &corev1.LifecycleHandler{Sleep: &corev1.SleepAction{Seconds: preStopSleepSeconds}}
+	for container, deployment := range map[string]*appsv1.Deployment{
+		"controller-manager":       ocmDeployment,
+		"route-controller-manager": rcmDeployment,
+	} {
+		t.Run(deployment.Name, func(t *testing.T) {
+			if !equality.Semantic.DeepEqual(expectedStrategy, deployment.Spec.Strategy) {
+				t.Errorf("unexpected strategy:\n%s", cmp.Diff(expectedStrategy, deployment.Spec.Strategy))
+			}
+			if deployment.Spec.MinReadySeconds != rolloutMinReadySeconds {
+				t.Errorf("expected minReadySeconds %d, got %d", rolloutMinReadySeconds, deployment.Spec.MinReadySeconds)
+			}
+			if !equality.Semantic.DeepEqual(ptr.To[int64](terminationGracePeriodSeconds), deployment.Spec.Template.Spec.TerminationGracePeriodSeconds) {
+				t.Errorf("expected terminationGracePeriodSeconds %d, got %v", terminationGracePeriodSeconds, deployment.Spec.Template.Spec.TerminationGracePeriodSeconds)
+			}
+
+			lifecycle := deployment.Spec.Template.Spec.Containers[0].Lifecycle
+			if lifecycle == nil || !equality.Semantic.DeepEqual(expectedPreStop, lifecycle.PreStop) {
+				t.Errorf("expected the %s container to sleep %ds before it is stopped, got %v", container, preStopSleepSeconds, lifecycle)
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package operator
+
+import (
+	"fmt"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	corelistersv1 "k8s.io/client-go/listers/core/v1"
+
+	operatorapiv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+)
+
+const (
+	imagePullDegradedType         = "OperandImagePullDegraded"
+	operandImagePullFailureReason = "OperandImagePullFailure"
+)
+
+// imagePullFailures are the waiting reasons of a container whose image cannot be pulled.
+var imagePullFailures = sets.New("ErrImagePull", "ImagePullBackOff", "InvalidImageName")
+
+// setImagePullDegradedCondition degrades the operand as soon as a pod of its deployment cannot pull the
+// image of a container, e.g. because of a bad image override or an unreachable registry, naming the image
+// and the error of the kubelet. Unlike a stuck rollout this does not resolve by waiting, so it does not
+// wait for rolloutStuckTimeout. Failing to list the pods keeps the condition as it is.
+func setImagePullDegradedCondition(
+	operatorConfig *operatorapiv1.OpenShiftControllerManager,
+	deployment *appsv1.Deployment,
+	pods corelistersv1.PodNamespaceLister,
+	conditionTypePrefix string,
+) {
+	deploymentPods, ok := deploymentPods(pods, deployment)
+	if !ok {
+		return
+	}
+
+	var messages []string
+	for _, pod := range deploymentPods {
+		images := map[string]string{}
+		for _, container := range append(pod.Spec.InitContainers, pod.Spec.Containers...) {
+			images[container.Name] = container.Image
+		}
+		for _, status := range append(pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses...) {
+			if status.State.Waiting == nil || !imagePullFailures.Has(status.State.Waiting.Reason) {
+				continue
+			}
+			image := status.Image
+			if len(image) == 0 {
+				image = images[status.Name]
+			}
+			messages = append(messages, fmt.Sprintf("pod/%s container %q: image %q: %s: %s", pod.Name, status.Name, image, status.State.Waiting.Reason, status.State.Waiting.Message))
+		}
+	}
+
+	condition := operatorapiv1.OperatorCondition{
+		Type:   conditionTypePrefix + imagePullDegradedType,
+		Status: operatorapiv1.ConditionFalse,
+	}
+	if len(messages) > 0 {
+		condition.Status = operatorapiv1.ConditionTrue
+		condition.Reason = operandImagePullFailureReason
+		condition.Message = fmt.Sprintf("deployment/%s -n %s: pods cannot pull their images\n%s", deployment.Name, deployment.Namespace, strings.Join(messages, "\n"))
+	}
+	v1helpers.SetOperatorCondition(&operatorConfig.Status.Conditions, condition)
+}
@@ -0,0 +1,79 @@
+package operator
+
+import (
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+)
+
+func TestSetImagePullDegradedCondition(t *testing.T) {
+	running := corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}
+	imagePullBackOff := corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{
+		Reason:  "ImagePullBackOff",
+		Message: `Back-off pulling image "quay.io/bad/image:latest"`,
+	}}
+	crashLoopBackOff := corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}}
+	withImage := func(pod *corev1.Pod, image string) *corev1.Pod {
+		pod.Spec.Containers = []corev1.Container{{Name: "controller-manager", Image: image}}
+		return pod
+	}
+
+	tests := []struct {
+		name             string
+		pods             []runtime.Object
+		expectedStatus   operatorv1.ConditionStatus
+		expectedReason   string
+		expectedMessages []string
+	}{
+		{
+			name: "pod in ImagePullBackOff",
+			pods: []runtime.Object{
+				rolloutPod("controller-manager-old-1", running),
+				withImage(rolloutPod("controller-manager-new-1", imagePullBackOff), "quay.io/bad/image:latest"),
+			},
+			expectedStatus: operatorv1.ConditionTrue,
+			expectedReason: operandImagePullFailureReason,
+			expectedMessages: []string{
+				"deployment/controller-manager -n openshift-controller-manager: pods cannot pull their images",
+				`pod/controller-manager-new-1 container "controller-manager": image "quay.io/bad/image:latest": ImagePullBackOff: Back-off pulling image "quay.io/bad/image:latest"`,
+			},
+		},
+		{
+			name:           "healthy pods",
+			pods:           []runtime.Object{rolloutPod("controller-manager-1", running), rolloutPod("controller-manager-2", running)},
+			expectedStatus: operatorv1.ConditionFalse,
+		},
+		{
+			name:           "other container failures are left to the rollout",
+			pods:           []runtime.Object{rolloutPod("controller-manager-1", crashLoopBackOff)},
+			expectedStatus: operatorv1.ConditionFalse,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			operatorConfig := &operatorv1.OpenShiftControllerManager{}
+			setImagePullDegradedCondition(operatorConfig, stuckDeployment(), podLister(t, tc.pods...), "")
+
+			condition := v1helpers.FindOperatorCondition(operatorConfig.Status.Conditions, imagePullDegradedType)
+			if condition == nil {
+				t.Fatalf("expected a %s condition", imagePullDegradedType)
+			}
+			if condition.Status != tc.expectedStatus {
+				t.Errorf("expected %s=%s, got %s", imagePullDegradedType, tc.expectedStatus, condition.Status)
+			}
+			if condition.Reason != tc.expectedReason {
+				t.Errorf("expected reason %q, got %q", tc.expectedReason, condition.Reason)
+			}
+			for _, message := range tc.expectedMessages {
+				if !strings.Contains(condition.Message, message) {
+					t.Errorf("expected message to contain %q, got %q", message, condition.Message)
+				}
+			}
+		})
+	}
+}
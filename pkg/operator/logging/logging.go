@@ -0,0 +1,85 @@
+package logging
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"k8s.io/klog/v2"
+)
+
+// The keys of the structured log lines of the operator controllers, so that the lines of a controller,
+// of an object or of a config change can be found with the same query whichever controller logged them.
+const (
+	// ControllerKey is the name of the controller which logged the line.
+	ControllerKey = "controller"
+	// ObjectKey is the object the controller reconciled, as namespace/name.
+	ObjectKey = "object"
+	// ObserverKey is the name of the config observer which logged the line.
+	ObserverKey = "observer"
+	// ChangedKeysKey lists the dotted paths of the observed config which changed.
+	ChangedKeysKey = "changedKeys"
+)
+
+// ForController returns logger with the name of the controller on every line.
+func ForController(logger klog.Logger, controller string) klog.Logger {
+	return logger.WithValues(ControllerKey, controller)
+}
+
+// FromContext returns the logger of ctx with the name of the controller on every line. Controllers use
+// it in their sync so that tests can capture the lines with klog.NewContext.
+func FromContext(ctx context.Context, controller string) klog.Logger {
+	return ForController(klog.FromContext(ctx), controller)
+}
+
+// WithObject returns logger with the object the controller reconciles on every line.
+func WithObject(logger klog.Logger, obj klog.KMetadata) klog.Logger {
+	return logger.WithValues(ObjectKey, klog.KObj(obj))
+}
+
+// ChangedKeys returns the sorted dotted paths of the values which differ between the existing and the
+// observed config, including the ones only in either of them. Lists are compared as a whole.
+func ChangedKeys(existing, observed map[string]interface{}) []string {
+	changed := map[string]bool{}
+	diffConfig(existing, observed, nil, changed)
+	keys := make([]string, 0, len(changed))
+	for key := range changed {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func diffConfig(existing, observed map[string]interface{}, prefix []string, changed map[string]bool) {
+	visit := func(key string) {
+		path := append(append([]string{}, prefix...), key)
+		existingValue, inExisting := existing[key]
+		observedValue, inObserved := observed[key]
+		existingNested, existingIsMap := existingValue.(map[string]interface{})
+		observedNested, observedIsMap := observedValue.(map[string]interface{})
+		switch {
+		// a map only in either config changed at each of its values
+		case (existingIsMap || !inExisting) && (observedIsMap || !inObserved):
+			diffConfig(existingNested, observedNested, path, changed)
+		case inExisting != inObserved || !jsonEqual(existingValue, observedValue):
+			changed[strings.Join(path, ".")] = true
+		}
+	}
+	for key := range existing {
+		visit(key)
+	}
+	for key := range observed {
+		if _, ok := existing[key]; !ok {
+			visit(key)
+		}
+	}
+}
+
+// jsonEqual compares the values as serialized, the existing config is decoded from JSON and so has
+// float64 numbers where an observer may return integers.
+func jsonEqual(a, b interface{}) bool {
+	aJSON, aErr := json.Marshal(a)
+	bJSON, bErr := json.Marshal(b)
+	return aErr == nil && bErr == nil && string(aJSON) == string(bJSON)
+}
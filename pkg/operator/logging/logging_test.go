@@ -0,0 +1,66 @@
+package logging
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr/funcr"
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+)
+
+func TestChangedKeys(t *testing.T) {
+	existing := map[string]interface{}{
+		"servingInfo": map[string]interface{}{
+			"minTLSVersion": "VersionTLS12",
+			"cipherSuites":  []interface{}{"TLS_AES_128_GCM_SHA256"},
+		},
+		"network": map[string]interface{}{
+			"clusterNetworks": []interface{}{map[string]interface{}{"hostSubnetLength": float64(9)}},
+		},
+		"build": map[string]interface{}{"buildDefaults": map[string]interface{}{"gitNoProxy": "example.com"}},
+	}
+	observed := map[string]interface{}{
+		"servingInfo": map[string]interface{}{
+			"minTLSVersion": "VersionTLS13",
+			"cipherSuites":  []interface{}{"TLS_AES_128_GCM_SHA256"},
+		},
+		// an integer observed for a float64 in the existing config is the same value
+		"network": map[string]interface{}{
+			"clusterNetworks": []interface{}{map[string]interface{}{"hostSubnetLength": int64(9)}},
+		},
+		"deployer": map[string]interface{}{"imageTemplateFormat": map[string]interface{}{"format": "quay.io/deployer"}},
+	}
+
+	expected := []string{"build.buildDefaults.gitNoProxy", "deployer.imageTemplateFormat.format", "servingInfo.minTLSVersion"}
+	if diff := cmp.Diff(expected, ChangedKeys(existing, observed)); len(diff) > 0 {
+		t.Errorf("unexpected changed keys (-want +got):\n%s", diff)
+	}
+	if keys := ChangedKeys(existing, existing); len(keys) > 0 {
+		t.Errorf("expected no changed keys, got %v", keys)
+	}
+}
+
+func TestFromContext(t *testing.T) {
+	var lines []string
+	logger := funcr.New(func(prefix, args string) { lines = append(lines, args) }, funcr.Options{})
+	ctx := klog.NewContext(context.Background(), logger)
+
+	obj := &metav1.ObjectMeta{Namespace: "openshift-controller-manager", Name: "pull-secret"}
+	WithObject(FromContext(ctx, "TestController"), obj).Info("Synced", ChangedKeysKey, []string{"auths"})
+
+	if len(lines) != 1 {
+		t.Fatalf("expected a log line, got %v", lines)
+	}
+	for _, expected := range []string{
+		`"controller"="TestController"`,
+		`"object"={"name"="pull-secret" "namespace"="openshift-controller-manager"}`,
+		`"changedKeys"=["auths"]`,
+	} {
+		if !strings.Contains(lines[0], expected) {
+			t.Errorf("expected %s in %s", expected, lines[0])
+		}
+	}
+}
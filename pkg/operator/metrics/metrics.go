@@ -0,0 +1,89 @@
+package metrics
+
+import (
+	"bytes"
+	"encoding/json"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/component-base/metrics/legacyregistry"
+	// instruments the workqueues of the controllers in the legacy registry, following the Kubernetes
+	// naming: workqueue_depth and workqueue_retries_total among others, labeled by the queue name
+	_ "k8s.io/component-base/metrics/prometheus/workqueue"
+	"k8s.io/klog/v2"
+
+	"github.com/openshift/library-go/pkg/operator/configobserver"
+	"github.com/openshift/library-go/pkg/operator/events"
+
+	"github.com/openshift/cluster-openshift-controller-manager-operator/pkg/operator/logging"
+)
+
+var (
+	// ReconcileDuration is the time a controller sync took, labeled by controller.
+	ReconcileDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "openshift_controller_manager_operator_reconcile_duration_seconds",
+			Help:    "Time in seconds a sync of an OpenShift Controller Manager Operator controller took.",
+			Buckets: prometheus.ExponentialBuckets(0.01, 2, 14),
+		},
+		[]string{"controller"},
+	)
+
+	// ObservedConfigChanges counts the observations that changed the observed config, labeled by observer.
+	ObservedConfigChanges = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "openshift_controller_manager_operator_observed_config_changes_total",
+			Help: "Number of times a config observer of the OpenShift Controller Manager Operator changed the observed config.",
+		},
+		[]string{"observer"},
+	)
+)
+
+func init() {
+	// the metrics endpoint of the operator serves the legacy registry, not the default prometheus one
+	legacyregistry.RawMustRegister(ReconcileDuration, ObservedConfigChanges)
+}
+
+// ObserveReconcileDuration records the duration of a sync of the given controller, which started at start.
+// It is meant to be deferred at the beginning of the sync.
+func ObserveReconcileDuration(controller string, start time.Time) {
+	ReconcileDuration.WithLabelValues(controller).Observe(time.Since(start).Seconds())
+}
+
+// InstrumentObserveConfigFunc returns the given observer counting and logging the observations which change the
+// part of the existing config it observed. Observations of an empty config are not counted.
+func InstrumentObserveConfigFunc(name string, observe configobserver.ObserveConfigFunc) configobserver.ObserveConfigFunc {
+	logger := logging.ForController(klog.Background(), "ConfigObserver").WithValues(logging.ObserverKey, name)
+	return func(listers configobserver.Listers, recorder events.Recorder, existingConfig map[string]interface{}) (map[string]interface{}, []error) {
+		observedConfig, errs := observe(listers, recorder, existingConfig)
+		if paths := leafPaths(observedConfig, nil); len(paths) > 0 {
+			if existing := configobserver.Pruned(existingConfig, paths...); !jsonEqual(observedConfig, existing) {
+				ObservedConfigChanges.WithLabelValues(name).Inc()
+				logger.V(2).Info("Observed config changed", logging.ChangedKeysKey, logging.ChangedKeys(existing, observedConfig))
+			}
+		}
+		return observedConfig, errs
+	}
+}
+
+// jsonEqual compares the configs as serialized, the existing config is decoded from JSON and so has float64
+// numbers where an observer may return integers.
+func jsonEqual(a, b map[string]interface{}) bool {
+	aJSON, aErr := json.Marshal(a)
+	bJSON, bErr := json.Marshal(b)
+	return aErr == nil && bErr == nil && bytes.Equal(aJSON, bJSON)
+}
+
+// leafPaths returns the paths to the values of the config which are not maps themselves.
+func leafPaths(config map[string]interface{}, prefix []string) [][]string {
+	var paths [][]string
+	for key, value := range config {
+		path := append(append([]string{}, prefix...), key)
+		if nested, ok := value.(map[string]interface{}); ok && len(nested) > 0 {
+			paths = append(paths, leafPaths(nested, path)...)
+			continue
+		}
+		paths = append(paths, path)
+	}
+	return paths
+}
@@ -0,0 +1,121 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/component-base/metrics/legacyregistry"
+	"k8s.io/utils/clock"
+
+	"github.com/openshift/library-go/pkg/operator/configobserver"
+	"github.com/openshift/library-go/pkg/operator/events"
+)
+
+func TestInstrumentObserveConfigFunc(t *testing.T) {
+	tests := []struct {
+		name           string
+		existingConfig map[string]interface{}
+		observedConfig map[string]interface{}
+		expectChange   bool
+	}{
+		{
+			name:           "first observation",
+			existingConfig: map[string]interface{}{},
+			observedConfig: map[string]interface{}{"network": map[string]interface{}{"serviceNetworkCIDR": "172.30.0.0/16"}},
+			expectChange:   true,
+		},
+		{
+			name: "unchanged observation",
+			existingConfig: map[string]interface{}{
+				"network": map[string]interface{}{
+					"serviceNetworkCIDR": "172.30.0.0/16",
+					"clusterNetworks":    []interface{}{map[string]interface{}{"cidr": "10.128.0.0/14", "hostSubnetLength": float64(9)}},
+				},
+				"build": map[string]interface{}{"additionalTrustedCA": "/ca.crt"},
+			},
+			observedConfig: map[string]interface{}{
+				"network": map[string]interface{}{
+					"serviceNetworkCIDR": "172.30.0.0/16",
+					"clusterNetworks":    []interface{}{map[string]interface{}{"cidr": "10.128.0.0/14", "hostSubnetLength": int64(9)}},
+				},
+			},
+		},
+		{
+			name:           "changed observation",
+			existingConfig: map[string]interface{}{"network": map[string]interface{}{"serviceNetworkCIDR": "172.30.0.0/16"}},
+			observedConfig: map[string]interface{}{"network": map[string]interface{}{"serviceNetworkCIDR": "172.31.0.0/16"}},
+			expectChange:   true,
+		},
+		{
+			name:           "empty observation",
+			existingConfig: map[string]interface{}{"network": map[string]interface{}{"serviceNetworkCIDR": "172.30.0.0/16"}},
+			observedConfig: map[string]interface{}{},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			observe := InstrumentObserveConfigFunc(tc.name, func(configobserver.Listers, events.Recorder, map[string]interface{}) (map[string]interface{}, []error) {
+				return tc.observedConfig, nil
+			})
+			before := testutil.ToFloat64(ObservedConfigChanges.WithLabelValues(tc.name))
+			observe(nil, events.NewInMemoryRecorder("", clock.RealClock{}), tc.existingConfig)
+			changes := testutil.ToFloat64(ObservedConfigChanges.WithLabelValues(tc.name)) - before
+			if tc.expectChange && changes != 1 {
+				t.Errorf("expected the change to be counted once, counted %v", changes)
+			}
+			if !tc.expectChange && changes != 0 {
+				t.Errorf("expected no change to be counted, counted %v", changes)
+			}
+		})
+	}
+}
+
+// workqueueMetric returns the value of the named workqueue metric of the queue.
+func workqueueMetric(t *testing.T, metric, queue string) float64 {
+	t.Helper()
+	families, err := legacyregistry.DefaultGatherer.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, family := range families {
+		if family.GetName() != metric {
+			continue
+		}
+		for _, m := range family.GetMetric() {
+			for _, label := range m.GetLabel() {
+				if label.GetName() == "name" && label.GetValue() == queue {
+					if m.GetGauge() != nil {
+						return m.GetGauge().GetValue()
+					}
+					return m.GetCounter().GetValue()
+				}
+			}
+		}
+	}
+	t.Fatalf("no %s metric of the queue %s", metric, queue)
+	return 0
+}
+
+func TestWorkqueueMetrics(t *testing.T) {
+	queue := workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "TestWorkqueueMetrics")
+	defer queue.ShutDown()
+
+	for _, key := range []string{"a", "b", "c"} {
+		queue.Add(key)
+	}
+	if depth := workqueueMetric(t, "workqueue_depth", "TestWorkqueueMetrics"); depth != 3 {
+		t.Errorf("expected the depth of the enqueued items 3, got %v", depth)
+	}
+
+	key, _ := queue.Get()
+	if depth := workqueueMetric(t, "workqueue_depth", "TestWorkqueueMetrics"); depth != 2 {
+		t.Errorf("expected the depth 2 while an item is processed, got %v", depth)
+	}
+	queue.AddRateLimited(key)
+	queue.Done(key)
+	if retries := workqueueMetric(t, "workqueue_retries_total", "TestWorkqueueMetrics"); retries != 1 {
+		t.Errorf("expected the retry to be counted once, counted %v", retries)
+	}
+}
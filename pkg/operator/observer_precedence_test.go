@@ -0,0 +1,263 @@
+package operator
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/yaml"
+
+	configv1 "github.com/openshift/api/config/v1"
+	operatorv1 "github.com/openshift/api/operator/v1"
+	configlistersv1 "github.com/openshift/client-go/config/listers/config/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/configobserver"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-openshift-controller-manager-operator/pkg/operator/configobservation"
+	"github.com/openshift/cluster-openshift-controller-manager-operator/pkg/operator/configobservation/apiserver"
+	"github.com/openshift/cluster-openshift-controller-manager-operator/pkg/operator/configobservation/builds"
+	"github.com/openshift/cluster-openshift-controller-manager-operator/pkg/operator/configobservation/validation"
+)
+
+// staticObserver returns an observer writing value at the dotted path.
+func staticObserver(path string, value interface{}) configobserver.ObserveConfigFunc {
+	return func(configobserver.Listers, events.Recorder, map[string]interface{}) (map[string]interface{}, []error) {
+		observedConfig := map[string]interface{}{}
+		if err := unstructured.SetNestedField(observedConfig, value, strings.Split(path, ".")...); err != nil {
+			return nil, []error{err}
+		}
+		return observedConfig, nil
+	}
+}
+
+// TestObserverPrecedence documents which value ends up in the operand config when several sources set the
+// same key, running the config observer as it is wired in the operator and rendering the operand config
+// from its observed config:
+//   - the unsupportedConfigOverrides win over any observer, maps are merged key by key and lists replaced
+//     as a whole,
+//   - the observers win over the defaults of the operand config,
+//   - of observers writing the same key, the one given first to the validating observer wins,
+//   - observers combining several inputs document their own precedence, e.g. the git no-proxy list of the
+//     Build config wins over the one of the cluster proxy.
+func TestObserverPrecedence(t *testing.T) {
+	modern := &configv1.TLSSecurityProfile{Type: configv1.TLSProfileModernType, Modern: &configv1.ModernTLSProfile{}}
+	tlsObserver := func(operatorClient v1helpers.OperatorClient) configobserver.ObserveConfigFunc {
+		return apiserver.NewObserveTLSSecurityProfileFunc(operatorClient, &flakyAPIServerInformer{}, func(time.Duration) {}, &fakePassiveClock{now: time.Now()})
+	}
+	buildDefaultsObserver := func(v1helpers.OperatorClient) configobserver.ObserveConfigFunc {
+		return builds.ObserveBuildControllerConfig
+	}
+	gitNoProxyObserver := func(v1helpers.OperatorClient) configobserver.ObserveConfigFunc {
+		return builds.ObserveGitNoProxy
+	}
+	static := func(path string, value interface{}) func(v1helpers.OperatorClient) configobserver.ObserveConfigFunc {
+		return func(v1helpers.OperatorClient) configobserver.ObserveConfigFunc {
+			return staticObserver(path, value)
+		}
+	}
+
+	tests := []struct {
+		name       string
+		apiServer  configv1.APIServerSpec
+		build      configv1.BuildSpec
+		proxy      configv1.ProxyStatus
+		observers  []func(v1helpers.OperatorClient) configobserver.ObserveConfigFunc
+		overrides  string
+		path       string
+		expected   interface{}
+		unexpected []string
+	}{
+		{
+			name:      "TLS profile of the APIServer config without an override",
+			apiServer: configv1.APIServerSpec{TLSSecurityProfile: modern},
+			observers: []func(v1helpers.OperatorClient) configobserver.ObserveConfigFunc{tlsObserver},
+			path:      "servingInfo.minTLSVersion",
+			expected:  "VersionTLS13",
+		},
+		{
+			name:      "override wins over the TLS profile of the APIServer config",
+			apiServer: configv1.APIServerSpec{TLSSecurityProfile: modern},
+			observers: []func(v1helpers.OperatorClient) configobserver.ObserveConfigFunc{tlsObserver},
+			overrides: `{"servingInfo": {"minTLSVersion": "VersionTLS12"}}`,
+			path:      "servingInfo.minTLSVersion",
+			expected:  "VersionTLS12",
+		},
+		{
+			name:      "override of another serving key keeps the observed TLS version",
+			apiServer: configv1.APIServerSpec{TLSSecurityProfile: modern},
+			observers: []func(v1helpers.OperatorClient) configobserver.ObserveConfigFunc{tlsObserver},
+			overrides: `{"servingInfo": {"maxRequestsInFlight": 100}}`,
+			path:      "servingInfo.minTLSVersion",
+			expected:  "VersionTLS13",
+		},
+		{
+			name:      "build defaults of the Build config without an override",
+			build:     configv1.BuildSpec{BuildDefaults: configv1.BuildDefaults{Env: []corev1.EnvVar{{Name: "FROM_BUILD", Value: "1"}}}},
+			observers: []func(v1helpers.OperatorClient) configobserver.ObserveConfigFunc{buildDefaultsObserver},
+			path:      "build.buildDefaults.env",
+			expected:  []interface{}{map[string]interface{}{"name": "FROM_BUILD", "value": "1"}},
+		},
+		{
+			name:       "override replaces the build defaults of the Build config as a whole",
+			build:      configv1.BuildSpec{BuildDefaults: configv1.BuildDefaults{Env: []corev1.EnvVar{{Name: "FROM_BUILD", Value: "1"}}}},
+			observers:  []func(v1helpers.OperatorClient) configobserver.ObserveConfigFunc{buildDefaultsObserver},
+			overrides:  `{"build": {"buildDefaults": {"env": [{"name": "FROM_OVERRIDE", "value": "2"}]}}}`,
+			path:       "build.buildDefaults.env",
+			expected:   []interface{}{map[string]interface{}{"name": "FROM_OVERRIDE", "value": "2"}},
+			unexpected: []string{"FROM_BUILD"},
+		},
+		{
+			name:      "observer wins over the operand defaults",
+			observers: []func(v1helpers.OperatorClient) configobserver.ObserveConfigFunc{static("leaderElection.name", "observed")},
+			path:      "leaderElection.name",
+			expected:  "observed",
+		},
+		{
+			name:      "first observer wins over a later one writing the same key",
+			observers: []func(v1helpers.OperatorClient) configobserver.ObserveConfigFunc{static("build.buildDefaults.gitNoProxy", "first"), static("build.buildDefaults.gitNoProxy", "second")},
+			path:      "build.buildDefaults.gitNoProxy",
+			expected:  "first",
+		},
+		{
+			name:      "git no-proxy list of the Build config wins over the cluster proxy",
+			build:     configv1.BuildSpec{BuildDefaults: configv1.BuildDefaults{GitProxy: &configv1.ProxySpec{NoProxy: ".build.example.com"}}},
+			proxy:     configv1.ProxyStatus{NoProxy: ".proxy.example.com"},
+			observers: []func(v1helpers.OperatorClient) configobserver.ObserveConfigFunc{gitNoProxyObserver},
+			path:      "build.buildDefaults.gitNoProxy",
+			expected:  ".build.example.com",
+		},
+		{
+			name:      "override wins over the git no-proxy list of the cluster proxy",
+			proxy:     configv1.ProxyStatus{NoProxy: ".proxy.example.com"},
+			observers: []func(v1helpers.OperatorClient) configobserver.ObserveConfigFunc{gitNoProxyObserver},
+			overrides: `{"build": {"buildDefaults": {"gitNoProxy": ".override.example.com"}}}`,
+			path:      "build.buildDefaults.gitNoProxy",
+			expected:  ".override.example.com",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			operatorSpec := &operatorv1.OperatorSpec{ManagementState: operatorv1.Managed}
+			if len(tc.overrides) > 0 {
+				operatorSpec.UnsupportedConfigOverrides = runtime.RawExtension{Raw: []byte(tc.overrides)}
+			}
+			operatorClient := v1helpers.NewFakeOperatorClient(operatorSpec, &operatorv1.OperatorStatus{}, nil)
+			var observers []configobserver.ObserveConfigFunc
+			for _, observer := range tc.observers {
+				observers = append(observers, observer(operatorClient))
+			}
+
+			observedConfig := observeConfig(t, operatorClient, configInputs{apiServer: tc.apiServer, build: tc.build, proxy: tc.proxy}, observers...)
+			operandConfig := renderOperandConfig(t, observedConfig, operatorSpec.UnsupportedConfigOverrides.Raw)
+
+			value, found, err := unstructured.NestedFieldNoCopy(operandConfig, strings.Split(tc.path, ".")...)
+			if err != nil || !found {
+				t.Fatalf("expected %s in the operand config, got %v: %v", tc.path, operandConfig, err)
+			}
+			if diff := cmp.Diff(tc.expected, value); len(diff) > 0 {
+				t.Errorf("unexpected %s (-want +got):\n%s", tc.path, diff)
+			}
+			raw, err := json.Marshal(operandConfig)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, unexpected := range tc.unexpected {
+				if strings.Contains(string(raw), unexpected) {
+					t.Errorf("expected %q not to be in the operand config, got %s", unexpected, raw)
+				}
+			}
+		})
+	}
+}
+
+// configInputs are the cluster config objects the observers under test read.
+type configInputs struct {
+	apiServer configv1.APIServerSpec
+	build     configv1.BuildSpec
+	proxy     configv1.ProxyStatus
+}
+
+// observeConfig runs the observers once through the validating observer as the config observer controller
+// does and returns the observed config it wrote.
+func observeConfig(t *testing.T, operatorClient v1helpers.OperatorClient, inputs configInputs, observers ...configobserver.ObserveConfigFunc) []byte {
+	t.Helper()
+	indexer := func(obj interface{}) cache.Indexer {
+		indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+		if err := indexer.Add(obj); err != nil {
+			t.Fatal(err)
+		}
+		return indexer
+	}
+	listers := configobservation.Listers{
+		APIServerLister_:  configlistersv1.NewAPIServerLister(indexer(&configv1.APIServer{ObjectMeta: metav1.ObjectMeta{Name: "cluster"}, Spec: inputs.apiServer})),
+		BuildConfigLister: configlistersv1.NewBuildLister(indexer(&configv1.Build{ObjectMeta: metav1.ObjectMeta{Name: "cluster"}, Spec: inputs.build})),
+		ProxyLister:       configlistersv1.NewProxyLister(indexer(&configv1.Proxy{ObjectMeta: metav1.ObjectMeta{Name: "cluster"}, Status: inputs.proxy})),
+	}
+	recorder := events.NewInMemoryRecorder("", &fakePassiveClock{now: time.Now()})
+	observer := configobserver.NewConfigObserver(
+		"openshift-controller-manager",
+		operatorClient,
+		recorder,
+		listers,
+		nil,
+		validation.NewValidatingObserveConfigFunc(operatorClient, observers...),
+	)
+	if err := observer.Sync(context.TODO(), factory.NewSyncContext("test", recorder)); err != nil {
+		t.Fatal(err)
+	}
+
+	spec, _, _, err := operatorClient.GetOperatorState()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(spec.ObservedConfig.Raw) > 0 {
+		return spec.ObservedConfig.Raw
+	}
+	// the fake operator client keeps the written observed config as an object
+	raw, err := json.Marshal(spec.ObservedConfig.Object)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return raw
+}
+
+// renderOperandConfig renders the config of the openshift-controller-manager operand from the observed
+// config and the unsupportedConfigOverrides as the operator does and returns it decoded.
+func renderOperandConfig(t *testing.T, observedConfig, unsupportedConfigOverrides []byte) map[string]interface{} {
+	t.Helper()
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	if err := indexer.Add(&configv1.ClusterVersion{ObjectMeta: metav1.ObjectMeta{Name: "version"}}); err != nil {
+		t.Fatal(err)
+	}
+	kubeClient := fake.NewSimpleClientset()
+	operatorConfig := &operatorv1.OpenShiftControllerManager{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+		Spec: operatorv1.OpenShiftControllerManagerSpec{OperatorSpec: operatorv1.OperatorSpec{
+			ObservedConfig:             runtime.RawExtension{Raw: observedConfig},
+			UnsupportedConfigOverrides: runtime.RawExtension{Raw: unsupportedConfigOverrides},
+		}},
+	}
+	configMap, _, err := manageOpenShiftControllerManagerConfigMap_v311_00_to_latest(configlistersv1.NewClusterVersionLister(indexer), kubeClient, kubeClient.CoreV1(),
+		events.NewInMemoryRecorder("", &fakePassiveClock{now: time.Now()}), operatorConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	operandConfig := map[string]interface{}{}
+	if err := yaml.Unmarshal([]byte(configMap.Data["config.yaml"]), &operandConfig); err != nil {
+		t.Fatal(err)
+	}
+	return operandConfig
+}
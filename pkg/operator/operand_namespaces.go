@@ -0,0 +1,10 @@
+package operator
+
+// operandNamespaceAssets are the namespaces the operands run in. The static resource controller applies
+// them first and again whenever a namespace changes, which restores the labels and annotations they
+// require, e.g. the cluster monitoring label the ServiceMonitors of the operands are scraped by, and keeps
+// the labels and annotations set by others.
+var operandNamespaceAssets = []string{
+	"assets/openshift-controller-manager/ns.yaml",
+	"assets/openshift-controller-manager/route-controller-manager-ns.yaml",
+}
@@ -0,0 +1,74 @@
+package operator
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/clock"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/resource/resourceapply"
+	"github.com/openshift/library-go/pkg/operator/staticresourcecontroller"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-openshift-controller-manager-operator/bindata"
+	"github.com/openshift/cluster-openshift-controller-manager-operator/pkg/util"
+)
+
+func TestOperandNamespaceLabelsReconciled(t *testing.T) {
+	// the monitoring label was removed and the pod security level lowered, someone else added a label
+	kubeClient := fake.NewSimpleClientset(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+		Name: util.TargetNamespace,
+		Labels: map[string]string{
+			"openshift.io/run-level":             "",
+			"pod-security.kubernetes.io/enforce": "restricted",
+			"example.com/team":                   "builds",
+		},
+		Annotations: map[string]string{"example.com/owner": "platform"},
+	}})
+	recorder := events.NewInMemoryRecorder("", clock.RealClock{})
+	controller := staticresourcecontroller.NewStaticResourceController(
+		"OperandNamespaces",
+		bindata.Asset,
+		operandNamespaceAssets,
+		resourceapply.NewKubeClientHolder(kubeClient),
+		v1helpers.NewFakeOperatorClient(&operatorv1.OperatorSpec{ManagementState: operatorv1.Managed}, &operatorv1.OperatorStatus{}, nil),
+		recorder,
+	)
+	if err := controller.Sync(context.TODO(), factory.NewSyncContext("test", recorder)); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{util.TargetNamespace, util.RouteControllerTargetNamespace} {
+		namespace, err := kubeClient.CoreV1().Namespaces().Get(context.TODO(), name, metav1.GetOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for label, value := range map[string]string{
+			"openshift.io/cluster-monitoring":    "true",
+			"pod-security.kubernetes.io/enforce": "privileged",
+			"pod-security.kubernetes.io/audit":   "privileged",
+			"pod-security.kubernetes.io/warn":    "privileged",
+		} {
+			if namespace.Labels[label] != value {
+				t.Errorf("expected namespace/%s to have the label %s=%s, got %v", name, label, value, namespace.Labels)
+			}
+		}
+		if namespace.Annotations["workload.openshift.io/allowed"] != "management" {
+			t.Errorf("expected namespace/%s to allow the management workload partition, got %v", name, namespace.Annotations)
+		}
+	}
+
+	namespace, err := kubeClient.CoreV1().Namespaces().Get(context.TODO(), util.TargetNamespace, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if namespace.Labels["example.com/team"] != "builds" || namespace.Annotations["example.com/owner"] != "platform" {
+		t.Errorf("expected the unrelated label and annotation to be kept, got labels %v and annotations %v", namespace.Labels, namespace.Annotations)
+	}
+}
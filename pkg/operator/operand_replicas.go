@@ -0,0 +1,96 @@
+package operator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/yaml"
+
+	operatorapiv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+)
+
+const (
+	// operandReplicasKey is the key of the unsupportedConfigOverrides holding replica overrides for the
+	// operand deployments, by deployment name, e.g.
+	//
+	//	operandReplicas:
+	//	  controller-manager: 4
+	//
+	// so that the operands can be scaled up to check their leader election. The operands run at most one pod
+	// per control plane node, an override above their count is capped at it. The key is not passed on to the
+	// operand config.
+	operandReplicasKey = "operandReplicas"
+
+	operandReplicasDegradedType = "OperandReplicasDegraded"
+	invalidReplicasReason       = "InvalidOverride"
+	belowReplicaFloorReason     = "BelowReplicaFloor"
+)
+
+// operandDeployments are the operand deployments whose replicas can be overridden.
+var operandDeployments = sets.New("controller-manager", "route-controller-manager")
+
+// operandReplicaOverrides reads the replica overrides of the operand deployments from the
+// unsupportedConfigOverrides.
+func operandReplicaOverrides(unsupportedConfigOverrides []byte) (map[string]int32, error) {
+	if len(unsupportedConfigOverrides) == 0 {
+		return nil, nil
+	}
+	overrides := struct {
+		OperandReplicas map[string]int32 `json:"operandReplicas"`
+	}{}
+	if err := yaml.Unmarshal(unsupportedConfigOverrides, &overrides); err != nil {
+		return nil, fmt.Errorf("failed to read %s from unsupportedConfigOverrides: %v", operandReplicasKey, err)
+	}
+	for name := range overrides.OperandReplicas {
+		if !operandDeployments.Has(name) {
+			return nil, fmt.Errorf("invalid %s override: unknown deployment %q, expected one of %v", operandReplicasKey, name, sets.List(operandDeployments))
+		}
+	}
+	return overrides.OperandReplicas, nil
+}
+
+// applyReplicaOverrides overrides the replicas of the floors of the operand deployments, by deployment name,
+// with the replica overrides of the operator config. An override below the floor is rejected and the
+// deployment keeps the replicas the floor computes, as it does without an override. The
+// OperandReplicasDegraded condition names the rejected overrides.
+func applyReplicaOverrides(operatorConfig *operatorapiv1.OpenShiftControllerManager, floors map[string]*replicaFloor) {
+	condition := operatorapiv1.OperatorCondition{
+		Type:   operandReplicasDegradedType,
+		Status: operatorapiv1.ConditionFalse,
+	}
+	overrides, err := operandReplicaOverrides(operatorConfig.Spec.UnsupportedConfigOverrides.Raw)
+	if err != nil {
+		condition.Status = operatorapiv1.ConditionTrue
+		condition.Reason = invalidReplicasReason
+		condition.Message = err.Error()
+		v1helpers.SetOperatorCondition(&operatorConfig.Status.Conditions, condition)
+		return
+	}
+
+	// sorted for stable messages
+	names := make([]string, 0, len(overrides))
+	for name := range overrides {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var rejected []string
+	for _, name := range names {
+		floor, ok := floors[name]
+		if !ok {
+			continue
+		}
+		if err := floor.override(overrides[name]); err != nil {
+			rejected = append(rejected, fmt.Sprintf("invalid %s override for deployment %q: %v", operandReplicasKey, name, err))
+		}
+	}
+	if len(rejected) > 0 {
+		condition.Status = operatorapiv1.ConditionTrue
+		condition.Reason = belowReplicaFloorReason
+		condition.Message = strings.Join(rejected, "\n")
+	}
+	v1helpers.SetOperatorCondition(&operatorConfig.Status.Conditions, condition)
+}
@@ -0,0 +1,110 @@
+package operator
+
+import (
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+
+	configv1 "github.com/openshift/api/config/v1"
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+)
+
+func TestApplyReplicaOverrides(t *testing.T) {
+	tests := []struct {
+		name             string
+		overrides        string
+		expectedReplicas int32
+		expectedStatus   operatorv1.ConditionStatus
+		expectedReason   string
+		expectedMessage  string
+		expectedCapped   bool
+	}{
+		{
+			name:             "override above the floor is applied",
+			overrides:        `{"operandReplicas": {"controller-manager": 4}}`,
+			expectedReplicas: 4,
+			expectedStatus:   operatorv1.ConditionFalse,
+		},
+		{
+			name:             "override above the control plane nodes is capped",
+			overrides:        `{"operandReplicas": {"controller-manager": 7}}`,
+			expectedReplicas: 5,
+			expectedStatus:   operatorv1.ConditionFalse,
+			expectedCapped:   true,
+		},
+		{
+			name:             "override at the floor is applied",
+			overrides:        `{"operandReplicas": {"controller-manager": 2}}`,
+			expectedReplicas: 2,
+			expectedStatus:   operatorv1.ConditionFalse,
+		},
+		{
+			name:             "override below the floor is rejected",
+			overrides:        `{"operandReplicas": {"controller-manager": 1}}`,
+			expectedReplicas: 5,
+			expectedStatus:   operatorv1.ConditionTrue,
+			expectedReason:   belowReplicaFloorReason,
+			expectedMessage:  `invalid operandReplicas override for deployment "controller-manager": 1 replicas are below the floor of 2 for the "HighlyAvailable" control plane topology`,
+		},
+		{
+			name:             "unknown deployment is rejected",
+			overrides:        `{"operandReplicas": {"controller-manager-typo": 5}}`,
+			expectedReplicas: 5,
+			expectedStatus:   operatorv1.ConditionTrue,
+			expectedReason:   invalidReplicasReason,
+			expectedMessage:  `unknown deployment "controller-manager-typo"`,
+		},
+		{
+			name:             "cleared override returns to the computed replicas",
+			overrides:        `{"build": {}}`,
+			expectedReplicas: 5,
+			expectedStatus:   operatorv1.ConditionFalse,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			floor, err := newReplicaFloor(infrastructureLister(t, configv1.HighlyAvailableTopologyMode))
+			if err != nil {
+				t.Fatal(err)
+			}
+			operatorConfig := &operatorv1.OpenShiftControllerManager{
+				Spec: operatorv1.OpenShiftControllerManagerSpec{
+					OperatorSpec: operatorv1.OperatorSpec{
+						UnsupportedConfigOverrides: runtime.RawExtension{Raw: []byte(tc.overrides)},
+					},
+				},
+			}
+
+			applyReplicaOverrides(operatorConfig, map[string]*replicaFloor{"controller-manager": &floor})
+
+			// five control plane nodes
+			replicas, err := floor.countReplicas(func(map[string]string) (*int32, error) { return ptr.To[int32](5), nil })(nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if *replicas != tc.expectedReplicas {
+				t.Errorf("expected %d replicas, got %d", tc.expectedReplicas, *replicas)
+			}
+			if floor.capped != tc.expectedCapped {
+				t.Errorf("expected the override to be capped %t, got %t", tc.expectedCapped, floor.capped)
+			}
+
+			condition := v1helpers.FindOperatorCondition(operatorConfig.Status.Conditions, operandReplicasDegradedType)
+			if condition == nil {
+				t.Fatalf("expected a %s condition", operandReplicasDegradedType)
+			}
+			if condition.Status != tc.expectedStatus {
+				t.Errorf("expected %s=%s, got %s", operandReplicasDegradedType, tc.expectedStatus, condition.Status)
+			}
+			if condition.Reason != tc.expectedReason {
+				t.Errorf("expected reason %q, got %q", tc.expectedReason, condition.Reason)
+			}
+			if !strings.Contains(condition.Message, tc.expectedMessage) {
+				t.Errorf("expected the condition message to contain %q, got %q", tc.expectedMessage, condition.Message)
+			}
+		})
+	}
+}
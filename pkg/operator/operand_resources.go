@@ -0,0 +1,132 @@
+package operator
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/yaml"
+)
+
+// operandResourcesKey is the key of the unsupportedConfigOverrides holding resource requirement overrides
+// for the operand containers, by container name, e.g.
+//
+//	operandResources:
+//	  controller-manager:
+//	    requests:
+//	      memory: 200Mi
+//
+// The key is not passed on to the operand config.
+const operandResourcesKey = "operandResources"
+
+// operandContainers are the containers of the operand deployments whose resources can be overridden.
+var operandContainers = sets.New("controller-manager", "route-controller-manager")
+
+// operandResourceOverrides reads the resource requirement overrides of the operand containers from the
+// unsupportedConfigOverrides.
+func operandResourceOverrides(unsupportedConfigOverrides []byte) (map[string]corev1.ResourceRequirements, error) {
+	if len(unsupportedConfigOverrides) == 0 {
+		return nil, nil
+	}
+	overrides := struct {
+		OperandResources map[string]corev1.ResourceRequirements `json:"operandResources"`
+	}{}
+	if err := yaml.Unmarshal(unsupportedConfigOverrides, &overrides); err != nil {
+		return nil, fmt.Errorf("failed to read %s from unsupportedConfigOverrides: %v", operandResourcesKey, err)
+	}
+	for name := range overrides.OperandResources {
+		if !operandContainers.Has(name) {
+			return nil, fmt.Errorf("invalid %s override: unknown container %q, expected one of %v", operandResourcesKey, name, sets.List(operandContainers))
+		}
+	}
+	return overrides.OperandResources, nil
+}
+
+// operandDeploymentOverrideKeys are the keys of the unsupportedConfigOverrides which only configure the
+// operand deployments.
+var operandDeploymentOverrideKeys = []string{operandResourcesKey, operandReplicasKey}
+
+// withoutOperandDeploymentOverrides returns the unsupportedConfigOverrides without the operand resource
+// and replica overrides, which only configure the operand deployments.
+func withoutOperandDeploymentOverrides(unsupportedConfigOverrides []byte) ([]byte, error) {
+	if len(unsupportedConfigOverrides) == 0 {
+		return unsupportedConfigOverrides, nil
+	}
+	overrides := map[string]interface{}{}
+	if err := yaml.Unmarshal(unsupportedConfigOverrides, &overrides); err != nil {
+		return nil, err
+	}
+	var found bool
+	for _, key := range operandDeploymentOverrideKeys {
+		if _, ok := overrides[key]; ok {
+			delete(overrides, key)
+			found = true
+		}
+	}
+	if !found {
+		return unsupportedConfigOverrides, nil
+	}
+	return json.Marshal(overrides)
+}
+
+// applyResourceOverrides merges the resource overrides into the requirements of the pod spec's containers,
+// overrides of other containers are ignored. Requests exceeding their limits are rejected, the pod spec
+// must not be used in that case.
+func applyResourceOverrides(podSpec *corev1.PodSpec, overrides map[string]corev1.ResourceRequirements) error {
+	// sorted for stable error messages
+	names := make([]string, 0, len(overrides))
+	for name := range overrides {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		override := overrides[name]
+		for i := range podSpec.Containers {
+			container := &podSpec.Containers[i]
+			if container.Name != name {
+				continue
+			}
+			container.Resources.Requests = mergeResourceList(container.Resources.Requests, override.Requests)
+			container.Resources.Limits = mergeResourceList(container.Resources.Limits, override.Limits)
+			if err := validateResourceRequirements(container.Resources); err != nil {
+				return fmt.Errorf("invalid %s override for container %q: %v", operandResourcesKey, name, err)
+			}
+		}
+	}
+	return nil
+}
+
+func mergeResourceList(base, override corev1.ResourceList) corev1.ResourceList {
+	if len(override) == 0 {
+		return base
+	}
+	merged := corev1.ResourceList{}
+	for name, quantity := range base {
+		merged[name] = quantity
+	}
+	for name, quantity := range override {
+		merged[name] = quantity
+	}
+	return merged
+}
+
+// validateResourceRequirements makes sure no request exceeds the limit of its resource.
+func validateResourceRequirements(resources corev1.ResourceRequirements) error {
+	names := make([]string, 0, len(resources.Requests))
+	for name := range resources.Requests {
+		names = append(names, string(name))
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		request := resources.Requests[corev1.ResourceName(name)]
+		limit, ok := resources.Limits[corev1.ResourceName(name)]
+		if ok && request.Cmp(limit) > 0 {
+			return fmt.Errorf("%s request %s exceeds its limit %s", name, request.String(), limit.String())
+		}
+	}
+	return nil
+}
@@ -0,0 +1,232 @@
+package operator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/utils/clock"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	configlistersv1 "github.com/openshift/client-go/config/listers/config/v1"
+	workloadcontroller "github.com/openshift/library-go/pkg/operator/apiserver/controller/workload"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/resource/resourcemerge"
+	"github.com/openshift/library-go/pkg/operator/resource/resourceread"
+
+	"github.com/openshift/cluster-openshift-controller-manager-operator/bindata"
+)
+
+func TestOperandResourceOverrides(t *testing.T) {
+	tests := []struct {
+		name      string
+		overrides string
+		expected  map[string]corev1.ResourceRequirements
+		expectErr string
+	}{
+		{
+			name: "no overrides",
+		},
+		{
+			name:      "other overrides only",
+			overrides: `{"build": {"buildDefaults": {}}}`,
+		},
+		{
+			name:      "container overrides",
+			overrides: `{"operandResources": {"controller-manager": {"requests": {"memory": "200Mi"}, "limits": {"memory": "1Gi"}}}}`,
+			expected: map[string]corev1.ResourceRequirements{
+				"controller-manager": {
+					Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("200Mi")},
+					Limits:   corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")},
+				},
+			},
+		},
+		{
+			name:      "unknown container",
+			overrides: `{"operandResources": {"controler-manager": {"requests": {"memory": "200Mi"}}}}`,
+			expectErr: `unknown container "controler-manager"`,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			overrides, err := operandResourceOverrides([]byte(tc.overrides))
+			if len(tc.expectErr) > 0 {
+				if err == nil || !strings.Contains(err.Error(), tc.expectErr) {
+					t.Fatalf("expected error containing %q, got %v", tc.expectErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !equality.Semantic.DeepEqual(tc.expected, overrides) {
+				t.Errorf("expected overrides %v, got %v", tc.expected, overrides)
+			}
+		})
+	}
+}
+
+func TestWithoutOperandDeploymentOverrides(t *testing.T) {
+	overrides, err := withoutOperandDeploymentOverrides([]byte(`{"operandResources": {"controller-manager": {}}, "operandReplicas": {"controller-manager": 3}, "build": {"buildDefaults": {}}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := `{"build":{"buildDefaults":{}}}`; string(overrides) != expected {
+		t.Errorf("expected overrides %s, got %s", expected, overrides)
+	}
+
+	unchanged := []byte(`{"build": {}}`)
+	overrides, err = withoutOperandDeploymentOverrides(unchanged)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(overrides) != string(unchanged) {
+		t.Errorf("expected overrides without %v to be unchanged, got %s", operandDeploymentOverrideKeys, overrides)
+	}
+}
+
+func TestApplyResourceOverrides(t *testing.T) {
+	tests := []struct {
+		name      string
+		overrides map[string]corev1.ResourceRequirements
+		expected  corev1.ResourceRequirements
+		expectErr string
+	}{
+		{
+			name: "no overrides",
+			expected: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceMemory: resource.MustParse("100Mi"),
+					corev1.ResourceCPU:    resource.MustParse("100m"),
+				},
+			},
+		},
+		{
+			name: "request raised and limit added",
+			overrides: map[string]corev1.ResourceRequirements{
+				"controller-manager": {
+					Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("200Mi")},
+					Limits:   corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")},
+				},
+			},
+			expected: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceMemory: resource.MustParse("200Mi"),
+					corev1.ResourceCPU:    resource.MustParse("100m"),
+				},
+				Limits: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")},
+			},
+		},
+		{
+			name: "other container is ignored",
+			overrides: map[string]corev1.ResourceRequirements{
+				"route-controller-manager": {
+					Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("200Mi")},
+				},
+			},
+			expected: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceMemory: resource.MustParse("100Mi"),
+					corev1.ResourceCPU:    resource.MustParse("100m"),
+				},
+			},
+		},
+		{
+			name: "limit below the default request",
+			overrides: map[string]corev1.ResourceRequirements{
+				"controller-manager": {
+					Limits: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("50m")},
+				},
+			},
+			expectErr: "cpu request 100m exceeds its limit 50m",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			required := resourceread.ReadDeploymentV1OrDie(bindata.MustAsset("assets/openshift-controller-manager/deploy.yaml"))
+			err := applyResourceOverrides(&required.Spec.Template.Spec, tc.overrides)
+			if len(tc.expectErr) > 0 {
+				if err == nil || !strings.Contains(err.Error(), tc.expectErr) {
+					t.Fatalf("expected error containing %q, got %v", tc.expectErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if actual := required.Spec.Template.Spec.Containers[0].Resources; !equality.Semantic.DeepEqual(tc.expected, actual) {
+				t.Errorf("unexpected resources:\n%s", cmp.Diff(tc.expected, actual))
+			}
+		})
+	}
+}
+
+func TestResourceOverridesRollout(t *testing.T) {
+	kubeClient := fake.NewSimpleClientset()
+	proxyLister := configlistersv1.NewProxyLister(cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{}))
+	recorder := events.NewInMemoryRecorder("", clock.RealClock{})
+	countNodes := func(nodeSelector map[string]string) (*int32, error) {
+		result := int32(3)
+		return &result, nil
+	}
+	var generations []operatorv1.GenerationStatus
+	manage := func(overrides string) (bool, error) {
+		operatorConfig := &operatorv1.OpenShiftControllerManager{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+			Spec: operatorv1.OpenShiftControllerManagerSpec{
+				OperatorSpec: operatorv1.OperatorSpec{
+					UnsupportedConfigOverrides: runtime.RawExtension{Raw: []byte(overrides)},
+				},
+			},
+		}
+		deployment, modified, err := manageOpenShiftControllerManagerDeployment_v311_00_to_latest(
+			bindata.MustAsset,
+			kubeClient.AppsV1(),
+			countNodes,
+			workloadcontroller.EnsureAtMostOnePodPerNode,
+			recorder,
+			operatorConfig,
+			"my.co/repo/img:latest",
+			generations,
+			proxyLister,
+			map[string]string{},
+		)
+		if deployment != nil {
+			resourcemerge.SetDeploymentGeneration(&generations, deployment)
+		}
+		return modified, err
+	}
+
+	if _, err := manage(""); err != nil {
+		t.Fatal(err)
+	}
+
+	// overriding with the default resources must not roll out
+	modified, err := manage(`{"operandResources": {"controller-manager": {"requests": {"memory": "100Mi", "cpu": "100m"}}}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if modified {
+		t.Error("expected a no-op override to not update the deployment")
+	}
+
+	modified, err = manage(`{"operandResources": {"controller-manager": {"requests": {"memory": "200Mi"}}}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !modified {
+		t.Error("expected a changed override to update the deployment")
+	}
+
+	if _, err := manage(`{"operandResources": {"controller-manager": {"requests": {"memory": "2Gi"}, "limits": {"memory": "1Gi"}}}}`); err == nil {
+		t.Error("expected an error for requests exceeding their limits")
+	}
+}
@@ -0,0 +1,75 @@
+package operator
+
+import (
+	"fmt"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+
+	operatorapiv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-openshift-controller-manager-operator/pkg/util"
+)
+
+const (
+	operandVersionProgressingType = "OperandVersionProgressing"
+	operandVersionNotLiveReason   = "OperandVersionNotLive"
+)
+
+// setOperandVersion reports the version the deployments are annotated with as the version of the operator
+// once every deployment runs it: its latest generation is observed and as many of its updated replicas are
+// available as its rolling update keeps available, so that a single replica which cannot be scheduled does
+// not hold back the version and with it the upgrade. Until then the previous version is kept, which the
+// ClusterOperator reports, and the OperandVersionProgressing condition tells which deployments are not live
+// yet, so that the CVO does not move on while the operands still run an older revision.
+func setOperandVersion(operatorConfig *operatorapiv1.OpenShiftControllerManager, deployments ...*appsv1.Deployment) {
+	var desiredVersion string
+	var pending []string
+	for _, deployment := range deployments {
+		version := deployment.Annotations[util.VersionAnnotation]
+		if len(version) == 0 {
+			pending = append(pending, fmt.Sprintf("deployment/%s: version annotation %s missing", deployment.Name, util.VersionAnnotation))
+			continue
+		}
+		if len(desiredVersion) == 0 {
+			desiredVersion = version
+		} else if version != desiredVersion {
+			pending = append(pending, fmt.Sprintf("deployment/%s: is annotated with version %s, expected %s", deployment.Name, version, desiredVersion))
+			continue
+		}
+		if reason := rolloutPending(deployment); len(reason) > 0 {
+			pending = append(pending, fmt.Sprintf("deployment/%s: %s", deployment.Name, reason))
+		}
+	}
+	if len(pending) == 0 {
+		operatorConfig.Status.Version = desiredVersion
+	}
+
+	condition := operatorapiv1.OperatorCondition{
+		Type:   operandVersionProgressingType,
+		Status: operatorapiv1.ConditionFalse,
+	}
+	if len(desiredVersion) > 0 && desiredVersion != operatorConfig.Status.Version {
+		reported := operatorConfig.Status.Version
+		if len(reported) == 0 {
+			reported = "no version"
+		}
+		condition.Status = operatorapiv1.ConditionTrue
+		condition.Reason = operandVersionNotLiveReason
+		condition.Message = fmt.Sprintf("reporting %s until the operands run version %s:\n%s", reported, desiredVersion, strings.Join(pending, "\n"))
+	}
+	v1helpers.SetOperatorCondition(&operatorConfig.Status.Conditions, condition)
+}
+
+// rolloutPending returns why the deployment does not serve with its latest revision yet, empty once it
+// does.
+func rolloutPending(deployment *appsv1.Deployment) string {
+	if deployment.Status.ObservedGeneration < deployment.Generation {
+		return fmt.Sprintf("observed generation is %d, desired generation is %d", deployment.Status.ObservedGeneration, deployment.Generation)
+	}
+	if available, needed := updatedAvailableReplicas(deployment), minAvailableReplicas(deployment); available < needed {
+		return fmt.Sprintf("%d of %d updated replicas are available, %d are needed", available, desiredReplicas(deployment), needed)
+	}
+	return ""
+}
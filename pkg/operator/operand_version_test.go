@@ -0,0 +1,146 @@
+package operator
+
+import (
+	"strings"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+
+	operatorapiv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-openshift-controller-manager-operator/pkg/util"
+)
+
+func versionedDeployment(name, version string, generation int64, status appsv1.DeploymentStatus) *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Generation:  generation,
+			Annotations: map[string]string{util.VersionAnnotation: version},
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: ptr.To[int32](3),
+			Strategy: appsv1.DeploymentStrategy{
+				Type:          appsv1.RollingUpdateDeploymentStrategyType,
+				RollingUpdate: &appsv1.RollingUpdateDeployment{MaxUnavailable: ptr.To(intstr.FromInt32(1))},
+			},
+		},
+		Status: status,
+	}
+}
+
+func TestSetOperandVersion(t *testing.T) {
+	rolledOut := appsv1.DeploymentStatus{ObservedGeneration: 2, Replicas: 3, UpdatedReplicas: 3, AvailableReplicas: 3}
+	tests := []struct {
+		name               string
+		reportedVersion    string
+		deployments        []*appsv1.Deployment
+		expectedVersion    string
+		expectProgressing  bool
+		expectedInProgress string
+	}{
+		{
+			name:            "operands run the new version",
+			reportedVersion: "4.18.0",
+			deployments: []*appsv1.Deployment{
+				versionedDeployment("controller-manager", "4.19.0", 2, rolledOut),
+				versionedDeployment("route-controller-manager", "4.19.0", 2, rolledOut),
+			},
+			expectedVersion: "4.19.0",
+		},
+		{
+			name:            "new generation not observed yet",
+			reportedVersion: "4.18.0",
+			deployments: []*appsv1.Deployment{
+				// the status still describes the fully rolled out previous revision
+				versionedDeployment("controller-manager", "4.19.0", 3, rolledOut),
+				versionedDeployment("route-controller-manager", "4.19.0", 2, rolledOut),
+			},
+			expectedVersion:    "4.18.0",
+			expectProgressing:  true,
+			expectedInProgress: "deployment/controller-manager: observed generation is 2, desired generation is 3",
+		},
+		{
+			name:            "old replicas still serving",
+			reportedVersion: "4.18.0",
+			deployments: []*appsv1.Deployment{
+				// the available replicas may all be of the older revision
+				versionedDeployment("controller-manager", "4.19.0", 2, appsv1.DeploymentStatus{ObservedGeneration: 2, Replicas: 3, UpdatedReplicas: 1, AvailableReplicas: 3}),
+				versionedDeployment("route-controller-manager", "4.19.0", 2, rolledOut),
+			},
+			expectedVersion:    "4.18.0",
+			expectProgressing:  true,
+			expectedInProgress: "deployment/controller-manager: 1 of 3 updated replicas are available, 2 are needed",
+		},
+		{
+			name:            "one updated replica cannot be scheduled",
+			reportedVersion: "4.18.0",
+			deployments: []*appsv1.Deployment{
+				versionedDeployment("controller-manager", "4.19.0", 2, appsv1.DeploymentStatus{ObservedGeneration: 2, Replicas: 3, UpdatedReplicas: 3, AvailableReplicas: 2}),
+				versionedDeployment("route-controller-manager", "4.19.0", 2, rolledOut),
+			},
+			expectedVersion: "4.19.0",
+		},
+		{
+			name:            "updated replicas not available",
+			reportedVersion: "4.18.0",
+			deployments: []*appsv1.Deployment{
+				versionedDeployment("controller-manager", "4.19.0", 2, rolledOut),
+				versionedDeployment("route-controller-manager", "4.19.0", 2, appsv1.DeploymentStatus{ObservedGeneration: 2, Replicas: 3, UpdatedReplicas: 3, AvailableReplicas: 1}),
+			},
+			expectedVersion:    "4.18.0",
+			expectProgressing:  true,
+			expectedInProgress: "deployment/route-controller-manager: 1 of 3 updated replicas are available, 2 are needed",
+		},
+		{
+			name: "first rollout",
+			deployments: []*appsv1.Deployment{
+				versionedDeployment("controller-manager", "4.19.0", 1, appsv1.DeploymentStatus{ObservedGeneration: 1, Replicas: 3, UpdatedReplicas: 1}),
+				versionedDeployment("route-controller-manager", "4.19.0", 1, rolledOut),
+			},
+			expectProgressing:  true,
+			expectedInProgress: "reporting no version until the operands run version 4.19.0",
+		},
+		{
+			name:            "rollout of the reported version",
+			reportedVersion: "4.19.0",
+			deployments: []*appsv1.Deployment{
+				versionedDeployment("controller-manager", "4.19.0", 3, rolledOut),
+				versionedDeployment("route-controller-manager", "4.19.0", 2, rolledOut),
+			},
+			expectedVersion: "4.19.0",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			operatorConfig := &operatorapiv1.OpenShiftControllerManager{}
+			operatorConfig.Status.Version = tc.reportedVersion
+
+			setOperandVersion(operatorConfig, tc.deployments...)
+
+			if operatorConfig.Status.Version != tc.expectedVersion {
+				t.Errorf("expected the version %q to be reported, got %q", tc.expectedVersion, operatorConfig.Status.Version)
+			}
+			condition := v1helpers.FindOperatorCondition(operatorConfig.Status.Conditions, operandVersionProgressingType)
+			if condition == nil {
+				t.Fatalf("expected the %s condition to be set", operandVersionProgressingType)
+			}
+			if !tc.expectProgressing {
+				if condition.Status != operatorapiv1.ConditionFalse {
+					t.Errorf("expected %s=False, got %s: %s", operandVersionProgressingType, condition.Status, condition.Message)
+				}
+				return
+			}
+			if condition.Status != operatorapiv1.ConditionTrue || condition.Reason != operandVersionNotLiveReason {
+				t.Errorf("expected %s=True with reason %s, got %s with reason %s", operandVersionProgressingType, operandVersionNotLiveReason, condition.Status, condition.Reason)
+			}
+			if !strings.Contains(condition.Message, tc.expectedInProgress) {
+				t.Errorf("expected the message to contain %q, got %q", tc.expectedInProgress, condition.Message)
+			}
+		})
+	}
+}
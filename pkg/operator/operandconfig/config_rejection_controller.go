@@ -0,0 +1,158 @@
+package operandconfig
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	corelistersv1 "k8s.io/client-go/listers/core/v1"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-openshift-controller-manager-operator/pkg/operator/metrics"
+	"github.com/openshift/cluster-openshift-controller-manager-operator/pkg/util"
+)
+
+const (
+	configDegradedType    = "OperandConfigDegraded"
+	configRejectedReason  = "OperandConfigRejected"
+	crashLoopBackOff      = "CrashLoopBackOff"
+	maxRejectionSnippet   = 256
+	controllerName        = "OperandConfigRejectionController"
+	controllerEventSuffix = "operand-config-rejection-controller"
+)
+
+// configErrorPattern matches the lines the operands log when they fail to read, decode or validate
+// their config file on startup.
+var configErrorPattern = regexp.MustCompile(`(?i)(unable to (read|load|decode|parse) (the )?config|error (reading|loading|decoding|parsing) (the )?config|invalid config|cannot unmarshal|unknown field|failed to (read|load|decode|parse) (the )?config)`)
+
+type configRejectionController struct {
+	factory.Controller
+	namespaces     []string
+	operatorClient v1helpers.OperatorClient
+	podListers     map[string]corelistersv1.PodNamespaceLister
+	eventListers   map[string]corelistersv1.EventNamespaceLister
+}
+
+// NewConfigRejectionController returns a controller degrading the operator when an operand pod of the
+// given namespaces crash loops because it rejects its config. The operands log why on startup, the log
+// tail is the termination message of their containers, the events of the pods are checked as well. The
+// condition is left as it is while reconciliation is paused.
+func NewConfigRejectionController(
+	namespaces []string,
+	operatorClient v1helpers.OperatorClient,
+	kubeInformers v1helpers.KubeInformersForNamespaces,
+	recorder events.Recorder,
+) factory.Controller {
+	c := &configRejectionController{
+		namespaces:     namespaces,
+		operatorClient: operatorClient,
+		podListers:     map[string]corelistersv1.PodNamespaceLister{},
+		eventListers:   map[string]corelistersv1.EventNamespaceLister{},
+	}
+	var informers []factory.Informer
+	for _, namespace := range namespaces {
+		coreInformers := kubeInformers.InformersFor(namespace).Core().V1()
+		c.podListers[namespace] = coreInformers.Pods().Lister().Pods(namespace)
+		c.eventListers[namespace] = coreInformers.Events().Lister().Events(namespace)
+		informers = append(informers, coreInformers.Pods().Informer(), coreInformers.Events().Informer())
+	}
+	c.Controller = factory.New().
+		WithInformers(informers...).
+		WithSync(c.sync).
+		ResyncEvery(5*time.Minute).
+		ToController(controllerName, recorder.WithComponentSuffix(controllerEventSuffix))
+	return c
+}
+
+func (c *configRejectionController) sync(ctx context.Context, _ factory.SyncContext) error {
+	defer metrics.ObserveReconcileDuration(controllerName, time.Now())
+
+	if paused, err := util.ReconciliationPaused(c.operatorClient); err != nil || paused {
+		return err
+	}
+
+	var rejections []string
+	for _, namespace := range c.namespaces {
+		pods, err := c.podListers[namespace].List(labels.Everything())
+		if err != nil {
+			return fmt.Errorf("unable to list pods (ns=%q): %w", namespace, err)
+		}
+		podEvents, err := c.eventListers[namespace].List(labels.Everything())
+		if err != nil {
+			return fmt.Errorf("unable to list events (ns=%q): %w", namespace, err)
+		}
+		for _, pod := range pods {
+			rejections = append(rejections, configRejections(pod, podEvents)...)
+		}
+	}
+	sort.Strings(rejections)
+
+	condition := operatorv1.OperatorCondition{
+		Type:   configDegradedType,
+		Status: operatorv1.ConditionFalse,
+	}
+	if len(rejections) > 0 {
+		condition.Status = operatorv1.ConditionTrue
+		condition.Reason = configRejectedReason
+		condition.Message = strings.Join(rejections, "\n")
+	}
+	_, _, err := v1helpers.UpdateStatus(ctx, c.operatorClient, v1helpers.UpdateConditionFn(condition))
+	return err
+}
+
+// configRejections describes the containers of the pod which crash loop with a config error in their
+// last termination message or in a warning event of the pod.
+func configRejections(pod *corev1.Pod, podEvents []*corev1.Event) []string {
+	var eventSnippet string
+	for _, event := range podEvents {
+		if event.Type != corev1.EventTypeWarning || event.InvolvedObject.Kind != "Pod" || event.InvolvedObject.Name != pod.Name {
+			continue
+		}
+		if snippet := configErrorSnippet(event.Message); len(snippet) > 0 {
+			eventSnippet = snippet
+			break
+		}
+	}
+
+	var rejections []string
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.State.Waiting == nil || status.State.Waiting.Reason != crashLoopBackOff {
+			continue
+		}
+		snippet := eventSnippet
+		if terminated := status.LastTerminationState.Terminated; terminated != nil {
+			if terminationSnippet := configErrorSnippet(terminated.Message); len(terminationSnippet) > 0 {
+				snippet = terminationSnippet
+			}
+		}
+		if len(snippet) == 0 {
+			continue
+		}
+		rejections = append(rejections, fmt.Sprintf("pod/%s -n %s: container %s rejects its config: %s", pod.Name, pod.Namespace, status.Name, snippet))
+	}
+	return rejections
+}
+
+// configErrorSnippet returns the first line of message with a config error, cut to maxRejectionSnippet.
+func configErrorSnippet(message string) string {
+	for _, line := range strings.Split(message, "\n") {
+		if !configErrorPattern.MatchString(line) {
+			continue
+		}
+		line = strings.TrimSpace(line)
+		if len(line) > maxRejectionSnippet {
+			line = line[:maxRejectionSnippet] + "..."
+		}
+		return line
+	}
+	return ""
+}
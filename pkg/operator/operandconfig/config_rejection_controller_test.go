@@ -0,0 +1,164 @@
+package operandconfig
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corelistersv1 "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-openshift-controller-manager-operator/pkg/util"
+)
+
+const testNamespace = "openshift-controller-manager"
+
+func crashLoopingPod(terminationMessage string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "controller-manager-abc", Namespace: testNamespace},
+		Status: corev1.PodStatus{
+			ContainerStatuses: []corev1.ContainerStatus{
+				{
+					Name:  "controller-manager",
+					State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+					LastTerminationState: corev1.ContainerState{
+						Terminated: &corev1.ContainerStateTerminated{ExitCode: 255, Message: terminationMessage},
+					},
+				},
+			},
+		},
+	}
+}
+
+func podEvent(podName, message string) *corev1.Event {
+	return &corev1.Event{
+		ObjectMeta:     metav1.ObjectMeta{Name: podName + ".1", Namespace: testNamespace},
+		InvolvedObject: corev1.ObjectReference{Kind: "Pod", Name: podName, Namespace: testNamespace},
+		Type:           corev1.EventTypeWarning,
+		Message:        message,
+	}
+}
+
+func TestConfigRejectionController(t *testing.T) {
+	tests := []struct {
+		name            string
+		pods            []*corev1.Pod
+		events          []*corev1.Event
+		expectedStatus  operatorv1.ConditionStatus
+		expectedMessage string
+	}{
+		{
+			name: "no operand pods",
+			// a previous rejection is cleared
+			expectedStatus: operatorv1.ConditionFalse,
+		},
+		{
+			name:           "crash loop without a config error",
+			pods:           []*corev1.Pod{crashLoopingPod("panic: runtime error: invalid memory address")},
+			events:         []*corev1.Event{podEvent("controller-manager-abc", "Back-off restarting failed container")},
+			expectedStatus: operatorv1.ConditionFalse,
+		},
+		{
+			name:            "config error event",
+			pods:            []*corev1.Pod{crashLoopingPod("")},
+			events:          []*corev1.Event{podEvent("controller-manager-abc", `Error: unable to load config: json: unknown field "servingInfo.foo"`)},
+			expectedStatus:  operatorv1.ConditionTrue,
+			expectedMessage: `pod/controller-manager-abc -n openshift-controller-manager: container controller-manager rejects its config: Error: unable to load config: json: unknown field "servingInfo.foo"`,
+		},
+		{
+			name: "config error in the termination message",
+			pods: []*corev1.Pod{crashLoopingPod("I1014 starting\nF1014 error reading config: json: cannot unmarshal string into Go struct field\n")},
+			// the events of other pods do not matter
+			events:          []*corev1.Event{podEvent("controller-manager-other", "unable to load config: invalid")},
+			expectedStatus:  operatorv1.ConditionTrue,
+			expectedMessage: "pod/controller-manager-abc -n openshift-controller-manager: container controller-manager rejects its config: F1014 error reading config: json: cannot unmarshal string into Go struct field",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			podIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+			for _, pod := range tc.pods {
+				if err := podIndexer.Add(pod); err != nil {
+					t.Fatal(err)
+				}
+			}
+			eventIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+			for _, event := range tc.events {
+				if err := eventIndexer.Add(event); err != nil {
+					t.Fatal(err)
+				}
+			}
+			operatorClient := v1helpers.NewFakeOperatorClient(&operatorv1.OperatorSpec{ManagementState: operatorv1.Managed}, &operatorv1.OperatorStatus{
+				Conditions: []operatorv1.OperatorCondition{{Type: "OperandConfigDegraded", Status: operatorv1.ConditionTrue, Reason: "OperandConfigRejected"}},
+			}, nil)
+			c := &configRejectionController{
+				namespaces:     []string{testNamespace},
+				operatorClient: operatorClient,
+				podListers:     map[string]corelistersv1.PodNamespaceLister{testNamespace: corelistersv1.NewPodLister(podIndexer).Pods(testNamespace)},
+				eventListers:   map[string]corelistersv1.EventNamespaceLister{testNamespace: corelistersv1.NewEventLister(eventIndexer).Events(testNamespace)},
+			}
+
+			if err := c.sync(context.TODO(), nil); err != nil {
+				t.Fatal(err)
+			}
+
+			_, status, _, err := operatorClient.GetOperatorState()
+			if err != nil {
+				t.Fatal(err)
+			}
+			condition := v1helpers.FindOperatorCondition(status.Conditions, "OperandConfigDegraded")
+			if condition == nil {
+				t.Fatal("expected the OperandConfigDegraded condition")
+			}
+			if condition.Status != tc.expectedStatus {
+				t.Errorf("expected status %s, got %s", tc.expectedStatus, condition.Status)
+			}
+			if tc.expectedStatus == operatorv1.ConditionTrue && condition.Reason != "OperandConfigRejected" {
+				t.Errorf("expected reason OperandConfigRejected, got %s", condition.Reason)
+			}
+			if condition.Message != tc.expectedMessage {
+				t.Errorf("expected message:\n%s\ngot:\n%s", tc.expectedMessage, condition.Message)
+			}
+		})
+	}
+}
+
+func TestConfigRejectionControllerPaused(t *testing.T) {
+	podIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	if err := podIndexer.Add(crashLoopingPod("F1014 error reading config: json: unknown field")); err != nil {
+		t.Fatal(err)
+	}
+	operatorClient := v1helpers.NewFakeOperatorClientWithObjectMeta(
+		&metav1.ObjectMeta{Annotations: map[string]string{util.PauseReconcileAnnotation: "true"}},
+		&operatorv1.OperatorSpec{ManagementState: operatorv1.Managed}, &operatorv1.OperatorStatus{}, nil)
+	c := &configRejectionController{
+		namespaces:     []string{testNamespace},
+		operatorClient: operatorClient,
+		podListers:     map[string]corelistersv1.PodNamespaceLister{testNamespace: corelistersv1.NewPodLister(podIndexer).Pods(testNamespace)},
+		eventListers:   map[string]corelistersv1.EventNamespaceLister{testNamespace: corelistersv1.NewEventLister(cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})).Events(testNamespace)},
+	}
+
+	if err := c.sync(context.TODO(), nil); err != nil {
+		t.Fatal(err)
+	}
+	_, status, _, err := operatorClient.GetOperatorState()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if condition := v1helpers.FindOperatorCondition(status.Conditions, "OperandConfigDegraded"); condition != nil {
+		t.Errorf("expected the OperandConfigDegraded condition not to be set while reconciliation is paused, got %v", condition)
+	}
+}
+
+func TestConfigErrorSnippetIsCut(t *testing.T) {
+	snippet := configErrorSnippet("unable to load config: " + strings.Repeat("x", 1000))
+	if len(snippet) != maxRejectionSnippet+len("...") {
+		t.Errorf("expected the snippet to be cut to %d characters, got %d", maxRejectionSnippet, len(snippet))
+	}
+}
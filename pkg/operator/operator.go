@@ -7,21 +7,26 @@ import (
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/kubernetes"
 	coreclientv1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	corelistersv1 "k8s.io/client-go/listers/core/v1"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/util/flowcontrol"
 	"k8s.io/client-go/util/workqueue"
 	"k8s.io/klog/v2"
 
+	operatorapiv1 "github.com/openshift/api/operator/v1"
 	configinformerv1 "github.com/openshift/client-go/config/informers/externalversions/config/v1"
 	proxyvclient1 "github.com/openshift/client-go/config/listers/config/v1"
 	v1 "github.com/openshift/client-go/config/listers/config/v1"
 	operatorclientv1 "github.com/openshift/client-go/operator/clientset/versioned/typed/operator/v1"
 	operatorinformersv1 "github.com/openshift/client-go/operator/informers/externalversions/operator/v1"
+	"github.com/openshift/cluster-openshift-controller-manager-operator/pkg/operator/logging"
+	"github.com/openshift/cluster-openshift-controller-manager-operator/pkg/operator/metrics"
 	"github.com/openshift/cluster-openshift-controller-manager-operator/pkg/util"
 	"github.com/openshift/library-go/pkg/operator/events"
 	"github.com/openshift/library-go/pkg/operator/management"
@@ -29,6 +34,7 @@ import (
 )
 
 const (
+	controllerName            = "OpenShiftControllerManagerOperator"
 	workQueueKey              = "key"
 	workloadDegradedCondition = "WorkloadDegraded"
 )
@@ -48,6 +54,8 @@ type OpenShiftControllerManagerOperator struct {
 
 	kubeClient       kubernetes.Interface
 	configMapsGetter coreclientv1.ConfigMapsGetter
+	// podListers list the cached pods of the operand namespaces, by namespace.
+	podListers map[string]corelistersv1.PodNamespaceLister
 
 	// countNodes a function to return count of nodes on which the workload will be installed
 	countNodes nodeCountFunc
@@ -61,7 +69,9 @@ type OpenShiftControllerManagerOperator struct {
 
 	rateLimiter          flowcontrol.RateLimiter
 	recorder             events.Recorder
+	logger               klog.Logger
 	clusterVersionLister v1.ClusterVersionLister
+	infrastructureLister v1.InfrastructureLister
 }
 
 func NewOpenShiftControllerManagerOperator(
@@ -69,6 +79,7 @@ func NewOpenShiftControllerManagerOperator(
 	routeControllerManagerTargetImagePullSpec string,
 	operatorConfigInformer operatorinformersv1.OpenShiftControllerManagerInformer,
 	proxyInformer configinformerv1.ProxyInformer,
+	infrastructureInformer configinformerv1.InfrastructureInformer,
 	kubeInformers v1helpers.KubeInformersForNamespaces,
 	operatorConfigClient operatorclientv1.OperatorV1Interface,
 	countNodes nodeCountFunc,
@@ -86,21 +97,27 @@ func NewOpenShiftControllerManagerOperator(
 		proxyLister:                               proxyInformer.Lister(),
 		kubeClient:                                kubeClient,
 		configMapsGetter:                          v1helpers.CachedConfigMapGetter(kubeClient.CoreV1(), kubeInformers),
-		queue:                                     workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "OpenshiftControllerManagerOperator"),
+		queue:                                     workqueue.NewNamedRateLimitingQueue(newRequeueRateLimiter(), "OpenshiftControllerManagerOperator"),
 		rateLimiter:                               flowcontrol.NewTokenBucketRateLimiter(0.05 /*3 per minute*/, 4),
 		recorder:                                  recorder,
+		logger:                                    logging.ForController(klog.Background(), controllerName),
 		clusterVersionLister:                      clusterVersionLister,
+		infrastructureLister:                      infrastructureInformer.Lister(),
 	}
 
 	operatorConfigInformer.Informer().AddEventHandler(c.eventHandler())
 	proxyInformer.Informer().AddEventHandler(c.eventHandler())
+	infrastructureInformer.Informer().AddEventHandler(c.eventHandler())
 
 	targetInformers := kubeInformers.InformersFor(util.TargetNamespace)
 
 	targetInformers.Core().V1().ConfigMaps().Informer().AddEventHandler(c.eventHandler())
 	targetInformers.Core().V1().ServiceAccounts().Informer().AddEventHandler(c.eventHandler())
 	targetInformers.Core().V1().Services().Informer().AddEventHandler(c.eventHandler())
-	targetInformers.Apps().V1().Deployments().Informer().AddEventHandler(c.eventHandler())
+	targetInformers.Apps().V1().Deployments().Informer().AddEventHandler(c.deploymentEventHandler())
+	c.podListers = map[string]corelistersv1.PodNamespaceLister{
+		util.TargetNamespace: targetInformers.Core().V1().Pods().Lister().Pods(util.TargetNamespace),
+	}
 
 	// we only watch some namespaces
 	targetInformers.Core().V1().Namespaces().Informer().AddEventHandler(c.namespaceEventHandler(util.TargetNamespace))
@@ -110,7 +127,8 @@ func NewOpenShiftControllerManagerOperator(
 	rcTargetInformers.Core().V1().ConfigMaps().Informer().AddEventHandler(c.eventHandler())
 	rcTargetInformers.Core().V1().ServiceAccounts().Informer().AddEventHandler(c.eventHandler())
 	rcTargetInformers.Core().V1().Services().Informer().AddEventHandler(c.eventHandler())
-	rcTargetInformers.Apps().V1().Deployments().Informer().AddEventHandler(c.eventHandler())
+	rcTargetInformers.Apps().V1().Deployments().Informer().AddEventHandler(c.deploymentEventHandler())
+	c.podListers[util.RouteControllerTargetNamespace] = rcTargetInformers.Core().V1().Pods().Lister().Pods(util.RouteControllerTargetNamespace)
 
 	// we only watch some namespaces
 	rcTargetInformers.Core().V1().Namespaces().Informer().AddEventHandler(c.namespaceEventHandler(util.RouteControllerTargetNamespace))
@@ -124,10 +142,20 @@ func NewOpenShiftControllerManagerOperator(
 }
 
 func (c OpenShiftControllerManagerOperator) sync() error {
+	defer metrics.ObserveReconcileDuration(controllerName, time.Now())
+
 	operatorConfig, err := c.operatorConfigClient.OpenShiftControllerManagers().Get(context.TODO(), "cluster", metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		// the informer requeues the sync once the recreated config is observed
+		return c.createDefaultOperatorConfig()
+	}
 	if err != nil {
 		return err
 	}
+	if isReconciliationPaused(operatorConfig) {
+		logging.WithObject(c.logger, operatorConfig).V(2).Info("Reconciliation is paused", "annotation", pauseReconcileAnnotation)
+		return reportReconciliationPaused(c, operatorConfig)
+	}
 
 	forceRequeue, err := syncOpenShiftControllerManager_v311_00_to_latest(c, operatorConfig, c.countNodes, c.ensureAtMostOnePodPerNode)
 	if forceRequeue && err != nil {
@@ -137,13 +165,38 @@ func (c OpenShiftControllerManagerOperator) sync() error {
 	return err
 }
 
-// Run starts the openshift-controller-manager and blocks until stopCh is closed.
+// defaultOperatorConfig is the operator config shipped in the release manifests.
+func defaultOperatorConfig() *operatorapiv1.OpenShiftControllerManager {
+	return &operatorapiv1.OpenShiftControllerManager{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+		Spec: operatorapiv1.OpenShiftControllerManagerSpec{
+			OperatorSpec: operatorapiv1.OperatorSpec{ManagementState: operatorapiv1.Managed},
+		},
+	}
+}
+
+// createDefaultOperatorConfig recreates the operator config after it was deleted, so the operand keeps
+// being managed and the operator status keeps being reported.
+func (c OpenShiftControllerManagerOperator) createDefaultOperatorConfig() error {
+	_, err := c.operatorConfigClient.OpenShiftControllerManagers().Create(context.TODO(), defaultOperatorConfig(), metav1.CreateOptions{})
+	if errors.IsAlreadyExists(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("unable to recreate the missing openshiftcontrollermanagers.operator.openshift.io/cluster: %w", err)
+	}
+	c.recorder.Warningf("OperatorConfigRecreated", "openshiftcontrollermanagers.operator.openshift.io/cluster was not found, created it with the defaults")
+	return nil
+}
+
+// Run starts the openshift-controller-manager and blocks until stopCh is closed. It returns once the
+// in-flight sync finished, so that the shutdown of the operator waits for it.
 func (c *OpenShiftControllerManagerOperator) Run(ctx context.Context, workers int) {
 	defer utilruntime.HandleCrash()
-	defer c.queue.ShutDown()
+	defer c.queue.ShutDownWithDrain()
 
-	klog.Infof("Starting OpenShiftControllerManagerOperator")
-	defer klog.Infof("Shutting down OpenShiftControllerManagerOperator")
+	c.logger.Info("Starting")
+	defer c.logger.Info("Shutting down")
 
 	// doesn't matter what workers say, only start one.
 	go wait.UntilWithContext(ctx, c.runWorker, time.Second)
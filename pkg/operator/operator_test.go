@@ -0,0 +1,111 @@
+package operator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clienttesting "k8s.io/client-go/testing"
+	"k8s.io/utils/clock"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	operatorfake "github.com/openshift/client-go/operator/clientset/versioned/fake"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/configobserver"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-openshift-controller-manager-operator/pkg/operator/configobservation"
+	"github.com/openshift/cluster-openshift-controller-manager-operator/pkg/operator/metrics"
+)
+
+func reconcileSampleCount(t *testing.T, controller string) uint64 {
+	m := &dto.Metric{}
+	if err := metrics.ReconcileDuration.WithLabelValues(controller).(prometheus.Metric).Write(m); err != nil {
+		t.Fatal(err)
+	}
+	return m.GetHistogram().GetSampleCount()
+}
+
+func TestSyncMetrics(t *testing.T) {
+	c := OpenShiftControllerManagerOperator{
+		operatorConfigClient: operatorfake.NewSimpleClientset().OperatorV1(),
+		recorder:             events.NewInMemoryRecorder("", clock.RealClock{}),
+	}
+	before := reconcileSampleCount(t, "OpenShiftControllerManagerOperator")
+	// the operator config does not exist, the sync only recreating it is measured all the same
+	if err := c.sync(); err != nil {
+		t.Fatal(err)
+	}
+	if samples := reconcileSampleCount(t, "OpenShiftControllerManagerOperator") - before; samples != 1 {
+		t.Errorf("expected the sync duration to be observed once, observed %d times", samples)
+	}
+
+	observe := metrics.InstrumentObserveConfigFunc("TestObserver", func(configobserver.Listers, events.Recorder, map[string]interface{}) (map[string]interface{}, []error) {
+		return map[string]interface{}{"build": map[string]interface{}{"additionalTrustedCA": "/ca.crt"}}, nil
+	})
+	operatorClient := v1helpers.NewFakeOperatorClient(&operatorv1.OperatorSpec{ManagementState: operatorv1.Managed}, &operatorv1.OperatorStatus{}, nil)
+	recorder := events.NewInMemoryRecorder("", clock.RealClock{})
+	observer := configobserver.NewConfigObserver("test", operatorClient, recorder, configobservation.Listers{}, nil, observe)
+
+	changesBefore := testutil.ToFloat64(metrics.ObservedConfigChanges.WithLabelValues("TestObserver"))
+	if err := observer.Sync(context.TODO(), factory.NewSyncContext("test", recorder)); err != nil {
+		t.Fatal(err)
+	}
+	if changes := testutil.ToFloat64(metrics.ObservedConfigChanges.WithLabelValues("TestObserver")) - changesBefore; changes != 1 {
+		t.Errorf("expected the observed config change to be counted once, counted %v", changes)
+	}
+}
+
+func TestSyncRecreatesMissingOperatorConfig(t *testing.T) {
+	operatorClient := operatorfake.NewSimpleClientset()
+	recorder := events.NewInMemoryRecorder("", clock.RealClock{})
+	c := OpenShiftControllerManagerOperator{
+		operatorConfigClient: operatorClient.OperatorV1(),
+		podListers:           operandPodListers(),
+		recorder:             recorder,
+	}
+	if err := c.sync(); err != nil {
+		t.Fatal(err)
+	}
+
+	var created []runtime.Object
+	for _, action := range operatorClient.Actions() {
+		if create, ok := action.(clienttesting.CreateAction); ok && action.GetResource().Resource == "openshiftcontrollermanagers" {
+			created = append(created, create.GetObject())
+		}
+	}
+	if len(created) != 1 {
+		t.Fatalf("expected the operator config to be created once, got actions %v", operatorClient.Actions())
+	}
+	expected := &operatorv1.OpenShiftControllerManager{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+		Spec: operatorv1.OpenShiftControllerManagerSpec{
+			OperatorSpec: operatorv1.OperatorSpec{ManagementState: operatorv1.Managed},
+		},
+	}
+	if !equality.Semantic.DeepEqual(expected, created[0]) {
+		t.Errorf("unexpected operator config created:\n%s", cmp.Diff(expected, created[0]))
+	}
+
+	found := false
+	for _, event := range recorder.Events() {
+		if event.Reason == "OperatorConfigRecreated" {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("expected an OperatorConfigRecreated event")
+	}
+
+	// a config created concurrently is not an error
+	if err := c.createDefaultOperatorConfig(); err != nil {
+		t.Errorf("expected an existing operator config to be left alone, got %v", err)
+	}
+}
@@ -0,0 +1,54 @@
+package operator
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+const (
+	// DefaultResyncInterval is how often the informers and controllers of the operator resync everything
+	// when not configured otherwise.
+	DefaultResyncInterval = 10 * time.Minute
+	// MinResyncInterval is the shortest resync interval accepted, shorter ones make the controllers
+	// hot-loop against the apiserver.
+	MinResyncInterval = 30 * time.Second
+)
+
+// Options are the settings of the operator given on its command line.
+type Options struct {
+	// ResyncInterval is how often the informers and controllers of the operator resync everything.
+	// Large clusters can lengthen it to reduce the load on the apiserver, tests can shorten it to
+	// converge faster.
+	ResyncInterval time.Duration
+	// SelfTest makes the operator check once after starting that a marker set in the operator config
+	// reaches the operand config and is removed from it again. It is for CI smoke tests only and requires
+	// OCM_OPERATOR_SELF_TEST=true in the environment.
+	SelfTest bool
+}
+
+// NewOptions returns the default options of the operator.
+func NewOptions() *Options {
+	return &Options{ResyncInterval: DefaultResyncInterval}
+}
+
+// AddFlags adds the flags of the options to fs.
+func (o *Options) AddFlags(fs *pflag.FlagSet) {
+	fs.DurationVar(&o.ResyncInterval, "resync-interval", o.ResyncInterval,
+		fmt.Sprintf("How often the informers and controllers of the operator resync everything, at least %v.", MinResyncInterval))
+	fs.BoolVar(&o.SelfTest, "self-test", o.SelfTest,
+		fmt.Sprintf("Check once after starting that the operand config follows the operator config, for test environments only, requires %s=true.", selfTestEnv))
+}
+
+// Validate returns an error if the options cannot be run with.
+func (o *Options) Validate() error {
+	if o.ResyncInterval < MinResyncInterval {
+		return fmt.Errorf("--resync-interval %v is shorter than the minimum of %v", o.ResyncInterval, MinResyncInterval)
+	}
+	if o.SelfTest && !selfTestAllowed(os.LookupEnv) {
+		return fmt.Errorf("--self-test changes the operator config and rolls out the operand, it requires %s=true", selfTestEnv)
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package operator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/spf13/pflag"
+)
+
+func TestOptionsResyncInterval(t *testing.T) {
+	tests := []struct {
+		name      string
+		args      []string
+		expected  time.Duration
+		expectErr bool
+	}{
+		{
+			name:     "default",
+			expected: DefaultResyncInterval,
+		},
+		{
+			name:     "lengthened for a large cluster",
+			args:     []string{"--resync-interval=1h"},
+			expected: time.Hour,
+		},
+		{
+			name:     "minimum",
+			args:     []string{"--resync-interval=30s"},
+			expected: MinResyncInterval,
+		},
+		{
+			name:      "below the minimum",
+			args:      []string{"--resync-interval=5s"},
+			expected:  5 * time.Second,
+			expectErr: true,
+		},
+		{
+			name:      "zero",
+			args:      []string{"--resync-interval=0"},
+			expectErr: true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			options := NewOptions()
+			fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+			options.AddFlags(fs)
+			if err := fs.Parse(tc.args); err != nil {
+				t.Fatal(err)
+			}
+			if options.ResyncInterval != tc.expected {
+				t.Errorf("expected the resync interval %v, got %v", tc.expected, options.ResyncInterval)
+			}
+			if err := options.Validate(); tc.expectErr != (err != nil) {
+				t.Errorf("expected error %t, got %v", tc.expectErr, err)
+			}
+		})
+	}
+}
+
+func TestOptionsSelfTest(t *testing.T) {
+	tests := []struct {
+		name      string
+		env       string
+		args      []string
+		expectErr bool
+	}{
+		{name: "disabled"},
+		{name: "disabled in a test environment", env: "true"},
+		{name: "enabled in a test environment", env: "true", args: []string{"--self-test"}},
+		{name: "enabled without the environment", args: []string{"--self-test"}, expectErr: true},
+		{name: "enabled with the environment turned off", env: "false", args: []string{"--self-test"}, expectErr: true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv(selfTestEnv, tc.env)
+			options := NewOptions()
+			fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+			options.AddFlags(fs)
+			if err := fs.Parse(tc.args); err != nil {
+				t.Fatal(err)
+			}
+			if err := options.Validate(); tc.expectErr != (err != nil) {
+				t.Errorf("expected error %t, got %v", tc.expectErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,219 @@
+package ownerreference
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/kubernetes"
+	corelistersv1 "k8s.io/client-go/listers/core/v1"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	operatorv1informers "github.com/openshift/client-go/operator/informers/externalversions/operator/v1"
+	operatorlistersv1 "github.com/openshift/client-go/operator/listers/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-openshift-controller-manager-operator/pkg/operator/metrics"
+	"github.com/openshift/cluster-openshift-controller-manager-operator/pkg/util"
+)
+
+const (
+	// ConfigMaps and Secrets are the resources of the managed objects the controller repairs.
+	ConfigMaps = "configmaps"
+	Secrets    = "secrets"
+
+	controllerName = "OwnerReferenceController"
+)
+
+// OperandObjects returns the objects of the operands which point back to the operator config, the
+// config ConfigMaps of both operands.
+func OperandObjects() []ManagedObject {
+	return []ManagedObject{
+		{Resource: ConfigMaps, Namespace: util.TargetNamespace, Name: "config"},
+		{Resource: ConfigMaps, Namespace: util.RouteControllerTargetNamespace, Name: "config"},
+	}
+}
+
+// ManagedObject is an object the operator manages in an operand namespace.
+type ManagedObject struct {
+	// Resource is ConfigMaps or Secrets.
+	Resource  string
+	Namespace string
+	Name      string
+}
+
+func (o ManagedObject) String() string {
+	return fmt.Sprintf("%s/%s -n %s", o.Resource, o.Name, o.Namespace)
+}
+
+type ownerReferenceController struct {
+	factory.Controller
+	objects              []ManagedObject
+	kubeClient           kubernetes.Interface
+	operatorConfigLister operatorlistersv1.OpenShiftControllerManagerLister
+	configMapListers     map[string]corelistersv1.ConfigMapNamespaceLister
+	secretListers        map[string]corelistersv1.SecretNamespaceLister
+	recorder             events.Recorder
+}
+
+// NewOwnerReferenceController returns a controller making the managed objects point back to the operator
+// config with an owner reference, so that they are not orphaned. A missing owner reference is added, one to
+// an operator config of another name or UID, e.g. left behind when the operator config was recreated, is
+// replaced. The other owner references of the objects are kept, objects which do not exist are skipped.
+// The objects are checked again every resyncInterval, they are left as they are while reconciliation is
+// paused.
+//
+// The owner reference lets the garbage collector delete the objects along with the operator config, so
+// only objects the operator recreates on its next sync are managed. The operand Deployments are not: they
+// would be deleted with the operator config and the operands would be down until it is recreated.
+func NewOwnerReferenceController(
+	objects []ManagedObject,
+	kubeClient kubernetes.Interface,
+	operatorConfigInformer operatorv1informers.OpenShiftControllerManagerInformer,
+	kubeInformers v1helpers.KubeInformersForNamespaces,
+	recorder events.Recorder,
+	resyncInterval time.Duration,
+) factory.Controller {
+	c := &ownerReferenceController{
+		objects:              objects,
+		kubeClient:           kubeClient,
+		operatorConfigLister: operatorConfigInformer.Lister(),
+		configMapListers:     map[string]corelistersv1.ConfigMapNamespaceLister{},
+		secretListers:        map[string]corelistersv1.SecretNamespaceLister{},
+		recorder:             recorder.WithComponentSuffix("owner-reference-controller"),
+	}
+	informers := []factory.Informer{operatorConfigInformer.Informer()}
+	for _, object := range objects {
+		namespaceInformers := kubeInformers.InformersFor(object.Namespace)
+		switch object.Resource {
+		case ConfigMaps:
+			c.configMapListers[object.Namespace] = namespaceInformers.Core().V1().ConfigMaps().Lister().ConfigMaps(object.Namespace)
+			informers = append(informers, namespaceInformers.Core().V1().ConfigMaps().Informer())
+		case Secrets:
+			c.secretListers[object.Namespace] = namespaceInformers.Core().V1().Secrets().Lister().Secrets(object.Namespace)
+			informers = append(informers, namespaceInformers.Core().V1().Secrets().Informer())
+		}
+	}
+	c.Controller = factory.New().
+		WithInformers(informers...).
+		WithSync(c.sync).
+		ResyncEvery(resyncInterval).
+		ToController(controllerName, c.recorder)
+	return c
+}
+
+func (c *ownerReferenceController) sync(ctx context.Context, _ factory.SyncContext) error {
+	defer metrics.ObserveReconcileDuration(controllerName, time.Now())
+
+	operatorConfig, err := c.operatorConfigLister.Get("cluster")
+	if errors.IsNotFound(err) {
+		// there is nothing to point the objects to
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if operatorConfig.Annotations[util.PauseReconcileAnnotation] == "true" {
+		return nil
+	}
+	owner := ownerReference(operatorConfig)
+
+	var errs []error
+	for _, object := range c.objects {
+		current, err := c.get(object)
+		if errors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("unable to get %s: %w", object, err))
+			continue
+		}
+		required := withOwnerReference(current.GetOwnerReferences(), owner)
+		if equality.Semantic.DeepEqual(required, current.GetOwnerReferences()) {
+			continue
+		}
+		if err := c.patch(ctx, object, current, required); err != nil {
+			errs = append(errs, fmt.Errorf("unable to repair the owner references of %s: %w", object, err))
+			continue
+		}
+		c.recorder.Eventf("OwnerReferenceRepaired", "Set the owner reference of %s to %s/%s", object, owner.Kind, owner.Name)
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+// ownerReference returns the owner reference to the operator config. It is not a controller reference
+// and does not block the deletion of the operator config.
+func ownerReference(operatorConfig *operatorv1.OpenShiftControllerManager) metav1.OwnerReference {
+	return metav1.OwnerReference{
+		APIVersion: operatorv1.GroupVersion.String(),
+		Kind:       "OpenShiftControllerManager",
+		Name:       operatorConfig.Name,
+		UID:        operatorConfig.UID,
+	}
+}
+
+// withOwnerReference returns the owner references with owner in place of the references to any operator
+// config, the others are kept in their order.
+func withOwnerReference(ownerReferences []metav1.OwnerReference, owner metav1.OwnerReference) []metav1.OwnerReference {
+	var required []metav1.OwnerReference
+	found := false
+	for _, ownerReference := range ownerReferences {
+		gv, err := schema.ParseGroupVersion(ownerReference.APIVersion)
+		if err != nil || gv.Group != operatorv1.GroupName || ownerReference.Kind != owner.Kind {
+			required = append(required, ownerReference)
+			continue
+		}
+		if !found {
+			required = append(required, owner)
+			found = true
+		}
+	}
+	if !found {
+		required = append(required, owner)
+	}
+	return required
+}
+
+func (c *ownerReferenceController) get(object ManagedObject) (metav1.Object, error) {
+	switch object.Resource {
+	case ConfigMaps:
+		return c.configMapListers[object.Namespace].Get(object.Name)
+	case Secrets:
+		return c.secretListers[object.Namespace].Get(object.Name)
+	default:
+		return nil, fmt.Errorf("unsupported resource %q", object.Resource)
+	}
+}
+
+// patch replaces the owner references of the object, the UID and the resource version the references were
+// read at guard against patching an object which changed in the meantime.
+func (c *ownerReferenceController) patch(ctx context.Context, object ManagedObject, current metav1.Object, ownerReferences []metav1.OwnerReference) error {
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"uid":             current.GetUID(),
+			"resourceVersion": current.GetResourceVersion(),
+			"ownerReferences": ownerReferences,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	switch object.Resource {
+	case ConfigMaps:
+		_, err = c.kubeClient.CoreV1().ConfigMaps(object.Namespace).Patch(ctx, object.Name, types.MergePatchType, patch, metav1.PatchOptions{})
+	case Secrets:
+		_, err = c.kubeClient.CoreV1().Secrets(object.Namespace).Patch(ctx, object.Name, types.MergePatchType, patch, metav1.PatchOptions{})
+	default:
+		err = fmt.Errorf("unsupported resource %q", object.Resource)
+	}
+	return err
+}
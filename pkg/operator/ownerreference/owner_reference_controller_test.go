@@ -0,0 +1,276 @@
+package ownerreference
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	corelistersv1 "k8s.io/client-go/listers/core/v1"
+	clienttesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/utils/clock"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	operatorlistersv1 "github.com/openshift/client-go/operator/listers/operator/v1"
+	"github.com/openshift/library-go/pkg/operator/events"
+
+	"github.com/openshift/cluster-openshift-controller-manager-operator/pkg/util"
+)
+
+const testNamespace = "openshift-controller-manager"
+
+var (
+	owner = metav1.OwnerReference{
+		APIVersion: "operator.openshift.io/v1",
+		Kind:       "OpenShiftControllerManager",
+		Name:       "cluster",
+		UID:        "operator-config-uid",
+	}
+	// staleOwner is left behind by an operator config which was deleted and recreated.
+	staleOwner = metav1.OwnerReference{
+		APIVersion: "operator.openshift.io/v1",
+		Kind:       "OpenShiftControllerManager",
+		Name:       "cluster",
+		UID:        "deleted-operator-config-uid",
+	}
+	otherOwner = metav1.OwnerReference{
+		APIVersion: "v1",
+		Kind:       "ConfigMap",
+		Name:       "other",
+		UID:        "other-uid",
+	}
+)
+
+func objectMeta(name string, ownerReferences ...metav1.OwnerReference) metav1.ObjectMeta {
+	return metav1.ObjectMeta{Name: name, Namespace: testNamespace, ResourceVersion: "1", OwnerReferences: ownerReferences}
+}
+
+func TestOwnerReferenceController(t *testing.T) {
+	tests := []struct {
+		name     string
+		object   runtime.Object
+		resource string
+		// expected are the owner references after the sync, nil if the object is expected to be left alone
+		expected []metav1.OwnerReference
+	}{
+		{
+			name:     "missing owner reference of a configmap is added",
+			object:   &corev1.ConfigMap{ObjectMeta: objectMeta("config")},
+			resource: ConfigMaps,
+			expected: []metav1.OwnerReference{owner},
+		},
+		{
+			name:     "missing owner reference of a secret is added next to the other owners",
+			object:   &corev1.Secret{ObjectMeta: objectMeta("serving-cert", otherOwner)},
+			resource: Secrets,
+			expected: []metav1.OwnerReference{otherOwner, owner},
+		},
+		{
+			name:     "stale owner reference of a configmap is replaced",
+			object:   &corev1.ConfigMap{ObjectMeta: objectMeta("config", staleOwner, otherOwner)},
+			resource: ConfigMaps,
+			expected: []metav1.OwnerReference{owner, otherOwner},
+		},
+		{
+			name:     "correct owner reference is kept",
+			object:   &corev1.Secret{ObjectMeta: objectMeta("serving-cert", otherOwner, owner)},
+			resource: Secrets,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+			if err := indexer.Add(tc.object); err != nil {
+				t.Fatal(err)
+			}
+			operatorConfigIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+			if err := operatorConfigIndexer.Add(&operatorv1.OpenShiftControllerManager{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster", UID: "operator-config-uid"},
+			}); err != nil {
+				t.Fatal(err)
+			}
+			kubeClient := fake.NewSimpleClientset(tc.object)
+			metadata, err := meta.Accessor(tc.object)
+			if err != nil {
+				t.Fatal(err)
+			}
+			c := &ownerReferenceController{
+				objects: []ManagedObject{
+					{Resource: tc.resource, Namespace: testNamespace, Name: metadata.GetName()},
+					// objects which were not created yet are skipped
+					{Resource: ConfigMaps, Namespace: testNamespace, Name: "missing"},
+				},
+				kubeClient:           kubeClient,
+				operatorConfigLister: operatorlistersv1.NewOpenShiftControllerManagerLister(operatorConfigIndexer),
+				configMapListers:     map[string]corelistersv1.ConfigMapNamespaceLister{testNamespace: corelistersv1.NewConfigMapLister(indexer).ConfigMaps(testNamespace)},
+				secretListers:        map[string]corelistersv1.SecretNamespaceLister{testNamespace: corelistersv1.NewSecretLister(indexer).Secrets(testNamespace)},
+				recorder:             events.NewInMemoryRecorder("", clock.RealClock{}),
+			}
+
+			if err := c.sync(context.TODO(), nil); err != nil {
+				t.Fatal(err)
+			}
+
+			var patches []clienttesting.PatchAction
+			for _, action := range kubeClient.Actions() {
+				if patch, ok := action.(clienttesting.PatchAction); ok {
+					patches = append(patches, patch)
+				}
+			}
+			if tc.expected == nil {
+				if len(patches) > 0 {
+					t.Errorf("expected no patch, got %s", patches[0].GetPatch())
+				}
+				return
+			}
+			if len(patches) != 1 {
+				t.Fatalf("expected a single patch, got %d", len(patches))
+			}
+			if resource := patches[0].GetResource().Resource; resource != tc.resource {
+				t.Errorf("expected %s to be patched, got %s", tc.resource, resource)
+			}
+
+			var patched runtime.Object
+			switch tc.resource {
+			case ConfigMaps:
+				patched, err = kubeClient.CoreV1().ConfigMaps(testNamespace).Get(context.TODO(), metadata.GetName(), metav1.GetOptions{})
+			case Secrets:
+				patched, err = kubeClient.CoreV1().Secrets(testNamespace).Get(context.TODO(), metadata.GetName(), metav1.GetOptions{})
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			patchedMetadata, err := meta.Accessor(patched)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.expected, patchedMetadata.GetOwnerReferences()); len(diff) > 0 {
+				t.Errorf("unexpected owner references (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestOwnerReferenceControllerNoOperatorConfig(t *testing.T) {
+	configMap := &corev1.ConfigMap{ObjectMeta: objectMeta("config")}
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	if err := indexer.Add(configMap); err != nil {
+		t.Fatal(err)
+	}
+	kubeClient := fake.NewSimpleClientset(configMap)
+	c := &ownerReferenceController{
+		objects:              []ManagedObject{{Resource: ConfigMaps, Namespace: testNamespace, Name: "config"}},
+		kubeClient:           kubeClient,
+		operatorConfigLister: operatorlistersv1.NewOpenShiftControllerManagerLister(cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})),
+		configMapListers:     map[string]corelistersv1.ConfigMapNamespaceLister{testNamespace: corelistersv1.NewConfigMapLister(indexer).ConfigMaps(testNamespace)},
+		recorder:             events.NewInMemoryRecorder("", clock.RealClock{}),
+	}
+
+	if err := c.sync(context.TODO(), nil); err != nil {
+		t.Fatal(err)
+	}
+	if actions := kubeClient.Actions(); len(actions) > 0 {
+		t.Errorf("expected no actions without an operator config, got %v", actions)
+	}
+}
+
+func TestOwnerReferenceControllerPaused(t *testing.T) {
+	configMap := &corev1.ConfigMap{ObjectMeta: objectMeta("config")}
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	if err := indexer.Add(configMap); err != nil {
+		t.Fatal(err)
+	}
+	operatorConfigIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	if err := operatorConfigIndexer.Add(&operatorv1.OpenShiftControllerManager{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster", UID: "operator-config-uid", Annotations: map[string]string{util.PauseReconcileAnnotation: "true"}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	kubeClient := fake.NewSimpleClientset(configMap)
+	c := &ownerReferenceController{
+		objects:              []ManagedObject{{Resource: ConfigMaps, Namespace: testNamespace, Name: "config"}},
+		kubeClient:           kubeClient,
+		operatorConfigLister: operatorlistersv1.NewOpenShiftControllerManagerLister(operatorConfigIndexer),
+		configMapListers:     map[string]corelistersv1.ConfigMapNamespaceLister{testNamespace: corelistersv1.NewConfigMapLister(indexer).ConfigMaps(testNamespace)},
+		recorder:             events.NewInMemoryRecorder("", clock.RealClock{}),
+	}
+
+	if err := c.sync(context.TODO(), nil); err != nil {
+		t.Fatal(err)
+	}
+	if actions := kubeClient.Actions(); len(actions) > 0 {
+		t.Errorf("expected no actions while reconciliation is paused, got %v", actions)
+	}
+}
+
+// TestOwnerReferenceControllerOperatorConfigDeleted asserts that deleting the operator config, which has
+// the garbage collector delete the objects it is the only owner of, leaves the operand Deployments alone.
+func TestOwnerReferenceControllerOperatorConfigDeleted(t *testing.T) {
+	objects := []runtime.Object{
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: util.TargetNamespace, ResourceVersion: "1"}},
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: util.RouteControllerTargetNamespace, ResourceVersion: "1"}},
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "controller-manager", Namespace: util.TargetNamespace, ResourceVersion: "1"}},
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "route-controller-manager", Namespace: util.RouteControllerTargetNamespace, ResourceVersion: "1"}},
+	}
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	for _, object := range objects {
+		if err := indexer.Add(object); err != nil {
+			t.Fatal(err)
+		}
+	}
+	operatorConfigIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	if err := operatorConfigIndexer.Add(&operatorv1.OpenShiftControllerManager{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster", UID: "operator-config-uid"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	kubeClient := fake.NewSimpleClientset(objects...)
+	configMapListers := map[string]corelistersv1.ConfigMapNamespaceLister{}
+	for _, namespace := range []string{util.TargetNamespace, util.RouteControllerTargetNamespace} {
+		configMapListers[namespace] = corelistersv1.NewConfigMapLister(indexer).ConfigMaps(namespace)
+	}
+	c := &ownerReferenceController{
+		objects:              OperandObjects(),
+		kubeClient:           kubeClient,
+		operatorConfigLister: operatorlistersv1.NewOpenShiftControllerManagerLister(operatorConfigIndexer),
+		configMapListers:     configMapListers,
+		recorder:             events.NewInMemoryRecorder("", clock.RealClock{}),
+	}
+
+	if err := c.sync(context.TODO(), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// the garbage collector deletes the objects whose only owner is the deleted operator config
+	collected := func(object metav1.Object) bool {
+		ownerReferences := object.GetOwnerReferences()
+		return len(ownerReferences) == 1 && ownerReferences[0].UID == owner.UID
+	}
+	for _, namespace := range []string{util.TargetNamespace, util.RouteControllerTargetNamespace} {
+		configMap, err := kubeClient.CoreV1().ConfigMaps(namespace).Get(context.TODO(), "config", metav1.GetOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !collected(configMap) {
+			t.Errorf("expected configmap %s/config to be collected with the operator config, got owner references %v", namespace, configMap.OwnerReferences)
+		}
+	}
+	deployments := map[string]string{util.TargetNamespace: "controller-manager", util.RouteControllerTargetNamespace: "route-controller-manager"}
+	for namespace, name := range deployments {
+		deployment, err := kubeClient.AppsV1().Deployments(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if collected(deployment) {
+			t.Errorf("expected deployment %s/%s to survive the deletion of the operator config, got owner references %v", namespace, name, deployment.OwnerReferences)
+		}
+	}
+}
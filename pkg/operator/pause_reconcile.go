@@ -0,0 +1,45 @@
+package operator
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	operatorapiv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-openshift-controller-manager-operator/pkg/util"
+)
+
+const (
+	// pauseReconcileAnnotation pauses the main sync as well as the controllers keeping the operands' CA
+	// bundles and owner references in shape and watching them for rejected configs and expiring serving
+	// certs, see util.ReconciliationPaused. The config observer, the resource syncer and the static
+	// resources keep being reconciled.
+	pauseReconcileAnnotation = util.PauseReconcileAnnotation
+	// reconciliationPausedConditionType is informational only, the ClusterOperator status does not include it.
+	reconciliationPausedConditionType = "ReconciliationPaused"
+)
+
+func isReconciliationPaused(operatorConfig *operatorapiv1.OpenShiftControllerManager) bool {
+	return operatorConfig.Annotations[pauseReconcileAnnotation] == "true"
+}
+
+// reportReconciliationPaused sets the ReconciliationPaused condition and leaves all the other conditions
+// as they were last reconciled.
+func reportReconciliationPaused(c OpenShiftControllerManagerOperator, originalOperatorConfig *operatorapiv1.OpenShiftControllerManager) error {
+	operatorConfig := originalOperatorConfig.DeepCopy()
+	v1helpers.SetOperatorCondition(&operatorConfig.Status.Conditions, operatorapiv1.OperatorCondition{
+		Type:    reconciliationPausedConditionType,
+		Status:  operatorapiv1.ConditionTrue,
+		Reason:  "PauseAnnotationSet",
+		Message: fmt.Sprintf("reconciliation is paused by the %s=true annotation, remove it to resume", pauseReconcileAnnotation),
+	})
+	if equality.Semantic.DeepEqual(operatorConfig.Status, originalOperatorConfig.Status) {
+		return nil
+	}
+	_, err := c.operatorConfigClient.OpenShiftControllerManagers().UpdateStatus(context.TODO(), operatorConfig, metav1.UpdateOptions{})
+	return err
+}
@@ -0,0 +1,149 @@
+package operator
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/utils/clock"
+
+	configv1 "github.com/openshift/api/config/v1"
+	operatorv1 "github.com/openshift/api/operator/v1"
+	configlistersv1 "github.com/openshift/client-go/config/listers/config/v1"
+	operatorfake "github.com/openshift/client-go/operator/clientset/versioned/fake"
+	workloadcontroller "github.com/openshift/library-go/pkg/operator/apiserver/controller/workload"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+)
+
+func TestPauseReconcile(t *testing.T) {
+	const driftEnvName = "OUT_OF_BAND_EDIT"
+
+	kubeClient := fake.NewSimpleClientset(
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "serving-cert", Namespace: "openshift-controller-manager"}},
+		&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "etcd-client", Namespace: "kube-system"}},
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "client-ca", Namespace: "openshift-kube-apiserver"}},
+	)
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	if err := indexer.Add(&configv1.ClusterVersion{ObjectMeta: metav1.ObjectMeta{Name: "version"}}); err != nil {
+		t.Fatal(err)
+	}
+	operatorClient := operatorfake.NewSimpleClientset(&operatorv1.OpenShiftControllerManager{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+		Spec: operatorv1.OpenShiftControllerManagerSpec{
+			OperatorSpec: operatorv1.OperatorSpec{ManagementState: operatorv1.Managed},
+		},
+	})
+	c := OpenShiftControllerManagerOperator{
+		kubeClient:           kubeClient,
+		configMapsGetter:     kubeClient.CoreV1(),
+		proxyLister:          configlistersv1.NewProxyLister(indexer),
+		recorder:             events.NewInMemoryRecorder("", clock.RealClock{}),
+		operatorConfigClient: operatorClient.OperatorV1(),
+		podListers:           operandPodListers(),
+		clusterVersionLister: configlistersv1.NewClusterVersionLister(indexer),
+		infrastructureLister: configlistersv1.NewInfrastructureLister(cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})),
+		countNodes: func(nodeSelector map[string]string) (*int32, error) {
+			result := int32(3)
+			return &result, nil
+		},
+		ensureAtMostOnePodPerNode: workloadcontroller.EnsureAtMostOnePodPerNode,
+		queue:                     workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter()),
+	}
+	defer c.queue.ShutDown()
+
+	getOperatorConfig := func() *operatorv1.OpenShiftControllerManager {
+		t.Helper()
+		operatorConfig, err := operatorClient.OperatorV1().OpenShiftControllerManagers().Get(context.TODO(), "cluster", metav1.GetOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return operatorConfig
+	}
+	setPaused := func(paused bool) {
+		t.Helper()
+		operatorConfig := getOperatorConfig()
+		if paused {
+			metav1.SetMetaDataAnnotation(&operatorConfig.ObjectMeta, pauseReconcileAnnotation, "true")
+		} else {
+			delete(operatorConfig.Annotations, pauseReconcileAnnotation)
+		}
+		if _, err := operatorClient.OperatorV1().OpenShiftControllerManagers().Update(context.TODO(), operatorConfig, metav1.UpdateOptions{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	drifted := func() bool {
+		t.Helper()
+		deployment, err := kubeClient.AppsV1().Deployments("openshift-controller-manager").Get(context.TODO(), "controller-manager", metav1.GetOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, env := range deployment.Spec.Template.Spec.Containers[0].Env {
+			if env.Name == driftEnvName {
+				return true
+			}
+		}
+		return false
+	}
+	paused := func() operatorv1.ConditionStatus {
+		t.Helper()
+		condition := v1helpers.FindOperatorCondition(getOperatorConfig().Status.Conditions, reconciliationPausedConditionType)
+		if condition == nil {
+			t.Fatalf("expected a %s condition", reconciliationPausedConditionType)
+		}
+		return condition.Status
+	}
+
+	if err := c.sync(); err != nil {
+		t.Fatal(err)
+	}
+	if status := paused(); status != operatorv1.ConditionFalse {
+		t.Errorf("expected %s=False without the annotation, got %s", reconciliationPausedConditionType, status)
+	}
+
+	// edit the deployment out-of-band while paused
+	setPaused(true)
+	deployment, err := kubeClient.AppsV1().Deployments("openshift-controller-manager").Get(context.TODO(), "controller-manager", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	deployment.Spec.Template.Spec.Containers[0].Env = append(deployment.Spec.Template.Spec.Containers[0].Env, corev1.EnvVar{Name: driftEnvName, Value: "true"})
+	if _, err := kubeClient.AppsV1().Deployments("openshift-controller-manager").Update(context.TODO(), deployment, metav1.UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	conditionsBefore := getOperatorConfig().Status.Conditions
+
+	if err := c.sync(); err != nil {
+		t.Fatal(err)
+	}
+	if !drifted() {
+		t.Error("expected the drifted deployment to be left alone while paused")
+	}
+	if status := paused(); status != operatorv1.ConditionTrue {
+		t.Errorf("expected %s=True while paused, got %s", reconciliationPausedConditionType, status)
+	}
+	for _, before := range conditionsBefore {
+		if before.Type == reconciliationPausedConditionType {
+			continue
+		}
+		after := v1helpers.FindOperatorCondition(getOperatorConfig().Status.Conditions, before.Type)
+		if after == nil || after.Status != before.Status || after.Reason != before.Reason || after.Message != before.Message {
+			t.Errorf("expected condition %s to be left alone while paused, was %v, got %v", before.Type, before, after)
+		}
+	}
+
+	setPaused(false)
+	if err := c.sync(); err != nil {
+		t.Fatal(err)
+	}
+	if drifted() {
+		t.Error("expected the drift to be reverted once resumed")
+	}
+	if status := paused(); status != operatorv1.ConditionFalse {
+		t.Errorf("expected %s=False once resumed, got %s", reconciliationPausedConditionType, status)
+	}
+}
@@ -0,0 +1,55 @@
+package operator
+
+import (
+	"sort"
+
+	configv1 "github.com/openshift/api/config/v1"
+
+	"github.com/openshift/cluster-openshift-controller-manager-operator/pkg/util"
+)
+
+// relatedObjects returns the related objects of the ClusterOperator, which must-gather and oc adm inspect
+// collect: the operator config, the namespaces of the operator and its operands along with the global
+// config namespaces the operands' inputs are synced from, and the cluster configs the build and image
+// controllers of the operands are configured from. The namespaces cover the objects the operator manages in
+// them.
+func relatedObjects() []configv1.ObjectReference {
+	return sortedRelatedObjects([]configv1.ObjectReference{
+		{Group: "operator.openshift.io", Resource: "openshiftcontrollermanagers", Name: "cluster"},
+		{Resource: "namespaces", Name: util.UserSpecifiedGlobalConfigNamespace},
+		{Resource: "namespaces", Name: util.MachineSpecifiedGlobalConfigNamespace},
+		{Resource: "namespaces", Name: util.OperatorNamespace},
+		{Resource: "namespaces", Name: util.TargetNamespace},
+		{Resource: "namespaces", Name: util.RouteControllerTargetNamespace},
+		{Group: configv1.GroupName, Resource: "builds", Name: "cluster"},
+		{Group: configv1.GroupName, Resource: "images", Name: "cluster"},
+	})
+}
+
+// sortedRelatedObjects returns the objects without duplicates, sorted by group, resource, namespace and
+// name, so that the ClusterOperator is not updated for a mere change of their order.
+func sortedRelatedObjects(objects []configv1.ObjectReference) []configv1.ObjectReference {
+	seen := map[configv1.ObjectReference]bool{}
+	var sorted []configv1.ObjectReference
+	for _, object := range objects {
+		if seen[object] {
+			continue
+		}
+		seen[object] = true
+		sorted = append(sorted, object)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		if a.Group != b.Group {
+			return a.Group < b.Group
+		}
+		if a.Resource != b.Resource {
+			return a.Resource < b.Resource
+		}
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Name < b.Name
+	})
+	return sorted
+}
@@ -0,0 +1,79 @@
+package operator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/clock"
+
+	configv1 "github.com/openshift/api/config/v1"
+	operatorv1 "github.com/openshift/api/operator/v1"
+	configfake "github.com/openshift/client-go/config/clientset/versioned/fake"
+	configinformers "github.com/openshift/client-go/config/informers/externalversions"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/status"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+)
+
+func TestRelatedObjectsWritten(t *testing.T) {
+	clusterOperator := &configv1.ClusterOperator{ObjectMeta: metav1.ObjectMeta{Name: "openshift-controller-manager"}}
+	configClient := configfake.NewSimpleClientset(clusterOperator)
+	configInformers := configinformers.NewSharedInformerFactory(configClient, 0)
+	if err := configInformers.Config().V1().ClusterOperators().Informer().GetIndexer().Add(clusterOperator); err != nil {
+		t.Fatal(err)
+	}
+	recorder := events.NewInMemoryRecorder("", clock.RealClock{})
+	statusSyncer := status.NewClusterOperatorStatusController(
+		"openshift-controller-manager",
+		relatedObjects(),
+		configClient.ConfigV1(),
+		configInformers.Config().V1().ClusterOperators(),
+		v1helpers.NewFakeOperatorClient(&operatorv1.OperatorSpec{ManagementState: operatorv1.Managed}, &operatorv1.OperatorStatus{}, nil),
+		status.NewVersionGetter(),
+		recorder,
+		clock.RealClock{},
+	)
+
+	if err := statusSyncer.Sync(context.TODO(), factory.NewSyncContext("test", recorder)); err != nil {
+		t.Fatal(err)
+	}
+
+	written, err := configClient.ConfigV1().ClusterOperators().Get(context.TODO(), "openshift-controller-manager", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []configv1.ObjectReference{
+		{Resource: "namespaces", Name: "openshift-config"},
+		{Resource: "namespaces", Name: "openshift-config-managed"},
+		{Resource: "namespaces", Name: "openshift-controller-manager"},
+		{Resource: "namespaces", Name: "openshift-controller-manager-operator"},
+		{Resource: "namespaces", Name: "openshift-route-controller-manager"},
+		{Group: "config.openshift.io", Resource: "builds", Name: "cluster"},
+		{Group: "config.openshift.io", Resource: "images", Name: "cluster"},
+		{Group: "operator.openshift.io", Resource: "openshiftcontrollermanagers", Name: "cluster"},
+	}
+	if diff := cmp.Diff(expected, written.Status.RelatedObjects); len(diff) > 0 {
+		t.Errorf("unexpected related objects (-want +got):\n%s", diff)
+	}
+}
+
+func TestSortedRelatedObjects(t *testing.T) {
+	objects := []configv1.ObjectReference{
+		{Resource: "namespaces", Name: "b"},
+		{Group: "config.openshift.io", Resource: "images", Name: "cluster"},
+		{Resource: "namespaces", Name: "a"},
+		{Resource: "namespaces", Name: "b"},
+	}
+	expected := []configv1.ObjectReference{
+		{Resource: "namespaces", Name: "a"},
+		{Resource: "namespaces", Name: "b"},
+		{Group: "config.openshift.io", Resource: "images", Name: "cluster"},
+	}
+	if diff := cmp.Diff(expected, sortedRelatedObjects(objects)); len(diff) > 0 {
+		t.Errorf("unexpected related objects (-want +got):\n%s", diff)
+	}
+}
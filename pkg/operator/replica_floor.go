@@ -0,0 +1,110 @@
+package operator
+
+import (
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+
+	configv1 "github.com/openshift/api/config/v1"
+	configlistersv1 "github.com/openshift/client-go/config/listers/config/v1"
+	"github.com/openshift/library-go/pkg/operator/events"
+)
+
+// replicaFloor is the minimum number of replicas of an operand deployment on the control plane topology
+// of the cluster.
+type replicaFloor struct {
+	topology configv1.TopologyMode
+	minimum  int32
+	// enforced is whether the last count of replicas was raised to the minimum.
+	enforced bool
+	// nodes is the last count of control plane nodes.
+	nodes int32
+	// replicas overrides the count of replicas when set, it is never below the minimum.
+	replicas *int32
+	// capped is whether the last count of replicas was lowered from the override to the control plane
+	// nodes.
+	capped bool
+}
+
+// newReplicaFloor returns the replica floor for the control plane topology of the cluster: highly
+// available topologies run at least two replicas, so that losing a pod does not stop the controllers,
+// all others at least one. A missing infrastructure config has no topology and gets a floor of one.
+// The operands run at most one pod per control plane node, so a floor above the count of control plane
+// nodes is capped at it, but not below one.
+func newReplicaFloor(infrastructureLister configlistersv1.InfrastructureLister) (replicaFloor, error) {
+	infrastructure, err := infrastructureLister.Get("cluster")
+	if apierrors.IsNotFound(err) {
+		return replicaFloor{minimum: 1}, nil
+	}
+	if err != nil {
+		return replicaFloor{minimum: 1}, err
+	}
+	topology := infrastructure.Status.ControlPlaneTopology
+	switch topology {
+	case configv1.HighlyAvailableTopologyMode, configv1.HighlyAvailableArbiterMode, configv1.DualReplicaTopologyMode:
+		return replicaFloor{topology: topology, minimum: 2}, nil
+	default:
+		return replicaFloor{topology: topology, minimum: 1}, nil
+	}
+}
+
+// override makes the floor count replicas instead of the control plane nodes, replicas below the minimum
+// are rejected.
+func (f *replicaFloor) override(replicas int32) error {
+	if replicas < f.minimum {
+		return fmt.Errorf("%d replicas are below the floor of %d for the %q control plane topology", replicas, f.minimum, f.topology)
+	}
+	f.replicas = &replicas
+	return nil
+}
+
+// countReplicas returns a nodeCountFunc counting the control plane nodes with countNodes, raised to the
+// minimum of the floor, or the overridden replicas capped at the count of control plane nodes like the
+// floor. A deployment scaled below the result is reverted as drift.
+func (f *replicaFloor) countReplicas(countNodes nodeCountFunc) nodeCountFunc {
+	return func(nodeSelector map[string]string) (*int32, error) {
+		count, err := countNodes(nodeSelector)
+		if err != nil || count == nil {
+			return count, err
+		}
+		f.nodes = *count
+		if f.replicas != nil {
+			f.enforced = false
+			replicas := min(*f.replicas, max(*count, 1))
+			f.capped = replicas < *f.replicas
+			return &replicas, nil
+		}
+		f.capped = false
+		minimum := f.schedulableMinimum()
+		f.enforced = *count < minimum
+		if !f.enforced {
+			return count, nil
+		}
+		return &minimum, nil
+	}
+}
+
+// schedulableMinimum returns the minimum of the floor capped at the last count of control plane nodes, a
+// replica more than there are nodes to run it on would stay pending.
+func (f *replicaFloor) schedulableMinimum() int32 {
+	return max(min(f.minimum, f.nodes), 1)
+}
+
+// reportEnforced records an event when the floor raised the replicas of the deployment the apply just
+// modified, or lowered them from the override, so that the event is not repeated on every sync.
+func (f *replicaFloor) reportEnforced(recorder events.Recorder, deployment *appsv1.Deployment, modified bool) {
+	if !modified || deployment == nil {
+		return
+	}
+	if f.capped {
+		recorder.Eventf("ReplicaOverrideCapped", "deployment/%s -n %s runs %d replicas instead of the %d of the %s override, one per control plane node",
+			deployment.Name, deployment.Namespace, max(f.nodes, 1), *f.replicas, operandReplicasKey)
+		return
+	}
+	if !f.enforced {
+		return
+	}
+	recorder.Eventf("ReplicaFloorEnforced", "deployment/%s -n %s runs %d replicas, the floor for the %s control plane topology, on %d control plane nodes",
+		deployment.Name, deployment.Namespace, f.schedulableMinimum(), f.topology, f.nodes)
+}
@@ -0,0 +1,177 @@
+package operator
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/utils/clock"
+	"k8s.io/utils/ptr"
+
+	configv1 "github.com/openshift/api/config/v1"
+	operatorv1 "github.com/openshift/api/operator/v1"
+	configlistersv1 "github.com/openshift/client-go/config/listers/config/v1"
+	workloadcontroller "github.com/openshift/library-go/pkg/operator/apiserver/controller/workload"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/resource/resourcemerge"
+
+	"github.com/openshift/cluster-openshift-controller-manager-operator/bindata"
+)
+
+func infrastructureLister(t *testing.T, topology configv1.TopologyMode) configlistersv1.InfrastructureLister {
+	t.Helper()
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	if len(topology) > 0 {
+		if err := indexer.Add(&configv1.Infrastructure{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+			Status:     configv1.InfrastructureStatus{ControlPlaneTopology: topology},
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return configlistersv1.NewInfrastructureLister(indexer)
+}
+
+func TestReplicaFloor(t *testing.T) {
+	tests := []struct {
+		name             string
+		topology         configv1.TopologyMode
+		nodes            int32
+		expectedReplicas int32
+		expectedEnforced bool
+	}{
+		{
+			name:             "highly available with a single control plane node",
+			topology:         configv1.HighlyAvailableTopologyMode,
+			nodes:            1,
+			expectedReplicas: 1,
+		},
+		{
+			name:             "highly available without schedulable control plane nodes",
+			topology:         configv1.HighlyAvailableTopologyMode,
+			nodes:            0,
+			expectedReplicas: 1,
+			expectedEnforced: true,
+		},
+		{
+			name:             "highly available with three control plane nodes",
+			topology:         configv1.HighlyAvailableTopologyMode,
+			nodes:            3,
+			expectedReplicas: 3,
+		},
+		{
+			name:             "single replica",
+			topology:         configv1.SingleReplicaTopologyMode,
+			nodes:            1,
+			expectedReplicas: 1,
+		},
+		{
+			name:             "single replica without schedulable control plane nodes",
+			topology:         configv1.SingleReplicaTopologyMode,
+			nodes:            0,
+			expectedReplicas: 1,
+			expectedEnforced: true,
+		},
+		{
+			name:             "no infrastructure config",
+			nodes:            1,
+			expectedReplicas: 1,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			floor, err := newReplicaFloor(infrastructureLister(t, tc.topology))
+			if err != nil {
+				t.Fatal(err)
+			}
+			replicas, err := floor.countReplicas(func(map[string]string) (*int32, error) { return ptr.To(tc.nodes), nil })(nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if *replicas != tc.expectedReplicas {
+				t.Errorf("expected %d replicas, got %d", tc.expectedReplicas, *replicas)
+			}
+			if floor.enforced != tc.expectedEnforced {
+				t.Errorf("expected the floor to be enforced %t, got %t", tc.expectedEnforced, floor.enforced)
+			}
+		})
+	}
+}
+
+func TestReplicaFloorRevertsScaleDown(t *testing.T) {
+	for _, tc := range []struct {
+		name             string
+		topology         configv1.TopologyMode
+		nodes            int32
+		expectedReplicas int32
+		// expectedEnforcedEvents are recorded when the deployment is created and when the scale down is
+		// reverted, if the floor raises the replicas
+		expectedEnforcedEvents int
+	}{
+		{name: "highly available", topology: configv1.HighlyAvailableTopologyMode, nodes: 2, expectedReplicas: 2},
+		{name: "highly available without schedulable control plane nodes", topology: configv1.HighlyAvailableTopologyMode, expectedReplicas: 1, expectedEnforcedEvents: 2},
+		{name: "single replica", topology: configv1.SingleReplicaTopologyMode, nodes: 1, expectedReplicas: 1},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			kubeClient := fake.NewSimpleClientset()
+			recorder := events.NewInMemoryRecorder("", clock.RealClock{})
+			proxyLister := configlistersv1.NewProxyLister(cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{}))
+			floor, err := newReplicaFloor(infrastructureLister(t, tc.topology))
+			if err != nil {
+				t.Fatal(err)
+			}
+			countNodes := func(map[string]string) (*int32, error) { return ptr.To(tc.nodes), nil }
+			var generations []operatorv1.GenerationStatus
+			manage := func() int32 {
+				t.Helper()
+				deployment, modified, err := manageOpenShiftControllerManagerDeployment_v311_00_to_latest(
+					bindata.MustAsset,
+					kubeClient.AppsV1(),
+					floor.countReplicas(countNodes),
+					workloadcontroller.EnsureAtMostOnePodPerNode,
+					recorder,
+					&operatorv1.OpenShiftControllerManager{},
+					"my.co/repo/img:latest",
+					generations,
+					proxyLister,
+					map[string]string{},
+				)
+				if err != nil {
+					t.Fatal(err)
+				}
+				floor.reportEnforced(recorder, deployment, modified)
+				resourcemerge.SetDeploymentGeneration(&generations, deployment)
+				return *deployment.Spec.Replicas
+			}
+
+			if replicas := manage(); replicas != tc.expectedReplicas {
+				t.Fatalf("expected %d replicas, got %d", tc.expectedReplicas, replicas)
+			}
+
+			// an admin scales the deployment below the floor
+			existing, err := kubeClient.AppsV1().Deployments("openshift-controller-manager").Get(context.TODO(), "controller-manager", metav1.GetOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			existing.Spec.Replicas = ptr.To[int32](0)
+			if _, err := kubeClient.AppsV1().Deployments("openshift-controller-manager").Update(context.TODO(), existing, metav1.UpdateOptions{}); err != nil {
+				t.Fatal(err)
+			}
+			if replicas := manage(); replicas != tc.expectedReplicas {
+				t.Errorf("expected the scale down to be reverted to %d replicas, got %d", tc.expectedReplicas, replicas)
+			}
+
+			enforcedEvents := 0
+			for _, event := range recorder.Events() {
+				if event.Reason == "ReplicaFloorEnforced" {
+					enforcedEvents++
+				}
+			}
+			if enforcedEvents != tc.expectedEnforcedEvents {
+				t.Errorf("expected %d ReplicaFloorEnforced events, got %d", tc.expectedEnforcedEvents, enforcedEvents)
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package operator
+
+import (
+	"math/rand"
+	"time"
+
+	"k8s.io/client-go/util/workqueue"
+)
+
+const (
+	// requeueBaseDelay is the delay of the first requeue after a failed sync, doubled on every further
+	// failure in a row.
+	requeueBaseDelay = 500 * time.Millisecond
+	// requeueMaxDelay caps the delay of the requeues after failed syncs, so that a sync keeps being retried
+	// during a long outage of the APIServer config or the operand deployments.
+	requeueMaxDelay = 5 * time.Minute
+	// requeueJitterFactor is the largest fraction by which a requeue delay is shortened at random, so that
+	// the retries of the operators hitting the same flapping API spread out.
+	requeueJitterFactor = 0.2
+)
+
+// jitteredBackoffRateLimiter backs off exponentially from requeueBaseDelay up to requeueMaxDelay on the
+// failures of an item in a row and takes a random part of up to requeueJitterFactor off each delay. Only
+// the requeues of failed syncs go through it, the event driven and scheduled requeues are not delayed. The
+// overall rate of the syncs is limited by the token bucket of the operator.
+type jitteredBackoffRateLimiter struct {
+	backoff workqueue.RateLimiter
+	// random returns a number in [0, 1).
+	random func() float64
+}
+
+func newRequeueRateLimiter() workqueue.RateLimiter {
+	return &jitteredBackoffRateLimiter{
+		backoff: workqueue.NewItemExponentialFailureRateLimiter(requeueBaseDelay, requeueMaxDelay),
+		random:  rand.Float64,
+	}
+}
+
+func (r *jitteredBackoffRateLimiter) When(item interface{}) time.Duration {
+	delay := r.backoff.When(item)
+	return delay - time.Duration(r.random()*requeueJitterFactor*float64(delay))
+}
+
+func (r *jitteredBackoffRateLimiter) Forget(item interface{}) {
+	r.backoff.Forget(item)
+}
+
+func (r *jitteredBackoffRateLimiter) NumRequeues(item interface{}) int {
+	return r.backoff.NumRequeues(item)
+}
@@ -0,0 +1,52 @@
+package operator
+
+import (
+	"testing"
+	"time"
+
+	"k8s.io/client-go/util/workqueue"
+)
+
+func TestRequeueBackoff(t *testing.T) {
+	random := 0.0
+	limiter := &jitteredBackoffRateLimiter{
+		backoff: workqueue.NewItemExponentialFailureRateLimiter(requeueBaseDelay, requeueMaxDelay),
+		random:  func() float64 { return random },
+	}
+
+	// every failed sync in a row waits longer, up to the cap
+	var previous time.Duration
+	for failure := 1; failure <= 20; failure++ {
+		delay := limiter.When(workQueueKey)
+		if delay > requeueMaxDelay {
+			t.Fatalf("failure %d: delay %s exceeds the cap %s", failure, delay, requeueMaxDelay)
+		}
+		if delay < previous {
+			t.Fatalf("failure %d: delay %s is shorter than the previous %s", failure, delay, previous)
+		}
+		if failure > 1 && previous < requeueMaxDelay && delay == previous {
+			t.Fatalf("failure %d: delay %s did not grow below the cap", failure, delay)
+		}
+		previous = delay
+	}
+	if previous != requeueMaxDelay {
+		t.Errorf("expected the delay to reach the cap %s, got %s", requeueMaxDelay, previous)
+	}
+	if limiter.NumRequeues(workQueueKey) != 20 {
+		t.Errorf("expected 20 requeues, got %d", limiter.NumRequeues(workQueueKey))
+	}
+
+	// the jitter only ever shortens the delay, by at most the jitter factor
+	random = 0.99
+	jittered := limiter.When(workQueueKey)
+	if jittered >= requeueMaxDelay || jittered < time.Duration(float64(requeueMaxDelay)*(1-requeueJitterFactor)) {
+		t.Errorf("expected the jittered delay within %.0f%% below %s, got %s", requeueJitterFactor*100, requeueMaxDelay, jittered)
+	}
+
+	// a successful sync starts over
+	limiter.Forget(workQueueKey)
+	random = 0
+	if delay := limiter.When(workQueueKey); delay != requeueBaseDelay {
+		t.Errorf("expected the base delay %s after a success, got %s", requeueBaseDelay, delay)
+	}
+}
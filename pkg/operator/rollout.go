@@ -0,0 +1,154 @@
+package operator
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	corelistersv1 "k8s.io/client-go/listers/core/v1"
+	"k8s.io/klog/v2"
+
+	operatorapiv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+)
+
+const (
+	// rolloutStuckTimeout is how long a rollout may progress before its not yet available replicas
+	// degrade the operator.
+	rolloutStuckTimeout = 10 * time.Minute
+	rolloutDegradedType = "RolloutDegraded"
+	rolloutStuckReason  = "RolloutStuck"
+)
+
+// setRolloutDegradedCondition degrades the operand when its deployment is still rolling out longer than
+// rolloutStuckTimeout after it started progressing, reporting why the pods of the rollout fail if they
+// tell. The rolling update strategy of the deployments, maxSurge 0 and maxUnavailable 1, keeps the pods of
+// the last good revision serving while the rollout is stuck, the operator does not scale them down.
+//
+// Make sure the Progressing condition is set by setControllerManagerStatusConditions before calling this.
+func setRolloutDegradedCondition(
+	operatorConfig *operatorapiv1.OpenShiftControllerManager,
+	deployment *appsv1.Deployment,
+	pods corelistersv1.PodNamespaceLister,
+	now time.Time,
+	conditionTypePrefix string,
+) {
+	conditionType := conditionTypePrefix + rolloutDegradedType
+	progressing := v1helpers.FindOperatorCondition(operatorConfig.Status.Conditions, conditionTypePrefix+operatorapiv1.OperatorStatusTypeProgressing)
+
+	if !rolloutIncomplete(deployment) || progressing == nil || progressing.Status != operatorapiv1.ConditionTrue ||
+		now.Sub(progressing.LastTransitionTime.Time) < rolloutStuckTimeout {
+		v1helpers.SetOperatorCondition(&operatorConfig.Status.Conditions, operatorapiv1.OperatorCondition{
+			Type:   conditionType,
+			Status: operatorapiv1.ConditionFalse,
+		})
+		return
+	}
+
+	reason, podMessages := failingPods(pods, deployment)
+	message := fmt.Sprintf("deployment/%s -n %s: rollout has not completed within %s, %d of %d replicas updated, %d available",
+		deployment.Name, deployment.Namespace, rolloutStuckTimeout, deployment.Status.UpdatedReplicas, desiredReplicas(deployment), deployment.Status.AvailableReplicas)
+	if len(podMessages) > 0 {
+		message = message + "\n" + strings.Join(podMessages, "\n")
+	}
+	v1helpers.SetOperatorCondition(&operatorConfig.Status.Conditions, operatorapiv1.OperatorCondition{
+		Type:    conditionType,
+		Status:  operatorapiv1.ConditionTrue,
+		Reason:  reason,
+		Message: message,
+	})
+}
+
+// rolloutIncomplete returns whether not all desired replicas are updated and available yet.
+func rolloutIncomplete(deployment *appsv1.Deployment) bool {
+	desired := desiredReplicas(deployment)
+	return deployment.Status.UpdatedReplicas < desired ||
+		deployment.Status.AvailableReplicas < desired ||
+		deployment.Status.Replicas > deployment.Status.UpdatedReplicas
+}
+
+func desiredReplicas(deployment *appsv1.Deployment) int32 {
+	if deployment.Spec.Replicas == nil {
+		return 1
+	}
+	return *deployment.Spec.Replicas
+}
+
+// minAvailableReplicas returns how many replicas the rolling update of the deployment keeps available, all
+// desired replicas without a rolling update, but at least one.
+func minAvailableReplicas(deployment *appsv1.Deployment) int32 {
+	desired := desiredReplicas(deployment)
+	var maxUnavailable int32
+	if rollingUpdate := deployment.Spec.Strategy.RollingUpdate; rollingUpdate != nil && rollingUpdate.MaxUnavailable != nil {
+		if value, err := intstr.GetScaledValueFromIntOrPercent(rollingUpdate.MaxUnavailable, int(desired), false); err == nil {
+			maxUnavailable = int32(value)
+		}
+	}
+	return max(desired-maxUnavailable, min(desired, 1))
+}
+
+// updatedAvailableReplicas returns how many replicas of the latest revision of the deployment are available
+// at least, the status only counts the available replicas of all revisions.
+func updatedAvailableReplicas(deployment *appsv1.Deployment) int32 {
+	status := deployment.Status
+	return max(min(status.AvailableReplicas-(status.Replicas-status.UpdatedReplicas), status.UpdatedReplicas), 0)
+}
+
+// failingPods returns the reason of the first failing container of the deployment's pods, falling back
+// to rolloutStuckReason, and a description of every failing container.
+func failingPods(pods corelistersv1.PodNamespaceLister, deployment *appsv1.Deployment) (string, []string) {
+	reason := rolloutStuckReason
+	deploymentPods, ok := deploymentPods(pods, deployment)
+	if !ok {
+		return reason, nil
+	}
+
+	var messages []string
+	for _, pod := range deploymentPods {
+		for _, status := range append(pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses...) {
+			containerReason, containerMessage := containerFailure(status)
+			if len(containerReason) == 0 {
+				continue
+			}
+			if reason == rolloutStuckReason {
+				reason = containerReason
+			}
+			messages = append(messages, fmt.Sprintf("pod/%s container %q: %s: %s", pod.Name, status.Name, containerReason, containerMessage))
+		}
+	}
+	return reason, messages
+}
+
+// deploymentPods returns the cached pods of the deployment, from the lister of its namespace, sorted by
+// name, for stable messages. Failing to list them is logged and reported as not ok, the callers then fall
+// back to a message without the pods.
+func deploymentPods(pods corelistersv1.PodNamespaceLister, deployment *appsv1.Deployment) ([]*corev1.Pod, bool) {
+	selector, err := metav1.LabelSelectorAsSelector(deployment.Spec.Selector)
+	if err != nil {
+		klog.Warningf("deployment/%s -n %s: invalid selector: %v", deployment.Name, deployment.Namespace, err)
+		return nil, false
+	}
+	selected, err := pods.List(selector)
+	if err != nil {
+		klog.Warningf("deployment/%s -n %s: failed to list pods: %v", deployment.Name, deployment.Namespace, err)
+		return nil, false
+	}
+	sort.Slice(selected, func(i, j int) bool { return selected[i].Name < selected[j].Name })
+	return selected, true
+}
+
+// containerFailure returns why a container is not running, if it failed.
+func containerFailure(status corev1.ContainerStatus) (string, string) {
+	switch {
+	case status.State.Waiting != nil && status.State.Waiting.Reason != "ContainerCreating" && status.State.Waiting.Reason != "PodInitializing":
+		return status.State.Waiting.Reason, status.State.Waiting.Message
+	case status.State.Terminated != nil && status.State.Terminated.ExitCode != 0:
+		return status.State.Terminated.Reason, status.State.Terminated.Message
+	}
+	return "", ""
+}
@@ -0,0 +1,144 @@
+package operator
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	appsclientv1 "k8s.io/client-go/kubernetes/typed/apps/v1"
+	"sigs.k8s.io/yaml"
+
+	"github.com/openshift/library-go/pkg/operator/events"
+)
+
+const (
+	// rolloutInputsAnnotation on an operand deployment holds the hashes of the inputs of its last rollout, so
+	// that the next rollout can tell which of them changed.
+	rolloutInputsAnnotation = "operator.openshift.io/rollout-inputs"
+	// operandConfigKey is the key of the operand config in its ConfigMap.
+	operandConfigKey = "config.yaml"
+)
+
+// rolloutInputs returns a hash of every input rolling out the operand deployment when it changes, keyed by
+// the path of each leaf of the operand config, the other keys of the config ConfigMap, e.g. the hashes of
+// the mounted certificates and CA bundles, and the pod template annotations. The annotations tracking
+// the config ConfigMap and the operator config generation are left out, the config leaves tell what
+// changed in them.
+func rolloutInputs(configMap *corev1.ConfigMap, specAnnotations map[string]string) (map[string]string, error) {
+	inputs := map[string]string{}
+	for key, value := range configMap.Data {
+		if key != operandConfigKey {
+			inputs[key] = inputHash(value)
+			continue
+		}
+		config := map[string]interface{}{}
+		if err := yaml.Unmarshal([]byte(value), &config); err != nil {
+			return nil, fmt.Errorf("configmap/%s -n %s: failed to parse %s: %w", configMap.Name, configMap.Namespace, key, err)
+		}
+		if err := addConfigLeaves(inputs, "", config); err != nil {
+			return nil, err
+		}
+	}
+	for key, value := range specAnnotations {
+		if key == "configmaps/config" || key == "openshiftcontrollermanagers.operator.openshift.io/cluster" {
+			continue
+		}
+		inputs[key] = inputHash(value)
+	}
+	return inputs, nil
+}
+
+func addConfigLeaves(inputs map[string]string, prefix string, config map[string]interface{}) error {
+	for key, value := range config {
+		path := key
+		if len(prefix) > 0 {
+			path = prefix + "." + key
+		}
+		if nested, ok := value.(map[string]interface{}); ok && len(nested) > 0 {
+			if err := addConfigLeaves(inputs, path, nested); err != nil {
+				return err
+			}
+			continue
+		}
+		raw, err := json.Marshal(value)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		inputs[path] = inputHash(string(raw))
+	}
+	return nil
+}
+
+func inputHash(value string) string {
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(value)))[:16]
+}
+
+// changedRolloutInputs returns the sorted keys of the inputs which were added, removed or changed.
+func changedRolloutInputs(previous, current map[string]string) []string {
+	var changed []string
+	for key, hash := range current {
+		if previousHash, ok := previous[key]; !ok || previousHash != hash {
+			changed = append(changed, key)
+		}
+	}
+	for key := range previous {
+		if _, ok := current[key]; !ok {
+			changed = append(changed, key)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
+
+// trackRolloutInputs records the inputs of the applied operand deployment, see recordRolloutInputs.
+func trackRolloutInputs(client appsclientv1.DeploymentsGetter, recorder events.Recorder, deployment *appsv1.Deployment, configMap *corev1.ConfigMap, specAnnotations map[string]string) error {
+	inputs, err := rolloutInputs(configMap, specAnnotations)
+	if err != nil {
+		return err
+	}
+	return recordRolloutInputs(client, recorder, deployment, inputs)
+}
+
+// recordRolloutInputs stores the inputs on the applied deployment and, when inputs of the previous rollout
+// changed, reports them in an OperandRolloutStarted event. The deployment is only written when its inputs
+// changed.
+func recordRolloutInputs(client appsclientv1.DeploymentsGetter, recorder events.Recorder, deployment *appsv1.Deployment, inputs map[string]string) error {
+	raw, err := json.Marshal(inputs)
+	if err != nil {
+		return err
+	}
+	previousRaw, recorded := deployment.Annotations[rolloutInputsAnnotation]
+	if recorded && previousRaw == string(raw) {
+		return nil
+	}
+
+	previous := map[string]string{}
+	if recorded {
+		if err := json.Unmarshal([]byte(previousRaw), &previous); err != nil {
+			// an unreadable snapshot is replaced, there is nothing to compare with
+			recorded = false
+		}
+	}
+	if changed := changedRolloutInputs(previous, inputs); recorded && len(changed) > 0 {
+		recorder.Eventf("OperandRolloutStarted", "Rolling out deployment/%s -n %s because these inputs changed: %s",
+			deployment.Name, deployment.Namespace, strings.Join(changed, ", "))
+	}
+
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]string{rolloutInputsAnnotation: string(raw)},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = client.Deployments(deployment.Namespace).Patch(context.TODO(), deployment.Name, types.MergePatchType, patch, metav1.PatchOptions{})
+	return err
+}
@@ -0,0 +1,100 @@
+package operator
+
+import (
+	"context"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/clock"
+
+	"github.com/openshift/library-go/pkg/operator/events"
+)
+
+func TestTrackRolloutInputs(t *testing.T) {
+	kubeClient := fake.NewSimpleClientset(&appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "controller-manager", Namespace: "openshift-controller-manager"},
+	})
+	recorder := events.NewInMemoryRecorder("", clock.RealClock{})
+
+	configMap := func(config, servingCertHash string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: "openshift-controller-manager"},
+			Data: map[string]string{
+				"config.yaml": config,
+				"openshift-controller-manager.serving-cert.secret": servingCertHash,
+				"openshift-controller-manager.client-ca.configmap": "client-ca-hash",
+			},
+		}
+	}
+	track := func(configMap *corev1.ConfigMap, specAnnotations map[string]string) {
+		t.Helper()
+		deployment, err := kubeClient.AppsV1().Deployments("openshift-controller-manager").Get(context.TODO(), "controller-manager", metav1.GetOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := trackRolloutInputs(kubeClient.AppsV1(), recorder, deployment, configMap, specAnnotations); err != nil {
+			t.Fatal(err)
+		}
+	}
+	rolloutEvents := func() []string {
+		var messages []string
+		for _, event := range recorder.Events() {
+			if event.Reason == "OperandRolloutStarted" {
+				messages = append(messages, event.Message)
+			}
+		}
+		return messages
+	}
+
+	// the first rollout has nothing to compare with
+	track(
+		configMap(`{"servingInfo":{"minTLSVersion":"VersionTLS12","cipherSuites":["TLS_AES_128_GCM_SHA256"]},"build":{"additionalTrustedCA":"/ca.crt"}}`, "cert-1"),
+		map[string]string{"configmaps/config": "1", "configmaps/client-ca": "10", "openshiftcontrollermanagers.operator.openshift.io/cluster": "1"},
+	)
+	if messages := rolloutEvents(); len(messages) > 0 {
+		t.Fatalf("expected no rollout event without recorded inputs, got %v", messages)
+	}
+
+	track(
+		configMap(`{"servingInfo":{"minTLSVersion":"VersionTLS13","cipherSuites":["TLS_AES_128_GCM_SHA256"]},"build":{"additionalTrustedCA":"/ca.crt"}}`, "cert-2"),
+		map[string]string{"configmaps/config": "2", "configmaps/client-ca": "10", "openshiftcontrollermanagers.operator.openshift.io/cluster": "2"},
+	)
+	expected := "Rolling out deployment/controller-manager -n openshift-controller-manager because these inputs changed: " +
+		"openshift-controller-manager.serving-cert.secret, servingInfo.minTLSVersion"
+	if messages := rolloutEvents(); len(messages) != 1 || messages[0] != expected {
+		t.Fatalf("expected the rollout event %q, got %v", expected, messages)
+	}
+
+	// unchanged inputs neither report a rollout nor write the deployment
+	kubeClient.ClearActions()
+	track(
+		configMap(`{"servingInfo":{"minTLSVersion":"VersionTLS13","cipherSuites":["TLS_AES_128_GCM_SHA256"]},"build":{"additionalTrustedCA":"/ca.crt"}}`, "cert-2"),
+		map[string]string{"configmaps/config": "2", "configmaps/client-ca": "10", "openshiftcontrollermanagers.operator.openshift.io/cluster": "2"},
+	)
+	if messages := rolloutEvents(); len(messages) != 1 {
+		t.Errorf("expected no further rollout event, got %v", messages)
+	}
+	for _, action := range kubeClient.Actions() {
+		if action.GetVerb() == "patch" {
+			t.Errorf("expected the deployment not to be written, got %v", action)
+		}
+	}
+}
+
+func TestChangedRolloutInputs(t *testing.T) {
+	previous := map[string]string{"kept": "a", "changed": "a", "removed": "a"}
+	current := map[string]string{"kept": "a", "changed": "b", "added": "a"}
+	changed := changedRolloutInputs(previous, current)
+	expected := []string{"added", "changed", "removed"}
+	if len(changed) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, changed)
+	}
+	for i := range expected {
+		if changed[i] != expected[i] {
+			t.Errorf("expected %v, got %v", expected, changed)
+		}
+	}
+}
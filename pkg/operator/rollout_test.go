@@ -0,0 +1,220 @@
+package operator
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes/fake"
+	corelistersv1 "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/utils/clock"
+	"k8s.io/utils/ptr"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	configlistersv1 "github.com/openshift/client-go/config/listers/config/v1"
+	workloadcontroller "github.com/openshift/library-go/pkg/operator/apiserver/controller/workload"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/resource/resourcemerge"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-openshift-controller-manager-operator/bindata"
+	"github.com/openshift/cluster-openshift-controller-manager-operator/pkg/util"
+)
+
+func stuckDeployment() *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "controller-manager", Namespace: "openshift-controller-manager", Generation: 2},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: ptr.To[int32](3),
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "openshift-controller-manager-a"}},
+		},
+		Status: appsv1.DeploymentStatus{
+			ObservedGeneration: 2,
+			Replicas:           3,
+			UpdatedReplicas:    1,
+			// the two pods of the last good revision keep serving
+			AvailableReplicas: 2,
+		},
+	}
+}
+
+func rolloutPod(name string, state corev1.ContainerState) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "openshift-controller-manager",
+			Labels:    map[string]string{"app": "openshift-controller-manager-a"},
+		},
+		Status: corev1.PodStatus{
+			ContainerStatuses: []corev1.ContainerStatus{{Name: "controller-manager", State: state}},
+		},
+	}
+}
+
+// podLister returns a lister of the pods of the operand namespace serving the given pods.
+func podLister(t *testing.T, pods ...runtime.Object) corelistersv1.PodNamespaceLister {
+	t.Helper()
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	for _, pod := range pods {
+		if err := indexer.Add(pod); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return corelistersv1.NewPodLister(indexer).Pods("openshift-controller-manager")
+}
+
+// operandPodListers returns listers of the pods of the operand namespaces serving no pods.
+func operandPodListers() map[string]corelistersv1.PodNamespaceLister {
+	lister := corelistersv1.NewPodLister(cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc}))
+	return map[string]corelistersv1.PodNamespaceLister{
+		util.TargetNamespace:                lister.Pods(util.TargetNamespace),
+		util.RouteControllerTargetNamespace: lister.Pods(util.RouteControllerTargetNamespace),
+	}
+}
+
+func TestSetRolloutDegradedCondition(t *testing.T) {
+	now := time.Now()
+	running := corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}
+	imagePullBackOff := corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "ImagePullBackOff", Message: "Back-off pulling image"}}
+
+	tests := []struct {
+		name             string
+		deployment       *appsv1.Deployment
+		progressingSince time.Duration
+		pods             []runtime.Object
+		expectedStatus   operatorv1.ConditionStatus
+		expectedReason   string
+		expectedMessages []string
+	}{
+		{
+			name:             "new replicas never become available",
+			deployment:       stuckDeployment(),
+			progressingSince: 15 * time.Minute,
+			pods: []runtime.Object{
+				rolloutPod("controller-manager-old-1", running),
+				rolloutPod("controller-manager-old-2", running),
+				rolloutPod("controller-manager-new-1", imagePullBackOff),
+			},
+			expectedStatus: operatorv1.ConditionTrue,
+			expectedReason: "ImagePullBackOff",
+			expectedMessages: []string{
+				"1 of 3 replicas updated, 2 available",
+				`pod/controller-manager-new-1 container "controller-manager": ImagePullBackOff: Back-off pulling image`,
+			},
+		},
+		{
+			name:             "stuck without a failing container",
+			deployment:       stuckDeployment(),
+			progressingSince: 15 * time.Minute,
+			expectedStatus:   operatorv1.ConditionTrue,
+			expectedReason:   rolloutStuckReason,
+		},
+		{
+			name:             "rollout within the timeout",
+			deployment:       stuckDeployment(),
+			progressingSince: 2 * time.Minute,
+			pods:             []runtime.Object{rolloutPod("controller-manager-new-1", imagePullBackOff)},
+			expectedStatus:   operatorv1.ConditionFalse,
+		},
+		{
+			name: "rollout complete",
+			deployment: func() *appsv1.Deployment {
+				d := stuckDeployment()
+				d.Status.UpdatedReplicas = 3
+				d.Status.AvailableReplicas = 3
+				return d
+			}(),
+			progressingSince: 15 * time.Minute,
+			expectedStatus:   operatorv1.ConditionFalse,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			operatorConfig := &operatorv1.OpenShiftControllerManager{
+				Status: operatorv1.OpenShiftControllerManagerStatus{
+					OperatorStatus: operatorv1.OperatorStatus{
+						Conditions: []operatorv1.OperatorCondition{
+							{
+								Type:               operatorv1.OperatorStatusTypeProgressing,
+								Status:             operatorv1.ConditionTrue,
+								LastTransitionTime: metav1.NewTime(now.Add(-tc.progressingSince)),
+							},
+						},
+					},
+				},
+			}
+			setRolloutDegradedCondition(operatorConfig, tc.deployment, podLister(t, tc.pods...), now, "")
+
+			condition := v1helpers.FindOperatorCondition(operatorConfig.Status.Conditions, rolloutDegradedType)
+			if condition == nil {
+				t.Fatalf("expected a %s condition", rolloutDegradedType)
+			}
+			if condition.Status != tc.expectedStatus {
+				t.Errorf("expected %s=%s, got %s", rolloutDegradedType, tc.expectedStatus, condition.Status)
+			}
+			if condition.Reason != tc.expectedReason {
+				t.Errorf("expected reason %q, got %q", tc.expectedReason, condition.Reason)
+			}
+			for _, message := range tc.expectedMessages {
+				if !strings.Contains(condition.Message, message) {
+					t.Errorf("expected message to contain %q, got %q", message, condition.Message)
+				}
+			}
+		})
+	}
+}
+
+func TestStuckRolloutKeepsGoodRevision(t *testing.T) {
+	kubeClient := fake.NewSimpleClientset()
+	proxyLister := configlistersv1.NewProxyLister(cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{}))
+	recorder := events.NewInMemoryRecorder("", clock.RealClock{})
+	countNodes := func(nodeSelector map[string]string) (*int32, error) {
+		return ptr.To[int32](3), nil
+	}
+	var generations []operatorv1.GenerationStatus
+	manage := func() (*appsv1.Deployment, bool) {
+		deployment, modified, err := manageOpenShiftControllerManagerDeployment_v311_00_to_latest(
+			bindata.MustAsset,
+			kubeClient.AppsV1(),
+			countNodes,
+			workloadcontroller.EnsureAtMostOnePodPerNode,
+			recorder,
+			&operatorv1.OpenShiftControllerManager{},
+			"my.co/repo/img:latest",
+			generations,
+			proxyLister,
+			map[string]string{},
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resourcemerge.SetDeploymentGeneration(&generations, deployment)
+		return deployment, modified
+	}
+
+	deployment, _ := manage()
+	// the new revision never becomes available
+	deployment.Status = stuckDeployment().Status
+	if _, err := kubeClient.AppsV1().Deployments(deployment.Namespace).UpdateStatus(context.TODO(), deployment, metav1.UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	deployment, modified := manage()
+	if modified {
+		t.Error("expected the stuck deployment to not be updated")
+	}
+	if *deployment.Spec.Replicas != 3 {
+		t.Errorf("expected the deployment to keep 3 replicas, got %d", *deployment.Spec.Replicas)
+	}
+	rollingUpdate := deployment.Spec.Strategy.RollingUpdate
+	if rollingUpdate == nil || *rollingUpdate.MaxSurge != intstr.FromInt32(0) || *rollingUpdate.MaxUnavailable != intstr.FromInt32(1) {
+		t.Errorf("expected at most one replica of the good revision to be replaced at a time, got %v", rollingUpdate)
+	}
+}
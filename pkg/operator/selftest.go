@@ -0,0 +1,146 @@
+package operator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	coreclientv1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/yaml"
+
+	operatorclientv1 "github.com/openshift/client-go/operator/clientset/versioned/typed/operator/v1"
+	"github.com/openshift/library-go/pkg/operator/events"
+
+	"github.com/openshift/cluster-openshift-controller-manager-operator/pkg/util"
+)
+
+const (
+	// selfTestEnv must be true in the environment of the operator for --self-test to be accepted, so that a
+	// flag copied into a production deployment does not run the self-test.
+	selfTestEnv = "OCM_OPERATOR_SELF_TEST"
+	// selfTestKey is the key of the operand config the self-test marker is written to, the operand ignores
+	// keys it does not know.
+	selfTestKey = "operatorSelfTest"
+	// selfTestMarkerKey is the key of the marker under selfTestKey.
+	selfTestMarkerKey = "marker"
+	// selfTestPollInterval is how often the self-test checks the operand config.
+	selfTestPollInterval = 5 * time.Second
+	// selfTestTimeout is how long the self-test waits for the operand config to add or drop the marker.
+	selfTestTimeout = 5 * time.Minute
+)
+
+// selfTestAllowed returns whether the environment allows the self-test.
+func selfTestAllowed(lookupEnv func(string) (string, bool)) bool {
+	value, ok := lookupEnv(selfTestEnv)
+	if !ok {
+		return false
+	}
+	allowed, err := strconv.ParseBool(value)
+	return err == nil && allowed
+}
+
+type selfTest struct {
+	operatorConfigClient operatorclientv1.OpenShiftControllerManagersGetter
+	configMapsGetter     coreclientv1.ConfigMapsGetter
+	recorder             events.Recorder
+	interval, timeout    time.Duration
+}
+
+// run checks the pipeline from the operator config to the operand config: it sets a marker in the
+// unsupportedConfigOverrides of the operator config, waits for the operand config to carry it, removes it
+// and waits for the operand config to drop it again. The marker is removed even when the operand config
+// never carries it. The result is logged and recorded as an event, it never fails the operator. Changing
+// the operand config rolls the operand out twice.
+func (s *selfTest) run(ctx context.Context, marker string) error {
+	err := s.roundTrip(ctx, marker)
+	if err != nil {
+		klog.Errorf("Self-test of the operand config propagation failed: %v", err)
+		s.recorder.Warningf("SelfTestFailed", "Self-test of the operand config propagation failed: %v", err)
+		return err
+	}
+	klog.Infof("Self-test of the operand config propagation succeeded with marker %s", marker)
+	s.recorder.Eventf("SelfTestSucceeded", "Self-test of the operand config propagation succeeded with marker %s", marker)
+	return nil
+}
+
+func (s *selfTest) roundTrip(ctx context.Context, marker string) error {
+	if err := s.setMarker(ctx, &marker); err != nil {
+		return err
+	}
+	verifyErr := s.waitForMarker(ctx, marker, true)
+	// the marker is removed with a fresh context, it must not outlive a self-test cut short
+	if err := s.setMarker(context.Background(), nil); err != nil {
+		return fmt.Errorf("failed to remove the self-test marker %s: %w", marker, err)
+	}
+	if verifyErr != nil {
+		return verifyErr
+	}
+	return s.waitForMarker(ctx, marker, false)
+}
+
+// setMarker sets the marker in the unsupportedConfigOverrides of the operator config, or removes it if
+// marker is nil, leaving the other overrides alone.
+func (s *selfTest) setMarker(ctx context.Context, marker *string) error {
+	var selfTest interface{}
+	if marker != nil {
+		selfTest = map[string]interface{}{selfTestMarkerKey: *marker}
+	}
+	patch, err := json.Marshal(map[string]interface{}{
+		"spec": map[string]interface{}{
+			"unsupportedConfigOverrides": map[string]interface{}{selfTestKey: selfTest},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = s.operatorConfigClient.OpenShiftControllerManagers().Patch(ctx, "cluster", types.MergePatchType, patch, metav1.PatchOptions{})
+	return err
+}
+
+// waitForMarker waits for the operand config to carry the marker, or not to if present is false.
+func (s *selfTest) waitForMarker(ctx context.Context, marker string, present bool) error {
+	var found string
+	err := wait.PollUntilContextTimeout(ctx, s.interval, s.timeout, true, func(ctx context.Context) (bool, error) {
+		configMap, err := s.configMapsGetter.ConfigMaps(util.TargetNamespace).Get(ctx, "config", metav1.GetOptions{})
+		if err != nil {
+			klog.V(2).Infof("Self-test failed to get configmap/config -n %s: %v", util.TargetNamespace, err)
+			return false, nil
+		}
+		config := map[string]interface{}{}
+		if err := yaml.Unmarshal([]byte(configMap.Data["config.yaml"]), &config); err != nil {
+			return false, fmt.Errorf("failed to decode configmap/config -n %s: %w", util.TargetNamespace, err)
+		}
+		found = ""
+		if selfTest, ok := config[selfTestKey].(map[string]interface{}); ok {
+			found, _ = selfTest[selfTestMarkerKey].(string)
+		}
+		return (found == marker) == present, nil
+	})
+	if err == nil {
+		return nil
+	}
+	if present {
+		return fmt.Errorf("configmap/config -n %s did not get the self-test marker %s within %s, has %q: %w", util.TargetNamespace, marker, s.timeout, found, err)
+	}
+	return fmt.Errorf("configmap/config -n %s still has the self-test marker %s after %s: %w", util.TargetNamespace, marker, s.timeout, err)
+}
+
+// runSelfTest runs the self-test once with a marker unique to this start of the operator.
+func runSelfTest(ctx context.Context, operatorConfigClient operatorclientv1.OpenShiftControllerManagersGetter, configMapsGetter coreclientv1.ConfigMapsGetter, recorder events.Recorder) {
+	s := &selfTest{
+		operatorConfigClient: operatorConfigClient,
+		configMapsGetter:     configMapsGetter,
+		recorder:             recorder,
+		interval:             selfTestPollInterval,
+		timeout:              selfTestTimeout,
+	}
+	hostname, _ := os.Hostname()
+	_ = s.run(ctx, fmt.Sprintf("%s-%d", hostname, time.Now().Unix()))
+}
@@ -0,0 +1,118 @@
+package operator
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+	"k8s.io/utils/clock"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	operatorfake "github.com/openshift/client-go/operator/clientset/versioned/fake"
+	"github.com/openshift/library-go/pkg/operator/events"
+)
+
+func TestSelfTest(t *testing.T) {
+	tests := []struct {
+		name string
+		// propagate is whether the operand config follows the overrides of the operator config
+		propagate     bool
+		expectedError string
+		expectedEvent string
+	}{
+		{
+			name:          "marker round-trips",
+			propagate:     true,
+			expectedEvent: "SelfTestSucceeded",
+		},
+		{
+			name:          "marker never reaches the operand",
+			expectedError: "configmap/config -n openshift-controller-manager did not get the self-test marker test-marker",
+			expectedEvent: "SelfTestFailed",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			operatorClient := operatorfake.NewSimpleClientset(&operatorv1.OpenShiftControllerManager{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+				Spec: operatorv1.OpenShiftControllerManagerSpec{OperatorSpec: operatorv1.OperatorSpec{
+					UnsupportedConfigOverrides: runtime.RawExtension{Raw: []byte(`{"kubeClientConfig":{"qps":100}}`)},
+				}},
+			})
+			kubeClient := fake.NewSimpleClientset()
+			// the operator writes the overrides of the operator config into the operand config
+			kubeClient.PrependReactor("get", "configmaps", func(clienttesting.Action) (bool, runtime.Object, error) {
+				data := "{}"
+				if tc.propagate {
+					operatorConfig, err := operatorClient.OperatorV1().OpenShiftControllerManagers().Get(context.TODO(), "cluster", metav1.GetOptions{})
+					if err != nil {
+						return true, nil, err
+					}
+					data = string(operatorConfig.Spec.UnsupportedConfigOverrides.Raw)
+				}
+				return true, &corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: "openshift-controller-manager"},
+					Data:       map[string]string{"config.yaml": data},
+				}, nil
+			})
+			recorder := events.NewInMemoryRecorder("", clock.RealClock{})
+			s := &selfTest{
+				operatorConfigClient: operatorClient.OperatorV1(),
+				configMapsGetter:     kubeClient.CoreV1(),
+				recorder:             recorder,
+				interval:             time.Millisecond,
+				timeout:              50 * time.Millisecond,
+			}
+
+			err := s.run(context.TODO(), "test-marker")
+			if len(tc.expectedError) == 0 && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(tc.expectedError) > 0 && (err == nil || !strings.Contains(err.Error(), tc.expectedError)) {
+				t.Fatalf("expected an error containing %q, got %v", tc.expectedError, err)
+			}
+
+			// the marker is set and removed again, the other overrides are left alone
+			var patches []string
+			for _, action := range operatorClient.Actions() {
+				if patch, ok := action.(clienttesting.PatchAction); ok {
+					patches = append(patches, string(patch.GetPatch()))
+				}
+			}
+			expectedPatches := []string{
+				`{"spec":{"unsupportedConfigOverrides":{"operatorSelfTest":{"marker":"test-marker"}}}}`,
+				`{"spec":{"unsupportedConfigOverrides":{"operatorSelfTest":null}}}`,
+			}
+			if diff := cmp.Diff(expectedPatches, patches); len(diff) > 0 {
+				t.Errorf("unexpected patches of the operator config (-want +got):\n%s", diff)
+			}
+			operatorConfig, err := operatorClient.OperatorV1().OpenShiftControllerManagers().Get(context.TODO(), "cluster", metav1.GetOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			overrides := map[string]interface{}{}
+			if err := json.Unmarshal(operatorConfig.Spec.UnsupportedConfigOverrides.Raw, &overrides); err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(map[string]interface{}{"kubeClientConfig": map[string]interface{}{"qps": float64(100)}}, overrides); len(diff) > 0 {
+				t.Errorf("unexpected overrides left behind (-want +got):\n%s", diff)
+			}
+
+			var reasons []string
+			for _, event := range recorder.Events() {
+				reasons = append(reasons, event.Reason)
+			}
+			if diff := cmp.Diff([]string{tc.expectedEvent}, reasons); len(diff) > 0 {
+				t.Errorf("unexpected events (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
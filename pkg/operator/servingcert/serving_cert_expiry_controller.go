@@ -0,0 +1,149 @@
+package servingcert
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+	corelistersv1 "k8s.io/client-go/listers/core/v1"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-openshift-controller-manager-operator/pkg/operator/metrics"
+	"github.com/openshift/cluster-openshift-controller-manager-operator/pkg/util"
+)
+
+const (
+	// ServingCertSecretName is the secret the service CA issues the serving cert of the operands into.
+	ServingCertSecretName = "serving-cert"
+	// DefaultExpiryThreshold is the fraction of its lifetime after which a serving cert is reported as expiring.
+	DefaultExpiryThreshold = 0.8
+
+	servingCertExpiringType = "ServingCertExpiring"
+	nearExpiryReason        = "NearExpiry"
+	controllerName          = "ServingCertExpiryController"
+	controllerEventSuffix   = "serving-cert-expiry-controller"
+)
+
+type servingCertExpiryController struct {
+	factory.Controller
+	namespaces      []string
+	expiryThreshold float64
+	operatorClient  v1helpers.OperatorClient
+	secretListers   map[string]corelistersv1.SecretNamespaceLister
+	recorder        events.Recorder
+	now             func() time.Time
+}
+
+// NewServingCertExpiryController returns a controller reporting the serving certs of the operands in the
+// given namespaces which are past expiryThreshold of their lifetime, DefaultExpiryThreshold if it is not in
+// (0, 1). The secrets are left alone: the service CA operator rotates the certs it issued and the operand
+// rolls out to reload them as the secret is an input of its deployment. The ServingCertExpiring condition
+// names the expiring certs, it is informational and does not degrade the operator. Nothing is reported
+// while reconciliation is paused.
+func NewServingCertExpiryController(
+	namespaces []string,
+	expiryThreshold float64,
+	operatorClient v1helpers.OperatorClient,
+	kubeInformers v1helpers.KubeInformersForNamespaces,
+	recorder events.Recorder,
+	resyncInterval time.Duration,
+) factory.Controller {
+	if expiryThreshold <= 0 || expiryThreshold >= 1 {
+		expiryThreshold = DefaultExpiryThreshold
+	}
+	c := &servingCertExpiryController{
+		namespaces:      namespaces,
+		expiryThreshold: expiryThreshold,
+		operatorClient:  operatorClient,
+		secretListers:   map[string]corelistersv1.SecretNamespaceLister{},
+		recorder:        recorder.WithComponentSuffix(controllerEventSuffix),
+		now:             time.Now,
+	}
+	var informers []factory.Informer
+	for _, namespace := range namespaces {
+		secrets := kubeInformers.InformersFor(namespace).Core().V1().Secrets()
+		c.secretListers[namespace] = secrets.Lister().Secrets(namespace)
+		informers = append(informers, secrets.Informer())
+	}
+	c.Controller = factory.New().
+		WithInformers(informers...).
+		WithSync(c.sync).
+		// a cert ages without its secret changing
+		ResyncEvery(resyncInterval).
+		ToController(controllerName, c.recorder)
+	return c
+}
+
+func (c *servingCertExpiryController) sync(ctx context.Context, _ factory.SyncContext) error {
+	defer metrics.ObserveReconcileDuration(controllerName, time.Now())
+
+	if paused, err := util.ReconciliationPaused(c.operatorClient); err != nil || paused {
+		return err
+	}
+
+	now := c.now()
+	var errs []error
+	var expiring []string
+	for _, namespace := range c.namespaces {
+		secret, err := c.secretListers[namespace].Get(ServingCertSecretName)
+		if errors.IsNotFound(err) {
+			// the service CA has not issued it yet
+			continue
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("unable to get secret %q (ns=%q): %w", ServingCertSecretName, namespace, err))
+			continue
+		}
+		cert, err := servingCert(secret)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("unable to read the serving cert of secret %q (ns=%q): %w", ServingCertSecretName, namespace, err))
+			continue
+		}
+		expiringAt := expiryTime(cert, c.expiryThreshold)
+		if now.Before(expiringAt) {
+			continue
+		}
+		expiring = append(expiring, fmt.Sprintf("secret/%s -n %s expires at %s, it is expiring since %s", ServingCertSecretName, namespace, cert.NotAfter.UTC().Format(time.RFC3339), expiringAt.UTC().Format(time.RFC3339)))
+	}
+	sort.Strings(expiring)
+
+	condition := operatorv1.OperatorCondition{
+		Type:   servingCertExpiringType,
+		Status: operatorv1.ConditionFalse,
+	}
+	if len(expiring) > 0 {
+		condition.Status = operatorv1.ConditionTrue
+		condition.Reason = nearExpiryReason
+		condition.Message = strings.Join(expiring, "\n")
+	}
+	if _, _, err := v1helpers.UpdateStatus(ctx, c.operatorClient, v1helpers.UpdateConditionFn(condition)); err != nil {
+		errs = append(errs, err)
+	}
+	return utilerrors.NewAggregate(errs)
+}
+
+// servingCert returns the leaf certificate of the secret, the first one of its tls.crt.
+func servingCert(secret *corev1.Secret) (*x509.Certificate, error) {
+	block, _ := pem.Decode(secret.Data[corev1.TLSCertKey])
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, fmt.Errorf("%s has no PEM encoded certificate", corev1.TLSCertKey)
+	}
+	return x509.ParseCertificate(block.Bytes)
+}
+
+// expiryTime returns when the cert is past the threshold of its lifetime.
+func expiryTime(cert *x509.Certificate, threshold float64) time.Time {
+	lifetime := cert.NotAfter.Sub(cert.NotBefore)
+	return cert.NotBefore.Add(time.Duration(float64(lifetime) * threshold))
+}
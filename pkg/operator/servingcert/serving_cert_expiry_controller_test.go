@@ -0,0 +1,181 @@
+package servingcert
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corelistersv1 "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/utils/clock"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-openshift-controller-manager-operator/pkg/util"
+)
+
+const testNamespace = "openshift-controller-manager"
+
+// servingCertSecret returns a serving cert secret with a cert valid from notBefore to notAfter.
+func servingCertSecret(t *testing.T, notBefore, notAfter time.Time) *corev1.Secret {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "controller-manager.openshift-controller-manager.svc"},
+		NotBefore:    notBefore,
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: ServingCertSecretName, Namespace: testNamespace, UID: "uid", ResourceVersion: "1"},
+		Type:       corev1.SecretTypeTLS,
+		Data: map[string][]byte{
+			corev1.TLSCertKey: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		},
+	}
+}
+
+func TestServingCertExpiryController(t *testing.T) {
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name            string
+		secret          func(t *testing.T) *corev1.Secret
+		expectedStatus  operatorv1.ConditionStatus
+		expectedMessage string
+	}{
+		{
+			name: "near expiry cert is reported",
+			secret: func(t *testing.T) *corev1.Secret {
+				// 90% of its two year lifetime has passed
+				return servingCertSecret(t, now.Add(-657*24*time.Hour), now.Add(73*24*time.Hour))
+			},
+			expectedStatus:  operatorv1.ConditionTrue,
+			expectedMessage: "secret/serving-cert -n openshift-controller-manager expires at 2026-08-13T00:00:00Z",
+		},
+		{
+			name: "fresh cert is not reported",
+			secret: func(t *testing.T) *corev1.Secret {
+				return servingCertSecret(t, now.Add(-24*time.Hour), now.Add(729*24*time.Hour))
+			},
+			expectedStatus: operatorv1.ConditionFalse,
+		},
+		{
+			name:           "secret not issued yet",
+			expectedStatus: operatorv1.ConditionFalse,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+			if tc.secret != nil {
+				if err := indexer.Add(tc.secret(t)); err != nil {
+					t.Fatal(err)
+				}
+			}
+			operatorClient := v1helpers.NewFakeOperatorClient(&operatorv1.OperatorSpec{}, &operatorv1.OperatorStatus{}, nil)
+			c := &servingCertExpiryController{
+				namespaces:      []string{testNamespace},
+				expiryThreshold: DefaultExpiryThreshold,
+				operatorClient:  operatorClient,
+				secretListers:   map[string]corelistersv1.SecretNamespaceLister{testNamespace: corelistersv1.NewSecretLister(indexer).Secrets(testNamespace)},
+				recorder:        events.NewInMemoryRecorder("", clock.RealClock{}),
+				now:             func() time.Time { return now },
+			}
+
+			if err := c.sync(context.TODO(), nil); err != nil {
+				t.Fatal(err)
+			}
+
+			_, status, _, err := operatorClient.GetOperatorState()
+			if err != nil {
+				t.Fatal(err)
+			}
+			condition := v1helpers.FindOperatorCondition(status.Conditions, servingCertExpiringType)
+			if condition == nil {
+				t.Fatalf("expected a %s condition", servingCertExpiringType)
+			}
+			if condition.Status != tc.expectedStatus {
+				t.Errorf("expected %s=%s, got %s", servingCertExpiringType, tc.expectedStatus, condition.Status)
+			}
+			if !strings.Contains(condition.Message, tc.expectedMessage) {
+				t.Errorf("expected the condition message to contain %q, got %q", tc.expectedMessage, condition.Message)
+			}
+		})
+	}
+}
+
+func TestServingCertExpiryControllerInvalidCert(t *testing.T) {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: ServingCertSecretName, Namespace: testNamespace},
+		Data:       map[string][]byte{corev1.TLSCertKey: []byte("not a cert")},
+	}
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	if err := indexer.Add(secret); err != nil {
+		t.Fatal(err)
+	}
+	c := &servingCertExpiryController{
+		namespaces:      []string{testNamespace},
+		expiryThreshold: DefaultExpiryThreshold,
+		operatorClient:  v1helpers.NewFakeOperatorClient(&operatorv1.OperatorSpec{}, &operatorv1.OperatorStatus{}, nil),
+		secretListers:   map[string]corelistersv1.SecretNamespaceLister{testNamespace: corelistersv1.NewSecretLister(indexer).Secrets(testNamespace)},
+		recorder:        events.NewInMemoryRecorder("", clock.RealClock{}),
+		now:             time.Now,
+	}
+
+	err := c.sync(context.TODO(), nil)
+	if err == nil || !strings.Contains(err.Error(), "no PEM encoded certificate") {
+		t.Errorf("expected an error about the missing certificate, got %v", err)
+	}
+}
+
+func TestServingCertExpiryControllerPaused(t *testing.T) {
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	// past the expiry threshold
+	secret := servingCertSecret(t, now.Add(-90*24*time.Hour), now.Add(5*24*time.Hour))
+	indexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	if err := indexer.Add(secret); err != nil {
+		t.Fatal(err)
+	}
+	operatorClient := v1helpers.NewFakeOperatorClientWithObjectMeta(
+		&metav1.ObjectMeta{Annotations: map[string]string{util.PauseReconcileAnnotation: "true"}},
+		&operatorv1.OperatorSpec{}, &operatorv1.OperatorStatus{}, nil)
+	c := &servingCertExpiryController{
+		namespaces:      []string{testNamespace},
+		expiryThreshold: DefaultExpiryThreshold,
+		operatorClient:  operatorClient,
+		secretListers:   map[string]corelistersv1.SecretNamespaceLister{testNamespace: corelistersv1.NewSecretLister(indexer).Secrets(testNamespace)},
+		recorder:        events.NewInMemoryRecorder("", clock.RealClock{}),
+		now:             func() time.Time { return now },
+	}
+
+	if err := c.sync(context.TODO(), nil); err != nil {
+		t.Fatal(err)
+	}
+	_, status, _, err := operatorClient.GetOperatorState()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if condition := v1helpers.FindOperatorCondition(status.Conditions, servingCertExpiringType); condition != nil {
+		t.Errorf("expected no %s condition while reconciliation is paused, got %v", servingCertExpiringType, condition)
+	}
+}
@@ -0,0 +1,52 @@
+package operator
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+// shutdownGracePeriod bounds how long the controllers get to finish their in-flight syncs once the
+// operator is asked to stop, e.g. on SIGTERM when its pod is evicted or upgraded. It is below the 10s
+// controllercmd gives the operator before it exits non-zero regardless.
+const shutdownGracePeriod = 8 * time.Second
+
+// runnable is a controller started with a number of workers, Run returns once they have all finished.
+type runnable interface {
+	Run(ctx context.Context, workers int)
+}
+
+// controllerRunner runs the controllers of the operator and keeps track of them, so that their in-flight
+// syncs are not cut off half way, e.g. with a half-written observed config, when the process exits.
+type controllerRunner struct {
+	wg sync.WaitGroup
+}
+
+// run starts controller with the workers until ctx is done.
+func (r *controllerRunner) run(ctx context.Context, controller runnable, workers int) {
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		controller.Run(ctx, workers)
+	}()
+}
+
+// wait waits for all the controllers to return, at most for gracePeriod.
+func (r *controllerRunner) wait(gracePeriod time.Duration) error {
+	drained := make(chan struct{})
+	go func() {
+		defer close(drained)
+		r.wg.Wait()
+	}()
+
+	select {
+	case <-drained:
+		klog.Infof("All controllers have finished their work")
+		return nil
+	case <-time.After(gracePeriod):
+		return fmt.Errorf("controllers did not finish their work within %v", gracePeriod)
+	}
+}
@@ -0,0 +1,98 @@
+package operator
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"k8s.io/utils/clock"
+
+	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/operator/events"
+)
+
+func TestControllerRunnerShutdown(t *testing.T) {
+	tests := []struct {
+		name      string
+		syncTime  time.Duration
+		expectErr bool
+	}{
+		{
+			name:     "in-flight sync finishes within the grace period",
+			syncTime: 200 * time.Millisecond,
+		},
+		{
+			name:      "in-flight sync outlasts the grace period",
+			syncTime:  3 * time.Second,
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM)
+			defer stop()
+
+			started := make(chan struct{}, 1)
+			var syncs, finished atomic.Int32
+			// the sync ignores the cancelled context like a sync half way through writing the observed config
+			controller := factory.New().
+				WithSync(func(_ context.Context, _ factory.SyncContext) error {
+					syncs.Add(1)
+					select {
+					case started <- struct{}{}:
+					default:
+					}
+					time.Sleep(tc.syncTime)
+					finished.Add(1)
+					return nil
+				}).
+				ResyncEvery(10*time.Millisecond).
+				ToController("test", events.NewInMemoryRecorder("test", clock.RealClock{}))
+
+			runner := &controllerRunner{}
+			runner.run(ctx, controller, 1)
+			select {
+			case <-started:
+			case <-time.After(5 * time.Second):
+				t.Fatal("the controller did not start syncing")
+			}
+
+			process, err := os.FindProcess(os.Getpid())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := process.Signal(syscall.SIGTERM); err != nil {
+				t.Fatal(err)
+			}
+			select {
+			case <-ctx.Done():
+			case <-time.After(time.Second):
+				t.Fatal("the context was not cancelled by the shutdown signal")
+			}
+
+			gracePeriod := time.Second
+			start := time.Now()
+			err = runner.wait(gracePeriod)
+			if elapsed := time.Since(start); elapsed > gracePeriod+500*time.Millisecond {
+				t.Errorf("expected the wait to be bounded by %v, took %v", gracePeriod, elapsed)
+			}
+			if tc.expectErr {
+				if err == nil {
+					t.Error("expected an error for the controllers not finishing in time")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if syncs.Load() != finished.Load() {
+				t.Errorf("expected every started sync to finish, %d of %d did", finished.Load(), syncs.Load())
+			}
+		})
+	}
+}
@@ -35,11 +35,15 @@ import (
 	"github.com/openshift/library-go/pkg/operator/v1helpers"
 
 	configobservationcontroller "github.com/openshift/cluster-openshift-controller-manager-operator/pkg/operator/configobservation/configobservercontroller"
+	"github.com/openshift/cluster-openshift-controller-manager-operator/pkg/operator/operandconfig"
+	"github.com/openshift/cluster-openshift-controller-manager-operator/pkg/operator/ownerreference"
+	"github.com/openshift/cluster-openshift-controller-manager-operator/pkg/operator/servingcert"
 	"github.com/openshift/cluster-openshift-controller-manager-operator/pkg/operator/usercaobservation"
 	"github.com/openshift/cluster-openshift-controller-manager-operator/pkg/util"
 )
 
-func RunOperator(ctx context.Context, controllerConfig *controllercmd.ControllerContext) error {
+// RunOperator starts the controllers of the operator with the options.
+func (o *Options) RunOperator(ctx context.Context, controllerConfig *controllercmd.ControllerContext) error {
 	// Increase QPS and burst to avoid client-side rate limits when reconciling RBAC API objects.
 	// See TODO below for the StaticResourceController
 	highRateLimitProtoKubeConfig := rest.CopyConfig(controllerConfig.ProtoKubeConfig)
@@ -74,8 +78,8 @@ func RunOperator(ctx context.Context, controllerConfig *controllercmd.Controller
 		util.InfraNamespace,
 		metav1.NamespaceSystem,
 	)
-	operatorConfigInformers := operatorinformers.NewSharedInformerFactory(operatorClient, 10*time.Minute)
-	configInformers := configinformers.NewSharedInformerFactory(configClient, 10*time.Minute)
+	operatorConfigInformers := operatorinformers.NewSharedInformerFactory(operatorClient, o.ResyncInterval)
+	configInformers := configinformers.NewSharedInformerFactory(configClient, o.ResyncInterval)
 
 	// OpenShiftControlllerManagerOperator reconciles the state of the openshift-controller-manager
 	// DaemonSet and associated ConfigMaps.
@@ -84,6 +88,7 @@ func RunOperator(ctx context.Context, controllerConfig *controllercmd.Controller
 		os.Getenv("ROUTE_CONTROLLER_MANAGER_IMAGE"),
 		operatorConfigInformers.Operator().V1().OpenShiftControllerManagers(),
 		configInformers.Config().V1().Proxies(),
+		configInformers.Config().V1().Infrastructures(),
 		kubeInformers,
 		operatorClient.OperatorV1(),
 		workloadcontroller.CountNodesFuncWrapper(kubeInformers.InformersFor("").Core().V1().Nodes().Lister()),
@@ -154,20 +159,29 @@ func RunOperator(ctx context.Context, controllerConfig *controllercmd.Controller
 	// them into configuration used by openshift-controller-manager
 	configObserver := configobservationcontroller.NewConfigObserver(
 		opClient,
+		operatorClient.OperatorV1(),
+		os.Getenv("RELEASE_VERSION"),
 		operatorConfigInformers,
 		configInformers,
 		kubeInformers.InformersFor(util.OperatorNamespace),
 		featureGateAccessor,
+		resourceSyncer,
 		controllerConfig.EventRecorder,
 		buildCapabilityEnabled,
 	)
 
 	// userCAObserver watches the cluster proxy config and updates the resourceSyncer.
+	// It also syncs the additional trusted CAs of the cluster image config.
 	userCAObserver := usercaobservation.NewController(
 		opClient,
 		configInformers,
+		kubeInformers.InformersFor(util.UserSpecifiedGlobalConfigNamespace),
+		kubeInformers.InformersFor(util.TargetNamespace),
+		kubeInformers.InformersFor(util.OperatorNamespace),
+		kubeClient.CoreV1(),
 		resourceSyncer,
 		controllerConfig.EventRecorder,
+		o.ResyncInterval,
 	)
 
 	versionGetter := &versionGetter{
@@ -176,17 +190,12 @@ func RunOperator(ctx context.Context, controllerConfig *controllercmd.Controller
 	}
 
 	// ClusterOperatorStatusController aggregates the conditions in our openshiftcontrollermanager
-	// object to the corresponding ClusterOperator object.
+	// object to the corresponding ClusterOperator object. Every controller and observer owns a *Degraded
+	// condition type of its own, Degraded is their union: True while any of them is, with the reasons of
+	// all of them and a message line per line of their messages, so no writer overwrites another.
 	clusterOperatorStatus := status.NewClusterOperatorStatusController(
 		util.ClusterOperatorName,
-		[]configv1.ObjectReference{
-			{Group: "operator.openshift.io", Resource: "openshiftcontrollermanagers", Name: "cluster"},
-			{Resource: "namespaces", Name: util.UserSpecifiedGlobalConfigNamespace},
-			{Resource: "namespaces", Name: util.MachineSpecifiedGlobalConfigNamespace},
-			{Resource: "namespaces", Name: util.OperatorNamespace},
-			{Resource: "namespaces", Name: util.TargetNamespace},
-			{Resource: "namespaces", Name: util.RouteControllerTargetNamespace},
-		},
+		relatedObjects(),
 		configClient.ConfigV1(),
 		configInformers.Config().V1().ClusterOperators(),
 		opClient,
@@ -201,19 +210,17 @@ func RunOperator(ctx context.Context, controllerConfig *controllercmd.Controller
 	staticResourceController := staticresourcecontroller.NewStaticResourceController(
 		"OpenshiftControllerManagerStaticResources",
 		bindata.Asset,
-		[]string{
+		append(append([]string{}, operandNamespaceAssets...),
 			"assets/openshift-controller-manager/informer-clusterrole.yaml",
 			"assets/openshift-controller-manager/informer-clusterrolebinding.yaml",
 			"assets/openshift-controller-manager/tokenreview-clusterrole.yaml",
 			"assets/openshift-controller-manager/tokenreview-clusterrolebinding.yaml",
 			"assets/openshift-controller-manager/leader-role.yaml",
 			"assets/openshift-controller-manager/leader-rolebinding.yaml",
-			"assets/openshift-controller-manager/ns.yaml",
 			"assets/openshift-controller-manager/route-controller-manager-clusterrole.yaml",
 			"assets/openshift-controller-manager/route-controller-manager-clusterrolebinding.yaml",
 			"assets/openshift-controller-manager/route-controller-manager-leader-role.yaml",
 			"assets/openshift-controller-manager/route-controller-manager-leader-rolebinding.yaml",
-			"assets/openshift-controller-manager/route-controller-manager-ns.yaml",
 			"assets/openshift-controller-manager/route-controller-manager-sa.yaml",
 			"assets/openshift-controller-manager/route-controller-manager-separate-sa-role.yaml",
 			"assets/openshift-controller-manager/route-controller-manager-separate-sa-rolebinding.yaml",
@@ -237,7 +244,7 @@ func RunOperator(ctx context.Context, controllerConfig *controllercmd.Controller
 			"assets/openshift-controller-manager/deployer-clusterrolebinding.yaml",
 			"assets/openshift-controller-manager/image-trigger-controller-clusterrole.yaml",
 			"assets/openshift-controller-manager/image-trigger-controller-clusterrolebinding.yaml",
-		},
+		),
 		resourceapply.NewKubeClientHolder(kubeClient),
 		opClient,
 		controllerConfig.EventRecorder,
@@ -265,20 +272,56 @@ func RunOperator(ctx context.Context, controllerConfig *controllercmd.Controller
 		controllerConfig.EventRecorder,
 	)
 
+	// operandConfigRejection degrades the operator when an operand crash loops because it rejects its config.
+	operandConfigRejection := operandconfig.NewConfigRejectionController(
+		[]string{util.TargetNamespace, util.RouteControllerTargetNamespace},
+		opClient,
+		kubeInformers,
+		controllerConfig.EventRecorder,
+	)
+
+	// servingCertExpiry reports the serving certs of the operands approaching their expiry.
+	servingCertExpiry := servingcert.NewServingCertExpiryController(
+		[]string{util.TargetNamespace, util.RouteControllerTargetNamespace},
+		servingcert.DefaultExpiryThreshold,
+		opClient,
+		kubeInformers,
+		controllerConfig.EventRecorder,
+		o.ResyncInterval,
+	)
+
+	// ownerReferenceRepair keeps the config ConfigMaps of the operands pointing back to the operator config.
+	ownerReferenceRepair := ownerreference.NewOwnerReferenceController(
+		ownerreference.OperandObjects(),
+		kubeClient,
+		operatorConfigInformers.Operator().V1().OpenShiftControllerManagers(),
+		kubeInformers,
+		controllerConfig.EventRecorder,
+		o.ResyncInterval,
+	)
+
 	ensureDaemonSetCleanup(ctx, kubeClient, controllerConfig.EventRecorder)
 
 	operatorConfigInformers.Start(ctx.Done())
 	kubeInformers.Start(ctx.Done())
 	configInformers.Start(ctx.Done())
 
-	go staticResourceController.Run(ctx, 1)
-	go operator.Run(ctx, 1)
-	go resourceSyncer.Run(ctx, 1)
-	go configObserver.Run(ctx, 1)
-	go userCAObserver.Run(ctx, 1)
-	go clusterOperatorStatus.Run(ctx, 1)
-	go logLevelController.Run(ctx, 1)
-	go imagePullSecretCleanupController.Run(ctx, 1)
+	runner := &controllerRunner{}
+	runner.run(ctx, staticResourceController, 1)
+	runner.run(ctx, operator, 1)
+	runner.run(ctx, resourceSyncer, 1)
+	runner.run(ctx, configObserver, 1)
+	runner.run(ctx, userCAObserver, 1)
+	runner.run(ctx, clusterOperatorStatus, 1)
+	runner.run(ctx, logLevelController, 1)
+	runner.run(ctx, imagePullSecretCleanupController, 1)
+	runner.run(ctx, operandConfigRejection, 1)
+	runner.run(ctx, servingCertExpiry, 1)
+	runner.run(ctx, ownerReferenceRepair, 1)
+
+	if o.SelfTest {
+		go runSelfTest(ctx, operatorClient.OperatorV1(), kubeClient.CoreV1(), controllerConfig.EventRecorder)
+	}
 
 	capabilityChangedCh := make(chan struct{})
 	if !buildCapabilityEnabled {
@@ -311,7 +354,9 @@ func RunOperator(ctx context.Context, controllerConfig *controllercmd.Controller
 	case <-capabilityChangedCh:
 		return fmt.Errorf("capability is enabled, stopping")
 	case <-ctx.Done():
-		return fmt.Errorf("stopped")
+		// the controllers take no new work once ctx is done, let their in-flight syncs finish before exiting
+		klog.Infof("Shutting down, waiting up to %v for the controllers to finish their work", shutdownGracePeriod)
+		return runner.wait(shutdownGracePeriod)
 	}
 }
 
@@ -8,6 +8,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/openshift/cluster-openshift-controller-manager-operator/bindata"
 	appsv1 "k8s.io/api/apps/v1"
@@ -34,11 +35,20 @@ import (
 	"github.com/openshift/library-go/pkg/operator/resource/resourceread"
 	"github.com/openshift/library-go/pkg/operator/v1helpers"
 
+	"github.com/openshift/cluster-openshift-controller-manager-operator/pkg/operator/logging"
 	"github.com/openshift/cluster-openshift-controller-manager-operator/pkg/util"
 )
 
 const rcmConditionTypePrefix = "RouteControllerManager"
 
+const (
+	// availableSettleWindow is how long an operand must have had an available replica before it is
+	// reported Available. During bootstrap the first pod turns ready before it has served a request,
+	// reporting Available right away makes the CVO move on while the operand is not serving yet.
+	availableSettleWindow = 30 * time.Second
+	operandReadyType      = "OperandReady"
+)
+
 // ControllerCapabilities maps controllers to capabilities, so we can enable/disable controllers
 // based on capabilities.
 var controllerCapabilities = map[controlplanev1.OpenShiftControllerName]configv1.ClusterVersionCapability{
@@ -67,6 +77,10 @@ func syncOpenShiftControllerManager_v311_00_to_latest(
 		err                  error
 	)
 	operatorConfig := originalOperatorConfig.DeepCopy()
+	v1helpers.SetOperatorCondition(&operatorConfig.Status.Conditions, operatorapiv1.OperatorCondition{
+		Type:   reconciliationPausedConditionType,
+		Status: operatorapiv1.ConditionFalse,
+	})
 
 	operandName := "openshift-controller-manager"
 	rcOperandName := "route-controller-manager"
@@ -132,11 +146,23 @@ func syncOpenShiftControllerManager_v311_00_to_latest(
 		rcSpecAnnotations["configmaps/client-ca"] = resourceVersion
 	}
 
+	// the replicas follow the control plane nodes, but not below the floor of the control plane topology
+	ocmReplicaFloor, err := newReplicaFloor(c.infrastructureLister)
+	if err != nil {
+		ocmErrors = append(ocmErrors, fmt.Errorf("%q %q: %v", operandName, "infrastructure", err))
+		rcmErrors = append(rcmErrors, fmt.Errorf("%q %q: %v", rcOperandName, "infrastructure", err))
+	}
+	rcmReplicaFloor := ocmReplicaFloor
+	applyReplicaOverrides(operatorConfig, map[string]*replicaFloor{
+		"controller-manager":       &ocmReplicaFloor,
+		"route-controller-manager": &rcmReplicaFloor,
+	})
+
 	// our configmaps and secrets are in order, now it is time to create the Deployment
-	actualDeployment, _, openshiftControllerManagerError := manageOpenShiftControllerManagerDeployment_v311_00_to_latest(
+	actualDeployment, ocmModified, openshiftControllerManagerError := manageOpenShiftControllerManagerDeployment_v311_00_to_latest(
 		bindata.MustAsset,
 		c.kubeClient.AppsV1(),
-		countNodes,
+		ocmReplicaFloor.countReplicas(countNodes),
 		ensureAtMostOnePodPerNodeFn,
 		c.recorder,
 		operatorConfig,
@@ -149,9 +175,9 @@ func syncOpenShiftControllerManager_v311_00_to_latest(
 		ocmErrors = append(ocmErrors, fmt.Errorf("%q %q: %v", operandName, "deployment", openshiftControllerManagerError))
 	}
 
-	actualRCDeployment, _, routerControllerManagerError := manageRouteControllerManagerDeployment_v311_00_to_latest(
+	actualRCDeployment, rcmModified, routerControllerManagerError := manageRouteControllerManagerDeployment_v311_00_to_latest(
 		c.kubeClient.AppsV1(),
-		countNodes,
+		rcmReplicaFloor.countReplicas(countNodes),
 		ensureAtMostOnePodPerNodeFn,
 		c.recorder,
 		operatorConfig,
@@ -162,20 +188,45 @@ func syncOpenShiftControllerManager_v311_00_to_latest(
 	if routerControllerManagerError != nil {
 		rcmErrors = append(rcmErrors, fmt.Errorf("%q %q: %v", rcOperandName, "deployment", routerControllerManagerError))
 	}
+	ocmReplicaFloor.reportEnforced(c.recorder, actualDeployment, ocmModified)
+	rcmReplicaFloor.reportEnforced(c.recorder, actualRCDeployment, rcmModified)
 
 	// library-go func called by manageOpenShiftControllerManagerDeployment_v311_00_to_latest can return nil with errors
 	if openshiftControllerManagerError != nil || routerControllerManagerError != nil {
 		return syncReturn(c, ocmErrors, rcmErrors, originalOperatorConfig, operatorConfig)
 	}
 
-	// manage status
-	// first we need to update operator config status version based on the OCM deployment
-	if d := actualDeployment; d.Status.AvailableReplicas > 0 && d.Status.UpdatedReplicas == d.Status.Replicas && len(d.Annotations[util.VersionAnnotation]) > 0 {
-		operatorConfig.Status.Version = d.Annotations[util.VersionAnnotation]
+	// failing to tell why a rollout started is not worth degrading, it is retried on the next sync
+	if configMap != nil {
+		if err := trackRolloutInputs(c.kubeClient.AppsV1(), c.recorder, actualDeployment, configMap, specAnnotations); err != nil {
+			logging.WithObject(c.logger, actualDeployment).Error(err, "Failed to record the rollout inputs")
+		}
+	}
+	if rcConfigMap != nil {
+		if err := trackRolloutInputs(c.kubeClient.AppsV1(), c.recorder, actualRCDeployment, rcConfigMap, rcSpecAnnotations); err != nil {
+			logging.WithObject(c.logger, actualRCDeployment).Error(err, "Failed to record the rollout inputs")
+		}
 	}
 
-	setControllerManagerStatusConditions(operatorConfig, actualDeployment, "openshift controller manager", "")
-	setControllerManagerStatusConditions(operatorConfig, actualRCDeployment, "route controller manager", rcmConditionTypePrefix)
+	// manage status
+	// first we need to update operator config status version based on the operand deployments
+	setOperandVersion(operatorConfig, actualDeployment, actualRCDeployment)
+
+	now := time.Now()
+	ocmSettling := setControllerManagerStatusConditions(operatorConfig, actualDeployment, "openshift controller manager", now, "")
+	rcmSettling := setControllerManagerStatusConditions(operatorConfig, actualRCDeployment, "route controller manager", now, rcmConditionTypePrefix)
+	ocmUnavailableGrace := setUnavailableDegradedCondition(operatorConfig, actualDeployment, now, "")
+	rcmUnavailableGrace := setUnavailableDegradedCondition(operatorConfig, actualRCDeployment, now, rcmConditionTypePrefix)
+	// nothing else requeues the sync once the settling or unavailable deployments stopped changing
+	for _, after := range []time.Duration{ocmSettling, rcmSettling, ocmUnavailableGrace, rcmUnavailableGrace} {
+		if after > 0 {
+			c.queue.AddAfter(workQueueKey, after)
+		}
+	}
+	setRolloutDegradedCondition(operatorConfig, actualDeployment, c.podListers[actualDeployment.Namespace], now, "")
+	setRolloutDegradedCondition(operatorConfig, actualRCDeployment, c.podListers[actualRCDeployment.Namespace], now, rcmConditionTypePrefix)
+	setImagePullDegradedCondition(operatorConfig, actualDeployment, c.podListers[actualDeployment.Namespace], "")
+	setImagePullDegradedCondition(operatorConfig, actualRCDeployment, c.podListers[actualRCDeployment.Namespace], rcmConditionTypePrefix)
 
 	v1helpers.SetOperatorCondition(&operatorConfig.Status.Conditions, operatorapiv1.OperatorCondition{
 		Type:   operatorapiv1.OperatorStatusTypeUpgradeable,
@@ -191,22 +242,47 @@ func syncOpenShiftControllerManager_v311_00_to_latest(
 
 // setControllerManagerStatusConditions sets operator status conditions for an operand.
 // Available and Progressing are being handled here, Degraded is handled in syncReturn.
+// An operand that is not Available yet becomes Available once its deployment has had an available
+// replica for availableSettleWindow, the returned duration is how long it is still settling.
 //
 // Make sure operatorConfig.Status.Version is set properly before calling this helper function.
 func setControllerManagerStatusConditions(
 	operatorConfig *operatorapiv1.OpenShiftControllerManager,
 	deployment *appsv1.Deployment,
 	operandReadableName string,
+	now time.Time,
 	conditionTypePrefix string,
-) {
+) time.Duration {
 	available := deployment.Status.AvailableReplicas > 0
 
-	// Available
+	// the transition time of OperandReady tells since when the deployment has had an available replica
+	readyStatus := operatorapiv1.ConditionFalse
 	if available {
+		readyStatus = operatorapiv1.ConditionTrue
+	}
+	v1helpers.SetOperatorCondition(&operatorConfig.Status.Conditions, operatorapiv1.OperatorCondition{
+		Type:   conditionTypePrefix + operandReadyType,
+		Status: readyStatus,
+	})
+
+	// Available
+	var settling time.Duration
+	if available && !v1helpers.IsOperatorConditionTrue(operatorConfig.Status.Conditions, conditionTypePrefix+operatorapiv1.OperatorStatusTypeAvailable) {
+		ready := v1helpers.FindOperatorCondition(operatorConfig.Status.Conditions, conditionTypePrefix+operandReadyType)
+		settling = availableSettleWindow - now.Sub(ready.LastTransitionTime.Time)
+	}
+	if available && settling <= 0 {
 		v1helpers.SetOperatorCondition(&operatorConfig.Status.Conditions, operatorapiv1.OperatorCondition{
 			Type:   conditionTypePrefix + operatorapiv1.OperatorStatusTypeAvailable,
 			Status: operatorapiv1.ConditionTrue,
 		})
+	} else if available {
+		v1helpers.SetOperatorCondition(&operatorConfig.Status.Conditions, operatorapiv1.OperatorCondition{
+			Type:    conditionTypePrefix + operatorapiv1.OperatorStatusTypeAvailable,
+			Status:  operatorapiv1.ConditionFalse,
+			Reason:  "OperandSettling",
+			Message: fmt.Sprintf("%s deployment pods have been available for less than %s", operandReadableName, availableSettleWindow),
+		})
 	} else {
 		v1helpers.SetOperatorCondition(&operatorConfig.Status.Conditions, operatorapiv1.OperatorCondition{
 			Type:    conditionTypePrefix + operatorapiv1.OperatorStatusTypeAvailable,
@@ -266,6 +342,8 @@ func setControllerManagerStatusConditions(
 			Message: strings.Join(progressingMessages, "\n"),
 		})
 	}
+
+	return max(settling, 0)
 }
 
 // syncReturn checks the error slices and sets Degraded conditions in the operator config before returning.
@@ -283,15 +361,16 @@ func syncReturn(
 			return
 		}
 
-		var msg strings.Builder
+		// the ClusterOperator status controller prefixes every line of the message with the condition
+		// type in the aggregated Degraded message, a trailing newline would add an empty line to it
+		messages := make([]string, 0, len(errs))
 		for _, err := range errs {
-			msg.WriteString(err.Error())
-			msg.WriteString("\n")
+			messages = append(messages, err.Error())
 		}
 		v1helpers.SetOperatorCondition(&operatorConfig.Status.Conditions, operatorapiv1.OperatorCondition{
 			Type:    conditionType,
 			Status:  operatorapiv1.ConditionTrue,
-			Message: msg.String(),
+			Message: strings.Join(messages, "\n"),
 			Reason:  "SyncError",
 		})
 	}
@@ -359,7 +438,11 @@ func manageOpenShiftControllerManagerConfigMap_v311_00_to_latest(clusterVersionL
 	if err != nil {
 		return nil, false, err
 	}
-	requiredConfigMap, _, err := resourcemerge.MergeConfigMap(configMap, "config.yaml", nil, ocmDefaultConfig, bytes, operatorConfig.Spec.ObservedConfig.Raw, operatorConfig.Spec.UnsupportedConfigOverrides.Raw)
+	unsupportedConfigOverrides, err := withoutOperandDeploymentOverrides(operatorConfig.Spec.UnsupportedConfigOverrides.Raw)
+	if err != nil {
+		return nil, false, err
+	}
+	requiredConfigMap, _, err := resourcemerge.MergeConfigMap(configMap, "config.yaml", nil, ocmDefaultConfig, bytes, operatorConfig.Spec.ObservedConfig.Raw, unsupportedConfigOverrides)
 	if err != nil {
 		return nil, false, err
 	}
@@ -379,6 +462,9 @@ func manageOpenShiftControllerManagerConfigMap_v311_00_to_latest(clusterVersionL
 	for k, v := range inputHashes {
 		requiredConfigMap.Data[k] = v
 	}
+	if err := keepEquivalentConfig(client, requiredConfigMap, "config.yaml"); err != nil {
+		return nil, false, err
+	}
 
 	return resourceapply.ApplyConfigMap(context.Background(), client, recorder, requiredConfigMap)
 }
@@ -387,7 +473,11 @@ func manageOpenShiftControllerManagerConfigMap_v311_00_to_latest(clusterVersionL
 func manageRouteControllerManagerConfigMap_v311_00_to_latest(kubeClient kubernetes.Interface, client coreclientv1.ConfigMapsGetter, recorder events.Recorder, operatorConfig *operatorapiv1.OpenShiftControllerManager) (*corev1.ConfigMap, bool, error) {
 	configMap := resourceread.ReadConfigMapV1OrDie(bindata.MustAsset("assets/openshift-controller-manager/route-controller-manager-cm.yaml"))
 	rcmDefaultConfig := bindata.MustAsset("assets/config/route-controller-manager-defaultconfig.yaml")
-	requiredConfigMap, _, err := resourcemerge.MergeConfigMap(configMap, "config.yaml", nil, rcmDefaultConfig, operatorConfig.Spec.ObservedConfig.Raw, operatorConfig.Spec.UnsupportedConfigOverrides.Raw)
+	unsupportedConfigOverrides, err := withoutOperandDeploymentOverrides(operatorConfig.Spec.UnsupportedConfigOverrides.Raw)
+	if err != nil {
+		return nil, false, err
+	}
+	requiredConfigMap, _, err := resourcemerge.MergeConfigMap(configMap, "config.yaml", nil, rcmDefaultConfig, operatorConfig.Spec.ObservedConfig.Raw, unsupportedConfigOverrides)
 	if err != nil {
 		return nil, false, err
 	}
@@ -405,6 +495,9 @@ func manageRouteControllerManagerConfigMap_v311_00_to_latest(kubeClient kubernet
 	for k, v := range inputHashes {
 		requiredConfigMap.Data[k] = v
 	}
+	if err := keepEquivalentConfig(client, requiredConfigMap, "config.yaml"); err != nil {
+		return nil, false, err
+	}
 
 	return resourceapply.ApplyConfigMap(context.Background(), client, recorder, requiredConfigMap)
 }
@@ -488,6 +581,8 @@ func manageOpenShiftControllerManagerDeployment_v311_00_to_latest(
 	required.Annotations[util.VersionAnnotation] = os.Getenv("RELEASE_VERSION")
 	resourcemerge.MergeMap(resourcemerge.BoolPtr(false), &required.Spec.Template.Annotations, specAnnotations)
 
+	ensureControlPlaneScheduling(&required.Spec.Template.Spec)
+
 	// Set the replica count to the number of master nodes.
 	masterNodeCount, err := countNodes(required.Spec.Template.Spec.NodeSelector)
 	if err != nil {
@@ -500,6 +595,16 @@ func manageOpenShiftControllerManagerDeployment_v311_00_to_latest(
 		return nil, false, fmt.Errorf("unable to ensure at most one pod per node: %v", err)
 	}
 
+	resourceOverrides, err := operandResourceOverrides(options.Spec.UnsupportedConfigOverrides.Raw)
+	if err != nil {
+		return nil, false, err
+	}
+	// invalid overrides must not roll out, the existing deployment is kept
+	if err := applyResourceOverrides(&required.Spec.Template.Spec, resourceOverrides); err != nil {
+		return nil, false, err
+	}
+	ensureGracefulRollout(&required.Spec, "controller-manager")
+
 	proxyCfg, err := proxyLister.Get("cluster")
 	if err != nil {
 		recorder.Eventf("ProxyConfigGetFailed", "Error retrieving global proxy config: %s", err.Error())
@@ -534,7 +639,7 @@ func manageOpenShiftControllerManagerDeployment_v311_00_to_latest(
 		}
 	}
 
-	return resourceapply.ApplyDeployment(context.Background(), client, recorder, required, resourcemerge.ExpectedDeploymentGeneration(required, generationStatus))
+	return applyDeploymentRevertingDrift(client, recorder, required, generationStatus)
 }
 
 func manageRouteControllerManagerDeployment_v311_00_to_latest(
@@ -571,6 +676,8 @@ func manageRouteControllerManagerDeployment_v311_00_to_latest(
 	required.Annotations[util.VersionAnnotation] = os.Getenv("RELEASE_VERSION")
 	resourcemerge.MergeMap(resourcemerge.BoolPtr(false), &required.Spec.Template.Annotations, specAnnotations)
 
+	ensureControlPlaneScheduling(&required.Spec.Template.Spec)
+
 	// Set the replica count to the number of master nodes.
 	masterNodeCount, err := countNodes(required.Spec.Template.Spec.NodeSelector)
 	if err != nil {
@@ -583,5 +690,15 @@ func manageRouteControllerManagerDeployment_v311_00_to_latest(
 		return nil, false, fmt.Errorf("unable to ensure at most one pod per node: %v", err)
 	}
 
-	return resourceapply.ApplyDeployment(context.Background(), client, recorder, required, resourcemerge.ExpectedDeploymentGeneration(required, generationStatus))
+	resourceOverrides, err := operandResourceOverrides(options.Spec.UnsupportedConfigOverrides.Raw)
+	if err != nil {
+		return nil, false, err
+	}
+	// invalid overrides must not roll out, the existing deployment is kept
+	if err := applyResourceOverrides(&required.Spec.Template.Spec, resourceOverrides); err != nil {
+		return nil, false, err
+	}
+	ensureGracefulRollout(&required.Spec, "route-controller-manager")
+
+	return applyDeploymentRevertingDrift(client, recorder, required, generationStatus)
 }
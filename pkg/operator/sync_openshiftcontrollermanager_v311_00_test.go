@@ -10,6 +10,7 @@ import (
 	"sort"
 	"strconv"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 
@@ -33,6 +34,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/util/workqueue"
 	"k8s.io/utils/clock"
 
 	operatorv1 "github.com/openshift/api/operator/v1"
@@ -288,7 +290,7 @@ func TestControllerDisabling(t *testing.T) {
 }
 
 func TestAvailableCondition(t *testing.T) {
-	prepareTestCases := func(deployNamespace, deployName, operandReadableName string) []conditionTestCase {
+	prepareTestCases := func(deployNamespace, deployName, operandReadableName, conditionTypePrefix string) []conditionTestCase {
 		replicas := int32(3)
 
 		newDeployment := func(availableReplicas int32) *appsv1.Deployment {
@@ -317,9 +319,62 @@ func TestAvailableCondition(t *testing.T) {
 				deployment:               newDeployment(replicas),
 				configGeneration:         100,
 				configObservedGeneration: 100,
-				expectedStatus:           operatorv1.ConditionTrue,
+				conditions: []operatorv1.OperatorCondition{
+					{Type: conditionTypePrefix + "Available", Status: operatorv1.ConditionTrue},
+				},
+				expectedStatus: operatorv1.ConditionTrue,
+				version:        "v1",
+			},
+			{
+				name:                     "BecameReady",
+				deployment:               newDeployment(1),
+				configGeneration:         100,
+				configObservedGeneration: 100,
+				expectedStatus:           operatorv1.ConditionFalse,
+				expectedReason:           "OperandSettling",
+				expectedMessage:          fmt.Sprintf("%s deployment pods have been available for less than 30s", operandReadableName),
 				version:                  "v1",
 			},
+			{
+				name:                     "ReadyWithinSettleWindow",
+				deployment:               newDeployment(1),
+				configGeneration:         100,
+				configObservedGeneration: 100,
+				conditions: []operatorv1.OperatorCondition{
+					{Type: conditionTypePrefix + "OperandReady", Status: operatorv1.ConditionTrue, LastTransitionTime: metav1.NewTime(time.Now().Add(-10 * time.Second))},
+					{Type: conditionTypePrefix + "Available", Status: operatorv1.ConditionFalse, Reason: "OperandSettling"},
+				},
+				expectedStatus:  operatorv1.ConditionFalse,
+				expectedReason:  "OperandSettling",
+				expectedMessage: fmt.Sprintf("%s deployment pods have been available for less than 30s", operandReadableName),
+				version:         "v1",
+			},
+			{
+				name:                     "ReadyForSettleWindow",
+				deployment:               newDeployment(1),
+				configGeneration:         100,
+				configObservedGeneration: 100,
+				conditions: []operatorv1.OperatorCondition{
+					{Type: conditionTypePrefix + "OperandReady", Status: operatorv1.ConditionTrue, LastTransitionTime: metav1.NewTime(time.Now().Add(-time.Minute))},
+					{Type: conditionTypePrefix + "Available", Status: operatorv1.ConditionFalse, Reason: "OperandSettling"},
+				},
+				expectedStatus: operatorv1.ConditionTrue,
+				version:        "v1",
+			},
+			{
+				name:                     "BecameUnavailableAfterSettling",
+				deployment:               newDeployment(0),
+				configGeneration:         100,
+				configObservedGeneration: 100,
+				conditions: []operatorv1.OperatorCondition{
+					{Type: conditionTypePrefix + "OperandReady", Status: operatorv1.ConditionTrue, LastTransitionTime: metav1.NewTime(time.Now().Add(-time.Minute))},
+					{Type: conditionTypePrefix + "Available", Status: operatorv1.ConditionTrue},
+				},
+				expectedStatus:  operatorv1.ConditionFalse,
+				expectedReason:  "NoPodsAvailable",
+				expectedMessage: fmt.Sprintf("no %s deployment pods available on any node", operandReadableName),
+				version:         "v1",
+			},
 			{
 				name:                     "DeploymentMissing",
 				deployment:               nil,
@@ -345,15 +400,48 @@ func TestAvailableCondition(t *testing.T) {
 
 	t.Run("OpenShiftControllerManager", func(t *testing.T) {
 		testControllerManagerCondition(t, "Available", prepareTestCases(
-			"openshift-controller-manager", "controller-manager", "openshift controller manager"))
+			"openshift-controller-manager", "controller-manager", "openshift controller manager", ""))
 	})
 
 	t.Run("RouteControllerManager", func(t *testing.T) {
 		testControllerManagerCondition(t, "RouteControllerManagerAvailable", prepareTestCases(
-			"openshift-route-controller-manager", "route-controller-manager", "route controller manager"))
+			"openshift-route-controller-manager", "route-controller-manager", "route controller manager", "RouteControllerManager"))
 	})
 }
 
+func TestAvailableAfterSettleWindow(t *testing.T) {
+	replicas := int32(1)
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "controller-manager", Namespace: "openshift-controller-manager"},
+		Spec:       appsv1.DeploymentSpec{Replicas: &replicas},
+	}
+	operatorConfig := &operatorv1.OpenShiftControllerManager{ObjectMeta: metav1.ObjectMeta{Name: "cluster"}}
+	start := time.Now()
+
+	for _, step := range []struct {
+		elapsed           time.Duration
+		availableReplicas int32
+		expectedAvailable operatorv1.ConditionStatus
+	}{
+		{elapsed: 0, availableReplicas: 0, expectedAvailable: operatorv1.ConditionFalse},
+		{elapsed: 5 * time.Second, availableReplicas: 1, expectedAvailable: operatorv1.ConditionFalse},
+		{elapsed: 20 * time.Second, availableReplicas: 1, expectedAvailable: operatorv1.ConditionFalse},
+		{elapsed: 40 * time.Second, availableReplicas: 1, expectedAvailable: operatorv1.ConditionTrue},
+		{elapsed: time.Minute, availableReplicas: 1, expectedAvailable: operatorv1.ConditionTrue},
+	} {
+		deployment.Status.AvailableReplicas = step.availableReplicas
+		settling := setControllerManagerStatusConditions(operatorConfig, deployment, "openshift controller manager", start.Add(step.elapsed), "")
+
+		available := operatorv1helpers.FindOperatorCondition(operatorConfig.Status.Conditions, operatorv1.OperatorStatusTypeAvailable)
+		if available.Status != step.expectedAvailable {
+			t.Errorf("after %s: expected Available %s, got %s: %s", step.elapsed, step.expectedAvailable, available.Status, available.Message)
+		}
+		if ready := step.availableReplicas > 0 && available.Status != operatorv1.ConditionTrue; ready != (settling > 0) {
+			t.Errorf("after %s: expected to be settling %v, got a settle time of %s", step.elapsed, ready, settling)
+		}
+	}
+}
+
 func TestProgressingCondition(t *testing.T) {
 	prepareTestCases := func(deployNamespace, deployName string) []conditionTestCase {
 		replicas := int32(3)
@@ -591,7 +679,7 @@ func TestDegradedCondition(t *testing.T) {
 				nodeCountError:           errors.New("node count error"),
 				expectedStatus:           operatorv1.ConditionTrue,
 				expectedReason:           "SyncError",
-				expectedMessage:          fmt.Sprintf("%q %q: failed to determine number of master nodes: node count error\n", operandName, "deployment"),
+				expectedMessage:          fmt.Sprintf("%q %q: failed to determine number of master nodes: node count error", operandName, "deployment"),
 				version:                  "v1",
 			},
 		}
@@ -614,6 +702,7 @@ type conditionTestCase struct {
 	configGeneration         int64
 	configObservedGeneration int64
 	nodeCountError           error
+	conditions               []operatorv1.OperatorCondition
 	expectedStatus           operatorv1.ConditionStatus
 	expectedReason           string
 	expectedMessage          string
@@ -655,6 +744,7 @@ func testControllerManagerCondition(t *testing.T, conditionType string, testCase
 				Status: operatorv1.OpenShiftControllerManagerStatus{
 					OperatorStatus: operatorv1.OperatorStatus{
 						ObservedGeneration: tc.configObservedGeneration,
+						Conditions:         tc.conditions,
 					},
 				},
 			}
@@ -672,8 +762,12 @@ func testControllerManagerCondition(t *testing.T, conditionType string, testCase
 				proxyLister:          proxyLister,
 				recorder:             events.NewInMemoryRecorder("", clock.RealClock{}),
 				operatorConfigClient: controllerManagerOperatorClient.OperatorV1(),
+				podListers:           operandPodListers(),
 				clusterVersionLister: configlistersv1.NewClusterVersionLister(indexer),
+				infrastructureLister: configlistersv1.NewInfrastructureLister(cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})),
+				queue:                workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter()),
 			}
+			defer operator.queue.ShutDown()
 
 			countNodes := func(nodeSelector map[string]string) (*int32, error) {
 				result := int32(3)
@@ -0,0 +1,62 @@
+package operator
+
+import (
+	"fmt"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+
+	operatorapiv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+)
+
+const (
+	// unavailableGracePeriod is how long the deployment of an operand may have no available replica before
+	// it degrades the operator. A rollout of a single replica, or of the control plane nodes draining one
+	// by one, briefly has none.
+	unavailableGracePeriod  = 2 * time.Minute
+	unavailableDegradedType = "OperandUnavailableDegraded"
+	noPodsAvailableReason   = "NoPodsAvailable"
+)
+
+// setUnavailableDegradedCondition degrades the operand once its deployment has had no available replica for
+// unavailableGracePeriod, so that a brief unavailability does not make Degraded flap. The returned duration
+// is how long the grace period still lasts, nothing else requeues the sync once the deployment stopped
+// changing.
+//
+// This is a new reason for the operator to be Degraded: an operand without an available replica used to
+// only be reported by Available=False with the NoPodsAvailable reason, which is still set right away. The
+// OperandUnavailableDegraded condition is aggregated into the Degraded condition of the cluster operator
+// like the other *Degraded conditions.
+//
+// Make sure the OperandReady condition is set by setControllerManagerStatusConditions before calling this.
+func setUnavailableDegradedCondition(
+	operatorConfig *operatorapiv1.OpenShiftControllerManager,
+	deployment *appsv1.Deployment,
+	now time.Time,
+	conditionTypePrefix string,
+) time.Duration {
+	conditionType := conditionTypePrefix + unavailableDegradedType
+	ready := v1helpers.FindOperatorCondition(operatorConfig.Status.Conditions, conditionTypePrefix+operandReadyType)
+
+	var remaining time.Duration
+	if ready != nil && ready.Status == operatorapiv1.ConditionFalse {
+		remaining = unavailableGracePeriod - now.Sub(ready.LastTransitionTime.Time)
+	}
+	if ready == nil || ready.Status != operatorapiv1.ConditionFalse || remaining > 0 {
+		v1helpers.SetOperatorCondition(&operatorConfig.Status.Conditions, operatorapiv1.OperatorCondition{
+			Type:   conditionType,
+			Status: operatorapiv1.ConditionFalse,
+		})
+		return max(remaining, 0)
+	}
+
+	v1helpers.SetOperatorCondition(&operatorConfig.Status.Conditions, operatorapiv1.OperatorCondition{
+		Type:   conditionType,
+		Status: operatorapiv1.ConditionTrue,
+		Reason: noPodsAvailableReason,
+		Message: fmt.Sprintf("deployment/%s -n %s: no replica has been available for more than %s, %d of %d replicas updated",
+			deployment.Name, deployment.Namespace, unavailableGracePeriod, deployment.Status.UpdatedReplicas, desiredReplicas(deployment)),
+	})
+	return 0
+}
@@ -0,0 +1,96 @@
+package operator
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+)
+
+func TestSetUnavailableDegradedCondition(t *testing.T) {
+	start := time.Now()
+	deployment := func(available int32) *appsv1.Deployment {
+		return &appsv1.Deployment{
+			ObjectMeta: metav1.ObjectMeta{Name: "controller-manager", Namespace: "openshift-controller-manager", Generation: 2},
+			Spec:       appsv1.DeploymentSpec{Replicas: ptr.To[int32](1)},
+			Status:     appsv1.DeploymentStatus{ObservedGeneration: 2, Replicas: 1, UpdatedReplicas: 1, AvailableReplicas: available},
+		}
+	}
+	type step struct {
+		after             time.Duration
+		available         int32
+		expectedStatus    operatorv1.ConditionStatus
+		expectedRemaining time.Duration
+	}
+
+	tests := []struct {
+		name  string
+		steps []step
+	}{
+		{
+			name: "brief unavailability during a rollout",
+			steps: []step{
+				{after: 0, available: 1, expectedStatus: operatorv1.ConditionFalse},
+				{after: time.Minute, available: 0, expectedStatus: operatorv1.ConditionFalse, expectedRemaining: unavailableGracePeriod},
+				{after: 2 * time.Minute, available: 0, expectedStatus: operatorv1.ConditionFalse, expectedRemaining: unavailableGracePeriod - time.Minute},
+				{after: 150 * time.Second, available: 1, expectedStatus: operatorv1.ConditionFalse},
+				{after: 10 * time.Minute, available: 1, expectedStatus: operatorv1.ConditionFalse},
+			},
+		},
+		{
+			name: "sustained unavailability",
+			steps: []step{
+				{after: 0, available: 1, expectedStatus: operatorv1.ConditionFalse},
+				{after: time.Minute, available: 0, expectedStatus: operatorv1.ConditionFalse, expectedRemaining: unavailableGracePeriod},
+				{after: time.Minute + unavailableGracePeriod, available: 0, expectedStatus: operatorv1.ConditionTrue},
+				{after: 10 * time.Minute, available: 0, expectedStatus: operatorv1.ConditionTrue},
+				{after: 11 * time.Minute, available: 1, expectedStatus: operatorv1.ConditionFalse},
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			operatorConfig := &operatorv1.OpenShiftControllerManager{}
+			for _, step := range tc.steps {
+				now := start.Add(step.after)
+				// SetOperatorCondition stamps new transitions with the wall clock, move them to the
+				// simulated time
+				var readyBefore operatorv1.ConditionStatus
+				if ready := v1helpers.FindOperatorCondition(operatorConfig.Status.Conditions, operandReadyType); ready != nil {
+					readyBefore = ready.Status
+				}
+				setControllerManagerStatusConditions(operatorConfig, deployment(step.available), "openshift controller manager", now, "")
+				if ready := v1helpers.FindOperatorCondition(operatorConfig.Status.Conditions, operandReadyType); ready.Status != readyBefore {
+					ready.LastTransitionTime = metav1.NewTime(now)
+				}
+
+				remaining := setUnavailableDegradedCondition(operatorConfig, deployment(step.available), now, "")
+
+				condition := v1helpers.FindOperatorCondition(operatorConfig.Status.Conditions, unavailableDegradedType)
+				if condition == nil {
+					t.Fatalf("after %s: expected a %s condition", step.after, unavailableDegradedType)
+				}
+				if condition.Status != step.expectedStatus {
+					t.Errorf("after %s: expected %s=%s, got %s", step.after, unavailableDegradedType, step.expectedStatus, condition.Status)
+				}
+				if remaining != step.expectedRemaining {
+					t.Errorf("after %s: expected the grace period to last %s, got %s", step.after, step.expectedRemaining, remaining)
+				}
+				if step.expectedStatus == operatorv1.ConditionTrue {
+					if condition.Reason != noPodsAvailableReason {
+						t.Errorf("after %s: expected reason %q, got %q", step.after, noPodsAvailableReason, condition.Reason)
+					}
+					if expected := "deployment/controller-manager -n openshift-controller-manager: no replica has been available for more than 2m0s"; !strings.Contains(condition.Message, expected) {
+						t.Errorf("after %s: expected the message to contain %q, got %q", step.after, expected, condition.Message)
+					}
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package usercaobservation
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/operator/resource/resourceapply"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-openshift-controller-manager-operator/pkg/util"
+)
+
+const additionalTrustedCADegradedType = "AdditionalTrustedCADegraded"
+
+// syncAdditionalTrustedCA copies the CAs of the ConfigMap referenced by the cluster image config's
+// spec.additionalTrustedCA into a single bundle in the openshift-controller-manager namespace, so
+// that builds can trust registries behind custom CAs. The ConfigMap holds one CA per registry
+// hostname, while the controller-manager expects a single pem bundle file.
+// A reference to a ConfigMap that does not exist degrades the operator, the last synced bundle is
+// kept in that case. Without a reference the bundle is deleted, if there is one.
+//
+// The image config may reference the ConfigMap of the cluster proxy config, which is also synced to
+// openshift-user-ca. That copy is kept as it is for the build controller to hand to build pods and is not
+// mounted into the controller-manager, so the shared ConfigMap is still combined into this bundle. Its CAs
+// are only added once, the proxy bundle usually lists the registry CAs again under its own key.
+func (c *Controller) syncAdditionalTrustedCA(ctx context.Context) error {
+	condition := operatorv1.OperatorCondition{
+		Type:   additionalTrustedCADegradedType,
+		Status: operatorv1.ConditionFalse,
+	}
+
+	sourceName := ""
+	imageConfig, err := c.imageConfigLister.Get("cluster")
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	if err == nil {
+		sourceName = imageConfig.Spec.AdditionalTrustedCA.Name
+	}
+
+	if len(sourceName) == 0 {
+		_, err := c.targetConfigMapLister.ConfigMaps(util.TargetNamespace).Get(util.AdditionalTrustedCAConfigMapName)
+		if errors.IsNotFound(err) {
+			return c.setCondition(ctx, condition)
+		}
+		if err != nil {
+			return err
+		}
+		err = c.configMapsGetter.ConfigMaps(util.TargetNamespace).Delete(ctx, util.AdditionalTrustedCAConfigMapName, metav1.DeleteOptions{})
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		return c.setCondition(ctx, condition)
+	}
+
+	source, err := c.userConfigMapLister.ConfigMaps(util.UserSpecifiedGlobalConfigNamespace).Get(sourceName)
+	if errors.IsNotFound(err) {
+		condition.Status = operatorv1.ConditionTrue
+		condition.Reason = "ConfigMapNotFound"
+		condition.Message = fmt.Sprintf("configmap %s/%s referenced by images.config.openshift.io/cluster spec.additionalTrustedCA not found",
+			util.UserSpecifiedGlobalConfigNamespace, sourceName)
+		return c.setCondition(ctx, condition)
+	}
+	if err != nil {
+		return err
+	}
+
+	required := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: util.TargetNamespace,
+			Name:      util.AdditionalTrustedCAConfigMapName,
+		},
+		Data: map[string]string{
+			util.AdditionalTrustedCAKey: combineCABundle(source),
+		},
+	}
+	if _, _, err := resourceapply.ApplyConfigMap(ctx, c.configMapsGetter, c.recorder, required); err != nil {
+		return err
+	}
+	return c.setCondition(ctx, condition)
+}
+
+func (c *Controller) setCondition(ctx context.Context, condition operatorv1.OperatorCondition) error {
+	_, _, err := v1helpers.UpdateStatus(ctx, c.operatorConfigClient, v1helpers.UpdateConditionFn(condition))
+	return err
+}
+
+// pemBlock matches a single pem encoded block, e.g. a certificate.
+var pemBlock = regexp.MustCompile(`(?s)-----BEGIN [^-]+-----.*?-----END [^-]+-----`)
+
+// combineCABundle concatenates the CAs of all keys of the ConfigMaps in a stable order, the ConfigMaps
+// in the given order and the keys of each sorted. A CA is only added once, as the same CA is commonly
+// listed for several registry hostnames, or the ConfigMap is the one of the cluster proxy config, which
+// carries its whole bundle in a single key.
+func combineCABundle(configMaps ...*corev1.ConfigMap) string {
+	var bundle strings.Builder
+	seen := sets.New[string]()
+	for _, configMap := range configMaps {
+		keys := make([]string, 0, len(configMap.Data))
+		for key := range configMap.Data {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			cas := pemBlock.FindAllString(configMap.Data[key], -1)
+			if len(cas) == 0 {
+				// not pem encoded, leave it to the consumers to reject it
+				cas = []string{configMap.Data[key]}
+			}
+			for _, ca := range cas {
+				ca = strings.TrimSpace(ca)
+				if len(ca) == 0 || seen.Has(ca) {
+					continue
+				}
+				seen.Insert(ca)
+				bundle.WriteString(ca)
+				bundle.WriteString("\n")
+			}
+		}
+	}
+	return bundle.String()
+}
@@ -0,0 +1,178 @@
+package usercaobservation
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	corelistersv1 "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/utils/clock"
+
+	configv1 "github.com/openshift/api/config/v1"
+	operatorv1 "github.com/openshift/api/operator/v1"
+	configlistersv1 "github.com/openshift/client-go/config/listers/config/v1"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-openshift-controller-manager-operator/pkg/util"
+)
+
+func TestSyncAdditionalTrustedCA(t *testing.T) {
+	imageConfig := &configv1.Image{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+		Spec: configv1.ImageSpec{
+			AdditionalTrustedCA: configv1.ConfigMapNameReference{Name: "registry-cas"},
+		},
+	}
+	registryCAs := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: util.UserSpecifiedGlobalConfigNamespace, Name: "registry-cas"},
+		Data: map[string]string{
+			"registry.b.example.com":       "-----BEGIN CERTIFICATE-----\nb\n-----END CERTIFICATE-----\n",
+			"registry.a.example.com..5000": "-----BEGIN CERTIFICATE-----\na\n-----END CERTIFICATE-----",
+		},
+	}
+	// the configmap of the cluster proxy config, also referenced by the image config: it is synced to
+	// openshift-user-ca as it is and combined into the bundle without duplicate CAs
+	sharedCAs := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: util.UserSpecifiedGlobalConfigNamespace, Name: "user-ca-bundle"},
+		Data: map[string]string{
+			"ca-bundle.crt":              "-----BEGIN CERTIFICATE-----\nproxy\n-----END CERTIFICATE-----\n-----BEGIN CERTIFICATE-----\nregistry\n-----END CERTIFICATE-----\n",
+			"registry.example.com":       "-----BEGIN CERTIFICATE-----\nregistry\n-----END CERTIFICATE-----\n",
+			"registry.example.com..5000": "-----BEGIN CERTIFICATE-----\nregistry\n-----END CERTIFICATE-----",
+		},
+	}
+	staleBundle := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: util.TargetNamespace, Name: util.AdditionalTrustedCAConfigMapName},
+		Data:       map[string]string{util.AdditionalTrustedCAKey: "stale"},
+	}
+
+	cases := []struct {
+		name             string
+		imageConfig      *configv1.Image
+		userConfigMaps   []*corev1.ConfigMap
+		existing         []runtime.Object
+		expectedBundle   string
+		expectNoBundle   bool
+		expectedDegraded operatorv1.ConditionStatus
+		expectedMessage  string
+	}{
+		{
+			name:             "no reference",
+			imageConfig:      &configv1.Image{ObjectMeta: metav1.ObjectMeta{Name: "cluster"}},
+			existing:         []runtime.Object{staleBundle},
+			expectNoBundle:   true,
+			expectedDegraded: operatorv1.ConditionFalse,
+		},
+		{
+			name:             "no reference and no bundle",
+			imageConfig:      &configv1.Image{ObjectMeta: metav1.ObjectMeta{Name: "cluster"}},
+			expectNoBundle:   true,
+			expectedDegraded: operatorv1.ConditionFalse,
+		},
+		{
+			name:             "referenced configmap present",
+			imageConfig:      imageConfig,
+			userConfigMaps:   []*corev1.ConfigMap{registryCAs},
+			existing:         []runtime.Object{staleBundle},
+			expectedBundle:   "-----BEGIN CERTIFICATE-----\na\n-----END CERTIFICATE-----\n-----BEGIN CERTIFICATE-----\nb\n-----END CERTIFICATE-----\n",
+			expectedDegraded: operatorv1.ConditionFalse,
+		},
+		{
+			name: "configmap shared with the proxy config",
+			imageConfig: &configv1.Image{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+				Spec: configv1.ImageSpec{
+					AdditionalTrustedCA: configv1.ConfigMapNameReference{Name: "user-ca-bundle"},
+				},
+			},
+			userConfigMaps:   []*corev1.ConfigMap{sharedCAs},
+			existing:         []runtime.Object{staleBundle},
+			expectedBundle:   "-----BEGIN CERTIFICATE-----\nproxy\n-----END CERTIFICATE-----\n-----BEGIN CERTIFICATE-----\nregistry\n-----END CERTIFICATE-----\n",
+			expectedDegraded: operatorv1.ConditionFalse,
+		},
+		{
+			name:             "referenced configmap missing",
+			imageConfig:      imageConfig,
+			existing:         []runtime.Object{staleBundle},
+			expectedBundle:   "stale",
+			expectedDegraded: operatorv1.ConditionTrue,
+			expectedMessage:  "openshift-config/registry-cas",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			imageIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+			if err := imageIndexer.Add(tc.imageConfig); err != nil {
+				t.Fatal(err)
+			}
+			configMapIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+			for _, cm := range tc.userConfigMaps {
+				if err := configMapIndexer.Add(cm); err != nil {
+					t.Fatal(err)
+				}
+			}
+			targetConfigMapIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+			for _, obj := range tc.existing {
+				if err := targetConfigMapIndexer.Add(obj); err != nil {
+					t.Fatal(err)
+				}
+			}
+			kubeClient := fake.NewSimpleClientset(tc.existing...)
+			fakeOperatorClient := v1helpers.NewFakeOperatorClient(
+				&operatorv1.OperatorSpec{ManagementState: operatorv1.Managed},
+				&operatorv1.OperatorStatus{},
+				nil,
+			)
+			c := &Controller{
+				operatorConfigClient:  fakeOperatorClient,
+				imageConfigLister:     configlistersv1.NewImageLister(imageIndexer),
+				userConfigMapLister:   corelistersv1.NewConfigMapLister(configMapIndexer),
+				targetConfigMapLister: corelistersv1.NewConfigMapLister(targetConfigMapIndexer),
+				configMapsGetter:      kubeClient.CoreV1(),
+				recorder:              events.NewInMemoryRecorder("test", clock.RealClock{}),
+			}
+
+			if err := c.syncAdditionalTrustedCA(context.TODO()); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			for _, action := range kubeClient.Actions() {
+				if action.GetVerb() == "delete" && len(tc.existing) == 0 {
+					t.Errorf("expected no delete without a bundle, got %v", action)
+				}
+			}
+
+			bundle, err := kubeClient.CoreV1().ConfigMaps(util.TargetNamespace).Get(context.TODO(), util.AdditionalTrustedCAConfigMapName, metav1.GetOptions{})
+			switch {
+			case tc.expectNoBundle:
+				if !errors.IsNotFound(err) {
+					t.Errorf("expected the bundle to be removed, got %v", err)
+				}
+			case err != nil:
+				t.Fatalf("expected a bundle: %v", err)
+			case bundle.Data[util.AdditionalTrustedCAKey] != tc.expectedBundle:
+				t.Errorf("expected bundle %q, got %q", tc.expectedBundle, bundle.Data[util.AdditionalTrustedCAKey])
+			}
+
+			_, status, _, err := fakeOperatorClient.GetOperatorState()
+			if err != nil {
+				t.Fatal(err)
+			}
+			condition := v1helpers.FindOperatorCondition(status.Conditions, additionalTrustedCADegradedType)
+			if condition == nil {
+				t.Fatalf("expected a %s condition", additionalTrustedCADegradedType)
+			}
+			if condition.Status != tc.expectedDegraded {
+				t.Errorf("expected %s=%s, got %s", additionalTrustedCADegradedType, tc.expectedDegraded, condition.Status)
+			}
+			if !strings.Contains(condition.Message, tc.expectedMessage) {
+				t.Errorf("expected condition message to contain %q, got %q", tc.expectedMessage, condition.Message)
+			}
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package usercaobservation
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	"github.com/openshift/library-go/pkg/operator/resource/resourceapply"
+	"github.com/openshift/library-go/pkg/operator/resourcesynccontroller"
+
+	"github.com/openshift/cluster-openshift-controller-manager-operator/pkg/util"
+)
+
+const (
+	gitTrustedCADegradedType = "GitTrustedCADegraded"
+	// gitTrustedCAAnnotation on builds.config.openshift.io/cluster names a ConfigMap in openshift-config
+	// with the CAs of the git servers builds clone their sources from. The build config API has no field
+	// for it, its additionalTrustedCA is for image pushes and pulls only.
+	gitTrustedCAAnnotation = "openshift-controller-manager.operator.openshift.io/git-trusted-ca"
+	// userCABundleConfigMapName is the bundle of the proxy and the git CAs in the operator namespace
+	// which is synced to the openshift-user-ca ConfigMap while a git CA is referenced.
+	userCABundleConfigMapName = "user-ca-bundle"
+	userCABundleKey           = "ca-bundle.crt"
+)
+
+// gitTrustedCASource returns where the openshift-user-ca ConfigMap is synced from given the CAs of the
+// cluster proxy config at proxySource. Build pods trust the openshift-user-ca bundle for every step,
+// including the git clone of their sources, so the CAs of the git servers are added to it: the proxy and
+// the git CAs are combined into one bundle without duplicates in the operator namespace, which becomes the
+// source. Without a git CA referenced the proxy CAs are synced as they are and the bundle is deleted, if
+// there is one.
+//
+// A reference to a ConfigMap that does not exist degrades the operator, sync is false then so that the
+// last synced bundle is kept.
+func (c *Controller) gitTrustedCASource(ctx context.Context, proxySource resourcesynccontroller.ResourceLocation) (source resourcesynccontroller.ResourceLocation, sync bool, err error) {
+	condition := operatorv1.OperatorCondition{
+		Type:   gitTrustedCADegradedType,
+		Status: operatorv1.ConditionFalse,
+	}
+
+	gitCAName := ""
+	buildConfig, err := c.buildConfigLister.Get("cluster")
+	if err != nil && !errors.IsNotFound(err) {
+		return source, false, err
+	}
+	if err == nil {
+		gitCAName = buildConfig.Annotations[gitTrustedCAAnnotation]
+	}
+
+	if len(gitCAName) == 0 {
+		_, err := c.operatorConfigMapLister.ConfigMaps(util.OperatorNamespace).Get(userCABundleConfigMapName)
+		if errors.IsNotFound(err) {
+			return proxySource, true, c.setCondition(ctx, condition)
+		}
+		if err != nil {
+			return source, false, err
+		}
+		err = c.configMapsGetter.ConfigMaps(util.OperatorNamespace).Delete(ctx, userCABundleConfigMapName, metav1.DeleteOptions{})
+		if err != nil && !errors.IsNotFound(err) {
+			return source, false, err
+		}
+		return proxySource, true, c.setCondition(ctx, condition)
+	}
+
+	gitCAs, err := c.userConfigMapLister.ConfigMaps(util.UserSpecifiedGlobalConfigNamespace).Get(gitCAName)
+	if errors.IsNotFound(err) {
+		condition.Status = operatorv1.ConditionTrue
+		condition.Reason = "ConfigMapNotFound"
+		condition.Message = fmt.Sprintf("configmap %s/%s referenced by builds.config.openshift.io/cluster annotation %s not found",
+			util.UserSpecifiedGlobalConfigNamespace, gitCAName, gitTrustedCAAnnotation)
+		return source, false, c.setCondition(ctx, condition)
+	}
+	if err != nil {
+		return source, false, err
+	}
+
+	configMaps := []*corev1.ConfigMap{}
+	if len(proxySource.Name) > 0 {
+		proxyCAs, err := c.userConfigMapLister.ConfigMaps(proxySource.Namespace).Get(proxySource.Name)
+		switch {
+		case err == nil:
+			configMaps = append(configMaps, proxyCAs)
+		case !errors.IsNotFound(err):
+			return source, false, err
+		}
+	}
+	configMaps = append(configMaps, gitCAs)
+
+	required := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: util.OperatorNamespace,
+			Name:      userCABundleConfigMapName,
+		},
+		Data: map[string]string{
+			userCABundleKey: combineCABundle(configMaps...),
+		},
+	}
+	if _, _, err := resourceapply.ApplyConfigMap(ctx, c.configMapsGetter, c.recorder, required); err != nil {
+		return source, false, err
+	}
+	source = resourcesynccontroller.ResourceLocation{
+		Namespace: util.OperatorNamespace,
+		Name:      userCABundleConfigMapName,
+	}
+	return source, true, c.setCondition(ctx, condition)
+}
@@ -0,0 +1,206 @@
+package usercaobservation
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	corelistersv1 "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/utils/clock"
+
+	configv1 "github.com/openshift/api/config/v1"
+	operatorv1 "github.com/openshift/api/operator/v1"
+	configlistersv1 "github.com/openshift/client-go/config/listers/config/v1"
+	"github.com/openshift/library-go/pkg/operator/events"
+	"github.com/openshift/library-go/pkg/operator/resourcesynccontroller"
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+
+	"github.com/openshift/cluster-openshift-controller-manager-operator/pkg/util"
+)
+
+func TestGitTrustedCASource(t *testing.T) {
+	proxySource := resourcesynccontroller.ResourceLocation{Namespace: util.UserSpecifiedGlobalConfigNamespace, Name: "user-ca-bundle"}
+	bundleSource := resourcesynccontroller.ResourceLocation{Namespace: util.OperatorNamespace, Name: userCABundleConfigMapName}
+	buildConfig := &configv1.Build{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "cluster",
+			Annotations: map[string]string{gitTrustedCAAnnotation: "git-cas"},
+		},
+	}
+	// the git server CA is also one of the proxy CAs
+	proxyCAs := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: util.UserSpecifiedGlobalConfigNamespace, Name: "user-ca-bundle"},
+		Data: map[string]string{
+			"ca-bundle.crt": "-----BEGIN CERTIFICATE-----\nproxy\n-----END CERTIFICATE-----\n-----BEGIN CERTIFICATE-----\ngit-a\n-----END CERTIFICATE-----\n",
+		},
+	}
+	gitCAs := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: util.UserSpecifiedGlobalConfigNamespace, Name: "git-cas"},
+		Data: map[string]string{
+			"git.b.example.com": "-----BEGIN CERTIFICATE-----\ngit-b\n-----END CERTIFICATE-----\n",
+			"git.a.example.com": "-----BEGIN CERTIFICATE-----\ngit-a\n-----END CERTIFICATE-----",
+		},
+	}
+	staleBundle := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: util.OperatorNamespace, Name: userCABundleConfigMapName},
+		Data:       map[string]string{userCABundleKey: "stale"},
+	}
+
+	cases := []struct {
+		name             string
+		buildConfig      *configv1.Build
+		proxySource      resourcesynccontroller.ResourceLocation
+		userConfigMaps   []*corev1.ConfigMap
+		existing         []runtime.Object
+		expectedSource   resourcesynccontroller.ResourceLocation
+		expectNoSync     bool
+		expectedBundle   string
+		expectNoBundle   bool
+		expectedDegraded operatorv1.ConditionStatus
+		expectedMessage  string
+	}{
+		{
+			name:             "no reference",
+			buildConfig:      &configv1.Build{ObjectMeta: metav1.ObjectMeta{Name: "cluster"}},
+			proxySource:      proxySource,
+			userConfigMaps:   []*corev1.ConfigMap{proxyCAs},
+			existing:         []runtime.Object{staleBundle},
+			expectedSource:   proxySource,
+			expectNoBundle:   true,
+			expectedDegraded: operatorv1.ConditionFalse,
+		},
+		{
+			name:             "no reference and no bundle",
+			buildConfig:      &configv1.Build{ObjectMeta: metav1.ObjectMeta{Name: "cluster"}},
+			proxySource:      proxySource,
+			userConfigMaps:   []*corev1.ConfigMap{proxyCAs},
+			expectedSource:   proxySource,
+			expectNoBundle:   true,
+			expectedDegraded: operatorv1.ConditionFalse,
+		},
+		{
+			name:             "no build config",
+			proxySource:      proxySource,
+			expectedSource:   proxySource,
+			expectNoBundle:   true,
+			expectedDegraded: operatorv1.ConditionFalse,
+		},
+		{
+			name:           "referenced configmap present",
+			buildConfig:    buildConfig,
+			proxySource:    proxySource,
+			userConfigMaps: []*corev1.ConfigMap{proxyCAs, gitCAs},
+			existing:       []runtime.Object{staleBundle},
+			expectedSource: bundleSource,
+			expectedBundle: "-----BEGIN CERTIFICATE-----\nproxy\n-----END CERTIFICATE-----\n" +
+				"-----BEGIN CERTIFICATE-----\ngit-a\n-----END CERTIFICATE-----\n" +
+				"-----BEGIN CERTIFICATE-----\ngit-b\n-----END CERTIFICATE-----\n",
+			expectedDegraded: operatorv1.ConditionFalse,
+		},
+		{
+			name:           "referenced configmap present without a proxy CA",
+			buildConfig:    buildConfig,
+			userConfigMaps: []*corev1.ConfigMap{gitCAs},
+			expectedSource: bundleSource,
+			expectedBundle: "-----BEGIN CERTIFICATE-----\ngit-a\n-----END CERTIFICATE-----\n" +
+				"-----BEGIN CERTIFICATE-----\ngit-b\n-----END CERTIFICATE-----\n",
+			expectedDegraded: operatorv1.ConditionFalse,
+		},
+		{
+			name:             "referenced configmap missing",
+			buildConfig:      buildConfig,
+			proxySource:      proxySource,
+			userConfigMaps:   []*corev1.ConfigMap{proxyCAs},
+			existing:         []runtime.Object{staleBundle},
+			expectNoSync:     true,
+			expectedBundle:   "stale",
+			expectedDegraded: operatorv1.ConditionTrue,
+			expectedMessage:  "openshift-config/git-cas",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			buildIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+			if tc.buildConfig != nil {
+				if err := buildIndexer.Add(tc.buildConfig); err != nil {
+					t.Fatal(err)
+				}
+			}
+			configMapIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+			for _, cm := range tc.userConfigMaps {
+				if err := configMapIndexer.Add(cm); err != nil {
+					t.Fatal(err)
+				}
+			}
+			operatorConfigMapIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+			for _, obj := range tc.existing {
+				if err := operatorConfigMapIndexer.Add(obj); err != nil {
+					t.Fatal(err)
+				}
+			}
+			kubeClient := fake.NewSimpleClientset(tc.existing...)
+			fakeOperatorClient := v1helpers.NewFakeOperatorClient(
+				&operatorv1.OperatorSpec{ManagementState: operatorv1.Managed},
+				&operatorv1.OperatorStatus{},
+				nil,
+			)
+			c := &Controller{
+				operatorConfigClient:    fakeOperatorClient,
+				buildConfigLister:       configlistersv1.NewBuildLister(buildIndexer),
+				userConfigMapLister:     corelistersv1.NewConfigMapLister(configMapIndexer),
+				operatorConfigMapLister: corelistersv1.NewConfigMapLister(operatorConfigMapIndexer),
+				configMapsGetter:        kubeClient.CoreV1(),
+				recorder:                events.NewInMemoryRecorder("test", clock.RealClock{}),
+			}
+
+			source, sync, err := c.gitTrustedCASource(context.TODO(), tc.proxySource)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			for _, action := range kubeClient.Actions() {
+				if action.GetVerb() == "delete" && len(tc.existing) == 0 {
+					t.Errorf("expected no delete without a bundle, got %v", action)
+				}
+			}
+			if sync == tc.expectNoSync {
+				t.Errorf("expected sync %t, got %t", !tc.expectNoSync, sync)
+			}
+			if sync && source != tc.expectedSource {
+				t.Errorf("expected source %v, got %v", tc.expectedSource, source)
+			}
+
+			bundle, err := kubeClient.CoreV1().ConfigMaps(util.OperatorNamespace).Get(context.TODO(), userCABundleConfigMapName, metav1.GetOptions{})
+			switch {
+			case tc.expectNoBundle:
+				if !errors.IsNotFound(err) {
+					t.Errorf("expected the bundle to be removed, got %v", err)
+				}
+			case err != nil:
+				t.Fatalf("expected a bundle: %v", err)
+			case bundle.Data[userCABundleKey] != tc.expectedBundle:
+				t.Errorf("expected bundle %q, got %q", tc.expectedBundle, bundle.Data[userCABundleKey])
+			}
+
+			_, status, _, err := fakeOperatorClient.GetOperatorState()
+			if err != nil {
+				t.Fatal(err)
+			}
+			condition := v1helpers.FindOperatorCondition(status.Conditions, gitTrustedCADegradedType)
+			if condition == nil {
+				t.Fatalf("expected a %s condition", gitTrustedCADegradedType)
+			}
+			if condition.Status != tc.expectedDegraded {
+				t.Errorf("expected %s=%s, got %s", gitTrustedCADegradedType, tc.expectedDegraded, condition.Status)
+			}
+			if !strings.Contains(condition.Message, tc.expectedMessage) {
+				t.Errorf("expected condition message to contain %q, got %q", tc.expectedMessage, condition.Message)
+			}
+		})
+	}
+}
@@ -4,6 +4,10 @@ import (
 	"context"
 	"time"
 
+	kubeinformers "k8s.io/client-go/informers"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	corelistersv1 "k8s.io/client-go/listers/core/v1"
+
 	configinformers "github.com/openshift/client-go/config/informers/externalversions"
 	configlistersv1 "github.com/openshift/client-go/config/listers/config/v1"
 	"github.com/openshift/library-go/pkg/controller/factory"
@@ -13,41 +17,72 @@ import (
 	"github.com/openshift/library-go/pkg/operator/v1helpers"
 	"k8s.io/apimachinery/pkg/api/errors"
 
+	"github.com/openshift/cluster-openshift-controller-manager-operator/pkg/operator/metrics"
 	"github.com/openshift/cluster-openshift-controller-manager-operator/pkg/util"
 )
 
 // Controller watches the cluster proxy config resource to see if a custom trusted CA has been
 // added or removed. In the event a change is detected, this Controller makes appropriate calls to
 // the provided ResourceSyncer instance.
+// It also combines the additional trusted CAs referenced by the cluster image config into a single
+// bundle in the openshift-controller-manager namespace, and adds the CAs of the git servers referenced
+// by the cluster build config to the proxy CAs trusted by build pods.
 type Controller struct {
 	name                 string
 	operatorConfigClient v1helpers.OperatorClient
 	proxyLister          configlistersv1.ProxyLister
-	resourceSyncer       resourcesynccontroller.ResourceSyncer
-	runFn                func(ctx context.Context, workers int)
-	syncCtxt             factory.SyncContext
+	imageConfigLister    configlistersv1.ImageLister
+	buildConfigLister    configlistersv1.BuildLister
+	userConfigMapLister  corelistersv1.ConfigMapLister
+	// targetConfigMapLister lists the ConfigMaps of the openshift-controller-manager namespace.
+	targetConfigMapLister corelistersv1.ConfigMapLister
+	// operatorConfigMapLister lists the ConfigMaps of the operator namespace.
+	operatorConfigMapLister corelistersv1.ConfigMapLister
+	configMapsGetter        corev1client.ConfigMapsGetter
+	recorder                events.Recorder
+	resourceSyncer          resourcesynccontroller.ResourceSyncer
+	runFn                   func(ctx context.Context, workers int)
+	syncCtxt                factory.SyncContext
 }
 
-// NewController creates a new usercaobservation.Controller instance.
+// NewController creates a new usercaobservation.Controller instance, syncing everything again every
+// resyncInterval.
 func NewController(operatorConfigClient v1helpers.OperatorClient,
 	configInformers configinformers.SharedInformerFactory,
+	kubeInformersForUserConfigNamespace kubeinformers.SharedInformerFactory,
+	kubeInformersForTargetNamespace kubeinformers.SharedInformerFactory,
+	kubeInformersForOperatorNamespace kubeinformers.SharedInformerFactory,
+	configMapsGetter corev1client.ConfigMapsGetter,
 	resourceSyncer resourcesynccontroller.ResourceSyncer,
-	eventRecorder events.Recorder) *Controller {
+	eventRecorder events.Recorder,
+	resyncInterval time.Duration) *Controller {
 	c := &Controller{
-		name:                 "UserCAObservationController",
-		operatorConfigClient: operatorConfigClient,
-		proxyLister:          configInformers.Config().V1().Proxies().Lister(),
-		resourceSyncer:       resourceSyncer,
+		name:                    "UserCAObservationController",
+		operatorConfigClient:    operatorConfigClient,
+		proxyLister:             configInformers.Config().V1().Proxies().Lister(),
+		imageConfigLister:       configInformers.Config().V1().Images().Lister(),
+		buildConfigLister:       configInformers.Config().V1().Builds().Lister(),
+		userConfigMapLister:     kubeInformersForUserConfigNamespace.Core().V1().ConfigMaps().Lister(),
+		targetConfigMapLister:   kubeInformersForTargetNamespace.Core().V1().ConfigMaps().Lister(),
+		operatorConfigMapLister: kubeInformersForOperatorNamespace.Core().V1().ConfigMaps().Lister(),
+		configMapsGetter:        configMapsGetter,
+		recorder:                eventRecorder.WithComponentSuffix("user-ca-observation-controller"),
+		resourceSyncer:          resourceSyncer,
 	}
 	informers := []factory.Informer{
 		operatorConfigClient.Informer(),
 		configInformers.Config().V1().Proxies().Informer(),
+		configInformers.Config().V1().Images().Informer(),
+		configInformers.Config().V1().Builds().Informer(),
+		kubeInformersForUserConfigNamespace.Core().V1().ConfigMaps().Informer(),
+		kubeInformersForTargetNamespace.Core().V1().ConfigMaps().Informer(),
+		kubeInformersForOperatorNamespace.Core().V1().ConfigMaps().Informer(),
 	}
 	f := factory.New().
 		WithSync(c.Sync).
 		WithSyncContext(c.syncCtxt).
 		WithInformers(informers...).
-		ResyncEvery(10*time.Minute).
+		ResyncEvery(resyncInterval).
 		ToController(c.name, eventRecorder.WithComponentSuffix("user-ca-observation-controller"))
 	c.runFn = f.Run
 	return c
@@ -60,6 +95,8 @@ func (c *Controller) Run(ctx context.Context, workers int) {
 
 // Sync runs the main synchronization logic for the controller.
 func (c *Controller) Sync(ctx context.Context, syncCtx factory.SyncContext) error {
+	defer metrics.ObserveReconcileDuration(c.name, time.Now())
+
 	operatorSpec, _, _, err := c.operatorConfigClient.GetOperatorState()
 	if err != nil {
 		return nil
@@ -68,6 +105,9 @@ func (c *Controller) Sync(ctx context.Context, syncCtx factory.SyncContext) erro
 	if !management.IsOperatorManaged(operatorSpec.ManagementState) {
 		return nil
 	}
+	if paused, err := util.ReconciliationPaused(c.operatorConfigClient); err != nil || paused {
+		return err
+	}
 
 	// Bug 1826183: copy the proxy CA trust bundle to the openshift-controller-manager namespace.
 	// If this ConfigMap exists, the build controller will copy the contents into a ConfigMap for
@@ -81,13 +121,22 @@ func (c *Controller) Sync(ctx context.Context, syncCtx factory.SyncContext) erro
 	if err != nil {
 		return err
 	}
+	source, sync, err := c.gitTrustedCASource(ctx, source)
+	if err != nil {
+		return err
+	}
 	destination := resourcesynccontroller.ResourceLocation{
 		Namespace: util.TargetNamespace,
 		Name:      "openshift-user-ca",
 		Provider:  "openshift-controller-manager-operator",
 	}
-	err = c.resourceSyncer.SyncConfigMap(destination, source)
-	return err
+	if sync {
+		if err := c.resourceSyncer.SyncConfigMap(destination, source); err != nil {
+			return err
+		}
+	}
+
+	return c.syncAdditionalTrustedCA(ctx)
 }
 
 func (c *Controller) findProxyCASource() (resourcesynccontroller.ResourceLocation, error) {
@@ -9,6 +9,8 @@ import (
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	kubeinformers "k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
 	"k8s.io/utils/clock"
 
 	configv1 "github.com/openshift/api/config/v1"
@@ -111,15 +113,26 @@ func TestFindProxyCASource(t *testing.T) {
 			}
 			fakeConfigClient := fakeconfig.NewSimpleClientset(configObjects...)
 			configInformer := configinformers.NewSharedInformerFactory(fakeConfigClient, 1*time.Minute)
+			kubeInformer := kubeinformers.NewSharedInformerFactoryWithOptions(fake.NewSimpleClientset(), 1*time.Minute, kubeinformers.WithNamespace(util.UserSpecifiedGlobalConfigNamespace))
+			targetKubeInformer := kubeinformers.NewSharedInformerFactoryWithOptions(fake.NewSimpleClientset(), 1*time.Minute, kubeinformers.WithNamespace(util.TargetNamespace))
+			operatorKubeInformer := kubeinformers.NewSharedInformerFactoryWithOptions(fake.NewSimpleClientset(), 1*time.Minute, kubeinformers.WithNamespace(util.OperatorNamespace))
 			syncer := newFakeSyncer()
 			controller := NewController(fakeOperatorClient,
 				configInformer,
+				kubeInformer,
+				targetKubeInformer,
+				operatorKubeInformer,
+				fake.NewSimpleClientset().CoreV1(),
 				syncer,
-				events.NewInMemoryRecorder("test", clock.RealClock{}))
+				events.NewInMemoryRecorder("test", clock.RealClock{}),
+				time.Minute)
 
 			ctx, ctxCancel := context.WithCancel(context.TODO())
 			defer ctxCancel()
 			go configInformer.Start(ctx.Done())
+			go kubeInformer.Start(ctx.Done())
+			go targetKubeInformer.Start(ctx.Done())
+			go operatorKubeInformer.Start(ctx.Done())
 			go controller.Run(ctx, 1)
 
 			select {
@@ -137,3 +150,31 @@ func TestFindProxyCASource(t *testing.T) {
 		})
 	}
 }
+
+func TestSyncPaused(t *testing.T) {
+	fakeOperatorClient := v1helpers.NewFakeOperatorClientWithObjectMeta(
+		&metav1.ObjectMeta{Annotations: map[string]string{util.PauseReconcileAnnotation: "true"}},
+		&operatorv1.OperatorSpec{ManagementState: operatorv1.Managed},
+		&operatorv1.OperatorStatus{},
+		nil,
+	)
+	kubeClient := fake.NewSimpleClientset()
+	syncer := newFakeSyncer()
+	c := &Controller{
+		name:                 "UserCAObservationController",
+		operatorConfigClient: fakeOperatorClient,
+		configMapsGetter:     kubeClient.CoreV1(),
+		resourceSyncer:       syncer,
+		recorder:             events.NewInMemoryRecorder("test", clock.RealClock{}),
+	}
+
+	if err := c.Sync(context.TODO(), nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(syncer.configMaps) > 0 {
+		t.Errorf("expected no configmap to be synced while reconciliation is paused, got %v", syncer.configMaps)
+	}
+	if actions := kubeClient.Actions(); len(actions) > 0 {
+		t.Errorf("expected no actions while reconciliation is paused, got %v", actions)
+	}
+}
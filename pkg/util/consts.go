@@ -10,4 +10,19 @@ const (
 	InfraNamespace                        = "openshift-infra"
 	VersionAnnotation                     = "release.openshift.io/version"
 	ClusterOperatorName                   = "openshift-controller-manager"
+
+	// AdditionalTrustedCAConfigMapName is the ConfigMap in the TargetNamespace holding the combined
+	// additional trusted CAs of the cluster image config.
+	AdditionalTrustedCAConfigMapName = "additional-trusted-ca"
+	AdditionalTrustedCAKey           = "ca-bundle.crt"
+	// AdditionalTrustedCAFile is where the controller-manager deployment mounts AdditionalTrustedCAKey.
+	AdditionalTrustedCAFile = "/var/run/configmaps/additional-trusted-ca/" + AdditionalTrustedCAKey
+
+	// ObservedConfigHistoryAnnotation of the operator config holds the recent changes of its observed
+	// config as a JSON list of revisions, each with its time and reason, oldest first.
+	ObservedConfigHistoryAnnotation = "openshift-controller-manager.operator.openshift.io/observed-config-history"
+
+	// PauseReconcileAnnotation on the operator config set to "true" stops the operator from reconciling the
+	// operands, so that they can be debugged without manual changes being reverted.
+	PauseReconcileAnnotation = "operator.openshift.io/pause-reconcile"
 )
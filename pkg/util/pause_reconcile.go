@@ -0,0 +1,20 @@
+package util
+
+import (
+	"k8s.io/apimachinery/pkg/api/errors"
+
+	"github.com/openshift/library-go/pkg/operator/v1helpers"
+)
+
+// ReconciliationPaused returns whether the operator config pauses reconciling the operands with the
+// PauseReconcileAnnotation. A missing operator config pauses nothing.
+func ReconciliationPaused(operatorClient v1helpers.OperatorClient) (bool, error) {
+	meta, err := operatorClient.GetObjectMeta()
+	if errors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return meta.Annotations[PauseReconcileAnnotation] == "true", nil
+}
@@ -1,6 +1,9 @@
 package version
 
 import (
+	"fmt"
+	"runtime"
+
 	"github.com/prometheus/client_golang/prometheus"
 
 	"k8s.io/apimachinery/pkg/version"
@@ -30,6 +33,9 @@ func Get() version.Info {
 		GitCommit:  commitFromGit,
 		GitVersion: versionFromGit,
 		BuildDate:  buildDate,
+		GoVersion:  runtime.Version(),
+		Compiler:   runtime.Compiler,
+		Platform:   fmt.Sprintf("%s/%s", runtime.GOOS, runtime.GOARCH),
 	}
 }
 
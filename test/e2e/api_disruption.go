@@ -0,0 +1,58 @@
+package e2e
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	g "github.com/onsi/ginkgo/v2"
+	o "github.com/onsi/gomega"
+
+	"github.com/openshift/cluster-openshift-controller-manager-operator/test/framework"
+)
+
+// apiDisruptionDuration is how long the operator is left without access to the API. It is below the
+// lease renew deadline, so that the operator stays the leader, and below the grace period read failures
+// of the observed config have before they degrade the operator.
+const apiDisruptionDuration = time.Minute
+
+var _ = g.Describe("[sig-openshift-controller-manager] API Disruption", func() {
+	g.It("[APIDisruption][Disruptive] should become Available again with the same observed config after briefly losing access to the API", func(ctx context.Context) {
+		testOperatorRecoversAfterAPIDisruption(ctx, g.GinkgoTB())
+	})
+})
+
+// testOperatorRecoversAfterAPIDisruption cuts the operator off the API for a while. The operator cannot
+// update its status meanwhile, what it reports during a disruption is covered by the unit tests of the
+// operator package; this checks it settles again afterwards.
+func testOperatorRecoversAfterAPIDisruption(ctx context.Context, t testing.TB) {
+	client := framework.MustNewClientset(t, nil)
+
+	// Make sure the operator is fully up and the operand is not rolling out
+	framework.MustEnsureClusterOperatorStatusIsSet(ctx, t, client)
+	framework.AssertProgressingClearsWithin(ctx, t, client, 10*time.Minute)
+	observedConfig, err := framework.GetObservedConfigRaw(ctx, t, client)
+	o.Expect(err).NotTo(o.HaveOccurred())
+	o.Expect(observedConfig).NotTo(o.BeEmpty(), "the operator has not observed any config yet")
+
+	g.By("Revoking the API access of the operator")
+	restore := framework.WithOperatorAPIAccessRevoked(ctx, t, client)
+	select {
+	case <-ctx.Done():
+		t.Fatalf("the test was cancelled during the API disruption: %v", ctx.Err())
+	case <-time.After(apiDisruptionDuration):
+	}
+	g.By("Restoring the API access of the operator")
+	restore()
+
+	g.By("Verifying that the operator becomes Available, not Progressing and not Degraded again")
+	framework.AssertProgressingClearsWithin(ctx, t, client, 5*time.Minute)
+	framework.MustEnsureClusterOperatorStatusIsSet(ctx, t, client)
+
+	g.By("Verifying that the observed config is the one before the disruption")
+	err = framework.WaitForObservedConfig(ctx, t, client, observedConfig, 2*time.Minute)
+	o.Expect(err).NotTo(o.HaveOccurred())
+
+	g.By("Verifying that the failures during the disruption do not degrade the operator afterwards")
+	framework.AssertNotDegradedFor(ctx, t, client, 2*time.Minute)
+}
@@ -0,0 +1,81 @@
+package e2e
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	g "github.com/onsi/ginkgo/v2"
+	o "github.com/onsi/gomega"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+
+	configv1 "github.com/openshift/api/config/v1"
+	"github.com/openshift/cluster-openshift-controller-manager-operator/test/framework"
+)
+
+var _ = g.Describe("[sig-openshift-controller-manager] Build Git Proxy", func() {
+	g.It("[Operator][Serial] should propagate the git proxy of the build config to OpenShift Controller Manager", func(ctx context.Context) {
+		testBuildGitProxyPropagation(ctx, g.GinkgoTB())
+	})
+})
+
+func testBuildGitProxyPropagation(ctx context.Context, t testing.TB) {
+	const gitNoProxy = ".e2e-git-proxy.example.com"
+	client := framework.MustNewClientset(t, nil)
+
+	// Make sure the operator is fully up
+	framework.MustEnsureClusterOperatorStatusIsSet(ctx, t, client)
+
+	build, err := client.Builds().Get(ctx, "cluster", metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		g.Skip("builds.config.openshift.io/cluster does not exist, the Build capability is disabled")
+	}
+	o.Expect(err).NotTo(o.HaveOccurred(), "failed to get the build config")
+
+	// The build controllers must be running to pick up the git proxy
+	err = framework.WaitForOperandReady(ctx, t, client, 1, 5*time.Minute)
+	o.Expect(err).NotTo(o.HaveOccurred())
+	originalGitProxy := build.Spec.BuildDefaults.GitProxy
+
+	g.By("Setting a git no-proxy list without a git proxy")
+	err = updateBuildGitProxy(ctx, client, &configv1.ProxySpec{NoProxy: gitNoProxy})
+	o.Expect(err).NotTo(o.HaveOccurred(), "failed to set the git proxy of the build config")
+	g.DeferCleanup(func(ctx context.Context) {
+		if framework.CleanupSkipped(t, "restore the original git proxy %v of the build config", originalGitProxy) {
+			return
+		}
+		g.By("Restoring the original git proxy")
+		if err := updateBuildGitProxy(ctx, client, originalGitProxy); err != nil {
+			g.GinkgoLogr.Error(err, "failed to restore the original git proxy")
+			return
+		}
+		framework.MustEnsureClusterOperatorStatusIsSet(ctx, t, client)
+		if originalGitProxy == nil {
+			o.Eventually(observedConfigFunc(client)).WithContext(ctx).WithTimeout(2 * time.Minute).WithPolling(5 * time.Second).ShouldNot(
+				framework.HaveObservedConfigValue("build.buildDefaults.gitNoProxy", gitNoProxy))
+			framework.AssertObservedConfigClean(ctx, t, client,
+				"build.buildDefaults.gitHTTPProxy", "build.buildDefaults.gitHTTPSProxy", "build.buildDefaults.gitNoProxy")
+		}
+	})
+
+	g.By("Verifying the git no-proxy list in observed config")
+	o.Eventually(observedConfigFunc(client)).WithContext(ctx).WithTimeout(2*time.Minute).WithPolling(5*time.Second).Should(
+		framework.HaveObservedConfigValue("build.buildDefaults.gitNoProxy", gitNoProxy),
+		"the git proxy of the build config was not propagated to OpenShift Controller Manager observed config")
+}
+
+// updateBuildGitProxy sets the git proxy of the build config, retrying on conflicts.
+func updateBuildGitProxy(ctx context.Context, client *framework.Clientset, gitProxy *configv1.ProxySpec) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		build, err := client.Builds().Get(ctx, "cluster", metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		build.Spec.BuildDefaults.GitProxy = gitProxy
+		_, err = client.Builds().Update(ctx, build, metav1.UpdateOptions{})
+		return err
+	})
+}
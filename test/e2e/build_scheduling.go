@@ -0,0 +1,77 @@
+package e2e
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	g "github.com/onsi/ginkgo/v2"
+	o "github.com/onsi/gomega"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+
+	"github.com/openshift/cluster-openshift-controller-manager-operator/test/framework"
+)
+
+var _ = g.Describe("[sig-openshift-controller-manager] Build Scheduling", func() {
+	g.It("[Operator][Build][Serial] should propagate the node selector of the build overrides to OpenShift Controller Manager", func(ctx context.Context) {
+		testBuildNodeSelectorPropagation(ctx, g.GinkgoTB())
+	})
+})
+
+func testBuildNodeSelectorPropagation(ctx context.Context, t testing.TB) {
+	// no node carries the label, no build runs during the test so none is left unschedulable
+	const labelKey, labelValue = "e2e-build-scheduling", "true"
+	client := framework.MustNewClientset(t, nil)
+
+	// Make sure the operator is fully up
+	framework.MustEnsureClusterOperatorStatusIsSet(ctx, t, client)
+
+	build, err := client.Builds().Get(ctx, "cluster", metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		g.Skip("builds.config.openshift.io/cluster does not exist, the Build capability is disabled")
+	}
+	o.Expect(err).NotTo(o.HaveOccurred(), "failed to get the build config")
+	originalNodeSelector := build.Spec.BuildOverrides.NodeSelector
+
+	g.By("Setting a node selector in the build overrides")
+	nodeSelector := map[string]string{labelKey: labelValue}
+	for key, value := range originalNodeSelector {
+		nodeSelector[key] = value
+	}
+	err = updateBuildNodeSelector(ctx, client, nodeSelector)
+	o.Expect(err).NotTo(o.HaveOccurred(), "failed to set the node selector of the build overrides")
+	g.DeferCleanup(func(ctx context.Context) {
+		if framework.CleanupSkipped(t, "restore the original node selector %v of the build overrides", originalNodeSelector) {
+			return
+		}
+		g.By("Restoring the original node selector")
+		if err := updateBuildNodeSelector(ctx, client, originalNodeSelector); err != nil {
+			g.GinkgoLogr.Error(err, "failed to restore the original node selector")
+			return
+		}
+		o.Eventually(observedConfigFunc(client)).WithContext(ctx).WithTimeout(2 * time.Minute).WithPolling(5 * time.Second).ShouldNot(
+			framework.HaveObservedConfigValue("build.buildOverrides.nodeSelector."+labelKey, labelValue))
+		framework.MustEnsureClusterOperatorStatusIsSet(ctx, t, client)
+	})
+
+	g.By("Verifying the node selector in observed config")
+	o.Eventually(observedConfigFunc(client)).WithContext(ctx).WithTimeout(2*time.Minute).WithPolling(5*time.Second).Should(
+		framework.HaveObservedConfigValue("build.buildOverrides.nodeSelector."+labelKey, labelValue),
+		"the node selector of the build overrides was not propagated to OpenShift Controller Manager observed config")
+}
+
+// updateBuildNodeSelector sets the node selector of the build overrides, retrying on conflicts.
+func updateBuildNodeSelector(ctx context.Context, client *framework.Clientset, nodeSelector map[string]string) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		build, err := client.Builds().Get(ctx, "cluster", metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		build.Spec.BuildOverrides.NodeSelector = nodeSelector
+		_, err = client.Builds().Update(ctx, build, metav1.UpdateOptions{})
+		return err
+	})
+}
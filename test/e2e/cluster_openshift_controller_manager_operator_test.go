@@ -21,7 +21,7 @@ func TestClusterOpenshiftControllerManagerOperator(t *testing.T) {
 	ctx := context.Background()
 	client := framework.MustNewClientset(t, nil)
 	// make sure the operator is fully up
-	framework.MustEnsureClusterOperatorStatusIsSet(t, client)
+	framework.MustEnsureClusterOperatorStatusIsSet(ctx, t, client)
 	// make sure the global trust bundle is injected
 	globalCAConfigMap, err := client.ConfigMaps(util.TargetNamespace).Get(ctx, "openshift-global-ca", metav1.GetOptions{})
 	if err != nil {
@@ -42,7 +42,11 @@ func TestClusterBuildConfigObservation(t *testing.T) {
 	ctx := context.Background()
 	client := framework.MustNewClientset(t, nil)
 	// make sure the operator is fully up
-	framework.MustEnsureClusterOperatorStatusIsSet(t, client)
+	framework.MustEnsureClusterOperatorStatusIsSet(ctx, t, client)
+	// make sure the build controllers are running
+	if err := framework.WaitForOperandReady(ctx, t, client, 1, 5*time.Minute); err != nil {
+		t.Fatal(err)
+	}
 
 	// The CVO should be creating these cluster configuration objects on cluster install
 	var buildConfig *configv1.Build
@@ -109,7 +113,7 @@ func TestClusterImageConfigObservation(t *testing.T) {
 	ctx := context.Background()
 	client := framework.MustNewClientset(t, nil)
 	// make sure the operator is fully up
-	framework.MustEnsureClusterOperatorStatusIsSet(t, client)
+	framework.MustEnsureClusterOperatorStatusIsSet(ctx, t, client)
 
 	err := wait.PollImmediate(5*time.Second, 1*time.Minute, func() (bool, error) {
 		cfg, err := client.OpenShiftControllerManagers().Get(ctx, "cluster", metav1.GetOptions{})
@@ -0,0 +1,75 @@
+package e2e
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	g "github.com/onsi/ginkgo/v2"
+	o "github.com/onsi/gomega"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openshift/cluster-openshift-controller-manager-operator/test/framework"
+)
+
+var _ = g.Describe("[sig-openshift-controller-manager] Cluster Proxy", func() {
+	g.It("[Operator][Build][Disruptive] should apply the no-proxy list of the cluster proxy to the git source of builds", func(ctx context.Context) {
+		testClusterProxyReachesBuildPods(ctx, g.GinkgoTB())
+	})
+})
+
+func testClusterProxyReachesBuildPods(ctx context.Context, t testing.TB) {
+	const noProxy = ".e2e-proxy.example.com"
+	client := framework.MustNewClientset(t, nil)
+
+	// Make sure the operator is fully up
+	framework.MustEnsureClusterOperatorStatusIsSet(ctx, t, client)
+
+	build, err := client.Builds().Get(ctx, "cluster", metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		g.Skip("builds.config.openshift.io/cluster does not exist, the Build capability is disabled")
+	}
+	o.Expect(err).NotTo(o.HaveOccurred(), "failed to get the build config")
+	if gitProxy := build.Spec.BuildDefaults.GitProxy; gitProxy != nil && len(gitProxy.NoProxy) > 0 {
+		g.Skip("the build config sets a git no-proxy list, it wins over the one of the cluster proxy")
+	}
+	proxy, err := client.Proxies().Get(ctx, "cluster", metav1.GetOptions{})
+	o.Expect(err).NotTo(o.HaveOccurred(), "failed to get the cluster proxy")
+	if len(proxy.Spec.HTTPProxy) > 0 || len(proxy.Spec.HTTPSProxy) > 0 {
+		// the no-proxy list of a cluster behind a proxy is rolled out to every node
+		g.Skip("the cluster is behind a proxy, changing its no-proxy list would roll out the nodes")
+	}
+
+	// Registered before the proxy is changed, the cleanup runs after the proxy was restored
+	g.DeferCleanup(func(ctx context.Context) {
+		framework.AssertNotDegradedFor(ctx, t, client, time.Minute)
+	})
+
+	g.By("Setting a no-proxy list on the cluster proxy")
+	framework.WithClusterProxyNoProxy(ctx, t, client, noProxy, func(ctx context.Context) {
+		g.By("Verifying the git no-proxy list in the operand config")
+		o.Eventually(func(ctx context.Context) ([]byte, error) {
+			return framework.GetOperandConfig(ctx, t, client)
+		}).WithContext(ctx).WithTimeout(2*time.Minute).WithPolling(5*time.Second).Should(
+			framework.HaveObservedConfigValue("build.buildDefaults.gitNoProxy", noProxy),
+			"the no-proxy list of the cluster proxy did not reach the config of OpenShift Controller Manager")
+		// The build controllers must run with the new config
+		err := framework.WaitForOperandReady(ctx, t, client, 1, 5*time.Minute)
+		o.Expect(err).NotTo(o.HaveOccurred())
+
+		g.By("Starting a build in a test namespace")
+		namespace := framework.CreateTestNamespace(ctx, t, client)
+		// the build fails cloning, its pod is created with the build defaults applied before that
+		buildName, err := framework.CreateDockerBuild(ctx, t, client, namespace, "https://git.e2e-proxy.invalid/repo.git")
+		o.Expect(err).NotTo(o.HaveOccurred())
+
+		g.By("Verifying the git no-proxy list of the build pod")
+		podBuild, err := framework.GetBuildPodBuild(ctx, t, client, namespace, buildName)
+		o.Expect(err).NotTo(o.HaveOccurred())
+		o.Expect(podBuild.Spec.Source.Git).NotTo(o.BeNil(), "the build of the pod has no git source")
+		o.Expect(podBuild.Spec.Source.Git.NoProxy).To(o.HaveValue(o.Equal(noProxy)),
+			"the no-proxy list of the cluster proxy was not applied to the build pod")
+	})
+}
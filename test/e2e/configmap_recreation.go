@@ -0,0 +1,89 @@
+package e2e
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	g "github.com/onsi/ginkgo/v2"
+	o "github.com/onsi/gomega"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	"github.com/openshift/cluster-openshift-controller-manager-operator/pkg/util"
+	"github.com/openshift/cluster-openshift-controller-manager-operator/test/framework"
+)
+
+var _ = g.Describe("[sig-openshift-controller-manager] Operand ConfigMaps", func() {
+	g.It("[Operator][Disruptive] should recreate a deleted operand configmap", func(ctx context.Context) {
+		testDeletedConfigMapIsRecreated(ctx, g.GinkgoTB())
+	})
+})
+
+func testDeletedConfigMapIsRecreated(ctx context.Context, t testing.TB) {
+	const (
+		configMapName = "openshift-global-ca"
+		// injectLabel is the label the operator sets for the cluster trust bundle to be injected
+		injectLabel = "config.openshift.io/inject-trusted-cabundle"
+	)
+	client := framework.MustNewClientset(t, nil)
+
+	// Make sure the operator is fully up
+	framework.MustEnsureClusterOperatorStatusIsSet(ctx, t, client)
+
+	original, err := client.ConfigMaps(util.TargetNamespace).Get(ctx, configMapName, metav1.GetOptions{})
+	o.Expect(err).NotTo(o.HaveOccurred(), "failed to get configmap/%s -n %s", configMapName, util.TargetNamespace)
+	o.Expect(original.Labels).To(o.HaveKeyWithValue(injectLabel, "true"))
+
+	g.By("Deleting the openshift-global-ca configmap of the operand")
+	err = client.ConfigMaps(util.TargetNamespace).Delete(ctx, configMapName, metav1.DeleteOptions{})
+	o.Expect(err).NotTo(o.HaveOccurred(), "failed to delete configmap/%s -n %s", configMapName, util.TargetNamespace)
+	g.DeferCleanup(func(ctx context.Context) {
+		// put the configmap back if the operator did not, so that the operand can still roll out
+		_, err := client.ConfigMaps(util.TargetNamespace).Get(ctx, configMapName, metav1.GetOptions{})
+		if !errors.IsNotFound(err) {
+			return
+		}
+		if framework.CleanupSkipped(t, "recreate configmap/%s -n %s the operator did not recreate", configMapName, util.TargetNamespace) {
+			return
+		}
+		g.By("Restoring the openshift-global-ca configmap the operator did not recreate")
+		restored := original.DeepCopy()
+		restored.ObjectMeta = metav1.ObjectMeta{
+			Name:        original.Name,
+			Namespace:   original.Namespace,
+			Labels:      original.Labels,
+			Annotations: original.Annotations,
+		}
+		if _, err := client.ConfigMaps(util.TargetNamespace).Create(ctx, restored, metav1.CreateOptions{}); err != nil && !errors.IsAlreadyExists(err) {
+			g.GinkgoLogr.Error(err, "failed to restore the configmap", "configmap", configMapName, "namespace", util.TargetNamespace)
+		}
+	})
+
+	g.By("Waiting for the operator to recreate the configmap")
+	err = wait.PollUntilContextTimeout(ctx, 5*time.Second, 5*time.Minute, true, func(ctx context.Context) (bool, error) {
+		configMap, err := client.ConfigMaps(util.TargetNamespace).Get(ctx, configMapName, metav1.GetOptions{})
+		if errors.IsNotFound(err) {
+			return false, nil
+		}
+		if err != nil {
+			g.GinkgoLogr.Error(err, "error getting the configmap", "configmap", configMapName)
+			return false, nil
+		}
+		if configMap.UID == original.UID {
+			// the deletion has not been observed yet
+			return false, nil
+		}
+		return configMap.Labels[injectLabel] == "true", nil
+	})
+	o.Expect(err).NotTo(o.HaveOccurred(), "the operator did not recreate configmap/%s -n %s with the %s label", configMapName, util.TargetNamespace, injectLabel)
+
+	// The recreated configmap rolls the operand out, it must become available again
+	g.By("Verifying that the operand becomes available again")
+	err = framework.WaitForOperandReady(ctx, t, client, 1, 5*time.Minute)
+	o.Expect(err).NotTo(o.HaveOccurred(), "the operand is not ready after the configmap was recreated")
+	framework.MustEnsureClusterOperatorStatusIsSet(ctx, t, client)
+	framework.AssertNotDegradedFor(ctx, t, client, time.Minute)
+}
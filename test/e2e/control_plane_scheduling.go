@@ -0,0 +1,30 @@
+package e2e
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	g "github.com/onsi/ginkgo/v2"
+
+	"github.com/openshift/cluster-openshift-controller-manager-operator/test/framework"
+)
+
+var _ = g.Describe("[sig-openshift-controller-manager] Control Plane Scheduling", func() {
+	g.It("[Operator][Parallel] should schedule the operand pods on the control-plane nodes tolerating their taints", func(ctx context.Context) {
+		testOperandControlPlaneScheduling(ctx, g.GinkgoTB())
+	})
+})
+
+func testOperandControlPlaneScheduling(ctx context.Context, t testing.TB) {
+	client := framework.MustNewClientset(t, nil)
+
+	// Make sure the operator is fully up and the operand has pods to check
+	framework.MustEnsureClusterOperatorStatusIsSet(ctx, t, client)
+	if err := framework.WaitForOperandReady(ctx, t, client, 1, 5*time.Minute); err != nil {
+		t.Fatal(err)
+	}
+
+	g.By("Verifying the tolerations and the nodes of the operand pods")
+	framework.AssertOperandControlPlaneScheduling(ctx, t, client)
+}
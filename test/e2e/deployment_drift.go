@@ -0,0 +1,71 @@
+package e2e
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	g "github.com/onsi/ginkgo/v2"
+	o "github.com/onsi/gomega"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	"github.com/openshift/cluster-openshift-controller-manager-operator/pkg/util"
+	"github.com/openshift/cluster-openshift-controller-manager-operator/test/framework"
+)
+
+var _ = g.Describe("[sig-openshift-controller-manager] Operand Deployment", func() {
+	g.It("[Operator][Disruptive] should revert out-of-band edits to the controller-manager deployment", func(ctx context.Context) {
+		testDeploymentDriftIsReverted(ctx, g.GinkgoTB())
+	})
+})
+
+func testDeploymentDriftIsReverted(ctx context.Context, t testing.TB) {
+	const (
+		deploymentName = "controller-manager"
+		driftEnvName   = "OUT_OF_BAND_EDIT"
+	)
+	client := framework.MustNewClientset(t, nil)
+
+	// Make sure the operator is fully up
+	framework.MustEnsureClusterOperatorStatusIsSet(ctx, t, client)
+
+	g.By("Adding an env var to the controller-manager deployment behind the operator's back")
+	patch := fmt.Sprintf(`[{"op": "add", "path": "/spec/template/spec/containers/0/env/-", "value": {"name": %q, "value": "true"}}]`, driftEnvName)
+	_, err := client.Deployments(util.TargetNamespace).Patch(ctx, deploymentName, types.JSONPatchType, []byte(patch), metav1.PatchOptions{})
+	o.Expect(err).NotTo(o.HaveOccurred(), "failed to patch the controller-manager deployment")
+
+	g.By("Waiting for the operator to revert the edit")
+	err = wait.PollUntilContextTimeout(ctx, 5*time.Second, 5*time.Minute, true, func(ctx context.Context) (bool, error) {
+		deployment, err := client.Deployments(util.TargetNamespace).Get(ctx, deploymentName, metav1.GetOptions{})
+		if errors.IsNotFound(err) {
+			return false, nil
+		}
+		if err != nil {
+			g.GinkgoLogr.Error(err, "error getting the controller-manager deployment")
+			return false, nil
+		}
+		for _, c := range deployment.Spec.Template.Spec.Containers {
+			for _, env := range c.Env {
+				if env.Name == driftEnvName {
+					return false, nil
+				}
+			}
+		}
+		return true, nil
+	})
+	o.Expect(err).NotTo(o.HaveOccurred(), "the operator did not revert the out-of-band edit to deployment/%s -n %s", deploymentName, util.TargetNamespace)
+
+	// Let the reverted rollout settle before the next serial test
+	framework.MustEnsureClusterOperatorStatusIsSet(ctx, t, client)
+	framework.AssertNotDegradedFor(ctx, t, client, time.Minute)
+
+	// The pods of the reverted rollout must run the image the operator deploys
+	expectedImage, err := framework.ExpectedOperandImage(ctx, client)
+	o.Expect(err).NotTo(o.HaveOccurred())
+	framework.AssertOperandImage(ctx, t, client, expectedImage, framework.ImageMatchExact)
+}
@@ -0,0 +1,27 @@
+package e2e
+
+import (
+	"context"
+
+	g "github.com/onsi/ginkgo/v2"
+
+	"github.com/openshift/cluster-openshift-controller-manager-operator/test/framework"
+)
+
+// Every spec captures a diagnostics bundle to the CI artifacts when it failed. The cleanup is registered
+// first so that it runs last, after the specs restored the cluster config, and also catches a failing
+// restore.
+var _ = g.BeforeEach(func() {
+	g.DeferCleanup(func(ctx context.Context) {
+		artifactDir := framework.ArtifactDir()
+		if !g.CurrentSpecReport().Failed() || len(artifactDir) == 0 {
+			return
+		}
+		client, err := framework.NewClientset(nil)
+		if err != nil {
+			g.GinkgoLogr.Error(err, "unable to capture the diagnostics bundle")
+			return
+		}
+		framework.CaptureBundle(ctx, g.GinkgoTB(), client, artifactDir)
+	})
+})
@@ -1,11 +1,14 @@
 package e2e
 
 import (
+	"flag"
 	"os"
 	"testing"
 
 	"github.com/onsi/ginkgo/v2"
 	"github.com/onsi/gomega"
+
+	"github.com/openshift/cluster-openshift-controller-manager-operator/test/framework"
 )
 
 func TestE2E(t *testing.T) {
@@ -14,5 +17,6 @@ func TestE2E(t *testing.T) {
 }
 
 func TestMain(m *testing.M) {
+	flag.StringVar(&framework.Kubeconfig, "kubeconfig", framework.Kubeconfig, "Path of the kubeconfig file of the cluster to test, overrides the KUBECONFIG environment variable.")
 	os.Exit(m.Run())
 }
@@ -0,0 +1,84 @@
+package e2e
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	g "github.com/onsi/ginkgo/v2"
+	o "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+
+	"github.com/openshift/cluster-openshift-controller-manager-operator/test/framework"
+)
+
+var _ = g.Describe("[sig-openshift-controller-manager] Operand Stability", func() {
+	g.It("[Operator][Serial] should not restart OpenShift Controller Manager on a no-op update of the APIServer config", func(ctx context.Context) {
+		testNoOpAPIServerUpdateDoesNotRestartOperand(ctx, g.GinkgoTB())
+	})
+})
+
+func testNoOpAPIServerUpdateDoesNotRestartOperand(ctx context.Context, t testing.TB) {
+	// noOpAnnotation changes the metadata of the APIServer config only, its spec stays the same
+	const noOpAnnotation = "e2e.openshift-controller-manager.openshift.io/no-op-update"
+	client := framework.MustNewClientset(t, nil)
+
+	// Make sure the operator is fully up and the operand is not rolling out
+	framework.MustEnsureClusterOperatorStatusIsSet(ctx, t, client)
+	err := framework.WaitForOperandReady(ctx, t, client, 1, 5*time.Minute)
+	o.Expect(err).NotTo(o.HaveOccurred())
+	before, err := framework.GetOperandSnapshot(ctx, client)
+	o.Expect(err).NotTo(o.HaveOccurred())
+
+	g.By("Updating the APIServer config without changing its spec")
+	apiServer, err := client.APIServers().Get(ctx, "cluster", metav1.GetOptions{})
+	o.Expect(err).NotTo(o.HaveOccurred(), "failed to get the APIServer config")
+	originalResourceVersion := apiServer.ResourceVersion
+	resourceVersion, err := setAPIServerAnnotation(ctx, client, noOpAnnotation, time.Now().UTC().Format(time.RFC3339Nano))
+	o.Expect(err).NotTo(o.HaveOccurred(), "failed to update the APIServer config")
+	o.Expect(resourceVersion).NotTo(o.Equal(originalResourceVersion), "the no-op update did not bump the resourceVersion of the APIServer config")
+	g.DeferCleanup(func(ctx context.Context) {
+		if framework.CleanupSkipped(t, "remove the annotation %s of the APIServer config", noOpAnnotation) {
+			return
+		}
+		g.By("Removing the no-op annotation of the APIServer config")
+		if _, err := setAPIServerAnnotation(ctx, client, noOpAnnotation, ""); err != nil {
+			g.GinkgoLogr.Error(err, "failed to remove the no-op annotation of the APIServer config")
+		}
+	})
+
+	g.By("Verifying that the operand does not roll out or restart")
+	framework.AssertOperandStableFor(ctx, t, client, before, 2*time.Minute)
+	err = framework.WaitForOperandReady(ctx, t, client, 1, time.Minute)
+	o.Expect(err).NotTo(o.HaveOccurred(), "the operand is not ready after the no-op update")
+	framework.AssertOperandHealthz(ctx, t, client)
+}
+
+// setAPIServerAnnotation sets the annotation of the APIServer config, or removes it for an empty value,
+// retrying on conflicts. It returns the resourceVersion of the updated APIServer config.
+func setAPIServerAnnotation(ctx context.Context, client *framework.Clientset, key, value string) (string, error) {
+	var resourceVersion string
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		apiServer, err := client.APIServers().Get(ctx, "cluster", metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		if len(value) == 0 {
+			delete(apiServer.Annotations, key)
+		} else {
+			if apiServer.Annotations == nil {
+				apiServer.Annotations = map[string]string{}
+			}
+			apiServer.Annotations[key] = value
+		}
+		updated, err := client.APIServers().Update(ctx, apiServer, metav1.UpdateOptions{})
+		if err != nil {
+			return err
+		}
+		resourceVersion = updated.ResourceVersion
+		return nil
+	})
+	return resourceVersion, err
+}
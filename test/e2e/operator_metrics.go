@@ -0,0 +1,40 @@
+package e2e
+
+import (
+	"context"
+	"testing"
+
+	g "github.com/onsi/ginkgo/v2"
+
+	"github.com/openshift/cluster-openshift-controller-manager-operator/test/framework"
+)
+
+var _ = g.Describe("[sig-openshift-controller-manager] Operator Metrics", func() {
+	g.It("[Operator][Serial] should expose the reconcile duration of the operator controllers", func(ctx context.Context) {
+		testReconcileDurationMetricIsExposed(ctx, g.GinkgoTB())
+	})
+
+	g.It("[Operator][Serial] should expose the workqueue depth of the operator controllers", func(ctx context.Context) {
+		testWorkqueueDepthMetricIsExposed(ctx, g.GinkgoTB())
+	})
+})
+
+func testReconcileDurationMetricIsExposed(ctx context.Context, t testing.TB) {
+	client := framework.MustNewClientset(t, nil)
+
+	// Make sure the operator is fully up, its controllers have synced by then
+	framework.MustEnsureClusterOperatorStatusIsSet(ctx, t, client)
+
+	g.By("Scraping the operator metrics endpoint")
+	framework.AssertMetricExposed(ctx, t, client, "openshift_controller_manager_operator_reconcile_duration_seconds")
+}
+
+func testWorkqueueDepthMetricIsExposed(ctx context.Context, t testing.TB) {
+	client := framework.MustNewClientset(t, nil)
+
+	// Make sure the operator is fully up, its controllers have created their queues by then
+	framework.MustEnsureClusterOperatorStatusIsSet(ctx, t, client)
+
+	g.By("Scraping the operator metrics endpoint")
+	framework.AssertMetricExposed(ctx, t, client, "workqueue_depth")
+}
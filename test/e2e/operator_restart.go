@@ -0,0 +1,51 @@
+package e2e
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	g "github.com/onsi/ginkgo/v2"
+	o "github.com/onsi/gomega"
+
+	"github.com/openshift/cluster-openshift-controller-manager-operator/test/framework"
+)
+
+var _ = g.Describe("[sig-openshift-controller-manager] Operator Restart", func() {
+	g.It("[Operator][Disruptive] should regenerate the same observed config after the operator restarts", func(ctx context.Context) {
+		testObservedConfigRegeneratedAfterRestart(ctx, g.GinkgoTB())
+	})
+})
+
+func testObservedConfigRegeneratedAfterRestart(ctx context.Context, t testing.TB) {
+	client := framework.MustNewClientset(t, nil)
+
+	// Make sure the operator is fully up and the operand is not rolling out
+	framework.MustEnsureClusterOperatorStatusIsSet(ctx, t, client)
+	framework.AssertProgressingClearsWithin(ctx, t, client, 10*time.Minute)
+	err := framework.WaitForOperandReady(ctx, t, client, 1, 5*time.Minute)
+	o.Expect(err).NotTo(o.HaveOccurred())
+
+	before, err := framework.GetOperandSnapshot(ctx, client)
+	o.Expect(err).NotTo(o.HaveOccurred())
+	observedConfig, err := framework.GetObservedConfigRaw(ctx, t, client)
+	o.Expect(err).NotTo(o.HaveOccurred())
+	o.Expect(observedConfig).NotTo(o.BeEmpty(), "the operator has not observed any config yet")
+
+	g.By("Restarting the operator")
+	restore := framework.WithOperatorScaledDown(ctx, t, client)
+	restore()
+
+	g.By("Verifying that the restarted operator settles")
+	framework.AssertProgressingClearsWithin(ctx, t, client, 5*time.Minute)
+
+	g.By("Verifying that the observed config converges to the one before the restart")
+	err = framework.WaitForObservedConfig(ctx, t, client, observedConfig, 2*time.Minute)
+	o.Expect(err).NotTo(o.HaveOccurred())
+
+	g.By("Verifying that the restart alone does not roll the operand out")
+	framework.AssertOperandStableFor(ctx, t, client, before, 2*time.Minute)
+	// a drift the operand config has not picked up yet does not show in the generation of the operand
+	err = framework.WaitForObservedConfig(ctx, t, client, observedConfig, time.Minute)
+	o.Expect(err).NotTo(o.HaveOccurred())
+}
@@ -0,0 +1,29 @@
+package e2e
+
+import (
+	"context"
+	"testing"
+
+	g "github.com/onsi/ginkgo/v2"
+
+	"github.com/openshift/cluster-openshift-controller-manager-operator/pkg/util"
+	"github.com/openshift/cluster-openshift-controller-manager-operator/test/framework"
+)
+
+var _ = g.Describe("[sig-openshift-controller-manager] Pod Security", func() {
+	g.It("[Operator][Parallel] should label the operand namespaces with the privileged pod security level", func(ctx context.Context) {
+		testOperandNamespacesPodSecurityLabels(ctx, g.GinkgoTB())
+	})
+})
+
+func testOperandNamespacesPodSecurityLabels(ctx context.Context, t testing.TB) {
+	client := framework.MustNewClientset(t, nil)
+
+	// Make sure the operator is fully up, it has applied the operand namespaces by then
+	framework.MustEnsureClusterOperatorStatusIsSet(ctx, t, client)
+
+	for _, namespace := range []string{util.TargetNamespace, util.RouteControllerTargetNamespace} {
+		g.By("Verifying the pod security labels of namespace " + namespace)
+		framework.AssertNamespacePSALabels(ctx, t, client, namespace, framework.PodSecurityPrivileged)
+	}
+}
@@ -0,0 +1,33 @@
+package e2e
+
+import (
+	"context"
+	"testing"
+
+	g "github.com/onsi/ginkgo/v2"
+
+	configv1 "github.com/openshift/api/config/v1"
+	"github.com/openshift/cluster-openshift-controller-manager-operator/pkg/util"
+	"github.com/openshift/cluster-openshift-controller-manager-operator/test/framework"
+)
+
+var _ = g.Describe("[sig-openshift-controller-manager] Related Objects", func() {
+	g.It("[Operator][Parallel] should list the operator config and the operand namespaces as related objects of the ClusterOperator", func(ctx context.Context) {
+		testRelatedObjectsArePopulated(ctx, g.GinkgoTB())
+	})
+})
+
+func testRelatedObjectsArePopulated(ctx context.Context, t testing.TB) {
+	client := framework.MustNewClientset(t, nil)
+
+	// Make sure the operator is fully up, its status controller has synced by then
+	framework.MustEnsureClusterOperatorStatusIsSet(ctx, t, client)
+
+	g.By("Verifying that must-gather finds the operator config and the operand namespaces")
+	framework.AssertRelatedObjects(ctx, t, client,
+		configv1.ObjectReference{Group: "operator.openshift.io", Resource: "openshiftcontrollermanagers", Name: "cluster"},
+		configv1.ObjectReference{Resource: "namespaces", Name: util.OperatorNamespace},
+		configv1.ObjectReference{Resource: "namespaces", Name: util.TargetNamespace},
+		configv1.ObjectReference{Resource: "namespaces", Name: util.RouteControllerTargetNamespace},
+	)
+}
@@ -0,0 +1,31 @@
+package e2e
+
+import (
+	"context"
+	"testing"
+
+	g "github.com/onsi/ginkgo/v2"
+
+	"github.com/openshift/cluster-openshift-controller-manager-operator/pkg/util"
+	"github.com/openshift/cluster-openshift-controller-manager-operator/test/framework"
+)
+
+var _ = g.Describe("[sig-openshift-controller-manager] Service Monitors", func() {
+	g.It("[Operator][Parallel] should have ServiceMonitors scraping the metrics services of the operator and the operands", func(ctx context.Context) {
+		testServiceMonitorsMatchMetricsServices(ctx, g.GinkgoTB())
+	})
+})
+
+func testServiceMonitorsMatchMetricsServices(ctx context.Context, t testing.TB) {
+	client := framework.MustNewClientset(t, nil)
+
+	// Make sure the operator is fully up, the operand services exist by then
+	framework.MustEnsureClusterOperatorStatusIsSet(ctx, t, client)
+
+	g.By("Verifying the ServiceMonitor of the operator")
+	framework.AssertServiceMonitor(ctx, t, client, util.OperatorNamespace, "openshift-controller-manager-operator")
+
+	g.By("Verifying the ServiceMonitors of the operands")
+	framework.AssertServiceMonitor(ctx, t, client, util.TargetNamespace, "openshift-controller-manager")
+	framework.AssertServiceMonitor(ctx, t, client, util.RouteControllerTargetNamespace, "openshift-route-controller-manager")
+}
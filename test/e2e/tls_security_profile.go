@@ -2,8 +2,7 @@ package e2e
 
 import (
 	"context"
-	"encoding/json"
-	"strings"
+	"fmt"
 	"testing"
 	"time"
 
@@ -11,282 +10,213 @@ import (
 	o "github.com/onsi/gomega"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
-	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/runtime"
 
 	configv1 "github.com/openshift/api/config/v1"
+	"github.com/openshift/cluster-openshift-controller-manager-operator/pkg/util"
 	"github.com/openshift/cluster-openshift-controller-manager-operator/test/framework"
+	"github.com/openshift/library-go/pkg/crypto"
 )
 
 var _ = g.Describe("[sig-openshift-controller-manager] TLS Security Profile", func() {
 	g.It("[Operator][TLS][Serial] should propagate Modern TLS profile from APIServer to OpenShift Controller Manager", func(ctx context.Context) {
 		testTLSSecurityProfilePropagation(ctx, g.GinkgoTB())
 	})
+
+	g.It("[Operator][TLS][Serial] should preserve the cipher order of the Intermediate TLS profile in OpenShift Controller Manager", func(ctx context.Context) {
+		testTLSSecurityProfileCipherOrder(ctx, g.GinkgoTB())
+	})
+
+	g.It("[Operator][TLS][Serial] should converge to the last TLS profile set back-to-back in OpenShift Controller Manager", func(ctx context.Context) {
+		testTLSSecurityProfileBackToBack(ctx, g.GinkgoTB())
+	})
+
+	g.It("[Operator][TLS][Serial] should degrade with TLSConfigInvalid and keep the observed config on an unknown cipher of a Custom TLS profile", func(ctx context.Context) {
+		testTLSSecurityProfileInvalidCustomCipher(ctx, g.GinkgoTB())
+	})
+
+	g.It("[Operator][TLS][Serial] should propagate a TLS profile change to the observed config within the SLO", func(ctx context.Context) {
+		testTLSSecurityProfilePropagationLatency(ctx, g.GinkgoTB())
+	})
 })
 
 func testTLSSecurityProfilePropagation(ctx context.Context, t testing.TB) {
 	client := framework.MustNewClientset(t, nil)
-
 	// Make sure the operator is fully up
-	framework.MustEnsureClusterOperatorStatusIsSet(t, client)
-
-	// Get the current APIServer config
-	apiServer, err := client.APIServers().Get(ctx, "cluster", metav1.GetOptions{})
-	o.Expect(err).NotTo(o.HaveOccurred(), "failed to get APIServer config")
+	framework.MustEnsureClusterOperatorStatusIsSet(ctx, t, client)
 
-	// Save the original TLS profile for cleanup
-	originalTLSProfile := apiServer.Spec.TLSSecurityProfile
+	before := framework.SnapshotOperatorStatus(ctx, t, client)
 
-	// Modify the TLS security profile to use Modern profile
 	// Modern profile uses TLS 1.3 with modern cipher suites
-	apiServer.Spec.TLSSecurityProfile = &configv1.TLSSecurityProfile{
-		Type:   configv1.TLSProfileModernType,
-		Modern: &configv1.ModernTLSProfile{},
-	}
-
-	_, err = client.APIServers().Update(ctx, apiServer, metav1.UpdateOptions{})
-	o.Expect(err).NotTo(o.HaveOccurred(), "failed to update APIServer TLS profile to Modern")
-
-	// Cleanup: restore original TLS profile and verify restoration
-	g.DeferCleanup(func(ctx context.Context) {
-		g.By("Restoring original TLS profile")
-		apiServer, err := client.APIServers().Get(ctx, "cluster", metav1.GetOptions{})
-		if err != nil {
-			g.GinkgoLogr.Error(err, "failed to get APIServer for cleanup")
-			return
-		}
-		apiServer.Spec.TLSSecurityProfile = originalTLSProfile
-		if _, err := client.APIServers().Update(ctx, apiServer, metav1.UpdateOptions{}); err != nil {
-			g.GinkgoLogr.Error(err, "failed to restore original TLS profile")
-			return
+	g.By("Setting the Modern TLS profile and waiting for the operator to reconcile it")
+	modern := &configv1.TLSSecurityProfile{Type: configv1.TLSProfileModernType, Modern: &configv1.ModernTLSProfile{}}
+	framework.WithAPIServerTLSProfile(ctx, t, client, modern, func(ctx context.Context) {
+		g.By("Verifying TLS config in observed config")
+		// Modern profile should have exactly these TLS 1.3 cipher suites
+		expectedCiphers := []string{
+			"TLS_AES_128_GCM_SHA256",
+			"TLS_AES_256_GCM_SHA384",
+			"TLS_CHACHA20_POLY1305_SHA256",
 		}
+		o.Eventually(observedConfigFunc(client)).WithContext(ctx).WithTimeout(2*time.Minute).WithPolling(5*time.Second).Should(o.And(
+			// Modern profile should use VersionTLS13 (exact string match)
+			framework.HaveObservedConfigValue("servingInfo.minTLSVersion", "VersionTLS13"),
+			framework.HaveObservedConfigValue("servingInfo.cipherSuites", o.ContainElements(expectedCiphers)),
+		), "Modern TLS security profile from APIServer was not propagated to OpenShift Controller Manager observed config")
 
-		// Wait for operator to reconcile the restoration
-		g.By("Waiting for operator to reconcile TLS profile restoration")
-		err = wait.PollUntilContextTimeout(ctx, 10*time.Second, 10*time.Minute, true, func(ctx context.Context) (bool, error) {
-			co, err := client.ClusterOperators().Get(ctx, "openshift-controller-manager", metav1.GetOptions{})
-			if err != nil {
-				g.GinkgoLogr.Error(err, "error getting clusteroperator during cleanup")
-				return false, nil
-			}
-
-			isAvailable := false
-			isProgressing := true
-
-			for _, c := range co.Status.Conditions {
-				if c.Type == configv1.OperatorAvailable && c.Status == configv1.ConditionTrue {
-					isAvailable = true
-				}
-				if c.Type == configv1.OperatorProgressing && c.Status == configv1.ConditionFalse {
-					isProgressing = false
-				}
-			}
+		g.By("Verifying the operand refuses TLS 1.2 handshakes and completes TLS 1.3 ones")
+		framework.AssertOperandTLSVersion(ctx, t, client, "VersionTLS13")
 
-			if isAvailable && !isProgressing {
-				g.GinkgoLogr.Info("Operator reconciliation after restoration complete")
-				return true, nil
-			}
-
-			return false, nil
-		})
-		if err != nil {
-			g.GinkgoLogr.Error(err, "operator did not complete reconciliation after restoration")
-			return
+		g.By("Verifying the TLS profile change moved neither the versions nor the related objects")
+		after := framework.SnapshotOperatorStatus(ctx, t, client)
+		for _, change := range framework.StatusChanges(before, after) {
+			o.Expect(change.Path).To(o.HavePrefix("conditions."), "unexpected change of the ClusterOperator status:\n%s", framework.DiffStatus(before, after))
 		}
+	})
+}
 
-		// Verify TLS profile was restored (should be back to default TLS 1.2 or original setting)
-		g.By("Verifying TLS profile was restored correctly")
-		err = wait.PollUntilContextTimeout(ctx, 5*time.Second, 2*time.Minute, true, func(ctx context.Context) (bool, error) {
-			cfg, err := client.OpenShiftControllerManagers().Get(ctx, "cluster", metav1.GetOptions{})
-			if err != nil {
-				g.GinkgoLogr.Error(err, "error getting openshift controller manager config during cleanup verification")
-				return false, nil
-			}
+func testTLSSecurityProfileCipherOrder(ctx context.Context, t testing.TB) {
+	client := framework.MustNewClientset(t, nil)
 
-			observedConfig := map[string]interface{}{}
-			if err := json.Unmarshal(cfg.Spec.ObservedConfig.Raw, &observedConfig); err != nil {
-				g.GinkgoLogr.Error(err, "failed to unmarshal observed config during cleanup")
-				return false, nil
-			}
+	mustSetTLSSecurityProfile(ctx, t, client, &configv1.TLSSecurityProfile{
+		Type:         configv1.TLSProfileIntermediateType,
+		Intermediate: &configv1.IntermediateTLSProfile{},
+	})
 
-			// Check the restored TLS version
-			minTLSVersion, found, err := unstructured.NestedString(observedConfig, "servingInfo", "minTLSVersion")
-			if err != nil {
-				g.GinkgoLogr.Error(err, "error reading minTLSVersion during cleanup")
-				return false, nil
-			}
+	// The ciphers are listed in order of preference, the observed config must keep that order
+	g.By("Verifying the cipher order in observed config")
+	intermediate := configv1.TLSProfiles[configv1.TLSProfileIntermediateType]
+	expectedCiphers := crypto.OpenSSLToIANACipherSuites(intermediate.Ciphers)
+	o.Expect(expectedCiphers).NotTo(o.BeEmpty(), "the Intermediate TLS profile has no ciphers")
+	o.Eventually(observedConfigFunc(client)).WithContext(ctx).WithTimeout(2*time.Minute).WithPolling(5*time.Second).Should(o.And(
+		framework.HaveObservedConfigValue("servingInfo.minTLSVersion", string(intermediate.MinTLSVersion)),
+		framework.HaveObservedConfigValue("servingInfo.cipherSuites", expectedCiphers),
+	), "Intermediate TLS security profile ciphers were not propagated in order to OpenShift Controller Manager observed config")
+}
 
-			// If original profile was nil, expect default (typically VersionTLS12)
-			// If original profile was set, it should match
-			if originalTLSProfile == nil {
-				// Default OpenShift TLS profile is typically TLS 1.2
-				if found && minTLSVersion == "VersionTLS12" {
-					g.GinkgoLogr.Info("TLS profile restored to default", "minTLSVersion", minTLSVersion)
-					return true, nil
-				}
-				// Also accept if TLS config is removed entirely (using cluster defaults)
-				if !found || minTLSVersion == "" {
-					g.GinkgoLogr.Info("TLS profile restored to cluster defaults (no explicit TLS version)")
-					return true, nil
-				}
-			} else {
-				// If there was an original profile, verify it's not TLS 1.3 anymore
-				if found && minTLSVersion != "VersionTLS13" {
-					g.GinkgoLogr.Info("TLS profile restored from Modern", "minTLSVersion", minTLSVersion)
-					return true, nil
-				}
-			}
+func testTLSSecurityProfileBackToBack(ctx context.Context, t testing.TB) {
+	client := framework.MustNewClientset(t, nil)
 
-			g.GinkgoLogr.Info("Waiting for TLS profile restoration to propagate", "current", minTLSVersion)
-			return false, nil
-		})
-		if err != nil {
-			g.GinkgoLogr.Error(err, "TLS profile was not properly restored in observed config")
-		}
+	mustSetTLSSecurityProfile(ctx, t, client, &configv1.TLSSecurityProfile{
+		Type:   configv1.TLSProfileModernType,
+		Modern: &configv1.ModernTLSProfile{},
 	})
 
-	// Wait for the operator to start progressing (detecting the change)
-	g.By("Waiting for operator to detect TLS profile change and start progressing")
-	err = wait.PollUntilContextTimeout(ctx, 5*time.Second, 5*time.Minute, true, func(ctx context.Context) (bool, error) {
-		co, err := client.ClusterOperators().Get(ctx, "openshift-controller-manager", metav1.GetOptions{})
-		if err != nil {
-			g.GinkgoLogr.Error(err, "error getting clusteroperator")
-			return false, nil
-		}
-		for _, c := range co.Status.Conditions {
-			if c.Type == configv1.OperatorProgressing && c.Status == configv1.ConditionTrue {
-				g.GinkgoLogr.Info("Operator is now progressing", "reason", c.Reason)
-				return true, nil
-			}
-		}
-		return false, nil
+	// Flip the profile again right away, without waiting for the operator to settle
+	g.By("Setting the Intermediate TLS profile right after the Modern one settled")
+	err := framework.SetAPIServerTLSProfile(ctx, client, &configv1.TLSSecurityProfile{
+		Type:         configv1.TLSProfileIntermediateType,
+		Intermediate: &configv1.IntermediateTLSProfile{},
+	})
+	o.Expect(err).NotTo(o.HaveOccurred(), "failed to update APIServer TLS profile to %s", configv1.TLSProfileIntermediateType)
+
+	g.By("Verifying the observed config converges to the Intermediate TLS profile")
+	o.Eventually(func(ctx context.Context) (string, error) {
+		minTLSVersion, _, err := framework.GetServingInfo(ctx, t, client)
+		return minTLSVersion, err
+	}).WithContext(ctx).WithTimeout(5*time.Minute).WithPolling(5*time.Second).Should(o.Equal("VersionTLS12"),
+		"OpenShift Controller Manager observed config did not converge to the last TLS security profile set")
+	framework.MustEnsureClusterOperatorStatusIsSet(ctx, t, client)
+}
+
+func testTLSSecurityProfileInvalidCustomCipher(ctx context.Context, t testing.TB) {
+	client := framework.MustNewClientset(t, nil)
+	// Make sure the operator is fully up
+	framework.MustEnsureClusterOperatorStatusIsSet(ctx, t, client)
+
+	minTLSVersion, cipherSuites, err := framework.GetServingInfo(ctx, t, client)
+	o.Expect(err).NotTo(o.HaveOccurred())
+
+	g.By("Setting a Custom TLS profile with an unknown cipher")
+	const bogusCipher = "BOGUS-CIPHER"
+	mutation := framework.APIServerTLSProfileMutation(t, client)
+	// the rejected profile is not rolled out, there is nothing to settle
+	mutation.Settle = nil
+	restore, err := mutation.Apply(ctx, t, &configv1.TLSSecurityProfile{
+		Type: configv1.TLSProfileCustomType,
+		Custom: &configv1.CustomTLSProfile{TLSProfileSpec: configv1.TLSProfileSpec{
+			MinTLSVersion: configv1.VersionTLS12,
+			Ciphers:       []string{"ECDHE-RSA-AES128-GCM-SHA256", bogusCipher},
+		}},
 	})
-	if err != nil {
-		g.GinkgoLogr.Info("Warning: operator did not start progressing within 5 minutes, continuing anyway", "error", err)
+	if restore != nil {
+		g.DeferCleanup(func(ctx context.Context) {
+			g.By("Restoring the original TLS profile")
+			o.Expect(restore(ctx)).To(o.Succeed(), "the original TLS profile was not restored")
+			framework.MustEnsureClusterOperatorStatusIsSet(ctx, t, client)
+		})
 	}
+	o.Expect(err).NotTo(o.HaveOccurred())
 
-	// Wait for the operator to finish progressing (reconciliation complete)
-	// This typically takes 12-15 minutes for TLS changes to propagate
-	g.By("Waiting for operator to complete reconciliation (may take up to 15 minutes)")
-	err = wait.PollUntilContextTimeout(ctx, 10*time.Second, 15*time.Minute, true, func(ctx context.Context) (bool, error) {
-		co, err := client.ClusterOperators().Get(ctx, "openshift-controller-manager", metav1.GetOptions{})
+	g.By("Verifying the ClusterOperator is degraded with a message naming the unknown cipher")
+	o.Eventually(func(ctx context.Context) (*configv1.ClusterOperatorStatusCondition, error) {
+		co, err := client.ClusterOperators().Get(ctx, util.ClusterOperatorName, metav1.GetOptions{})
 		if err != nil {
-			g.GinkgoLogr.Error(err, "error getting clusteroperator")
-			return false, nil
+			return nil, err
 		}
-
-		isAvailable := false
-		isProgressing := true
-		isDegraded := false
-
-		for _, c := range co.Status.Conditions {
-			if c.Type == configv1.OperatorAvailable && c.Status == configv1.ConditionTrue {
-				isAvailable = true
-			}
-			if c.Type == configv1.OperatorProgressing && c.Status == configv1.ConditionFalse {
-				isProgressing = false
+		for i := range co.Status.Conditions {
+			if co.Status.Conditions[i].Type == configv1.OperatorDegraded {
+				return &co.Status.Conditions[i], nil
 			}
-			if c.Type == configv1.OperatorDegraded && c.Status == configv1.ConditionTrue {
-				isDegraded = true
-			}
-		}
-
-		if isDegraded {
-			g.GinkgoLogr.Info("Warning: operator is degraded")
-			return false, nil
 		}
+		return nil, nil
+	}).WithContext(ctx).WithTimeout(5*time.Minute).WithPolling(5*time.Second).Should(o.And(
+		o.Not(o.BeNil()),
+		o.HaveField("Status", configv1.ConditionTrue),
+		o.HaveField("Reason", o.ContainSubstring("TLSConfigInvalid")),
+		o.HaveField("Message", o.ContainSubstring(bogusCipher)),
+	), "the operator did not report Degraded=True with reason TLSConfigInvalid for the unknown cipher")
+
+	g.By("Verifying the observed config still has the previous TLS config")
+	o.Consistently(func(ctx context.Context) ([]interface{}, error) {
+		currentMinTLSVersion, currentCipherSuites, err := framework.GetServingInfo(ctx, t, client)
+		return []interface{}{currentMinTLSVersion, currentCipherSuites}, err
+	}).WithContext(ctx).WithTimeout(30*time.Second).WithPolling(5*time.Second).Should(o.Equal([]interface{}{minTLSVersion, cipherSuites}),
+		"the Custom TLS profile with an unknown cipher was propagated to the OpenShift Controller Manager observed config")
+}
 
-		if isAvailable && !isProgressing {
-			g.GinkgoLogr.Info("Operator reconciliation complete", "available", true, "progressing", false)
-			return true, nil
-		}
+func testTLSSecurityProfilePropagationLatency(ctx context.Context, t testing.TB) {
+	client := framework.MustNewClientset(t, nil)
+	// Make sure the operator is fully up
+	framework.MustEnsureClusterOperatorStatusIsSet(ctx, t, client)
+
+	// the measured profile has to change the minimum TLS version
+	minTLSVersion, _, err := framework.GetServingInfo(ctx, t, client)
+	o.Expect(err).NotTo(o.HaveOccurred())
+	profile := &configv1.TLSSecurityProfile{Type: configv1.TLSProfileModernType, Modern: &configv1.ModernTLSProfile{}}
+	if minTLSVersion == string(configv1.VersionTLS13) {
+		profile = &configv1.TLSSecurityProfile{Type: configv1.TLSProfileIntermediateType, Intermediate: &configv1.IntermediateTLSProfile{}}
+	}
 
-		g.GinkgoLogr.Info("Operator still reconciling", "available", isAvailable, "progressing", isProgressing)
-		return false, nil
-	})
-	o.Expect(err).NotTo(o.HaveOccurred(), "operator did not complete reconciliation")
+	g.By(fmt.Sprintf("Measuring how long the %s TLS profile takes to reach the observed config", profile.Type))
+	latency := framework.AssertTLSPropagationWithin(ctx, t, client, profile, framework.DefaultTLSPropagationSLO)
+	g.AddReportEntry("TLS propagation latency", latency.String())
+}
 
-	// Now verify the TLS config was propagated to the observed config
-	g.By("Verifying TLS config in observed config")
-	err = wait.PollUntilContextTimeout(ctx, 5*time.Second, 2*time.Minute, true, func(ctx context.Context) (bool, error) {
+// observedConfigFunc returns a function polling the observed config of the operator.
+func observedConfigFunc(client *framework.Clientset) func(ctx context.Context) (runtime.RawExtension, error) {
+	return func(ctx context.Context) (runtime.RawExtension, error) {
 		cfg, err := client.OpenShiftControllerManagers().Get(ctx, "cluster", metav1.GetOptions{})
 		if err != nil {
-			g.GinkgoLogr.Error(err, "error getting openshift controller manager config")
-			return false, nil
-		}
-
-		observed := string(cfg.Spec.ObservedConfig.Raw)
-
-		// The Modern TLS profile should set minTLSVersion to TLS 1.3
-		// We're looking for the propagated TLS settings
-		hasTLSVersion := strings.Contains(observed, "\"minTLSVersion\"")
-		hasCipherSuites := strings.Contains(observed, "\"cipherSuites\"")
-
-		if !hasTLSVersion || !hasCipherSuites {
-			g.GinkgoLogr.Info("TLS config not yet observed in config", "config", observed)
-			return false, nil
-		}
-
-		g.GinkgoLogr.Info("TLS config successfully observed", "config", observed)
-
-		// Additional validation: parse the observed config
-		observedConfig := map[string]interface{}{}
-		if err := json.Unmarshal(cfg.Spec.ObservedConfig.Raw, &observedConfig); err != nil {
-			g.GinkgoLogr.Error(err, "failed to unmarshal observed config")
-			return false, nil
-		}
-
-		// Verify servingInfo exists
-		_, found, err := unstructured.NestedMap(observedConfig, "servingInfo")
-		if err != nil || !found {
-			g.GinkgoLogr.Info("servingInfo not found in observed config")
-			return false, nil
+			return runtime.RawExtension{}, err
 		}
+		return cfg.Spec.ObservedConfig, nil
+	}
+}
 
-		// Verify minTLSVersion is set to TLS 1.3 (Modern profile)
-		minTLSVersion, found, err := unstructured.NestedString(observedConfig, "servingInfo", "minTLSVersion")
-		if err != nil || !found || minTLSVersion == "" {
-			g.GinkgoLogr.Info("minTLSVersion not properly set", "found", found, "value", minTLSVersion)
-			return false, nil
-		}
-
-		// Modern profile should use VersionTLS13 (exact string match)
-		if minTLSVersion != "VersionTLS13" {
-			g.GinkgoLogr.Info("minTLSVersion not VersionTLS13 yet", "got", minTLSVersion, "expected", "VersionTLS13")
-			return false, nil
-		}
-
-		// Verify cipherSuites is set and contains the expected Modern profile ciphers
-		cipherSuites, found, err := unstructured.NestedStringSlice(observedConfig, "servingInfo", "cipherSuites")
-		if err != nil || !found || len(cipherSuites) == 0 {
-			g.GinkgoLogr.Info("cipherSuites not properly set", "found", found, "count", len(cipherSuites))
-			return false, nil
-		}
-
-		// Modern profile should have exactly these TLS 1.3 cipher suites
-		expectedCiphers := []string{
-			"TLS_AES_128_GCM_SHA256",
-			"TLS_AES_256_GCM_SHA384",
-			"TLS_CHACHA20_POLY1305_SHA256",
-		}
-
-		// Verify all expected ciphers are present
-		cipherSet := make(map[string]bool)
-		for _, cipher := range cipherSuites {
-			cipherSet[cipher] = true
-		}
-
-		for _, expected := range expectedCiphers {
-			if !cipherSet[expected] {
-				// Don't fail immediately, keep polling
-				g.GinkgoLogr.Info("expected cipher suite not found yet", "expected", expected, "got", cipherSuites)
-				return false, nil
-			}
-		}
-
-		g.GinkgoLogr.Info("Validated Modern TLS config", "minTLSVersion", minTLSVersion, "cipherSuites", cipherSuites)
-		return true, nil
-	})
-
-	o.Expect(err).NotTo(o.HaveOccurred(), "Modern TLS security profile from APIServer was not propagated to OpenShift Controller Manager observed config")
+// mustSetTLSSecurityProfile sets the TLS security profile of the APIServer config, restores the original
+// profile on cleanup and waits until the operator reconciled the change.
+func mustSetTLSSecurityProfile(ctx context.Context, t testing.TB, client *framework.Clientset, profile *configv1.TLSSecurityProfile) {
+	// Make sure the operator is fully up
+	framework.MustEnsureClusterOperatorStatusIsSet(ctx, t, client)
+
+	g.By(fmt.Sprintf("Setting the %s TLS profile and waiting for the operator to reconcile it", profile.Type))
+	restore, err := framework.APIServerTLSProfileMutation(t, client).Apply(ctx, t, profile)
+	if restore != nil {
+		g.DeferCleanup(func(ctx context.Context) {
+			g.By("Restoring the original TLS profile")
+			o.Expect(restore(ctx)).To(o.Succeed(), "the original TLS profile was not restored")
+		})
+	}
+	o.Expect(err).NotTo(o.HaveOccurred())
 }
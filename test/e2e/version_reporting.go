@@ -0,0 +1,71 @@
+package e2e
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	g "github.com/onsi/ginkgo/v2"
+	o "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	configv1 "github.com/openshift/api/config/v1"
+
+	"github.com/openshift/cluster-openshift-controller-manager-operator/pkg/util"
+	"github.com/openshift/cluster-openshift-controller-manager-operator/pkg/version"
+	"github.com/openshift/cluster-openshift-controller-manager-operator/test/framework"
+)
+
+var _ = g.Describe("[sig-openshift-controller-manager] Operator Version Reporting", func() {
+	g.It("[Upgrade][Serial] should report the version of the cluster in the ClusterOperator status", func(ctx context.Context) {
+		testVersionReporting(ctx, g.GinkgoTB())
+	})
+})
+
+// testVersionReporting records the ClusterOperator versions and, while the cluster upgrades, waits for the
+// operator to go Progressing and to converge reporting the version the cluster upgrades to. Without an
+// upgrade in progress only the invariants of the reported versions are checked.
+func testVersionReporting(ctx context.Context, t testing.TB) {
+	client := framework.MustNewClientset(t, nil)
+
+	co, err := client.ClusterOperators().Get(ctx, util.ClusterOperatorName, metav1.GetOptions{})
+	o.Expect(err).NotTo(o.HaveOccurred(), "failed to get clusteroperator/%s", util.ClusterOperatorName)
+	before := co.Status.Versions
+	g.GinkgoLogr.Info("Recorded the ClusterOperator versions", "versions", before)
+
+	cv, err := client.ClusterVersions().Get(ctx, "version", metav1.GetOptions{})
+	o.Expect(err).NotTo(o.HaveOccurred(), "failed to get clusterversion/version")
+	desiredVersion := cv.Status.Desired.Version
+
+	if framework.OperatorVersion(co) != desiredVersion {
+		g.By("Waiting for the operator to report the version the cluster upgrades to, " + desiredVersion)
+		sawProgressing := false
+		err = wait.PollUntilContextTimeout(ctx, 10*time.Second, 90*time.Minute, true, func(ctx context.Context) (bool, error) {
+			co, err = client.ClusterOperators().Get(ctx, util.ClusterOperatorName, metav1.GetOptions{})
+			if err != nil {
+				g.GinkgoLogr.Error(err, "error getting clusteroperator")
+				return false, nil
+			}
+			progressing := false
+			for _, c := range co.Status.Conditions {
+				if c.Type == configv1.OperatorProgressing && c.Status == configv1.ConditionTrue {
+					progressing = true
+				}
+			}
+			sawProgressing = sawProgressing || progressing
+			return !progressing && framework.OperatorVersion(co) == desiredVersion, nil
+		})
+		o.Expect(err).NotTo(o.HaveOccurred(), "clusteroperator/%s did not converge to version %s, reports %v", util.ClusterOperatorName, desiredVersion, co.Status.Versions)
+		o.Expect(sawProgressing).To(o.BeTrue(), "clusteroperator/%s was never Progressing while upgrading to %s", util.ClusterOperatorName, desiredVersion)
+	}
+
+	g.By("Verifying the reported versions")
+	framework.MustEnsureClusterOperatorStatusIsSet(ctx, t, client)
+	co, err = client.ClusterOperators().Get(ctx, util.ClusterOperatorName, metav1.GetOptions{})
+	o.Expect(err).NotTo(o.HaveOccurred(), "failed to get clusteroperator/%s", util.ClusterOperatorName)
+	o.Expect(framework.CheckVersionTransition(before, co.Status.Versions)).To(o.Succeed())
+	o.Expect(framework.OperatorVersion(co)).To(o.Equal(desiredVersion), "clusteroperator/%s does not report the version of the cluster", util.ClusterOperatorName)
+	o.Expect(framework.CheckVersionMatchesBuild(framework.OperatorVersion(co), version.Get())).To(o.Succeed())
+}
@@ -0,0 +1,104 @@
+package framework
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientrbacv1 "k8s.io/client-go/kubernetes/typed/rbac/v1"
+
+	configv1 "github.com/openshift/api/config/v1"
+	clientconfigv1 "github.com/openshift/client-go/config/clientset/versioned/typed/config/v1"
+)
+
+// operatorClusterRoleBindingName is the binding granting the operator cluster-admin, all of its access
+// to the API.
+const operatorClusterRoleBindingName = "system:openshift:operator:openshift-controller-manager-operator"
+
+type apiAccessClient interface {
+	clientrbacv1.ClusterRoleBindingsGetter
+	clientconfigv1.ClusterVersionsGetter
+}
+
+// WithOperatorAPIAccessRevoked is a DISRUPTIVE helper for [Disruptive] suites only. It deletes the
+// cluster role binding the operator gets all of its access to the API from, so that its requests are
+// forbidden like during a disruption of the API, while the informers keep serving what they cached. The
+// cluster version operator is told to leave the binding alone until then, it would recreate it
+// otherwise. The operator cannot update its status either meanwhile.
+//
+// The returned restore function recreates the binding and hands it back to the cluster version
+// operator. It is also registered as a cleanup, which runs when the test fails or panics, so the
+// operator never stays without access; calling it earlier is fine, it only restores once.
+func WithOperatorAPIAccessRevoked(ctx context.Context, t testing.TB, client *Clientset) func() {
+	t.Helper()
+	restore, err := revokeOperatorAPIAccess(ctx, t, client)
+	var once sync.Once
+	restoreOnce := func() {
+		once.Do(func() {
+			if restore == nil || CleanupSkipped(t, "recreate clusterrolebinding/%s and hand it back to the cluster version operator", operatorClusterRoleBindingName) {
+				return
+			}
+			// the test context may be done by the time the cleanup runs, restoring must not be skipped
+			if err := restore(context.WithoutCancel(ctx)); err != nil {
+				t.Errorf("failed to restore the API access of the operator: %v", err)
+			}
+		})
+	}
+	t.Cleanup(restoreOnce)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return restoreOnce
+}
+
+// revokeOperatorAPIAccess deletes the cluster role binding of the operator. The restore function it
+// returns is nil when nothing was changed, it is returned along with an error once the cluster was
+// changed so that the change is reverted either way.
+func revokeOperatorAPIAccess(ctx context.Context, logger Logger, client apiAccessClient) (func(ctx context.Context) error, error) {
+	override := configv1.ComponentOverride{
+		Kind:      "ClusterRoleBinding",
+		Group:     rbacv1.GroupName,
+		Name:      operatorClusterRoleBindingName,
+		Unmanaged: true,
+	}
+	addedOverride, err := setComponentUnmanaged(ctx, client, override, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hand clusterrolebinding/%s over from the cluster version operator: %w", operatorClusterRoleBindingName, err)
+	}
+	restoreOverride := func(ctx context.Context) error {
+		if !addedOverride {
+			return nil
+		}
+		if _, err := setComponentUnmanaged(ctx, client, override, false); err != nil {
+			return fmt.Errorf("failed to hand clusterrolebinding/%s back to the cluster version operator: %w", operatorClusterRoleBindingName, err)
+		}
+		return nil
+	}
+
+	binding, err := client.ClusterRoleBindings().Get(ctx, operatorClusterRoleBindingName, metav1.GetOptions{})
+	if err != nil {
+		return restoreOverride, fmt.Errorf("failed to get clusterrolebinding/%s: %w", operatorClusterRoleBindingName, err)
+	}
+	original := &rbacv1.ClusterRoleBinding{
+		ObjectMeta: metav1.ObjectMeta{Name: binding.Name, Labels: binding.Labels, Annotations: binding.Annotations},
+		RoleRef:    binding.RoleRef,
+		Subjects:   binding.Subjects,
+	}
+	restore := func(ctx context.Context) error {
+		_, err := client.ClusterRoleBindings().Create(ctx, original, metav1.CreateOptions{})
+		if err != nil && !errors.IsAlreadyExists(err) {
+			return fmt.Errorf("failed to recreate clusterrolebinding/%s: %w", operatorClusterRoleBindingName, err)
+		}
+		logger.Logf("recreated clusterrolebinding/%s, the operator has access to the API again", operatorClusterRoleBindingName)
+		return restoreOverride(ctx)
+	}
+	if err := client.ClusterRoleBindings().Delete(ctx, operatorClusterRoleBindingName, metav1.DeleteOptions{}); err != nil && !errors.IsNotFound(err) {
+		return restoreOverride, fmt.Errorf("failed to delete clusterrolebinding/%s: %w", operatorClusterRoleBindingName, err)
+	}
+	logger.Logf("deleted clusterrolebinding/%s, the operator lost access to the API", operatorClusterRoleBindingName)
+	return restore, nil
+}
@@ -0,0 +1,84 @@
+package framework
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+
+	configv1 "github.com/openshift/api/config/v1"
+	configfake "github.com/openshift/client-go/config/clientset/versioned/fake"
+)
+
+func newAPIAccessClient(binding *rbacv1.ClusterRoleBinding) *Clientset {
+	var kubeObjects []runtime.Object
+	if binding != nil {
+		kubeObjects = append(kubeObjects, binding)
+	}
+	configClient := configfake.NewSimpleClientset(&configv1.ClusterVersion{
+		ObjectMeta: metav1.ObjectMeta{Name: "version"},
+		Spec:       configv1.ClusterVersionSpec{Overrides: []configv1.ComponentOverride{otherOverride}},
+	})
+	return &Clientset{RbacV1Interface: fake.NewSimpleClientset(kubeObjects...).RbacV1(), ConfigV1Interface: configClient.ConfigV1()}
+}
+
+func TestRevokeOperatorAPIAccess(t *testing.T) {
+	binding := &rbacv1.ClusterRoleBinding{
+		ObjectMeta: metav1.ObjectMeta{Name: operatorClusterRoleBindingName, ResourceVersion: "7", Annotations: map[string]string{"include.release.openshift.io/self-managed-high-availability": "true"}},
+		RoleRef:    rbacv1.RoleRef{Kind: "ClusterRole", Name: "cluster-admin"},
+		Subjects:   []rbacv1.Subject{{Kind: "ServiceAccount", Namespace: "openshift-controller-manager-operator", Name: "openshift-controller-manager-operator"}},
+	}
+	client := newAPIAccessClient(binding)
+
+	restore, err := revokeOperatorAPIAccess(context.TODO(), &recordingLogger{}, client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.ClusterRoleBindings().Get(context.TODO(), operatorClusterRoleBindingName, metav1.GetOptions{}); !errors.IsNotFound(err) {
+		t.Errorf("expected the binding to be deleted, got %v", err)
+	}
+	bindingOverride := configv1.ComponentOverride{Kind: "ClusterRoleBinding", Group: "rbac.authorization.k8s.io", Name: operatorClusterRoleBindingName, Unmanaged: true}
+	if expected := []configv1.ComponentOverride{otherOverride, bindingOverride}; !reflect.DeepEqual(clusterVersionOverrides(t, client), expected) {
+		t.Errorf("expected the overrides %v while the access is revoked, got %v", expected, clusterVersionOverrides(t, client))
+	}
+
+	if err := restore(context.TODO()); err != nil {
+		t.Fatal(err)
+	}
+	restored, err := client.ClusterRoleBindings().Get(context.TODO(), operatorClusterRoleBindingName, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("expected the binding to be recreated, got %v", err)
+	}
+	if !reflect.DeepEqual(restored.RoleRef, binding.RoleRef) || !reflect.DeepEqual(restored.Subjects, binding.Subjects) || !reflect.DeepEqual(restored.Annotations, binding.Annotations) {
+		t.Errorf("expected the original binding %v to be recreated, got %v", binding, restored)
+	}
+	if expected := []configv1.ComponentOverride{otherOverride}; !reflect.DeepEqual(clusterVersionOverrides(t, client), expected) {
+		t.Errorf("expected the binding to be handed back to the cluster version operator, got the overrides %v", clusterVersionOverrides(t, client))
+	}
+}
+
+func TestRevokeOperatorAPIAccessMissingBinding(t *testing.T) {
+	client := newAPIAccessClient(nil)
+
+	restore, err := revokeOperatorAPIAccess(context.TODO(), &recordingLogger{}, client)
+	if err == nil {
+		t.Fatal("expected an error without the binding")
+	}
+	if restore == nil {
+		t.Fatal("expected a restore function along with the error, the override was added")
+	}
+	if err := restore(context.TODO()); err != nil {
+		t.Fatal(err)
+	}
+	if expected := []configv1.ComponentOverride{otherOverride}; !reflect.DeepEqual(clusterVersionOverrides(t, client), expected) {
+		t.Errorf("expected the override to be removed again, got the overrides %v", clusterVersionOverrides(t, client))
+	}
+	if _, err := client.ClusterRoleBindings().Get(context.TODO(), operatorClusterRoleBindingName, metav1.GetOptions{}); !errors.IsNotFound(err) {
+		t.Errorf("expected no binding to be created, got %v", err)
+	}
+}
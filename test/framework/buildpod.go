@@ -0,0 +1,107 @@
+package framework
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+
+	buildv1 "github.com/openshift/api/build/v1"
+)
+
+const (
+	// buildNameLabel is the label of a build pod naming its build.
+	buildNameLabel = "openshift.io/build.name"
+	// buildEnvName is the environment variable passing the build, as the build controller resolved it,
+	// to the containers of a build pod.
+	buildEnvName = "BUILD"
+
+	buildPodTimeout = 3 * time.Minute
+)
+
+// CreateDockerBuild creates a build of the Docker strategy from the git repository in the namespace and
+// returns its name. The build does not need to succeed for its pod to show what the build controller
+// applied to it, so gitURI may point nowhere.
+func CreateDockerBuild(ctx context.Context, t testing.TB, client *Clientset, namespace, gitURI string) (string, error) {
+	t.Helper()
+	// the build client is not vendored, the build API is reached through the REST client of the core API
+	build := &buildv1.Build{
+		TypeMeta:   metav1.TypeMeta{APIVersion: buildv1.GroupVersion.String(), Kind: "Build"},
+		ObjectMeta: metav1.ObjectMeta{GenerateName: "e2e-build-", Namespace: namespace},
+		Spec: buildv1.BuildSpec{CommonSpec: buildv1.CommonSpec{
+			Source:   buildv1.BuildSource{Type: buildv1.BuildSourceGit, Git: &buildv1.GitBuildSource{URI: gitURI}},
+			Strategy: buildv1.BuildStrategy{Type: buildv1.DockerBuildStrategyType, DockerStrategy: &buildv1.DockerBuildStrategy{}},
+		}},
+	}
+	body, err := json.Marshal(build)
+	if err != nil {
+		return "", err
+	}
+	raw, err := client.CoreV1Interface.RESTClient().Post().
+		AbsPath("/apis", buildv1.GroupVersion.Group, buildv1.GroupVersion.Version, "namespaces", namespace, "builds").
+		SetHeader("Content-Type", "application/json").
+		Body(body).
+		DoRaw(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to create a build in namespace %s: %w", namespace, err)
+	}
+	created := &buildv1.Build{}
+	if err := json.Unmarshal(raw, created); err != nil {
+		return "", fmt.Errorf("failed to decode the created build: %w", err)
+	}
+	t.Logf("created build %s -n %s", created.Name, namespace)
+	return created.Name, nil
+}
+
+// GetBuildPodBuild waits for the pod of the build and returns the build the build controller passed to it,
+// with the build defaults of the cluster applied.
+func GetBuildPodBuild(ctx context.Context, t testing.TB, client *Clientset, namespace, buildName string) (*buildv1.Build, error) {
+	t.Helper()
+	return getBuildPodBuild(ctx, t, client, namespace, buildName, 5*time.Second, buildPodTimeout)
+}
+
+func getBuildPodBuild(ctx context.Context, logger Logger, client clientcorev1.PodsGetter, namespace, buildName string, interval, timeout time.Duration) (*buildv1.Build, error) {
+	var build *buildv1.Build
+	var lastErr error
+	err := poll(ctx, interval, timeout, func(ctx context.Context) (bool, error) {
+		pods, err := client.Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: buildNameLabel + "=" + buildName})
+		if err != nil {
+			lastErr = err
+			return false, nil
+		}
+		if len(pods.Items) == 0 {
+			lastErr = fmt.Errorf("no pod of build %s -n %s", buildName, namespace)
+			logger.Logf("waiting for the build pod: %v", lastErr)
+			return false, nil
+		}
+		build, lastErr = buildFromPod(&pods.Items[0])
+		return lastErr == nil, lastErr
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the build of the pod of build %s -n %s: %v: %w", buildName, namespace, lastErr, err)
+	}
+	return build, nil
+}
+
+// buildFromPod decodes the build from the BUILD environment variable of the first container of the pod
+// setting it, the init containers included.
+func buildFromPod(pod *corev1.Pod) (*buildv1.Build, error) {
+	for _, container := range append(append([]corev1.Container{}, pod.Spec.InitContainers...), pod.Spec.Containers...) {
+		for _, env := range container.Env {
+			if env.Name != buildEnvName {
+				continue
+			}
+			build := &buildv1.Build{}
+			if err := json.Unmarshal([]byte(env.Value), build); err != nil {
+				return nil, fmt.Errorf("failed to decode the %s environment variable of container %s of pod/%s -n %s: %w", buildEnvName, container.Name, pod.Name, pod.Namespace, err)
+			}
+			return build, nil
+		}
+	}
+	return nil, fmt.Errorf("pod/%s -n %s has no container with the %s environment variable", pod.Name, pod.Namespace, buildEnvName)
+}
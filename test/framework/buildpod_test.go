@@ -0,0 +1,73 @@
+package framework
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestGetBuildPodBuild(t *testing.T) {
+	buildPod := func(name string, initEnv, env []corev1.EnvVar) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "e2e", Labels: map[string]string{buildNameLabel: "e2e-build-1"}},
+			Spec: corev1.PodSpec{
+				InitContainers: []corev1.Container{{Name: "git-clone", Env: initEnv}},
+				Containers:     []corev1.Container{{Name: "docker-build", Env: env}},
+			},
+		}
+	}
+	buildEnv := []corev1.EnvVar{{Name: "BUILD", Value: `{"kind":"Build","apiVersion":"build.openshift.io/v1","metadata":{"name":"e2e-build-1"},"spec":{"source":{"type":"Git","git":{"uri":"https://git.example.com/repo.git","noProxy":".e2e.example.com"}}}}`}}
+
+	tests := []struct {
+		name        string
+		pod         *corev1.Pod
+		expectedErr string
+	}{
+		{
+			name: "build of the init container",
+			pod:  buildPod("e2e-build-1-build", buildEnv, nil),
+		},
+		{
+			name: "build of the container",
+			pod:  buildPod("e2e-build-1-build", nil, buildEnv),
+		},
+		{
+			name:        "no build pod",
+			pod:         &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "e2e"}},
+			expectedErr: "no pod of build e2e-build-1 -n e2e",
+		},
+		{
+			name:        "no build env",
+			pod:         buildPod("e2e-build-1-build", nil, []corev1.EnvVar{{Name: "OTHER", Value: "x"}}),
+			expectedErr: "has no container with the BUILD environment variable",
+		},
+		{
+			name:        "invalid build env",
+			pod:         buildPod("e2e-build-1-build", nil, []corev1.EnvVar{{Name: "BUILD", Value: "{"}}),
+			expectedErr: "failed to decode the BUILD environment variable of container docker-build",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			client := fake.NewSimpleClientset(tc.pod)
+			build, err := getBuildPodBuild(context.TODO(), t, client.CoreV1(), "e2e", "e2e-build-1", time.Millisecond, 20*time.Millisecond)
+			if len(tc.expectedErr) > 0 {
+				if err == nil || !strings.Contains(err.Error(), tc.expectedErr) {
+					t.Fatalf("expected an error containing %q, got %v", tc.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if git := build.Spec.Source.Git; git == nil || git.NoProxy == nil || *git.NoProxy != ".e2e.example.com" {
+				t.Errorf("expected the git no-proxy list of the build, got %#v", git)
+			}
+		})
+	}
+}
@@ -0,0 +1,192 @@
+package framework
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientappsv1 "k8s.io/client-go/kubernetes/typed/apps/v1"
+	clientcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"sigs.k8s.io/yaml"
+
+	clientconfigv1 "github.com/openshift/client-go/config/clientset/versioned/typed/config/v1"
+	clientoperatorv1 "github.com/openshift/client-go/operator/clientset/versioned/typed/operator/v1"
+
+	"github.com/openshift/cluster-openshift-controller-manager-operator/pkg/util"
+)
+
+const (
+	// maxBundleEvents is how many of the most recent events of a namespace a bundle keeps.
+	maxBundleEvents = 100
+	// bundleMissingFile lists what a bundle could not collect.
+	bundleMissingFile = "missing.txt"
+)
+
+// bundleNamespaces are the namespaces of which a bundle has the events.
+var bundleNamespaces = []string{util.OperatorNamespace, util.TargetNamespace, util.RouteControllerTargetNamespace}
+
+// bundleDeployments are the operand deployments a bundle has, by namespace.
+var bundleDeployments = map[string]string{
+	util.TargetNamespace:                "controller-manager",
+	util.RouteControllerTargetNamespace: "route-controller-manager",
+}
+
+// bundleConfigMaps are the configmaps with the config of the operator and of the operands, by namespace.
+var bundleConfigMaps = map[string]string{
+	util.OperatorNamespace:              "openshift-controller-manager-operator-config",
+	util.TargetNamespace:                "config",
+	util.RouteControllerTargetNamespace: "config",
+}
+
+var unsafeBundleNameChars = regexp.MustCompile(`[^a-zA-Z0-9._-]+`)
+
+type bundleClient interface {
+	clientoperatorv1.OpenShiftControllerManagersGetter
+	clientconfigv1.ClusterOperatorsGetter
+	clientappsv1.DeploymentsGetter
+	clientcorev1.ConfigMapsGetter
+	clientcorev1.EventsGetter
+}
+
+// ArtifactDir returns the directory CI collects the artifacts of a test run from, empty outside of CI.
+func ArtifactDir() string {
+	return os.Getenv("ARTIFACT_DIR")
+}
+
+// CaptureBundle writes what support asks for when the operator misbehaves to a timestamped directory
+// under dir named after the test: the operator config, the ClusterOperator, the operand deployments, the
+// config configmaps and the recent events of the operator and operand namespaces. It never fails the
+// test, what it cannot collect is logged and listed in missing.txt of the bundle. It returns the
+// directory of the bundle, empty if it could not be created.
+func CaptureBundle(ctx context.Context, t testing.TB, client *Clientset, dir string) string {
+	t.Helper()
+	bundleDir, missing := captureBundle(ctx, client, filepath.Join(dir, bundleDirName(t.Name(), time.Now())))
+	for _, m := range missing {
+		t.Logf("diagnostics bundle: %s", m)
+	}
+	if len(bundleDir) > 0 {
+		t.Logf("wrote the diagnostics bundle to %s", bundleDir)
+	}
+	return bundleDir
+}
+
+func bundleDirName(testName string, now time.Time) string {
+	name := strings.Trim(unsafeBundleNameChars.ReplaceAllString(testName, "_"), "_")
+	if len(name) > 100 {
+		name = name[:100]
+	}
+	return now.UTC().Format("20060102-150405") + "-" + name
+}
+
+// captureBundle writes the bundle to bundleDir and returns it with what could not be collected.
+func captureBundle(ctx context.Context, client bundleClient, bundleDir string) (string, []string) {
+	if err := os.MkdirAll(bundleDir, 0755); err != nil {
+		return "", []string{fmt.Sprintf("unable to create %s: %v", bundleDir, err)}
+	}
+
+	var missing []string
+	write := func(file string, get func() (interface{}, error)) {
+		obj, err := get()
+		if err != nil {
+			missing = append(missing, fmt.Sprintf("%s: %v", file, err))
+			return
+		}
+		data, err := yaml.Marshal(obj)
+		if err != nil {
+			missing = append(missing, fmt.Sprintf("%s: %v", file, err))
+			return
+		}
+		if err := os.WriteFile(filepath.Join(bundleDir, file), data, 0644); err != nil {
+			missing = append(missing, fmt.Sprintf("%s: %v", file, err))
+		}
+	}
+
+	write("openshiftcontrollermanager-cluster.yaml", func() (interface{}, error) {
+		obj, err := client.OpenShiftControllerManagers().Get(ctx, "cluster", metav1.GetOptions{})
+		return withoutManagedFields(obj, err)
+	})
+	write("clusteroperator-"+util.ClusterOperatorName+".yaml", func() (interface{}, error) {
+		obj, err := client.ClusterOperators().Get(ctx, util.ClusterOperatorName, metav1.GetOptions{})
+		return withoutManagedFields(obj, err)
+	})
+	for _, namespace := range sortedKeys(bundleDeployments) {
+		name := bundleDeployments[namespace]
+		write(fmt.Sprintf("deployment-%s-%s.yaml", namespace, name), func() (interface{}, error) {
+			obj, err := client.Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+			return withoutManagedFields(obj, err)
+		})
+	}
+	for _, namespace := range sortedKeys(bundleConfigMaps) {
+		name := bundleConfigMaps[namespace]
+		write(fmt.Sprintf("configmap-%s-%s.yaml", namespace, name), func() (interface{}, error) {
+			obj, err := client.ConfigMaps(namespace).Get(ctx, name, metav1.GetOptions{})
+			return withoutManagedFields(obj, err)
+		})
+	}
+	for _, namespace := range bundleNamespaces {
+		write(fmt.Sprintf("events-%s.yaml", namespace), func() (interface{}, error) {
+			events, err := client.Events(namespace).List(ctx, metav1.ListOptions{})
+			if err != nil {
+				return nil, err
+			}
+			return recentEvents(events.Items), nil
+		})
+	}
+
+	if len(missing) > 0 {
+		if err := os.WriteFile(filepath.Join(bundleDir, bundleMissingFile), []byte(strings.Join(missing, "\n")+"\n"), 0644); err != nil {
+			missing = append(missing, fmt.Sprintf("%s: %v", bundleMissingFile, err))
+		}
+	}
+	return bundleDir, missing
+}
+
+// withoutManagedFields drops the managed fields of obj, they are noise in a bundle.
+func withoutManagedFields(obj metav1.Object, err error) (interface{}, error) {
+	if err != nil {
+		return nil, err
+	}
+	obj.SetManagedFields(nil)
+	return obj, nil
+}
+
+// recentEvents returns the most recent maxBundleEvents events, oldest first.
+func recentEvents(events []corev1.Event) []corev1.Event {
+	lastSeen := func(event corev1.Event) time.Time {
+		switch {
+		case !event.LastTimestamp.IsZero():
+			return event.LastTimestamp.Time
+		case !event.EventTime.IsZero():
+			return event.EventTime.Time
+		default:
+			return event.CreationTimestamp.Time
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		return lastSeen(events[i]).Before(lastSeen(events[j]))
+	})
+	if len(events) > maxBundleEvents {
+		events = events[len(events)-maxBundleEvents:]
+	}
+	for i := range events {
+		events[i].ManagedFields = nil
+	}
+	return events
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,128 @@
+package framework
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	"sigs.k8s.io/yaml"
+
+	configv1 "github.com/openshift/api/config/v1"
+	operatorv1 "github.com/openshift/api/operator/v1"
+	configfake "github.com/openshift/client-go/config/clientset/versioned/fake"
+	operatorfake "github.com/openshift/client-go/operator/clientset/versioned/fake"
+)
+
+func TestCaptureBundle(t *testing.T) {
+	kubeObjects := []runtime.Object{
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{
+			Name:          "controller-manager",
+			Namespace:     "openshift-controller-manager",
+			ManagedFields: []metav1.ManagedFieldsEntry{{Manager: "operator"}},
+		}},
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: "openshift-controller-manager"},
+			Data:       map[string]string{"config.yaml": "{}"},
+		},
+	}
+	start := time.Date(2026, 10, 14, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < maxBundleEvents+10; i++ {
+		kubeObjects = append(kubeObjects, &corev1.Event{
+			ObjectMeta:    metav1.ObjectMeta{Name: fmt.Sprintf("event-%03d", i), Namespace: "openshift-controller-manager"},
+			Reason:        fmt.Sprintf("Reason%03d", i),
+			LastTimestamp: metav1.NewTime(start.Add(time.Duration(i) * time.Minute)),
+		})
+	}
+	client := &Clientset{
+		CoreV1Interface:     fake.NewSimpleClientset(kubeObjects...).CoreV1(),
+		AppsV1Interface:     fake.NewSimpleClientset(kubeObjects...).AppsV1(),
+		ConfigV1Interface:   configfake.NewSimpleClientset(&configv1.ClusterOperator{ObjectMeta: metav1.ObjectMeta{Name: "openshift-controller-manager"}}).ConfigV1(),
+		OperatorV1Interface: operatorfake.NewSimpleClientset(&operatorv1.OpenShiftControllerManager{ObjectMeta: metav1.ObjectMeta{Name: "cluster"}}).OperatorV1(),
+	}
+
+	bundleDir := CaptureBundle(context.TODO(), t, client, t.TempDir())
+	if len(bundleDir) == 0 {
+		t.Fatal("expected a bundle")
+	}
+	if name := filepath.Base(bundleDir); !strings.HasSuffix(name, "-TestCaptureBundle") {
+		t.Errorf("expected the bundle to be named after the test, got %s", name)
+	}
+
+	for _, file := range []string{
+		"openshiftcontrollermanager-cluster.yaml",
+		"clusteroperator-openshift-controller-manager.yaml",
+		"deployment-openshift-controller-manager-controller-manager.yaml",
+		"configmap-openshift-controller-manager-config.yaml",
+		"events-openshift-controller-manager.yaml",
+		"events-openshift-route-controller-manager.yaml",
+	} {
+		if _, err := os.Stat(filepath.Join(bundleDir, file)); err != nil {
+			t.Errorf("expected %s in the bundle: %v", file, err)
+		}
+	}
+
+	deployment, err := os.ReadFile(filepath.Join(bundleDir, "deployment-openshift-controller-manager-controller-manager.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(deployment), "managedFields") {
+		t.Errorf("expected the managed fields to be dropped:\n%s", deployment)
+	}
+
+	data, err := os.ReadFile(filepath.Join(bundleDir, "events-openshift-controller-manager.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var events []corev1.Event
+	if err := yaml.Unmarshal(data, &events); err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != maxBundleEvents {
+		t.Fatalf("expected the %d most recent events, got %d", maxBundleEvents, len(events))
+	}
+	if first, last := events[0].Reason, events[len(events)-1].Reason; first != "Reason010" || last != "Reason109" {
+		t.Errorf("expected the events from Reason010 to Reason109, got %s to %s", first, last)
+	}
+
+	// what is not there is listed, the rest of the bundle is still written
+	missing, err := os.ReadFile(filepath.Join(bundleDir, "missing.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{
+		"deployment-openshift-route-controller-manager-route-controller-manager.yaml",
+		"configmap-openshift-controller-manager-operator-openshift-controller-manager-operator-config.yaml",
+		"configmap-openshift-route-controller-manager-config.yaml",
+	} {
+		if !strings.Contains(string(missing), expected) {
+			t.Errorf("expected %s to be listed as missing:\n%s", expected, missing)
+		}
+	}
+}
+
+func TestCaptureBundleUnwritableDir(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(file, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	client := &Clientset{
+		CoreV1Interface:     fake.NewSimpleClientset().CoreV1(),
+		AppsV1Interface:     fake.NewSimpleClientset().AppsV1(),
+		ConfigV1Interface:   configfake.NewSimpleClientset().ConfigV1(),
+		OperatorV1Interface: operatorfake.NewSimpleClientset().OperatorV1(),
+	}
+
+	// the bundle directory cannot be created below a file, the test must not fail for it
+	if bundleDir := CaptureBundle(context.TODO(), t, client, file); len(bundleDir) > 0 {
+		t.Errorf("expected no bundle, got %s", bundleDir)
+	}
+}
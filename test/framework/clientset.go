@@ -7,8 +7,12 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/spf13/pflag"
+	"k8s.io/client-go/dynamic"
 	clientappsv1 "k8s.io/client-go/kubernetes/typed/apps/v1"
+	clientcoordinationv1 "k8s.io/client-go/kubernetes/typed/coordination/v1"
 	clientcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	clientrbacv1 "k8s.io/client-go/kubernetes/typed/rbac/v1"
 	restclient "k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 
@@ -20,8 +24,12 @@ import (
 type Clientset struct {
 	clientcorev1.CoreV1Interface
 	clientappsv1.AppsV1Interface
+	clientcoordinationv1.CoordinationV1Interface
+	clientrbacv1.RbacV1Interface
 	clientconfigv1.ConfigV1Interface
 	operatorclientv1.OperatorV1Interface
+	// Interface is the dynamic client, for the resources without a typed client, e.g. ServiceMonitors
+	dynamic.Interface
 }
 
 // NewClientset creates a set of Kubernetes clients. The default kubeconfig is
@@ -43,6 +51,14 @@ func NewClientset(kubeconfig *restclient.Config) (clientset *Clientset, err erro
 	if err != nil {
 		return
 	}
+	clientset.CoordinationV1Interface, err = clientcoordinationv1.NewForConfig(kubeconfig)
+	if err != nil {
+		return
+	}
+	clientset.RbacV1Interface, err = clientrbacv1.NewForConfig(kubeconfig)
+	if err != nil {
+		return
+	}
 	clientset.ConfigV1Interface, err = clientconfigv1.NewForConfig(kubeconfig)
 	if err != nil {
 		return
@@ -51,6 +67,10 @@ func NewClientset(kubeconfig *restclient.Config) (clientset *Clientset, err erro
 	if err != nil {
 		return
 	}
+	clientset.Interface, err = dynamic.NewForConfig(kubeconfig)
+	if err != nil {
+		return
+	}
 	return
 }
 
@@ -65,20 +85,32 @@ func MustNewClientset(t testing.TB, kubeconfig *restclient.Config) *Clientset {
 	return clientset
 }
 
+// Kubeconfig is the path of the kubeconfig file the clients are created from
+// when no config is provided. It takes precedence over the KUBECONFIG
+// environment variable, the default loading rules are used if both are unset.
+var Kubeconfig string
+
+// AddKubeconfigFlag registers a --kubeconfig flag setting Kubeconfig.
+func AddKubeconfigFlag(fs *pflag.FlagSet) {
+	fs.StringVar(&Kubeconfig, "kubeconfig", Kubeconfig, "Path of the kubeconfig file of the cluster to test, overrides the KUBECONFIG environment variable.")
+}
+
 // getConfig creates a *rest.Config for talking to a Kubernetes apiserver.
 // Otherwise will assume running in cluster and use the cluster provided kubeconfig.
 //
 // # Config precedence
 //
+// * Kubeconfig, usually set by the --kubeconfig flag, pointing at a file
+//
 // * KUBECONFIG environment variable pointing at a file
 //
 // * In-cluster config if running in cluster
 //
 // * $HOME/.kube/config if exists
 func getConfig() (*restclient.Config, error) {
-	// If an env variable is specified with the config locaiton, use that
-	if len(os.Getenv("KUBECONFIG")) > 0 {
-		return clientcmd.BuildConfigFromFlags("", os.Getenv("KUBECONFIG"))
+	// If a path is specified explicitly or by env variable, use that
+	if path, source := kubeconfigPath(Kubeconfig, os.Getenv("KUBECONFIG")); len(path) > 0 {
+		return buildConfigFromFile(path, source)
 	}
 	// If no explicit location, try the in-cluster config
 	if c, err := restclient.InClusterConfig(); err == nil {
@@ -94,3 +126,26 @@ func getConfig() (*restclient.Config, error) {
 
 	return nil, fmt.Errorf("could not locate a kubeconfig")
 }
+
+// kubeconfigPath returns the explicitly specified kubeconfig path, else the
+// one of the KUBECONFIG environment variable, and where it was specified.
+func kubeconfigPath(explicit, env string) (string, string) {
+	if len(explicit) > 0 {
+		return explicit, "--kubeconfig"
+	}
+	if len(env) > 0 {
+		return env, "KUBECONFIG"
+	}
+	return "", ""
+}
+
+func buildConfigFromFile(path, source string) (*restclient.Config, error) {
+	if _, err := os.Stat(path); err != nil {
+		return nil, fmt.Errorf("kubeconfig %q specified by %s cannot be used: %w", path, source, err)
+	}
+	c, err := clientcmd.BuildConfigFromFlags("", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load kubeconfig %q specified by %s: %w", path, source, err)
+	}
+	return c, nil
+}
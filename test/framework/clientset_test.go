@@ -0,0 +1,81 @@
+package framework
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testKubeconfig = `apiVersion: v1
+kind: Config
+clusters:
+- name: dev
+  cluster:
+    server: https://api.dev.example.com:6443
+contexts:
+- name: dev
+  context:
+    cluster: dev
+    user: dev
+current-context: dev
+users:
+- name: dev
+  user:
+    token: secret
+`
+
+func TestKubeconfigPath(t *testing.T) {
+	tests := []struct {
+		name           string
+		explicit       string
+		env            string
+		expectedPath   string
+		expectedSource string
+	}{
+		{
+			name: "unset",
+		},
+		{
+			name:           "env only",
+			env:            "/env/kubeconfig",
+			expectedPath:   "/env/kubeconfig",
+			expectedSource: "KUBECONFIG",
+		},
+		{
+			name:           "flag overrides env",
+			explicit:       "/flag/kubeconfig",
+			env:            "/env/kubeconfig",
+			expectedPath:   "/flag/kubeconfig",
+			expectedSource: "--kubeconfig",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			path, source := kubeconfigPath(tc.explicit, tc.env)
+			if path != tc.expectedPath || source != tc.expectedSource {
+				t.Errorf("expected %q from %q, got %q from %q", tc.expectedPath, tc.expectedSource, path, source)
+			}
+		})
+	}
+}
+
+func TestBuildConfigFromFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "kubeconfig")
+	if err := os.WriteFile(path, []byte(testKubeconfig), 0600); err != nil {
+		t.Fatal(err)
+	}
+	config, err := buildConfigFromFile(path, "--kubeconfig")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.Host != "https://api.dev.example.com:6443" {
+		t.Errorf("expected the host of the kubeconfig, got %q", config.Host)
+	}
+
+	missing := filepath.Join(t.TempDir(), "missing")
+	_, err = buildConfigFromFile(missing, "--kubeconfig")
+	if err == nil || !strings.Contains(err.Error(), missing) || !strings.Contains(err.Error(), "--kubeconfig") {
+		t.Errorf("expected an error naming %q and the flag, got %v", missing, err)
+	}
+}
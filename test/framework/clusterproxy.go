@@ -0,0 +1,111 @@
+package framework
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+
+	clientconfigv1 "github.com/openshift/client-go/config/clientset/versioned/typed/config/v1"
+	clientoperatorv1 "github.com/openshift/client-go/operator/clientset/versioned/typed/operator/v1"
+)
+
+const (
+	gitNoProxyKey = "build.buildDefaults.gitNoProxy"
+	// clusterProxySettleTimeout is how long the git no-proxy list of the observed config may take to follow
+	// the cluster Proxy.
+	clusterProxySettleTimeout = 2 * time.Minute
+)
+
+type clusterProxyClient interface {
+	clientconfigv1.ProxiesGetter
+	clientoperatorv1.OpenShiftControllerManagersGetter
+}
+
+// WithClusterProxyNoProxy sets the no-proxy list of the cluster Proxy, waits for the operator to observe
+// it as the git no-proxy list of builds, runs body and restores the original list on cleanup.
+func WithClusterProxyNoProxy(ctx context.Context, t testing.TB, client *Clientset, noProxy string, body func(ctx context.Context)) {
+	t.Helper()
+	withClusterConfigMutation(ctx, t, ClusterProxyNoProxyMutation(t, client), noProxy, body)
+}
+
+// ClusterProxyNoProxyMutation changes the no-proxy list in the spec of the cluster Proxy. Settling waits for
+// the git no-proxy list of the observed config to be the one the operator derives from the Proxy, its
+// status if populated and else its spec, which also verifies a restored list. It relies on the build config
+// not setting a git no-proxy list of its own, which would win over the one of the Proxy.
+func ClusterProxyNoProxyMutation(logger Logger, client *Clientset) ClusterConfigMutation[string] {
+	return clusterProxyNoProxyMutation(logger, client, 5*time.Second, clusterProxySettleTimeout)
+}
+
+func clusterProxyNoProxyMutation(logger Logger, client clusterProxyClient, interval, timeout time.Duration) ClusterConfigMutation[string] {
+	return ClusterConfigMutation[string]{
+		Name: "cluster Proxy no-proxy list",
+		Get: func(ctx context.Context) (string, error) {
+			proxy, err := client.Proxies().Get(ctx, "cluster", metav1.GetOptions{})
+			if err != nil {
+				return "", err
+			}
+			return proxy.Spec.NoProxy, nil
+		},
+		Set: func(ctx context.Context, noProxy string) error {
+			return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+				proxy, err := client.Proxies().Get(ctx, "cluster", metav1.GetOptions{})
+				if err != nil {
+					return err
+				}
+				proxy.Spec.NoProxy = noProxy
+				_, err = client.Proxies().Update(ctx, proxy, metav1.UpdateOptions{})
+				return err
+			})
+		},
+		Settle: func(ctx context.Context) error {
+			var lastErr error
+			err := poll(ctx, interval, timeout, func(ctx context.Context) (bool, error) {
+				if lastErr = checkGitNoProxyObserved(ctx, client); lastErr != nil {
+					logger.Logf("waiting for the cluster Proxy to be observed: %v", lastErr)
+					return false, nil
+				}
+				return true, nil
+			})
+			if err != nil {
+				return fmt.Errorf("%v: %w", lastErr, err)
+			}
+			return nil
+		},
+	}
+}
+
+// checkGitNoProxyObserved returns an error unless the git no-proxy list of the observed config is the
+// no-proxy list of the cluster Proxy, absent if the Proxy has none.
+func checkGitNoProxyObserved(ctx context.Context, client clusterProxyClient) error {
+	proxy, err := client.Proxies().Get(ctx, "cluster", metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+	expected := proxy.Status.NoProxy
+	if len(expected) == 0 {
+		expected = proxy.Spec.NoProxy
+	}
+
+	raw, err := getObservedConfigRaw(ctx, client)
+	if err != nil {
+		return err
+	}
+	observedConfig, err := unmarshalObservedConfig(raw)
+	if err != nil {
+		return err
+	}
+	observed, found, err := observedConfigLeaf(observedConfig, gitNoProxyKey)
+	switch {
+	case err != nil:
+		return err
+	case len(expected) == 0 && found:
+		return fmt.Errorf("%s is %v, expected it to be absent", gitNoProxyKey, observed)
+	case len(expected) > 0 && observed != expected:
+		return fmt.Errorf("%s is %v, expected %q", gitNoProxyKey, observed, expected)
+	}
+	return nil
+}
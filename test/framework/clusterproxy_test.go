@@ -0,0 +1,123 @@
+package framework
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	configv1 "github.com/openshift/api/config/v1"
+	operatorv1 "github.com/openshift/api/operator/v1"
+	configfake "github.com/openshift/client-go/config/clientset/versioned/fake"
+	operatorfake "github.com/openshift/client-go/operator/clientset/versioned/fake"
+)
+
+func TestClusterProxyNoProxyMutation(t *testing.T) {
+	tests := []struct {
+		name           string
+		proxyStatus    configv1.ProxyStatus
+		observedConfig string
+		expectedErr    string
+	}{
+		{
+			name:           "spec observed",
+			observedConfig: `{"build":{"buildDefaults":{"gitNoProxy":".e2e.example.com"}}}`,
+		},
+		{
+			name:           "status wins over spec",
+			proxyStatus:    configv1.ProxyStatus{NoProxy: ".cluster.local,.e2e.example.com"},
+			observedConfig: `{"build":{"buildDefaults":{"gitNoProxy":".cluster.local,.e2e.example.com"}}}`,
+		},
+		{
+			name:           "not observed yet",
+			observedConfig: `{}`,
+			expectedErr:    `build.buildDefaults.gitNoProxy is <nil>, expected ".e2e.example.com"`,
+		},
+		{
+			name:           "stale list",
+			observedConfig: `{"build":{"buildDefaults":{"gitNoProxy":".stale.example.com"}}}`,
+			expectedErr:    `build.buildDefaults.gitNoProxy is .stale.example.com, expected ".e2e.example.com"`,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			configClient := configfake.NewSimpleClientset(&configv1.Proxy{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+				Status:     tc.proxyStatus,
+			})
+			operatorClient := operatorfake.NewSimpleClientset(&operatorv1.OpenShiftControllerManager{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+				Spec: operatorv1.OpenShiftControllerManagerSpec{OperatorSpec: operatorv1.OperatorSpec{
+					ObservedConfig: runtime.RawExtension{Raw: []byte(tc.observedConfig)},
+				}},
+			})
+			client := &Clientset{ConfigV1Interface: configClient.ConfigV1(), OperatorV1Interface: operatorClient.OperatorV1()}
+			mutation := clusterProxyNoProxyMutation(t, client, time.Millisecond, 20*time.Millisecond)
+
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			restore, err := mutation.Apply(ctx, t, ".e2e.example.com")
+			if len(tc.expectedErr) > 0 {
+				if err == nil || !strings.Contains(err.Error(), tc.expectedErr) {
+					t.Fatalf("expected an error containing %q, got %v", tc.expectedErr, err)
+				}
+			} else if err != nil {
+				t.Fatal(err)
+			}
+			proxy, err := client.Proxies().Get(ctx, "cluster", metav1.GetOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if proxy.Spec.NoProxy != ".e2e.example.com" {
+				t.Errorf("expected the no-proxy list to be set, got %q", proxy.Spec.NoProxy)
+			}
+			if restore == nil {
+				t.Fatal("expected a restore function")
+			}
+		})
+	}
+}
+
+func TestCheckGitNoProxyObservedCleared(t *testing.T) {
+	tests := []struct {
+		name           string
+		observedConfig string
+		expectedErr    string
+	}{
+		{
+			name:           "absent",
+			observedConfig: `{"build":{"buildDefaults":{}}}`,
+		},
+		{
+			name:           "left behind",
+			observedConfig: `{"build":{"buildDefaults":{"gitNoProxy":".e2e.example.com"}}}`,
+			expectedErr:    "build.buildDefaults.gitNoProxy is .e2e.example.com, expected it to be absent",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			configClient := configfake.NewSimpleClientset(&configv1.Proxy{ObjectMeta: metav1.ObjectMeta{Name: "cluster"}})
+			operatorClient := operatorfake.NewSimpleClientset(&operatorv1.OpenShiftControllerManager{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+				Spec: operatorv1.OpenShiftControllerManagerSpec{OperatorSpec: operatorv1.OperatorSpec{
+					ObservedConfig: runtime.RawExtension{Raw: []byte(tc.observedConfig)},
+				}},
+			})
+			client := &Clientset{ConfigV1Interface: configClient.ConfigV1(), OperatorV1Interface: operatorClient.OperatorV1()}
+
+			err := checkGitNoProxyObserved(context.TODO(), client)
+			if len(tc.expectedErr) == 0 {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tc.expectedErr {
+				t.Errorf("expected error %q, got %v", tc.expectedErr, err)
+			}
+		})
+	}
+}
@@ -2,16 +2,23 @@ package framework
 
 import (
 	"context"
+	"fmt"
 	"testing"
 	"time"
 
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
 
 	configv1 "github.com/openshift/api/config/v1"
+	clientconfigv1 "github.com/openshift/client-go/config/clientset/versioned/typed/config/v1"
+	"github.com/openshift/library-go/pkg/config/clusteroperator/v1helpers"
 )
 
+// clusterOperatorName is the name of the ClusterOperator of the operator.
+const clusterOperatorName = "openshift-controller-manager"
+
 func hasExpectedClusterOperatorConditions(status *configv1.ClusterOperator) bool {
 	gotAvailable := false
 	gotProgressing := false
@@ -34,10 +41,10 @@ func hasExpectedClusterOperatorConditions(status *configv1.ClusterOperator) bool
 	return gotAvailable && gotProgressing && gotDegraded && gotUpgradeable
 }
 
-func ensureClusterOperatorStatusIsSet(logger Logger, client *Clientset) error {
+func ensureClusterOperatorStatusIsSet(ctx context.Context, logger Logger, client clientconfigv1.ClusterOperatorsGetter) error {
 	var status *configv1.ClusterOperator
-	err := wait.Poll(1*time.Second, 2*time.Minute, func() (stop bool, err error) {
-		status, err = client.ClusterOperators().Get(context.TODO(), "openshift-controller-manager", metav1.GetOptions{})
+	err := poll(ctx, 1*time.Second, 2*time.Minute, func(ctx context.Context) (stop bool, err error) {
+		status, err = client.ClusterOperators().Get(ctx, clusterOperatorName, metav1.GetOptions{})
 		if errors.IsNotFound(err) {
 			logger.Logf("waiting for the cluster operator resource: the resource does not exist")
 			return false, nil
@@ -58,9 +65,56 @@ func ensureClusterOperatorStatusIsSet(logger Logger, client *Clientset) error {
 	return err
 }
 
-func MustEnsureClusterOperatorStatusIsSet(t testing.TB, client *Clientset) {
+// MustEnsureClusterOperatorStatusIsSet waits for the operator to be Available, not Progressing, not
+// Degraded and Upgradeable, and fails the test if it is not within 2 minutes or ctx is done first.
+func MustEnsureClusterOperatorStatusIsSet(ctx context.Context, t testing.TB, client *Clientset) {
+	t.Helper()
+	if err := ensureClusterOperatorStatusIsSet(ctx, t, client); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// degradedPollInterval is how often AssertNotDegradedFor checks the ClusterOperator.
+const degradedPollInterval = 5 * time.Second
+
+// assertNotDegradedFor polls the ClusterOperator every interval until duration elapsed and returns an
+// error as soon as it is observed Degraded=True.
+func assertNotDegradedFor(ctx context.Context, logger Logger, client clientconfigv1.ClusterOperatorsGetter, duration, interval time.Duration) error {
+	err := poll(ctx, interval, duration, func(ctx context.Context) (bool, error) {
+		status, err := client.ClusterOperators().Get(ctx, clusterOperatorName, metav1.GetOptions{})
+		if err != nil {
+			klog.V(4).Infof("error getting the cluster operator resource: %v", err)
+			return false, nil
+		}
+		degraded := v1helpers.FindStatusCondition(status.Status.Conditions, configv1.OperatorDegraded)
+		if degraded == nil {
+			klog.V(4).Infof("clusteroperator has no %s condition", configv1.OperatorDegraded)
+			return false, nil
+		}
+		klog.V(4).Infof("clusteroperator %s=%s reason=%q", degraded.Type, degraded.Status, degraded.Reason)
+		if degraded.Status == configv1.ConditionTrue {
+			return false, fmt.Errorf("clusteroperator became %s: %s: %s", configv1.OperatorDegraded, degraded.Reason, degraded.Message)
+		}
+		return false, nil
+	})
+	// the condition never finishes the poll, so running into the timeout means the window passed without degradation
+	if ctx.Err() != nil {
+		return err
+	}
+	if wait.Interrupted(err) {
+		return nil
+	}
+	if err != nil {
+		logger.Logf("clusteroperator was degraded within %s: %v", duration, err)
+	}
+	return err
+}
+
+// AssertNotDegradedFor fails the test if the operator reports Degraded=True at any point during the
+// given duration. Stopping short of the full window, e.g. because ctx is cancelled, fails the test as well.
+func AssertNotDegradedFor(ctx context.Context, t testing.TB, client *Clientset, duration time.Duration) {
 	t.Helper()
-	if err := ensureClusterOperatorStatusIsSet(t, client); err != nil {
+	if err := assertNotDegradedFor(ctx, t, client, duration, degradedPollInterval); err != nil {
 		t.Fatal(err)
 	}
 }
@@ -0,0 +1,89 @@
+package framework
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clienttesting "k8s.io/client-go/testing"
+
+	configv1 "github.com/openshift/api/config/v1"
+	configfake "github.com/openshift/client-go/config/clientset/versioned/fake"
+)
+
+func clusterOperatorWithDegraded(status configv1.ConditionStatus) *configv1.ClusterOperator {
+	return &configv1.ClusterOperator{
+		ObjectMeta: metav1.ObjectMeta{Name: "openshift-controller-manager"},
+		Status: configv1.ClusterOperatorStatus{
+			Conditions: []configv1.ClusterOperatorStatusCondition{
+				{Type: configv1.OperatorDegraded, Status: status, Reason: "SyncError", Message: "something broke"},
+			},
+		},
+	}
+}
+
+func TestAssertNotDegradedFor(t *testing.T) {
+	tests := []struct {
+		name string
+		// degradedAfter is the number of gets after which the operator reports Degraded=True, 0 never
+		degradedAfter int
+		cancel        bool
+		expectErr     []string
+	}{
+		{
+			name: "not degraded for the whole window",
+		},
+		{
+			name:          "degraded within the window",
+			degradedAfter: 3,
+			expectErr:     []string{"Degraded", "SyncError", "something broke"},
+		},
+		{
+			name:      "cancelled context",
+			cancel:    true,
+			expectErr: []string{"context canceled"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			client := configfake.NewSimpleClientset(clusterOperatorWithDegraded(configv1.ConditionFalse))
+			gets := 0
+			client.PrependReactor("get", "clusteroperators", func(clienttesting.Action) (bool, runtime.Object, error) {
+				gets++
+				if tc.degradedAfter > 0 && gets >= tc.degradedAfter {
+					return true, clusterOperatorWithDegraded(configv1.ConditionTrue), nil
+				}
+				return false, nil, nil
+			})
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			if tc.cancel {
+				cancel()
+			}
+
+			err := assertNotDegradedFor(ctx, t, client.ConfigV1(), 200*time.Millisecond, 10*time.Millisecond)
+			if len(tc.expectErr) == 0 {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if gets < 2 {
+					t.Errorf("expected the clusteroperator to be polled repeatedly, got %d gets", gets)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			for _, s := range tc.expectErr {
+				if !strings.Contains(err.Error(), s) {
+					t.Errorf("expected error to contain %q, got %q", s, err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package framework
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/onsi/gomega/types"
+
+	configv1 "github.com/openshift/api/config/v1"
+)
+
+// HaveCondition succeeds if the ClusterOperator has the condition of the given type with the given
+// status, e.g. HaveCondition(configv1.OperatorDegraded, configv1.ConditionFalse). The actual value can
+// be a configv1.ClusterOperator or a *configv1.ClusterOperator.
+func HaveCondition(conditionType configv1.ClusterStatusConditionType, status configv1.ConditionStatus) types.GomegaMatcher {
+	return &clusterOperatorConditionMatcher{
+		conditionType: conditionType,
+		field:         "status",
+		expected:      string(status),
+		value:         func(c *configv1.ClusterOperatorStatusCondition) string { return string(c.Status) },
+	}
+}
+
+// HaveConditionReason succeeds if the ClusterOperator has the condition of the given type with the given
+// reason, e.g. HaveConditionReason(configv1.OperatorDegraded, "ObservedConfig_TLSConfigInvalid"). The
+// actual value can be a configv1.ClusterOperator or a *configv1.ClusterOperator.
+func HaveConditionReason(conditionType configv1.ClusterStatusConditionType, reason string) types.GomegaMatcher {
+	return &clusterOperatorConditionMatcher{
+		conditionType: conditionType,
+		field:         "reason",
+		expected:      reason,
+		value:         func(c *configv1.ClusterOperatorStatusCondition) string { return c.Reason },
+	}
+}
+
+type clusterOperatorConditionMatcher struct {
+	conditionType configv1.ClusterStatusConditionType
+	// field names what value returns in the failure messages.
+	field    string
+	expected string
+	value    func(c *configv1.ClusterOperatorStatusCondition) string
+
+	// conditions and condition are recorded by Match for the failure messages.
+	conditions []configv1.ClusterOperatorStatusCondition
+	condition  *configv1.ClusterOperatorStatusCondition
+}
+
+func (m *clusterOperatorConditionMatcher) Match(actual interface{}) (bool, error) {
+	switch co := actual.(type) {
+	case configv1.ClusterOperator:
+		m.conditions = co.Status.Conditions
+	case *configv1.ClusterOperator:
+		if co == nil {
+			return false, fmt.Errorf("expected a ClusterOperator, got nil")
+		}
+		m.conditions = co.Status.Conditions
+	default:
+		return false, fmt.Errorf("expected a configv1.ClusterOperator or *configv1.ClusterOperator, got %T", actual)
+	}
+
+	m.condition = nil
+	for i := range m.conditions {
+		if m.conditions[i].Type == m.conditionType {
+			m.condition = &m.conditions[i]
+			break
+		}
+	}
+	return m.condition != nil && m.value(m.condition) == m.expected, nil
+}
+
+func (m *clusterOperatorConditionMatcher) FailureMessage(_ interface{}) string {
+	if m.condition == nil {
+		return fmt.Sprintf("expected condition %s with %s %q, but it is not set\n%s", m.conditionType, m.field, m.expected, m.describeConditions())
+	}
+	return fmt.Sprintf("expected condition %s to have %s %q, got %q\n%s", m.conditionType, m.field, m.expected, m.value(m.condition), m.describeConditions())
+}
+
+func (m *clusterOperatorConditionMatcher) NegatedFailureMessage(_ interface{}) string {
+	return fmt.Sprintf("expected condition %s not to have %s %q\n%s", m.conditionType, m.field, m.expected, m.describeConditions())
+}
+
+// describeConditions lists the conditions of the ClusterOperator, one per line, with the one the
+// matcher checks marked.
+func (m *clusterOperatorConditionMatcher) describeConditions() string {
+	if len(m.conditions) == 0 {
+		return "the ClusterOperator has no conditions"
+	}
+	lines := []string{"conditions:"}
+	for _, c := range m.conditions {
+		marker := " "
+		if c.Type == m.conditionType {
+			marker = ">"
+		}
+		lines = append(lines, fmt.Sprintf("%s %s=%s reason=%q message=%q", marker, c.Type, c.Status, c.Reason, c.Message))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,140 @@
+package framework
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/onsi/gomega"
+	"github.com/onsi/gomega/types"
+
+	configv1 "github.com/openshift/api/config/v1"
+)
+
+func TestConditionMatchers(t *testing.T) {
+	co := &configv1.ClusterOperator{
+		Status: configv1.ClusterOperatorStatus{
+			Conditions: []configv1.ClusterOperatorStatusCondition{
+				{Type: configv1.OperatorAvailable, Status: configv1.ConditionTrue, Reason: "AsExpected"},
+				{Type: configv1.OperatorProgressing, Status: configv1.ConditionFalse, Reason: "AsExpected"},
+				{
+					Type:    configv1.OperatorDegraded,
+					Status:  configv1.ConditionTrue,
+					Reason:  "ObservedConfig_TLSConfigInvalid",
+					Message: "ObservedConfigDegraded: unsupported minTLSVersion",
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name           string
+		matcher        types.GomegaMatcher
+		actual         interface{}
+		expectMatch    bool
+		expectErr      bool
+		expectFailures []string
+	}{
+		{
+			name:        "status",
+			matcher:     HaveCondition(configv1.OperatorDegraded, configv1.ConditionTrue),
+			actual:      co,
+			expectMatch: true,
+		},
+		{
+			name:        "status of a ClusterOperator value",
+			matcher:     HaveCondition(configv1.OperatorProgressing, configv1.ConditionFalse),
+			actual:      *co,
+			expectMatch: true,
+		},
+		{
+			name:    "status mismatch",
+			matcher: HaveCondition(configv1.OperatorAvailable, configv1.ConditionFalse),
+			actual:  co,
+			expectFailures: []string{
+				`expected condition Available to have status "False", got "True"`,
+				`> Available=True reason="AsExpected"`,
+				`  Degraded=True reason="ObservedConfig_TLSConfigInvalid" message="ObservedConfigDegraded: unsupported minTLSVersion"`,
+			},
+		},
+		{
+			name:           "missing condition",
+			matcher:        HaveCondition(configv1.OperatorUpgradeable, configv1.ConditionTrue),
+			actual:         co,
+			expectFailures: []string{`expected condition Upgradeable with status "True", but it is not set`, "conditions:"},
+		},
+		{
+			name:        "reason",
+			matcher:     HaveConditionReason(configv1.OperatorDegraded, "ObservedConfig_TLSConfigInvalid"),
+			actual:      co,
+			expectMatch: true,
+		},
+		{
+			name:    "reason mismatch",
+			matcher: HaveConditionReason(configv1.OperatorDegraded, "AsExpected"),
+			actual:  co,
+			expectFailures: []string{
+				`expected condition Degraded to have reason "AsExpected", got "ObservedConfig_TLSConfigInvalid"`,
+				`> Degraded=True`,
+			},
+		},
+		{
+			name:           "no conditions",
+			matcher:        HaveConditionReason(configv1.OperatorDegraded, "AsExpected"),
+			actual:         &configv1.ClusterOperator{},
+			expectFailures: []string{"it is not set", "the ClusterOperator has no conditions"},
+		},
+		{
+			name:      "nil ClusterOperator",
+			matcher:   HaveCondition(configv1.OperatorDegraded, configv1.ConditionFalse),
+			actual:    (*configv1.ClusterOperator)(nil),
+			expectErr: true,
+		},
+		{
+			name:      "unsupported actual type",
+			matcher:   HaveCondition(configv1.OperatorDegraded, configv1.ConditionFalse),
+			actual:    co.Status.Conditions,
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			match, err := tc.matcher.Match(tc.actual)
+			if tc.expectErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if match != tc.expectMatch {
+				t.Fatalf("expected match to be %v, got %v", tc.expectMatch, match)
+			}
+			message := tc.matcher.FailureMessage(tc.actual)
+			for _, s := range tc.expectFailures {
+				if !strings.Contains(message, s) {
+					t.Errorf("expected failure message to contain %q, got %q", s, message)
+				}
+			}
+		})
+	}
+}
+
+func TestConditionMatchersWithGomega(t *testing.T) {
+	co := configv1.ClusterOperator{
+		Status: configv1.ClusterOperatorStatus{
+			Conditions: []configv1.ClusterOperatorStatusCondition{
+				{Type: configv1.OperatorDegraded, Status: configv1.ConditionFalse, Reason: "AsExpected"},
+			},
+		},
+	}
+	g := gomega.NewGomega(func(message string, _ ...int) { t.Error(message) })
+	g.Expect(co).To(HaveCondition(configv1.OperatorDegraded, configv1.ConditionFalse))
+	g.Expect(co).NotTo(HaveConditionReason(configv1.OperatorDegraded, "ObservedConfig_TLSConfigInvalid"))
+	g.Expect(co).To(gomega.And(
+		HaveCondition(configv1.OperatorDegraded, configv1.ConditionFalse),
+		HaveConditionReason(configv1.OperatorDegraded, "AsExpected"),
+	))
+}
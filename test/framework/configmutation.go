@@ -0,0 +1,131 @@
+package framework
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+const (
+	// mutationVerifyPollInterval is how often a restored ClusterConfigMutation is verified.
+	mutationVerifyPollInterval = 5 * time.Second
+	// defaultMutationVerifyTimeout is how long a restored ClusterConfigMutation is verified unless it sets
+	// VerifyTimeout.
+	defaultMutationVerifyTimeout = 2 * time.Minute
+)
+
+// ClusterConfigMutation changes a value of the cluster config for the duration of a test and reverts it
+// afterwards. The closures carry what is specific to the mutated config, Apply captures the original
+// value, writes the new one and waits for the operator to settle, and the restore function it returns
+// writes the original value back and verifies the operator reverted what it derived from the change.
+type ClusterConfigMutation[T any] struct {
+	// Name describes the mutated value in logs and errors, e.g. "APIServer TLS security profile".
+	Name string
+	// Get returns the current value.
+	Get func(ctx context.Context) (T, error)
+	// Set writes the value, it is expected to retry on conflicts.
+	Set func(ctx context.Context, value T) error
+	// Settle waits for the operator to reconcile a written value. It is optional.
+	Settle func(ctx context.Context) error
+	// Verify returns an error until the original value is reflected again, it is retried on cleanup
+	// until VerifyTimeout.
+	Verify func(ctx context.Context, original T) error
+	// VerifyTimeout is how long Verify is retried, two minutes when unset.
+	VerifyTimeout time.Duration
+
+	verifyPollInterval time.Duration
+}
+
+// Apply sets value and waits for the operator to settle. The returned restore function is to be
+// registered as a cleanup by the caller right away, it is also returned when only settling failed so the
+// change is reverted either way. It is nil when the original value could not be read or value not set.
+func (m ClusterConfigMutation[T]) Apply(ctx context.Context, logger Logger, value T) (func(ctx context.Context) error, error) {
+	original, err := m.Get(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the original %s: %w", m.Name, err)
+	}
+	if err := m.Set(ctx, value); err != nil {
+		return nil, fmt.Errorf("failed to set the %s: %w", m.Name, err)
+	}
+	logger.Logf("set the %s to %v", m.Name, value)
+
+	restore := func(ctx context.Context) error {
+		return m.restore(ctx, logger, original)
+	}
+	if err := m.settle(ctx); err != nil {
+		return restore, fmt.Errorf("the operator did not settle after setting the %s: %w", m.Name, err)
+	}
+	return restore, nil
+}
+
+func (m ClusterConfigMutation[T]) restore(ctx context.Context, logger Logger, original T) error {
+	if CleanupSkipped(logger, "restore the original %s %v", m.Name, original) {
+		return nil
+	}
+	if err := m.Set(ctx, original); err != nil {
+		return fmt.Errorf("failed to restore the original %s: %w", m.Name, err)
+	}
+	logger.Logf("restored the original %s %v", m.Name, original)
+	if err := m.settle(ctx); err != nil {
+		return fmt.Errorf("the operator did not settle after restoring the %s: %w", m.Name, err)
+	}
+	if m.Verify == nil {
+		return nil
+	}
+
+	interval, timeout := m.verifyPollInterval, m.VerifyTimeout
+	if interval == 0 {
+		interval = mutationVerifyPollInterval
+	}
+	if timeout == 0 {
+		timeout = defaultMutationVerifyTimeout
+	}
+	var lastErr error
+	err := poll(ctx, interval, timeout, func(ctx context.Context) (bool, error) {
+		if lastErr = m.Verify(ctx, original); lastErr != nil {
+			logger.Logf("waiting for the %s to be reverted: %v", m.Name, lastErr)
+			return false, nil
+		}
+		return true, nil
+	})
+	if err != nil {
+		return fmt.Errorf("the %s was not reverted, last verification error: %v: %w", m.Name, lastErr, err)
+	}
+	return nil
+}
+
+func (m ClusterConfigMutation[T]) settle(ctx context.Context) error {
+	if m.Settle == nil {
+		return nil
+	}
+	return m.Settle(ctx)
+}
+
+// withClusterConfigMutation applies value, runs body with a context ending at the deadline of the test and
+// restores the original value on cleanup, which also runs when the test fails or panics.
+func withClusterConfigMutation[T any](ctx context.Context, t testing.TB, m ClusterConfigMutation[T], value T, body func(ctx context.Context)) {
+	t.Helper()
+	restore, err := m.Apply(ctx, t, value)
+	if restore != nil {
+		t.Cleanup(func() {
+			// the test context may be done by the time the cleanup runs, restoring must not be skipped
+			if err := restore(context.WithoutCancel(ctx)); err != nil {
+				t.Errorf("%v", err)
+			}
+		})
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bodyCtx, cancel := context.WithCancel(ctx)
+	if test, ok := t.(interface{ Deadline() (time.Time, bool) }); ok {
+		if deadline, ok := test.Deadline(); ok {
+			cancel()
+			bodyCtx, cancel = context.WithDeadline(ctx, deadline)
+		}
+	}
+	defer cancel()
+	body(bodyCtx)
+}
@@ -0,0 +1,166 @@
+package framework
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeConfig records the values written through a ClusterConfigMutation and how often it settled.
+type fakeConfig struct {
+	value   string
+	writes  []string
+	settles int
+}
+
+func (f *fakeConfig) mutation() ClusterConfigMutation[string] {
+	return ClusterConfigMutation[string]{
+		Name: "fake value",
+		Get:  func(context.Context) (string, error) { return f.value, nil },
+		Set: func(_ context.Context, value string) error {
+			f.value = value
+			f.writes = append(f.writes, value)
+			return nil
+		},
+		Settle: func(context.Context) error {
+			f.settles++
+			return nil
+		},
+		VerifyTimeout:      time.Second,
+		verifyPollInterval: time.Millisecond,
+	}
+}
+
+func TestClusterConfigMutation(t *testing.T) {
+	config := &fakeConfig{value: "original"}
+	verifications := 0
+	mutation := config.mutation()
+	mutation.Verify = func(_ context.Context, original string) error {
+		verifications++
+		if original != "original" {
+			t.Errorf("expected the original value to be verified, got %q", original)
+		}
+		// the operator takes a while to revert what it derived from the value
+		if verifications < 3 {
+			return errors.New("not reverted yet")
+		}
+		return nil
+	}
+
+	restore, err := mutation.Apply(context.Background(), t, "changed")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.value != "changed" || config.settles != 1 {
+		t.Fatalf("expected the value to be changed and settled once, got %q settled %d times", config.value, config.settles)
+	}
+	if verifications != 0 {
+		t.Fatal("expected no verification before the restore")
+	}
+
+	if err := restore(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if expected := []string{"changed", "original"}; !reflect.DeepEqual(config.writes, expected) {
+		t.Errorf("expected the writes %v, got %v", expected, config.writes)
+	}
+	if config.settles != 2 {
+		t.Errorf("expected to settle after the restore, settled %d times", config.settles)
+	}
+	if verifications != 3 {
+		t.Errorf("expected the verification to be retried until it passed, got %d verifications", verifications)
+	}
+}
+
+func TestClusterConfigMutationGetError(t *testing.T) {
+	config := &fakeConfig{value: "original"}
+	mutation := config.mutation()
+	mutation.Get = func(context.Context) (string, error) { return "", errors.New("boom") }
+
+	restore, err := mutation.Apply(context.Background(), t, "changed")
+	if err == nil || !strings.Contains(err.Error(), "failed to get the original fake value") {
+		t.Errorf("expected the get error, got %v", err)
+	}
+	if restore != nil {
+		t.Error("expected no restore without the original value")
+	}
+	if len(config.writes) > 0 {
+		t.Errorf("expected nothing to be written, got %v", config.writes)
+	}
+}
+
+func TestClusterConfigMutationRestoresAfterSettleError(t *testing.T) {
+	config := &fakeConfig{value: "original"}
+	mutation := config.mutation()
+	mutation.Settle = func(context.Context) error {
+		config.settles++
+		if config.settles == 1 {
+			return errors.New("still progressing")
+		}
+		return nil
+	}
+
+	restore, err := mutation.Apply(context.Background(), t, "changed")
+	if err == nil || !strings.Contains(err.Error(), "still progressing") {
+		t.Errorf("expected the settle error, got %v", err)
+	}
+	if restore == nil {
+		t.Fatal("expected a restore for the written value")
+	}
+	if err := restore(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if config.value != "original" {
+		t.Errorf("expected the original value to be restored, got %q", config.value)
+	}
+}
+
+func TestClusterConfigMutationVerifyTimeout(t *testing.T) {
+	config := &fakeConfig{value: "original"}
+	mutation := config.mutation()
+	mutation.VerifyTimeout = 20 * time.Millisecond
+	mutation.Verify = func(context.Context, string) error { return errors.New("value still derived from the change") }
+
+	restore, err := mutation.Apply(context.Background(), t, "changed")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = restore(context.Background())
+	if err == nil {
+		t.Fatal("expected the verification to time out")
+	}
+	for _, expected := range []string{"the fake value was not reverted", "value still derived from the change"} {
+		if !strings.Contains(err.Error(), expected) {
+			t.Errorf("expected %q in error %q", expected, err.Error())
+		}
+	}
+}
+
+func TestWithClusterConfigMutation(t *testing.T) {
+	config := &fakeConfig{value: "original"}
+	ran := false
+	t.Run("test", func(t *testing.T) {
+		withClusterConfigMutation(context.Background(), t, config.mutation(), "changed", func(ctx context.Context) {
+			ran = true
+			if config.value != "changed" || config.settles != 1 {
+				t.Errorf("expected the body to run on the settled value, got %q settled %d times", config.value, config.settles)
+			}
+			testDeadline, ok := t.Deadline()
+			if deadline, hasDeadline := ctx.Deadline(); ok && (!hasDeadline || !deadline.Equal(testDeadline)) {
+				t.Errorf("expected the body context to end at the test deadline %v, got %v", testDeadline, deadline)
+			}
+			if config.writes[len(config.writes)-1] != "changed" {
+				t.Errorf("expected the value not to be restored while the body runs, got %v", config.writes)
+			}
+		})
+	})
+	if !ran {
+		t.Fatal("expected the body to run")
+	}
+	if expected := []string{"changed", "original"}; !reflect.DeepEqual(config.writes, expected) {
+		t.Errorf("expected the original value to be restored on cleanup, got the writes %v", config.writes)
+	}
+}
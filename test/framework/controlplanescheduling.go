@@ -0,0 +1,115 @@
+package framework
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+
+	"github.com/openshift/cluster-openshift-controller-manager-operator/pkg/util"
+)
+
+const (
+	masterNodeRoleLabel        = "node-role.kubernetes.io/master"
+	controlPlaneNodeRoleLabel  = "node-role.kubernetes.io/control-plane"
+	routeControllerPodSelector = "app=route-controller-manager"
+)
+
+// controlPlaneTaints are the taints of the control-plane nodes, which carry the legacy master role, the
+// control-plane role or both.
+var controlPlaneTaints = []corev1.Taint{
+	{Key: masterNodeRoleLabel, Effect: corev1.TaintEffectNoSchedule},
+	{Key: controlPlaneNodeRoleLabel, Effect: corev1.TaintEffectNoSchedule},
+}
+
+// operandPods are the label selectors of the pods of the operand deployments by their namespace.
+var operandPods = []struct {
+	namespace string
+	selector  string
+}{
+	{namespace: util.TargetNamespace, selector: operandPodSelector},
+	{namespace: util.RouteControllerTargetNamespace, selector: routeControllerPodSelector},
+}
+
+type controlPlaneSchedulingClient interface {
+	clientcorev1.PodsGetter
+	clientcorev1.NodesGetter
+}
+
+// AssertOperandControlPlaneScheduling fails the test unless every pod of the operand deployments tolerates
+// the taints of the control-plane nodes and runs on a node with the master or the control-plane role,
+// listing every pod which does not.
+func AssertOperandControlPlaneScheduling(ctx context.Context, t testing.TB, client *Clientset) {
+	t.Helper()
+	// the config client has Nodes too
+	if err := checkOperandControlPlaneScheduling(ctx, client.CoreV1Interface); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func checkOperandControlPlaneScheduling(ctx context.Context, client controlPlaneSchedulingClient) error {
+	var failures []string
+	for _, operand := range operandPods {
+		pods, err := client.Pods(operand.namespace).List(ctx, metav1.ListOptions{LabelSelector: operand.selector})
+		if err != nil {
+			return fmt.Errorf("failed to list the operand pods in %s: %w", operand.namespace, err)
+		}
+		if len(pods.Items) == 0 {
+			return fmt.Errorf("found no operand pods with %q in %s", operand.selector, operand.namespace)
+		}
+		for _, pod := range pods.Items {
+			if pod.DeletionTimestamp != nil {
+				continue
+			}
+			if failure := checkPodControlPlaneScheduling(ctx, client, &pod); len(failure) > 0 {
+				failures = append(failures, fmt.Sprintf("pod/%s -n %s %s", pod.Name, pod.Namespace, failure))
+			}
+		}
+	}
+	if len(failures) > 0 {
+		return fmt.Errorf("%d operand pods are not scheduled on the control-plane nodes:\n%s", len(failures), strings.Join(failures, "\n"))
+	}
+	return nil
+}
+
+// checkPodControlPlaneScheduling returns why the pod is not scheduled on a control-plane node, empty if it is.
+func checkPodControlPlaneScheduling(ctx context.Context, client clientcorev1.NodesGetter, pod *corev1.Pod) string {
+	var untolerated []string
+	for _, taint := range controlPlaneTaints {
+		tolerated := false
+		for _, toleration := range pod.Spec.Tolerations {
+			if toleration.ToleratesTaint(&taint) {
+				tolerated = true
+				break
+			}
+		}
+		if !tolerated {
+			untolerated = append(untolerated, taint.ToString())
+		}
+	}
+	if len(untolerated) > 0 {
+		return fmt.Sprintf("does not tolerate %s", strings.Join(untolerated, ", "))
+	}
+
+	if len(pod.Spec.NodeName) == 0 {
+		return "is not scheduled"
+	}
+	node, err := client.Nodes().Get(ctx, pod.Spec.NodeName, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Sprintf("runs on node/%s which cannot be read: %v", pod.Spec.NodeName, err)
+	}
+	if !isControlPlaneNode(node) {
+		return fmt.Sprintf("runs on node/%s which is not a control-plane node", node.Name)
+	}
+	return ""
+}
+
+func isControlPlaneNode(node *corev1.Node) bool {
+	_, master := node.Labels[masterNodeRoleLabel]
+	_, controlPlane := node.Labels[controlPlaneNodeRoleLabel]
+	return master || controlPlane
+}
@@ -0,0 +1,93 @@
+package framework
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func controlPlaneNode(name string, labels ...string) *corev1.Node {
+	node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{}}}
+	for _, label := range labels {
+		node.Labels[label] = ""
+	}
+	return node
+}
+
+func scheduledOperandPod(name, namespace, app, nodeName string, tolerations ...corev1.Toleration) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Labels: map[string]string{"app": app}},
+		Spec:       corev1.PodSpec{NodeName: nodeName, Tolerations: tolerations},
+	}
+}
+
+func TestCheckOperandControlPlaneScheduling(t *testing.T) {
+	tolerateMaster := corev1.Toleration{Key: masterNodeRoleLabel, Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule}
+	tolerateControlPlane := corev1.Toleration{Key: controlPlaneNodeRoleLabel, Operator: corev1.TolerationOpExists, Effect: corev1.TaintEffectNoSchedule}
+	tolerateAll := corev1.Toleration{Operator: corev1.TolerationOpExists}
+	nodes := []runtime.Object{
+		controlPlaneNode("master-0", masterNodeRoleLabel),
+		controlPlaneNode("control-plane-0", controlPlaneNodeRoleLabel),
+		controlPlaneNode("worker-0", "node-role.kubernetes.io/worker"),
+	}
+
+	tests := []struct {
+		name           string
+		pods           []runtime.Object
+		expectedErrors []string
+	}{
+		{
+			name: "legacy and newer control-plane nodes",
+			pods: []runtime.Object{
+				scheduledOperandPod("controller-manager-a", "openshift-controller-manager", "openshift-controller-manager-a", "master-0", tolerateMaster, tolerateControlPlane),
+				scheduledOperandPod("route-controller-manager-a", "openshift-route-controller-manager", "route-controller-manager", "control-plane-0", tolerateAll),
+			},
+		},
+		{
+			name: "missing toleration and worker node",
+			pods: []runtime.Object{
+				scheduledOperandPod("controller-manager-a", "openshift-controller-manager", "openshift-controller-manager-a", "master-0", tolerateControlPlane),
+				scheduledOperandPod("route-controller-manager-a", "openshift-route-controller-manager", "route-controller-manager", "worker-0", tolerateAll),
+				scheduledOperandPod("route-controller-manager-b", "openshift-route-controller-manager", "route-controller-manager", "", tolerateAll),
+			},
+			expectedErrors: []string{
+				"3 operand pods are not scheduled on the control-plane nodes",
+				"pod/controller-manager-a -n openshift-controller-manager does not tolerate node-role.kubernetes.io/master:NoSchedule",
+				"pod/route-controller-manager-a -n openshift-route-controller-manager runs on node/worker-0 which is not a control-plane node",
+				"pod/route-controller-manager-b -n openshift-route-controller-manager is not scheduled",
+			},
+		},
+		{
+			name: "no route controller pods",
+			pods: []runtime.Object{
+				scheduledOperandPod("controller-manager-a", "openshift-controller-manager", "openshift-controller-manager-a", "master-0", tolerateAll),
+			},
+			expectedErrors: []string{`found no operand pods with "app=route-controller-manager" in openshift-route-controller-manager`},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			client := fake.NewSimpleClientset(append(append([]runtime.Object{}, nodes...), tc.pods...)...).CoreV1()
+			err := checkOperandControlPlaneScheduling(context.TODO(), client)
+			if len(tc.expectedErrors) == 0 {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			for _, expected := range tc.expectedErrors {
+				if !strings.Contains(err.Error(), expected) {
+					t.Errorf("expected the error to contain %q, got %v", expected, err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package framework
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+
+	"github.com/openshift/cluster-openshift-controller-manager-operator/pkg/util"
+)
+
+const (
+	eventPollInterval = 5 * time.Second
+	eventTimeout      = 2 * time.Minute
+	// maxReportedEvents is how many of the most recent events a failed assertion lists.
+	maxReportedEvents = 10
+)
+
+// AssertEvent fails the test unless an event of the reason about the involved object is recorded within
+// two minutes. The events are looked up in the namespace of the object, those about cluster-scoped objects
+// in the operator namespace, where the operator records them. The kind and name of the object must match
+// and its UID too if set.
+func AssertEvent(ctx context.Context, t testing.TB, client *Clientset, involvedObject corev1.ObjectReference, reason string) {
+	t.Helper()
+	AssertEventSince(ctx, t, client, involvedObject, reason, time.Time{})
+}
+
+// AssertEventSince is AssertEvent for an event occurring after since, e.g. the time the test changed what
+// the event reports, so that an earlier occurrence does not satisfy it. A deduplicated event, one with a
+// count above one, matches when it last occurred after since, however long ago it first did.
+func AssertEventSince(ctx context.Context, t testing.TB, client *Clientset, involvedObject corev1.ObjectReference, reason string, since time.Time) {
+	t.Helper()
+	if err := assertEvent(ctx, t, client, involvedObject, reason, since, eventPollInterval, eventTimeout); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func assertEvent(ctx context.Context, logger Logger, client clientcorev1.EventsGetter, involvedObject corev1.ObjectReference, reason string, since time.Time, interval, timeout time.Duration) error {
+	namespace := involvedObject.Namespace
+	if len(namespace) == 0 {
+		namespace = util.OperatorNamespace
+	}
+
+	var events []corev1.Event
+	var lastErr error
+	err := poll(ctx, interval, timeout, func(ctx context.Context) (bool, error) {
+		list, err := client.Events(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			lastErr = err
+			logger.Logf("failed to list the events in %s: %v", namespace, err)
+			return false, nil
+		}
+		events, lastErr = list.Items, nil
+		for i := range events {
+			if eventMatches(&events[i], involvedObject, reason, since) {
+				logger.Logf("found event %s", describeEvent(&events[i]))
+				return true, nil
+			}
+		}
+		return false, nil
+	})
+	if err == nil {
+		return nil
+	}
+	object := fmt.Sprintf("%s/%s", involvedObject.Kind, involvedObject.Name)
+	if lastErr != nil {
+		return fmt.Errorf("no %s event about %s in %s, failed to list the events: %v: %w", reason, object, namespace, lastErr, err)
+	}
+	return fmt.Errorf("no %s event about %s in %s since %s: %w, the most recent events are:\n%s",
+		reason, object, namespace, since.Format(time.RFC3339), err, describeRecentEvents(events, maxReportedEvents))
+}
+
+// eventMatches returns whether the event has the reason, is about the object and last occurred after since.
+func eventMatches(event *corev1.Event, involvedObject corev1.ObjectReference, reason string, since time.Time) bool {
+	if event.Reason != reason {
+		return false
+	}
+	regarding := event.InvolvedObject
+	if regarding.Kind != involvedObject.Kind || regarding.Name != involvedObject.Name {
+		return false
+	}
+	if len(involvedObject.UID) > 0 && regarding.UID != involvedObject.UID {
+		return false
+	}
+	return eventLastOccurred(event).After(since)
+}
+
+// eventLastOccurred returns when the event last occurred: a deduplicated event is a single object whose
+// count and last timestamp, or series for events of the events.k8s.io API, are bumped on every occurrence.
+func eventLastOccurred(event *corev1.Event) time.Time {
+	last := event.FirstTimestamp.Time
+	for _, t := range []time.Time{event.LastTimestamp.Time, event.EventTime.Time} {
+		if t.After(last) {
+			last = t
+		}
+	}
+	if event.Series != nil && event.Series.LastObservedTime.Time.After(last) {
+		last = event.Series.LastObservedTime.Time
+	}
+	return last
+}
+
+func eventCount(event *corev1.Event) int32 {
+	if event.Series != nil && event.Series.Count > event.Count {
+		return event.Series.Count
+	}
+	if event.Count == 0 {
+		return 1
+	}
+	return event.Count
+}
+
+// describeRecentEvents describes the most recently occurred events, one per line and the latest first.
+func describeRecentEvents(events []corev1.Event, limit int) string {
+	if len(events) == 0 {
+		return "  none"
+	}
+	sorted := make([]*corev1.Event, 0, len(events))
+	for i := range events {
+		sorted = append(sorted, &events[i])
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return eventLastOccurred(sorted[i]).After(eventLastOccurred(sorted[j]))
+	})
+	if len(sorted) > limit {
+		sorted = sorted[:limit]
+	}
+	lines := make([]string, 0, len(sorted))
+	for _, event := range sorted {
+		lines = append(lines, "  "+describeEvent(event))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func describeEvent(event *corev1.Event) string {
+	return fmt.Sprintf("%s %s/%s (%d times, last at %s): %s", event.Reason, event.InvolvedObject.Kind, event.InvolvedObject.Name,
+		eventCount(event), eventLastOccurred(event).Format(time.RFC3339), event.Message)
+}
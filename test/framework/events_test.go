@@ -0,0 +1,125 @@
+package framework
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/openshift/cluster-openshift-controller-manager-operator/pkg/util"
+)
+
+func TestAssertEvent(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	operatorDeployment := corev1.ObjectReference{Kind: "Deployment", Namespace: util.OperatorNamespace, Name: "openshift-controller-manager-operator"}
+	event := func(name, reason string, regarding corev1.ObjectReference, count int32, first, last time.Time) *corev1.Event {
+		return &corev1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Name: name, Namespace: util.OperatorNamespace},
+			InvolvedObject: regarding,
+			Reason:         reason,
+			Message:        reason + " happened",
+			Count:          count,
+			FirstTimestamp: metav1.NewTime(first),
+			LastTimestamp:  metav1.NewTime(last),
+		}
+	}
+
+	tests := []struct {
+		name           string
+		events         []runtime.Object
+		involvedObject corev1.ObjectReference
+		reason         string
+		since          time.Time
+		expectedErr    []string
+	}{
+		{
+			name:           "recorded once",
+			events:         []runtime.Object{event("a", "ObservedConfigChanged", operatorDeployment, 1, start, start)},
+			involvedObject: operatorDeployment,
+			reason:         "ObservedConfigChanged",
+		},
+		{
+			name: "deduplicated event occurring again",
+			// first recorded before since, the count was bumped afterwards
+			events:         []runtime.Object{event("a", "ObservedConfigChanged", operatorDeployment, 3, start, start.Add(time.Hour))},
+			involvedObject: operatorDeployment,
+			reason:         "ObservedConfigChanged",
+			since:          start.Add(time.Minute),
+		},
+		{
+			name: "series of the events API",
+			events: []runtime.Object{func() *corev1.Event {
+				e := event("a", "ObservedConfigChanged", operatorDeployment, 0, time.Time{}, time.Time{})
+				e.EventTime = metav1.NewMicroTime(start)
+				e.Series = &corev1.EventSeries{Count: 2, LastObservedTime: metav1.NewMicroTime(start.Add(time.Hour))}
+				return e
+			}()},
+			involvedObject: operatorDeployment,
+			reason:         "ObservedConfigChanged",
+			since:          start.Add(time.Minute),
+		},
+		{
+			name:           "cluster-scoped object",
+			events:         []runtime.Object{event("a", "OperatorStatusChanged", corev1.ObjectReference{Kind: "ClusterOperator", Name: "openshift-controller-manager"}, 1, start, start)},
+			involvedObject: corev1.ObjectReference{Kind: "ClusterOperator", Name: "openshift-controller-manager"},
+			reason:         "OperatorStatusChanged",
+		},
+		{
+			name:           "only before since",
+			events:         []runtime.Object{event("a", "ObservedConfigChanged", operatorDeployment, 5, start, start)},
+			involvedObject: operatorDeployment,
+			reason:         "ObservedConfigChanged",
+			since:          start.Add(time.Minute),
+			expectedErr:    []string{"no ObservedConfigChanged event about Deployment/openshift-controller-manager-operator", "ObservedConfigChanged Deployment/openshift-controller-manager-operator (5 times, last at 2024-01-01T12:00:00Z): ObservedConfigChanged happened"},
+		},
+		{
+			name: "other object and reason",
+			events: []runtime.Object{
+				event("a", "ObservedConfigChanged", corev1.ObjectReference{Kind: "Deployment", Namespace: util.OperatorNamespace, Name: "other"}, 1, start, start),
+				event("b", "DeploymentUpdated", operatorDeployment, 1, start, start.Add(time.Minute)),
+			},
+			involvedObject: operatorDeployment,
+			reason:         "ObservedConfigChanged",
+			// the latest first
+			expectedErr: []string{"most recent events are:\n  DeploymentUpdated Deployment/openshift-controller-manager-operator (1 times, last at 2024-01-01T12:01:00Z): DeploymentUpdated happened\n  ObservedConfigChanged Deployment/other"},
+		},
+		{
+			name:           "UID mismatch",
+			events:         []runtime.Object{event("a", "ObservedConfigChanged", corev1.ObjectReference{Kind: "Deployment", Name: "openshift-controller-manager-operator", UID: "old"}, 1, start, start)},
+			involvedObject: corev1.ObjectReference{Kind: "Deployment", Namespace: util.OperatorNamespace, Name: "openshift-controller-manager-operator", UID: "new"},
+			reason:         "ObservedConfigChanged",
+			expectedErr:    []string{"no ObservedConfigChanged event"},
+		},
+		{
+			name:           "no events",
+			involvedObject: operatorDeployment,
+			reason:         "ObservedConfigChanged",
+			expectedErr:    []string{"most recent events are:\n  none"},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			client := fake.NewSimpleClientset(tc.events...)
+			err := assertEvent(context.TODO(), t, client.CoreV1(), tc.involvedObject, tc.reason, tc.since, time.Millisecond, 20*time.Millisecond)
+			if len(tc.expectedErr) == 0 {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			for _, expected := range tc.expectedErr {
+				if !strings.Contains(err.Error(), expected) {
+					t.Errorf("expected the error to contain %q, got %q", expected, err)
+				}
+			}
+		})
+	}
+}
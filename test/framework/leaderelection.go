@@ -0,0 +1,126 @@
+package framework
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientcoordinationv1 "k8s.io/client-go/kubernetes/typed/coordination/v1"
+	clientcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+
+	"github.com/openshift/cluster-openshift-controller-manager-operator/pkg/util"
+)
+
+const (
+	// operatorLeaseName is the Lease the operator replicas elect their leader with, named after the
+	// component of the operator command.
+	operatorLeaseName = "openshift-controller-manager-operator-lock"
+	// leaderElectionPollInterval is how often the leader election helpers check the Lease.
+	leaderElectionPollInterval = 5 * time.Second
+	// singleLeaderTimeout is how long AssertSingleLeader waits for a leader, it outlasts the lease
+	// duration of a highly available control plane.
+	singleLeaderTimeout = 3 * time.Minute
+)
+
+type leaderElectionClient interface {
+	clientcoordinationv1.LeasesGetter
+	clientcorev1.PodsGetter
+}
+
+// AssertSingleLeader waits for the Lease of the operator to be held and renewed by a single running
+// operator pod and returns the name of that pod. It fails the test with why the Lease has no such holder
+// if it does not within singleLeaderTimeout.
+func AssertSingleLeader(ctx context.Context, t testing.TB, client *Clientset) string {
+	t.Helper()
+	leader, err := waitForSingleLeader(ctx, t, client, "", leaderElectionPollInterval, singleLeaderTimeout)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return leader
+}
+
+// AssertLeaderFailover is a DISRUPTIVE helper for [Disruptive] suites only. It deletes the pod of the
+// current leader of the operator and asserts that another operator pod takes over the Lease within timeout,
+// returning the name of the new leader. The deployment replaces the deleted pod.
+func AssertLeaderFailover(ctx context.Context, t testing.TB, client *Clientset, timeout time.Duration) string {
+	t.Helper()
+	leader := AssertSingleLeader(ctx, t, client)
+	newLeader, err := failOverLeader(ctx, t, client, leader, leaderElectionPollInterval, timeout)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return newLeader
+}
+
+func failOverLeader(ctx context.Context, logger Logger, client leaderElectionClient, leader string, interval, timeout time.Duration) (string, error) {
+	if err := client.Pods(util.OperatorNamespace).Delete(ctx, leader, metav1.DeleteOptions{}); err != nil {
+		return "", fmt.Errorf("failed to delete the leader pod/%s -n %s: %w", leader, util.OperatorNamespace, err)
+	}
+	logger.Logf("deleted the leader pod/%s -n %s", leader, util.OperatorNamespace)
+	return waitForSingleLeader(ctx, logger, client, leader, interval, timeout)
+}
+
+// waitForSingleLeader waits for the Lease to be held by a pod other than formerLeader, any pod if that is
+// empty, and returns its name.
+func waitForSingleLeader(ctx context.Context, logger Logger, client leaderElectionClient, formerLeader string, interval, timeout time.Duration) (string, error) {
+	var leader, lastState string
+	err := poll(ctx, interval, timeout, func(ctx context.Context) (bool, error) {
+		var err error
+		leader, err = currentLeader(ctx, client, time.Now())
+		if err == nil && leader == formerLeader {
+			err = fmt.Errorf("the Lease is still held by the former leader pod/%s", formerLeader)
+		}
+		if err != nil {
+			if err.Error() != lastState {
+				logger.Logf("waiting for a single leader of the operator: %v", err)
+			}
+			lastState = err.Error()
+			return false, nil
+		}
+		return true, nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("lease/%s -n %s did not get a single leader, %s: %w", operatorLeaseName, util.OperatorNamespace, lastState, err)
+	}
+	return leader, nil
+}
+
+// currentLeader returns the name of the operator pod holding the Lease, or why no running pod holds it.
+func currentLeader(ctx context.Context, client leaderElectionClient, now time.Time) (string, error) {
+	lease, err := client.Leases(util.OperatorNamespace).Get(ctx, operatorLeaseName, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return "", fmt.Errorf("the Lease does not exist")
+	}
+	if err != nil {
+		return "", err
+	}
+	if lease.Spec.HolderIdentity == nil || len(*lease.Spec.HolderIdentity) == 0 {
+		return "", fmt.Errorf("the Lease has no holder")
+	}
+	holder := *lease.Spec.HolderIdentity
+	if lease.Spec.RenewTime != nil && lease.Spec.LeaseDurationSeconds != nil {
+		expiry := lease.Spec.RenewTime.Add(time.Duration(*lease.Spec.LeaseDurationSeconds) * time.Second)
+		if now.After(expiry) {
+			return "", fmt.Errorf("the Lease of %s expired at %s", holder, expiry.UTC().Format(time.RFC3339))
+		}
+	}
+
+	// the identity of a replica is the name of its pod followed by a unique suffix
+	podName, _, _ := strings.Cut(holder, "_")
+	pod, err := client.Pods(util.OperatorNamespace).Get(ctx, podName, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return "", fmt.Errorf("the Lease is held by %s, pod/%s does not exist", holder, podName)
+	}
+	if err != nil {
+		return "", err
+	}
+	if pod.Status.Phase != corev1.PodRunning || pod.DeletionTimestamp != nil {
+		return "", fmt.Errorf("the Lease is held by %s, pod/%s is not running", holder, podName)
+	}
+	return podName, nil
+}
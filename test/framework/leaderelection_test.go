@@ -0,0 +1,147 @@
+package framework
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+	"k8s.io/utils/ptr"
+)
+
+func operatorLease(holder string, renewTime time.Time) *coordinationv1.Lease {
+	return &coordinationv1.Lease{
+		ObjectMeta: metav1.ObjectMeta{Name: "openshift-controller-manager-operator-lock", Namespace: "openshift-controller-manager-operator"},
+		Spec: coordinationv1.LeaseSpec{
+			HolderIdentity:       ptr.To(holder),
+			LeaseDurationSeconds: ptr.To[int32](137),
+			RenewTime:            &metav1.MicroTime{Time: renewTime},
+		},
+	}
+}
+
+func operatorPod(name string, phase corev1.PodPhase) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "openshift-controller-manager-operator"},
+		Status:     corev1.PodStatus{Phase: phase},
+	}
+}
+
+func TestWaitForSingleLeader(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name           string
+		objects        []runtime.Object
+		expectedLeader string
+		expectedErrors []string
+	}{
+		{
+			name: "running holder",
+			objects: []runtime.Object{
+				operatorLease("operator-a_6b1d1f6c", now),
+				operatorPod("operator-a", corev1.PodRunning),
+				operatorPod("operator-b", corev1.PodRunning),
+			},
+			expectedLeader: "operator-a",
+		},
+		{
+			name:           "no lease",
+			objects:        []runtime.Object{operatorPod("operator-a", corev1.PodRunning)},
+			expectedErrors: []string{"lease/openshift-controller-manager-operator-lock -n openshift-controller-manager-operator did not get a single leader", "the Lease does not exist"},
+		},
+		{
+			name: "expired lease",
+			objects: []runtime.Object{
+				operatorLease("operator-a_6b1d1f6c", now.Add(-5*time.Minute)),
+				operatorPod("operator-a", corev1.PodRunning),
+			},
+			expectedErrors: []string{"the Lease of operator-a_6b1d1f6c expired at"},
+		},
+		{
+			name:           "holder pod gone",
+			objects:        []runtime.Object{operatorLease("operator-a_6b1d1f6c", now)},
+			expectedErrors: []string{"the Lease is held by operator-a_6b1d1f6c, pod/operator-a does not exist"},
+		},
+		{
+			name: "holder pod not running",
+			objects: []runtime.Object{
+				operatorLease("operator-a_6b1d1f6c", now),
+				operatorPod("operator-a", corev1.PodFailed),
+			},
+			expectedErrors: []string{"pod/operator-a is not running"},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			kubeClient := fake.NewSimpleClientset(tc.objects...)
+			client := &Clientset{CoreV1Interface: kubeClient.CoreV1(), CoordinationV1Interface: kubeClient.CoordinationV1()}
+
+			leader, err := waitForSingleLeader(context.TODO(), t, client, "", time.Millisecond, 20*time.Millisecond)
+			if len(tc.expectedErrors) == 0 {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if leader != tc.expectedLeader {
+					t.Errorf("expected the leader %q, got %q", tc.expectedLeader, leader)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			for _, expected := range tc.expectedErrors {
+				if !strings.Contains(err.Error(), expected) {
+					t.Errorf("expected the error to contain %q, got %v", expected, err)
+				}
+			}
+		})
+	}
+}
+
+func TestFailOverLeader(t *testing.T) {
+	kubeClient := fake.NewSimpleClientset(
+		operatorLease("operator-a_6b1d1f6c", time.Now()),
+		operatorPod("operator-a", corev1.PodRunning),
+		operatorPod("operator-b", corev1.PodRunning),
+	)
+	client := &Clientset{CoreV1Interface: kubeClient.CoreV1(), CoordinationV1Interface: kubeClient.CoordinationV1()}
+	// the other replica takes over the Lease once the leader is gone
+	kubeClient.PrependReactor("delete", "pods", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		lease := operatorLease("operator-b_0f3e9a42", time.Now())
+		if err := kubeClient.Tracker().Update(coordinationv1.SchemeGroupVersion.WithResource("leases"), lease, lease.Namespace); err != nil {
+			t.Error(err)
+		}
+		return false, nil, nil
+	})
+
+	leader, err := failOverLeader(context.TODO(), t, client, "operator-a", time.Millisecond, time.Second)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if leader != "operator-b" {
+		t.Errorf("expected operator-b to take over, got %q", leader)
+	}
+}
+
+func TestFailOverLeaderTimeout(t *testing.T) {
+	kubeClient := fake.NewSimpleClientset(
+		// the deleted leader was recreated under the same name and holds the Lease again
+		operatorLease("operator-a_6b1d1f6c", time.Now()),
+		operatorPod("operator-a", corev1.PodRunning),
+	)
+	client := &Clientset{CoreV1Interface: kubeClient.CoreV1(), CoordinationV1Interface: kubeClient.CoordinationV1()}
+	kubeClient.PrependReactor("delete", "pods", func(clienttesting.Action) (bool, runtime.Object, error) {
+		return true, nil, nil
+	})
+
+	_, err := failOverLeader(context.TODO(), t, client, "operator-a", time.Millisecond, 20*time.Millisecond)
+	if err == nil || !strings.Contains(err.Error(), "the Lease is still held by the former leader pod/operator-a") {
+		t.Errorf("expected an error naming the former leader, got %v", err)
+	}
+}
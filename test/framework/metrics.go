@@ -0,0 +1,191 @@
+package framework
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientappsv1 "k8s.io/client-go/kubernetes/typed/apps/v1"
+	clientcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/utils/ptr"
+
+	"github.com/openshift/cluster-openshift-controller-manager-operator/pkg/util"
+)
+
+const (
+	// operatorMetricsURL is the metrics endpoint of the operator behind its metrics service.
+	operatorMetricsURL = "https://metrics." + util.OperatorNamespace + ".svc/metrics"
+	// metricsScraperNamespace and metricsScraperServiceAccount are who scrapes the operator metrics in the
+	// cluster, the scrape is authorized as them so that a broken RBAC for the scraping fails it too.
+	metricsScraperNamespace      = "openshift-monitoring"
+	metricsScraperServiceAccount = "prometheus-k8s"
+	// serviceCAFile is the service CA OpenShift adds to the service account token volume of every pod.
+	serviceCAFile = "/var/run/secrets/kubernetes.io/serviceaccount/service-ca.crt"
+
+	metricsScrapeInterval = 10 * time.Second
+	metricsScrapeTimeout  = 3 * time.Minute
+	// metricsScrapePodTimeout bounds a single scrape pod, so that a pod which cannot start does not use up
+	// the whole wait.
+	metricsScrapePodTimeout = time.Minute
+)
+
+type metricsClient interface {
+	clientcorev1.NamespacesGetter
+	clientcorev1.PodsGetter
+	clientcorev1.ServiceAccountsGetter
+	clientappsv1.DeploymentsGetter
+}
+
+// AssertMetricExposed fails the test unless the operator metrics endpoint exposes samples of the named
+// metric. The API server does not pass bearer tokens on through its proxy, so the endpoint is scraped
+// from a pod in a test namespace: it runs curl from the operator image against the metrics service over
+// TLS verified with the service CA, with a short-lived token of the service account Prometheus scrapes
+// with. Metrics without samples yet, e.g. right after the operator restarted, are waited for.
+func AssertMetricExposed(ctx context.Context, t testing.TB, client *Clientset, metricName string) {
+	t.Helper()
+	namespace := CreateTestNamespace(ctx, t, client)
+	if err := assertMetricExposed(ctx, t, client, namespace, metricName, metricsScrapeInterval, metricsScrapeTimeout, metricsScrapePodTimeout); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func assertMetricExposed(ctx context.Context, logger Logger, client metricsClient, namespace, metricName string, interval, timeout, podTimeout time.Duration) error {
+	image, err := operatorImage(ctx, client)
+	if err != nil {
+		return err
+	}
+
+	var lastErr error
+	err = poll(ctx, interval, timeout, func(ctx context.Context) (bool, error) {
+		var body []byte
+		body, lastErr = scrapeOperatorMetrics(ctx, client, namespace, image, podTimeout)
+		if lastErr != nil {
+			logger.Logf("failed to scrape %s: %v", operatorMetricsURL, lastErr)
+			return false, nil
+		}
+		if !metricExposed(body, metricName) {
+			lastErr = fmt.Errorf("%s has no samples of %s", operatorMetricsURL, metricName)
+			logger.Logf("%v", lastErr)
+			return false, nil
+		}
+		return true, nil
+	})
+	if err != nil {
+		return fmt.Errorf("metric %s is not exposed, last error: %v: %w", metricName, lastErr, err)
+	}
+	return nil
+}
+
+// operatorImage returns the image of the operator container, it has curl.
+func operatorImage(ctx context.Context, client clientappsv1.DeploymentsGetter) (string, error) {
+	deployment, err := client.Deployments(util.OperatorNamespace).Get(ctx, operatorDeploymentName, metav1.GetOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to get deployment/%s -n %s: %w", operatorDeploymentName, util.OperatorNamespace, err)
+	}
+	for _, container := range deployment.Spec.Template.Spec.Containers {
+		if container.Name == "openshift-controller-manager-operator" {
+			return container.Image, nil
+		}
+	}
+	return "", fmt.Errorf("deployment/%s -n %s has no openshift-controller-manager-operator container", operatorDeploymentName, util.OperatorNamespace)
+}
+
+// scrapeOperatorMetrics runs a pod scraping the operator metrics once and returns what it scraped.
+func scrapeOperatorMetrics(ctx context.Context, client metricsClient, namespace, image string, podTimeout time.Duration) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, podTimeout)
+	defer cancel()
+
+	token, err := client.ServiceAccounts(metricsScraperNamespace).CreateToken(ctx, metricsScraperServiceAccount, &authenticationv1.TokenRequest{
+		Spec: authenticationv1.TokenRequestSpec{ExpirationSeconds: ptr.To[int64](600)},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create a token for serviceaccount/%s -n %s: %w", metricsScraperServiceAccount, metricsScraperNamespace, err)
+	}
+
+	return runPod(ctx, client, metricsScrapePod(namespace, image, token.Status.Token), podTimeout)
+}
+
+// runPod creates the pod, waits for it to complete within podTimeout and returns its logs. The pod is
+// deleted again, a pod which failed is returned as an error with its logs.
+func runPod(ctx context.Context, client clientcorev1.PodsGetter, pod *corev1.Pod, podTimeout time.Duration) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, podTimeout)
+	defer cancel()
+
+	namespace := pod.Namespace
+	created, err := client.Pods(namespace).Create(ctx, pod, metav1.CreateOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create a %s pod in %s: %w", pod.GenerateName, namespace, err)
+	}
+	pod = created
+	defer func() {
+		// the namespace is deleted in the end anyway, this keeps the failed attempts from piling up
+		_ = client.Pods(namespace).Delete(context.Background(), pod.Name, metav1.DeleteOptions{})
+	}()
+
+	err = poll(ctx, time.Second, podTimeout, func(ctx context.Context) (bool, error) {
+		current, err := client.Pods(namespace).Get(ctx, pod.Name, metav1.GetOptions{})
+		if err != nil {
+			return false, nil
+		}
+		pod = current
+		return pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("pod/%s -n %s did not complete, it is %s: %w", pod.Name, namespace, pod.Status.Phase, err)
+	}
+	logs, err := client.Pods(namespace).GetLogs(pod.Name, &corev1.PodLogOptions{}).DoRaw(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the logs of pod/%s -n %s: %w", pod.Name, namespace, err)
+	}
+	if pod.Status.Phase == corev1.PodFailed {
+		return nil, fmt.Errorf("pod/%s -n %s failed: %s", pod.Name, namespace, bytes.TrimSpace(logs))
+	}
+	return logs, nil
+}
+
+// metricsScrapePod returns a pod printing the operator metrics once.
+func metricsScrapePod(namespace, image, token string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: "metrics-scrape-",
+			Namespace:    namespace,
+		},
+		Spec: corev1.PodSpec{
+			RestartPolicy: corev1.RestartPolicyNever,
+			Containers: []corev1.Container{{
+				Name:    "scrape",
+				Image:   image,
+				Command: []string{"/bin/bash", "-c"},
+				Args: []string{fmt.Sprintf(`curl --silent --show-error --fail --max-time 30 --cacert %s -H "Authorization: Bearer ${TOKEN}" %s`,
+					serviceCAFile, operatorMetricsURL)},
+				Env: []corev1.EnvVar{{Name: "TOKEN", Value: token}},
+			}},
+		},
+	}
+}
+
+// metricExposed returns whether the metrics in the text exposition format have samples of the named
+// metric, for histograms and summaries their _bucket, _sum and _count series count.
+func metricExposed(body []byte, metricName string) bool {
+	scanner := bufio.NewScanner(bytes.NewReader(body))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		for _, series := range []string{metricName, metricName + "_bucket", metricName + "_sum", metricName + "_count"} {
+			if rest, ok := strings.CutPrefix(line, series); ok && (strings.HasPrefix(rest, "{") || strings.HasPrefix(rest, " ")) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,112 @@
+package framework
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+)
+
+func TestMetricExposed(t *testing.T) {
+	body := []byte(`# HELP openshift_controller_manager_operator_reconcile_duration_seconds Time in seconds a sync took.
+# TYPE openshift_controller_manager_operator_reconcile_duration_seconds histogram
+openshift_controller_manager_operator_reconcile_duration_seconds_bucket{controller="ConfigObserver",le="0.01"} 3
+openshift_controller_manager_operator_reconcile_duration_seconds_sum{controller="ConfigObserver"} 0.02
+openshift_controller_manager_operator_reconcile_duration_seconds_count{controller="ConfigObserver"} 3
+# HELP openshift_controller_manager_operator_observed_config_changes_total Number of changes.
+# TYPE openshift_controller_manager_operator_observed_config_changes_total counter
+go_goroutines 42
+`)
+
+	tests := []struct {
+		metricName string
+		expected   bool
+	}{
+		{metricName: "openshift_controller_manager_operator_reconcile_duration_seconds", expected: true},
+		{metricName: "go_goroutines", expected: true},
+		// declared without samples
+		{metricName: "openshift_controller_manager_operator_observed_config_changes_total"},
+		// a prefix of an exposed metric
+		{metricName: "openshift_controller_manager_operator_reconcile"},
+		{metricName: "go_goroutine"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.metricName, func(t *testing.T) {
+			if got := metricExposed(body, tc.metricName); got != tc.expected {
+				t.Errorf("expected exposed %t, got %t", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestMetricsScrapePod(t *testing.T) {
+	pod := metricsScrapePod("e2e-test", "quay.io/openshift/operator:latest", "secret-token")
+	if pod.Spec.RestartPolicy != corev1.RestartPolicyNever {
+		t.Errorf("expected the pod not to be restarted, got %s", pod.Spec.RestartPolicy)
+	}
+	container := pod.Spec.Containers[0]
+	command := strings.Join(append(container.Command, container.Args...), " ")
+	for _, expected := range []string{"https://metrics.openshift-controller-manager-operator.svc/metrics", "--cacert " + serviceCAFile, "Bearer ${TOKEN}", "--max-time"} {
+		if !strings.Contains(command, expected) {
+			t.Errorf("expected the command to contain %q, got %q", expected, command)
+		}
+	}
+	if strings.Contains(command, "secret-token") {
+		t.Errorf("expected the token not to be in the command, got %q", command)
+	}
+	if len(container.Env) != 1 || container.Env[0].Value != "secret-token" {
+		t.Errorf("expected the token in the environment, got %v", container.Env)
+	}
+}
+
+func TestAssertMetricExposedTimesOut(t *testing.T) {
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "openshift-controller-manager-operator", Namespace: "openshift-controller-manager-operator"},
+		Spec: appsv1.DeploymentSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{{Name: "openshift-controller-manager-operator", Image: "quay.io/openshift/operator:latest"}},
+				},
+			},
+		},
+	}
+	kubeClient := fake.NewSimpleClientset(deployment)
+	kubeClient.PrependReactor("create", "serviceaccounts", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		if action.GetSubresource() != "token" {
+			return false, nil, nil
+		}
+		return true, &authenticationv1.TokenRequest{Status: authenticationv1.TokenRequestStatus{Token: "token"}}, nil
+	})
+	client := &Clientset{CoreV1Interface: kubeClient.CoreV1(), AppsV1Interface: kubeClient.AppsV1()}
+
+	// the scrape pods never run
+	start := time.Now()
+	err := assertMetricExposed(context.TODO(), t, client, "e2e-test", "openshift_controller_manager_operator_reconcile_duration_seconds",
+		10*time.Millisecond, 200*time.Millisecond, 50*time.Millisecond)
+	if err == nil {
+		t.Fatal("expected an error for the metrics not being scraped")
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("expected the assertion to time out after 200ms, took %v", elapsed)
+	}
+	for _, expected := range []string{"openshift_controller_manager_operator_reconcile_duration_seconds", "did not complete"} {
+		if !strings.Contains(err.Error(), expected) {
+			t.Errorf("expected the error to contain %q, got %v", expected, err)
+		}
+	}
+	pods, err := kubeClient.CoreV1().Pods("e2e-test").List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pods.Items) > 0 {
+		t.Errorf("expected the scrape pods to be deleted, got %d", len(pods.Items))
+	}
+}
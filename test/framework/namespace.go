@@ -0,0 +1,102 @@
+package framework
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilrand "k8s.io/apimachinery/pkg/util/rand"
+	clientcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+const (
+	testNamespacePrefix = "e2e-openshift-controller-manager-"
+	// testNamespaceCreateAttempts is how often a namespace is created with a new name when the name is taken.
+	testNamespaceCreateAttempts = 5
+	testNamespaceDeleteTimeout  = 5 * time.Minute
+)
+
+// testNamespaceLabels allow the tests to run privileged workloads, e.g. builds, in their namespace.
+var testNamespaceLabels = map[string]string{
+	"pod-security.kubernetes.io/enforce":             "privileged",
+	"pod-security.kubernetes.io/audit":               "privileged",
+	"pod-security.kubernetes.io/warn":                "privileged",
+	"security.openshift.io/scc.podSecurityLabelSync": "false",
+}
+
+// createTestNamespace creates a namespace with a unique name, picking a new name if it is already taken.
+func createTestNamespace(ctx context.Context, client clientcorev1.NamespacesGetter) (*corev1.Namespace, error) {
+	var lastErr error
+	for i := 0; i < testNamespaceCreateAttempts; i++ {
+		namespace := &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   testNamespacePrefix + utilrand.String(5),
+				Labels: testNamespaceLabels,
+			},
+		}
+		created, err := client.Namespaces().Create(ctx, namespace, metav1.CreateOptions{})
+		if errors.IsAlreadyExists(err) {
+			lastErr = err
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to create test namespace: %v", err)
+		}
+		return created, nil
+	}
+	return nil, fmt.Errorf("failed to create test namespace after %d attempts: %v", testNamespaceCreateAttempts, lastErr)
+}
+
+// deleteTestNamespace deletes the namespace and waits for its termination to finish.
+func deleteTestNamespace(ctx context.Context, client clientcorev1.NamespacesGetter, name string, timeout time.Duration) error {
+	err := client.Namespaces().Delete(ctx, name, metav1.DeleteOptions{})
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to delete test namespace %s: %v", name, err)
+	}
+
+	var namespace *corev1.Namespace
+	err = poll(ctx, time.Second, timeout, func(ctx context.Context) (bool, error) {
+		namespace, err = client.Namespaces().Get(ctx, name, metav1.GetOptions{})
+		// other errors are retried until the timeout
+		return errors.IsNotFound(err), nil
+	})
+	if err != nil {
+		if namespace != nil {
+			return fmt.Errorf("test namespace %s was not deleted within %s, it is %s with conditions %#v: %w", name, timeout, namespace.Status.Phase, namespace.Status.Conditions, err)
+		}
+		return fmt.Errorf("test namespace %s was not deleted within %s: %w", name, timeout, err)
+	}
+	return nil
+}
+
+// CreateTestNamespace creates a uniquely named namespace allowing privileged workloads and returns its
+// name. The namespace is deleted in the test cleanup, which under Ginkgo is a DeferCleanup, and the
+// cleanup fails the test if the namespace does not terminate.
+func CreateTestNamespace(ctx context.Context, t testing.TB, client *Clientset) string {
+	t.Helper()
+	namespace, err := createTestNamespace(ctx, client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Logf("created test namespace %s", namespace.Name)
+
+	t.Cleanup(func() {
+		if CleanupSkipped(t, "delete the test namespace %s", namespace.Name) {
+			return
+		}
+		// the test's context is likely done by the time the cleanup runs
+		ctx, cancel := context.WithTimeout(context.Background(), testNamespaceDeleteTimeout+time.Minute)
+		defer cancel()
+		if err := deleteTestNamespace(ctx, client, namespace.Name, testNamespaceDeleteTimeout); err != nil {
+			t.Error(err)
+		}
+	})
+	return namespace.Name
+}
@@ -0,0 +1,72 @@
+package framework
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+)
+
+func TestCreateTestNamespace(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	creates := 0
+	client.PrependReactor("create", "namespaces", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		creates++
+		if creates == 1 {
+			name := action.(clienttesting.CreateAction).GetObject().(*corev1.Namespace).Name
+			return true, nil, errors.NewAlreadyExists(schema.GroupResource{Resource: "namespaces"}, name)
+		}
+		return false, nil, nil
+	})
+
+	namespace, err := createTestNamespace(context.TODO(), client.CoreV1())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if creates != 2 {
+		t.Errorf("expected the create to be retried once, got %d creates", creates)
+	}
+	if !strings.HasPrefix(namespace.Name, testNamespacePrefix) {
+		t.Errorf("expected namespace name with prefix %q, got %q", testNamespacePrefix, namespace.Name)
+	}
+	if namespace.Labels["pod-security.kubernetes.io/enforce"] != "privileged" {
+		t.Errorf("expected privileged pod security labels, got %v", namespace.Labels)
+	}
+
+	if err := deleteTestNamespace(context.TODO(), client.CoreV1(), namespace.Name, time.Second); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.CoreV1().Namespaces().Get(context.TODO(), namespace.Name, metav1.GetOptions{}); !errors.IsNotFound(err) {
+		t.Errorf("expected the namespace to be deleted, got %v", err)
+	}
+}
+
+func TestDeleteTestNamespaceHangs(t *testing.T) {
+	terminating := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: "e2e-openshift-controller-manager-abcde"},
+		Status:     corev1.NamespaceStatus{Phase: corev1.NamespaceTerminating},
+	}
+	client := fake.NewSimpleClientset(terminating)
+	// the namespace never finishes terminating
+	client.PrependReactor("delete", "namespaces", func(clienttesting.Action) (bool, runtime.Object, error) {
+		return true, nil, nil
+	})
+
+	err := deleteTestNamespace(context.TODO(), client.CoreV1(), terminating.Name, 100*time.Millisecond)
+	if err == nil {
+		t.Fatal("expected an error for a namespace that is not deleted")
+	}
+	for _, s := range []string{terminating.Name, "was not deleted", "Terminating"} {
+		if !strings.Contains(err.Error(), s) {
+			t.Errorf("expected error to contain %q, got %q", s, err)
+		}
+	}
+}
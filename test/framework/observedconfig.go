@@ -0,0 +1,185 @@
+package framework
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/onsi/gomega/format"
+	"github.com/onsi/gomega/types"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	openshiftcontrolplanev1 "github.com/openshift/api/openshiftcontrolplane/v1"
+	clientoperatorv1 "github.com/openshift/client-go/operator/clientset/versioned/typed/operator/v1"
+)
+
+// HaveObservedConfigValue succeeds if the observed config has the expected value at the given
+// dotted path, e.g. HaveObservedConfigValue("servingInfo.minTLSVersion", "VersionTLS13").
+// The actual value can be a runtime.RawExtension, a *runtime.RawExtension or raw JSON bytes.
+// Supported leaf types are string, []string and bool. The expected value may also be a Gomega
+// matcher, which is then applied to the leaf value.
+func HaveObservedConfigValue(path string, expected interface{}) types.GomegaMatcher {
+	return &observedConfigValueMatcher{
+		path:     path,
+		expected: expected,
+	}
+}
+
+type observedConfigValueMatcher struct {
+	path     string
+	expected interface{}
+
+	// leaf and found are recorded by Match for the failure messages.
+	leaf  interface{}
+	found bool
+}
+
+func (m *observedConfigValueMatcher) Match(actual interface{}) (bool, error) {
+	observedConfig, err := unmarshalObservedConfig(actual)
+	if err != nil {
+		return false, err
+	}
+
+	m.leaf, m.found, err = observedConfigLeaf(observedConfig, m.path)
+	if err != nil || !m.found {
+		return false, err
+	}
+
+	if matcher, ok := m.expected.(types.GomegaMatcher); ok {
+		return matcher.Match(m.leaf)
+	}
+	return reflect.DeepEqual(m.leaf, m.expected), nil
+}
+
+func (m *observedConfigValueMatcher) FailureMessage(_ interface{}) string {
+	if !m.found {
+		return fmt.Sprintf("expected %s to be %s, but it is not set", m.path, m.describeExpected())
+	}
+	if matcher, ok := m.expected.(types.GomegaMatcher); ok {
+		return fmt.Sprintf("unexpected value of %s:\n%s", m.path, matcher.FailureMessage(m.leaf))
+	}
+	return fmt.Sprintf("expected %s to be %s, got %s", m.path, m.describeExpected(), format.Object(m.leaf, 0))
+}
+
+func (m *observedConfigValueMatcher) NegatedFailureMessage(_ interface{}) string {
+	if matcher, ok := m.expected.(types.GomegaMatcher); ok {
+		return fmt.Sprintf("unexpected value of %s:\n%s", m.path, matcher.NegatedFailureMessage(m.leaf))
+	}
+	return fmt.Sprintf("expected %s not to be %s", m.path, m.describeExpected())
+}
+
+func (m *observedConfigValueMatcher) describeExpected() string {
+	if _, ok := m.expected.(types.GomegaMatcher); ok {
+		return "matched"
+	}
+	return format.Object(m.expected, 0)
+}
+
+// unmarshalObservedConfig decodes the observed config held by actual into a generic map.
+func unmarshalObservedConfig(actual interface{}) (map[string]interface{}, error) {
+	var raw []byte
+	switch v := actual.(type) {
+	case runtime.RawExtension:
+		raw = v.Raw
+	case *runtime.RawExtension:
+		if v != nil {
+			raw = v.Raw
+		}
+	case []byte:
+		raw = v
+	default:
+		return nil, fmt.Errorf("expected a runtime.RawExtension, *runtime.RawExtension or []byte, got %T", actual)
+	}
+
+	observedConfig := map[string]interface{}{}
+	if len(raw) == 0 {
+		return observedConfig, nil
+	}
+	if err := json.Unmarshal(raw, &observedConfig); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal observed config: %w", err)
+	}
+	return observedConfig, nil
+}
+
+// observedConfigLeaf returns the string, []string or bool value at the dotted path.
+func observedConfigLeaf(observedConfig map[string]interface{}, path string) (interface{}, bool, error) {
+	val, found, err := unstructured.NestedFieldNoCopy(observedConfig, strings.Split(path, ".")...)
+	if err != nil || !found {
+		return nil, found, err
+	}
+
+	switch v := val.(type) {
+	case string, bool:
+		return v, true, nil
+	case []interface{}:
+		ret := make([]string, 0, len(v))
+		for _, item := range v {
+			s, ok := item.(string)
+			if !ok {
+				return nil, true, fmt.Errorf("%s contains a non-string item of type %T", path, item)
+			}
+			ret = append(ret, s)
+		}
+		return ret, true, nil
+	default:
+		return nil, true, fmt.Errorf("%s has unsupported type %T, expected a string, a string slice or a bool", path, val)
+	}
+}
+
+// GetObservedConfig returns the observed config of the operator.
+func GetObservedConfig(ctx context.Context, t testing.TB, client *Clientset) (map[string]interface{}, error) {
+	t.Helper()
+	raw, err := getObservedConfigRaw(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+	return unmarshalObservedConfig(raw)
+}
+
+// GetObservedConfigRaw returns the observed config of the operator as the JSON it is stored as, e.g. to
+// compare it with DiffObservedConfig later.
+func GetObservedConfigRaw(ctx context.Context, t testing.TB, client *Clientset) ([]byte, error) {
+	t.Helper()
+	return getObservedConfigRaw(ctx, client)
+}
+
+// GetServingInfo returns the minimum TLS version and the cipher suites of the observed config. They
+// are empty if not observed.
+func GetServingInfo(ctx context.Context, t testing.TB, client *Clientset) (string, []string, error) {
+	t.Helper()
+	raw, err := getObservedConfigRaw(ctx, client)
+	if err != nil {
+		return "", nil, err
+	}
+	return parseServingInfo(raw)
+}
+
+func getObservedConfigRaw(ctx context.Context, client clientoperatorv1.OpenShiftControllerManagersGetter) ([]byte, error) {
+	cfg, err := client.OpenShiftControllerManagers().Get(ctx, "cluster", metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get openshiftcontrollermanagers.operator.openshift.io/cluster: %w", err)
+	}
+	return cfg.Spec.ObservedConfig.Raw, nil
+}
+
+// parseServingInfo decodes the observed config into the operand's config type and returns its serving
+// info TLS settings.
+func parseServingInfo(raw []byte) (string, []string, error) {
+	if len(raw) == 0 {
+		return "", nil, nil
+	}
+	config := &openshiftcontrolplanev1.OpenShiftControllerManagerConfig{}
+	if err := json.Unmarshal(raw, config); err != nil {
+		return "", nil, fmt.Errorf("failed to unmarshal observed config into %T: %w", config, err)
+	}
+	if config.ServingInfo == nil {
+		return "", nil, nil
+	}
+	return config.ServingInfo.MinTLSVersion, config.ServingInfo.CipherSuites, nil
+}
@@ -0,0 +1,206 @@
+package framework
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	operatorfake "github.com/openshift/client-go/operator/clientset/versioned/fake"
+)
+
+func TestHaveObservedConfigValue(t *testing.T) {
+	observedConfig := runtime.RawExtension{Raw: []byte(`{
+		"servingInfo": {
+			"minTLSVersion": "VersionTLS13",
+			"cipherSuites": ["TLS_AES_128_GCM_SHA256", "TLS_AES_256_GCM_SHA384"]
+		},
+		"build": {"buildOverrides": {"forcePull": true}},
+		"ingress": {"ingressIPNetworkCIDR": 10}
+	}`)}
+
+	tests := []struct {
+		name           string
+		actual         interface{}
+		path           string
+		expected       interface{}
+		expectMatch    bool
+		expectErr      bool
+		expectFailures []string
+	}{
+		{
+			name:        "string leaf",
+			actual:      observedConfig,
+			path:        "servingInfo.minTLSVersion",
+			expected:    "VersionTLS13",
+			expectMatch: true,
+		},
+		{
+			name:           "string leaf mismatch",
+			actual:         observedConfig,
+			path:           "servingInfo.minTLSVersion",
+			expected:       "VersionTLS12",
+			expectFailures: []string{"expected servingInfo.minTLSVersion to be", "VersionTLS12", "got", "VersionTLS13"},
+		},
+		{
+			name:        "string slice leaf",
+			actual:      &observedConfig,
+			path:        "servingInfo.cipherSuites",
+			expected:    []string{"TLS_AES_128_GCM_SHA256", "TLS_AES_256_GCM_SHA384"},
+			expectMatch: true,
+		},
+		{
+			name:     "string slice leaf is order sensitive",
+			actual:   observedConfig,
+			path:     "servingInfo.cipherSuites",
+			expected: []string{"TLS_AES_256_GCM_SHA384", "TLS_AES_128_GCM_SHA256"},
+		},
+		{
+			name:        "string slice leaf with nested matcher",
+			actual:      observedConfig,
+			path:        "servingInfo.cipherSuites",
+			expected:    gomega.ContainElements("TLS_AES_256_GCM_SHA384", "TLS_AES_128_GCM_SHA256"),
+			expectMatch: true,
+		},
+		{
+			name:        "bool leaf",
+			actual:      observedConfig.Raw,
+			path:        "build.buildOverrides.forcePull",
+			expected:    true,
+			expectMatch: true,
+		},
+		{
+			name:           "missing path",
+			actual:         observedConfig,
+			path:           "servingInfo.bindAddress",
+			expected:       "0.0.0.0:8443",
+			expectFailures: []string{"expected servingInfo.bindAddress to be", "it is not set"},
+		},
+		{
+			name:           "empty observed config",
+			actual:         runtime.RawExtension{},
+			path:           "servingInfo.minTLSVersion",
+			expected:       "VersionTLS13",
+			expectFailures: []string{"it is not set"},
+		},
+		{
+			name:      "unsupported leaf type",
+			actual:    observedConfig,
+			path:      "ingress.ingressIPNetworkCIDR",
+			expected:  "10",
+			expectErr: true,
+		},
+		{
+			name:      "unsupported actual type",
+			actual:    "servingInfo",
+			path:      "servingInfo.minTLSVersion",
+			expected:  "VersionTLS13",
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			matcher := HaveObservedConfigValue(tc.path, tc.expected)
+			match, err := matcher.Match(tc.actual)
+			if tc.expectErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if match != tc.expectMatch {
+				t.Fatalf("expected match to be %v, got %v", tc.expectMatch, match)
+			}
+			message := matcher.FailureMessage(tc.actual)
+			for _, s := range tc.expectFailures {
+				if !strings.Contains(message, s) {
+					t.Errorf("expected failure message to contain %q, got %q", s, message)
+				}
+			}
+		})
+	}
+}
+
+func TestParseServingInfo(t *testing.T) {
+	tests := []struct {
+		name                  string
+		raw                   []byte
+		expectedMinTLSVersion string
+		expectedCipherSuites  []string
+		expectErr             bool
+	}{
+		{
+			name: "absent observed config",
+		},
+		{
+			name: "empty observed config",
+			raw:  []byte(`{}`),
+		},
+		{
+			name:                  "serving info",
+			raw:                   []byte(`{"servingInfo": {"minTLSVersion": "VersionTLS12", "cipherSuites": ["TLS_AES_128_GCM_SHA256", "TLS_AES_256_GCM_SHA384"]}, "build": {}}`),
+			expectedMinTLSVersion: "VersionTLS12",
+			expectedCipherSuites:  []string{"TLS_AES_128_GCM_SHA256", "TLS_AES_256_GCM_SHA384"},
+		},
+		{
+			name:      "malformed observed config",
+			raw:       []byte(`{"servingInfo": {"minTLSVersion": 12}}`),
+			expectErr: true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			minTLSVersion, cipherSuites, err := parseServingInfo(tc.raw)
+			if tc.expectErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if minTLSVersion != tc.expectedMinTLSVersion {
+				t.Errorf("expected minTLSVersion %q, got %q", tc.expectedMinTLSVersion, minTLSVersion)
+			}
+			if strings.Join(cipherSuites, ",") != strings.Join(tc.expectedCipherSuites, ",") {
+				t.Errorf("expected cipherSuites %q, got %q", tc.expectedCipherSuites, cipherSuites)
+			}
+		})
+	}
+}
+
+func TestGetObservedConfigRaw(t *testing.T) {
+	client := operatorfake.NewSimpleClientset(&operatorv1.OpenShiftControllerManager{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+		Spec: operatorv1.OpenShiftControllerManagerSpec{
+			OperatorSpec: operatorv1.OperatorSpec{
+				ObservedConfig: runtime.RawExtension{Raw: []byte(`{"servingInfo": {"minTLSVersion": "VersionTLS13"}}`)},
+			},
+		},
+	})
+	raw, err := getObservedConfigRaw(context.TODO(), client.OperatorV1())
+	if err != nil {
+		t.Fatal(err)
+	}
+	observedConfig, err := unmarshalObservedConfig(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !gomega.NewGomega(func(message string, _ ...int) { t.Error(message) }).Expect(observedConfig).To(gomega.HaveKey("servingInfo")) {
+		return
+	}
+
+	_, err = getObservedConfigRaw(context.TODO(), operatorfake.NewSimpleClientset().OperatorV1())
+	if err == nil || !strings.Contains(err.Error(), "openshiftcontrollermanagers.operator.openshift.io/cluster") {
+		t.Errorf("expected an error naming the operator config, got %v", err)
+	}
+}
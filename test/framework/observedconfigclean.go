@@ -0,0 +1,82 @@
+package framework
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	configv1 "github.com/openshift/api/config/v1"
+	"github.com/openshift/library-go/pkg/crypto"
+)
+
+// observedConfigDefaults are the documented values of the observed config keys which are not absent when
+// nothing is configured: the Intermediate TLS profile applies when the APIServer config sets none.
+var observedConfigDefaults = map[string]interface{}{
+	"servingInfo.minTLSVersion": string(configv1.TLSProfiles[configv1.TLSProfileIntermediateType].MinTLSVersion),
+	"servingInfo.cipherSuites":  crypto.OpenSSLToIANACipherSuites(configv1.TLSProfiles[configv1.TLSProfileIntermediateType].Ciphers),
+}
+
+// AssertObservedConfigClean fails the test unless each of the given dotted path keys of the observed config
+// is absent, or has its documented default for the keys which have one. Tests restoring the cluster config
+// on cleanup call it to catch values the operator did not revert.
+func AssertObservedConfigClean(ctx context.Context, t testing.TB, client *Clientset, keys ...string) {
+	t.Helper()
+	if err := CheckObservedConfigClean(ctx, client, keys...); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// CheckObservedConfigClean is AssertObservedConfigClean returning an error, for verifications which are
+// retried.
+func CheckObservedConfigClean(ctx context.Context, client *Clientset, keys ...string) error {
+	raw, err := getObservedConfigRaw(ctx, client)
+	if err != nil {
+		return err
+	}
+	return checkObservedConfigClean(raw, keys)
+}
+
+func checkObservedConfigClean(raw []byte, keys []string) error {
+	observedConfig, err := unmarshalObservedConfig(raw)
+	if err != nil {
+		return err
+	}
+
+	var leaks []string
+	for _, key := range keys {
+		value, found, err := observedConfigLeaf(observedConfig, key)
+		if err != nil {
+			leaks = append(leaks, err.Error())
+			continue
+		}
+		if !found {
+			continue
+		}
+		defaultValue, hasDefault := observedConfigDefaults[key]
+		switch {
+		case !hasDefault:
+			leaks = append(leaks, fmt.Sprintf("%s still present after cleanup: %v", key, value))
+		case !reflect.DeepEqual(normalizeLeaf(value), normalizeLeaf(defaultValue)):
+			leaks = append(leaks, fmt.Sprintf("%s is %v after cleanup, expected the default %v", key, value, defaultValue))
+		}
+	}
+	if len(leaks) > 0 {
+		sort.Strings(leaks)
+		return fmt.Errorf("observed config of openshiftcontrollermanagers.operator.openshift.io/cluster was not reverted:\n%s", strings.Join(leaks, "\n"))
+	}
+	return nil
+}
+
+// normalizeLeaf sorts string slices, the order of e.g. the cipher suites does not depend on the profile.
+func normalizeLeaf(value interface{}) interface{} {
+	items, ok := value.([]string)
+	if !ok {
+		return value
+	}
+	sorted := append([]string{}, items...)
+	sort.Strings(sorted)
+	return sorted
+}
@@ -0,0 +1,83 @@
+package framework
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestCheckObservedConfigClean(t *testing.T) {
+	defaultCiphers := observedConfigDefaults["servingInfo.cipherSuites"].([]string)
+	tests := []struct {
+		name           string
+		observedConfig map[string]interface{}
+		keys           []string
+		expectedErrors []string
+	}{
+		{
+			name: "absent",
+			observedConfig: map[string]interface{}{
+				"build": map[string]interface{}{"buildDefaults": map[string]interface{}{}},
+			},
+			keys: []string{"build.buildDefaults.gitHTTPProxy", "servingInfo.cipherSuites"},
+		},
+		{
+			name: "documented defaults",
+			observedConfig: map[string]interface{}{
+				"servingInfo": map[string]interface{}{
+					"minTLSVersion": "VersionTLS12",
+					"cipherSuites":  defaultCiphers,
+				},
+			},
+			keys: []string{"servingInfo.minTLSVersion", "servingInfo.cipherSuites"},
+		},
+		{
+			name: "leaked values",
+			observedConfig: map[string]interface{}{
+				"servingInfo": map[string]interface{}{
+					"minTLSVersion": "VersionTLS13",
+					"cipherSuites":  []string{"TLS_AES_128_GCM_SHA256"},
+				},
+				"build": map[string]interface{}{
+					"buildDefaults": map[string]interface{}{"gitNoProxy": ".example.com"},
+				},
+			},
+			keys: []string{"servingInfo.minTLSVersion", "servingInfo.cipherSuites", "build.buildDefaults.gitNoProxy"},
+			expectedErrors: []string{
+				"servingInfo.minTLSVersion is VersionTLS13 after cleanup, expected the default VersionTLS12",
+				"servingInfo.cipherSuites is [TLS_AES_128_GCM_SHA256] after cleanup",
+				"build.buildDefaults.gitNoProxy still present after cleanup: .example.com",
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			raw, err := json.Marshal(tc.observedConfig)
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = checkObservedConfigClean(raw, tc.keys)
+			if len(tc.expectedErrors) == 0 {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			for _, expected := range tc.expectedErrors {
+				if !strings.Contains(err.Error(), expected) {
+					t.Errorf("expected %q in error %q", expected, err.Error())
+				}
+			}
+		})
+	}
+}
+
+func TestCheckObservedConfigCleanEmpty(t *testing.T) {
+	if err := checkObservedConfigClean(nil, []string{"servingInfo.cipherSuites"}); err != nil {
+		t.Errorf("expected an empty observed config to be clean, got %v", err)
+	}
+}
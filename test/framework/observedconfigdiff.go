@@ -0,0 +1,125 @@
+package framework
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	clientoperatorv1 "github.com/openshift/client-go/operator/clientset/versioned/typed/operator/v1"
+)
+
+// observedConfigPollInterval is how often WaitForObservedConfig checks the observed config.
+const observedConfigPollInterval = 5 * time.Second
+
+// DiffObservedConfig returns the differences between two observed configs, one line per dotted key path
+// sorted by path: "+ path: value" for an added key, "- path: value" for a removed one and
+// "~ path: before -> after" for a changed one, values as JSON. Objects are compared key by key, lists as a
+// whole. It returns an empty string when the configs are equal, an empty blob being an empty config, and
+// a line naming the blob for one that is not JSON, so that it can always be logged as is.
+func DiffObservedConfig(before, after []byte) string {
+	beforeConfig, err := unmarshalObservedConfig(before)
+	if err != nil {
+		return fmt.Sprintf("the observed config before is invalid: %v", err)
+	}
+	afterConfig, err := unmarshalObservedConfig(after)
+	if err != nil {
+		return fmt.Sprintf("the observed config after is invalid: %v", err)
+	}
+
+	var diffs []observedConfigDiff
+	diffObservedConfigValues(&diffs, nil, beforeConfig, afterConfig)
+	sort.Slice(diffs, func(i, j int) bool {
+		return diffs[i].path < diffs[j].path
+	})
+	lines := make([]string, 0, len(diffs))
+	for _, diff := range diffs {
+		lines = append(lines, diff.line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// observedConfigDiff is the line describing how the value at a path differs.
+type observedConfigDiff struct {
+	path string
+	line string
+}
+
+func diffObservedConfigValues(diffs *[]observedConfigDiff, path []string, before, after interface{}) {
+	beforeMap, beforeIsMap := before.(map[string]interface{})
+	afterMap, afterIsMap := after.(map[string]interface{})
+	if beforeIsMap && afterIsMap {
+		for key, beforeValue := range beforeMap {
+			afterValue, ok := afterMap[key]
+			if !ok {
+				keyPath := diffPath(append(path, key))
+				*diffs = append(*diffs, observedConfigDiff{path: keyPath, line: fmt.Sprintf("- %s: %s", keyPath, diffValue(beforeValue))})
+				continue
+			}
+			diffObservedConfigValues(diffs, append(path[:len(path):len(path)], key), beforeValue, afterValue)
+		}
+		for key, afterValue := range afterMap {
+			if _, ok := beforeMap[key]; !ok {
+				keyPath := diffPath(append(path, key))
+				*diffs = append(*diffs, observedConfigDiff{path: keyPath, line: fmt.Sprintf("+ %s: %s", keyPath, diffValue(afterValue))})
+			}
+		}
+		return
+	}
+	if !reflect.DeepEqual(before, after) {
+		keyPath := diffPath(path)
+		*diffs = append(*diffs, observedConfigDiff{path: keyPath, line: fmt.Sprintf("~ %s: %s -> %s", keyPath, diffValue(before), diffValue(after))})
+	}
+}
+
+func diffPath(path []string) string {
+	return strings.Join(path, ".")
+}
+
+// diffValue encodes the value as JSON, which sorts the keys of objects.
+func diffValue(value interface{}) string {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+	return string(raw)
+}
+
+// WaitForObservedConfig waits for the observed config of the operator to be semantically equal to expected,
+// e.g. one recorded with GetObservedConfigRaw before a disruption. The differences are logged whenever
+// they change, the error on timeout has the last of them.
+func WaitForObservedConfig(ctx context.Context, t testing.TB, client *Clientset, expected []byte, timeout time.Duration) error {
+	t.Helper()
+	return waitForObservedConfig(ctx, t, client, expected, observedConfigPollInterval, timeout)
+}
+
+func waitForObservedConfig(ctx context.Context, logger Logger, client clientoperatorv1.OpenShiftControllerManagersGetter, expected []byte, interval, timeout time.Duration) error {
+	var lastDiff string
+	var lastErr error
+	err := poll(ctx, interval, timeout, func(ctx context.Context) (bool, error) {
+		raw, err := getObservedConfigRaw(ctx, client)
+		if err != nil {
+			logger.Logf("%v", err)
+			lastErr = err
+			return false, nil
+		}
+		lastErr = nil
+		diff := DiffObservedConfig(expected, raw)
+		if len(diff) > 0 && diff != lastDiff {
+			logger.Logf("the observed config differs from the expected one:\n%s", diff)
+		}
+		lastDiff = diff
+		return len(diff) == 0, nil
+	})
+	if err == nil {
+		return nil
+	}
+	if lastErr != nil {
+		return fmt.Errorf("the observed config did not become the expected one, last error: %v: %w", lastErr, err)
+	}
+	return fmt.Errorf("the observed config did not become the expected one, it differs in:\n%s\n%w", lastDiff, err)
+}
@@ -0,0 +1,150 @@
+package framework
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clienttesting "k8s.io/client-go/testing"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	operatorfake "github.com/openshift/client-go/operator/clientset/versioned/fake"
+)
+
+func TestDiffObservedConfig(t *testing.T) {
+	tests := []struct {
+		name     string
+		before   string
+		after    string
+		expected string
+	}{
+		{
+			name:   "equal",
+			before: `{"servingInfo": {"minTLSVersion": "VersionTLS12"}}`,
+			after:  `{"servingInfo":{"minTLSVersion":"VersionTLS12"}}`,
+		},
+		{
+			name: "empty blobs",
+		},
+		{
+			name:     "additions",
+			before:   `{"servingInfo": {"minTLSVersion": "VersionTLS12"}}`,
+			after:    `{"servingInfo": {"minTLSVersion": "VersionTLS12", "cipherSuites": ["TLS_AES_128_GCM_SHA256"]}, "controllers": ["*"]}`,
+			expected: "+ controllers: [\"*\"]\n+ servingInfo.cipherSuites: [\"TLS_AES_128_GCM_SHA256\"]",
+		},
+		{
+			name:     "removals",
+			before:   `{"build": {"buildDefaults": {"gitNoProxy": ".example.com", "gitHTTPProxy": "http://proxy"}}, "controllers": ["*"]}`,
+			after:    `{"build": {"buildDefaults": {"gitHTTPProxy": "http://proxy"}}}`,
+			expected: "- build.buildDefaults.gitNoProxy: \".example.com\"\n- controllers: [\"*\"]",
+		},
+		{
+			name:     "removal of a whole section",
+			before:   `{"leaderElection": {"leaseDuration": "137s", "retryPeriod": "26s"}}`,
+			after:    `{}`,
+			expected: `- leaderElection: {"leaseDuration":"137s","retryPeriod":"26s"}`,
+		},
+		{
+			name:   "nested value changes",
+			before: `{"servingInfo": {"minTLSVersion": "VersionTLS12", "cipherSuites": ["A", "B"]}, "network": {"clusterNetworks": [{"cidr": "10.128.0.0/14", "hostSubnetLength": 9}]}}`,
+			after:  `{"servingInfo": {"minTLSVersion": "VersionTLS13", "cipherSuites": ["A"]}, "network": {"clusterNetworks": [{"cidr": "10.128.0.0/14", "hostSubnetLength": 8}]}}`,
+			expected: "~ network.clusterNetworks: [{\"cidr\":\"10.128.0.0/14\",\"hostSubnetLength\":9}] -> [{\"cidr\":\"10.128.0.0/14\",\"hostSubnetLength\":8}]\n" +
+				"~ servingInfo.cipherSuites: [\"A\",\"B\"] -> [\"A\"]\n" +
+				"~ servingInfo.minTLSVersion: \"VersionTLS12\" -> \"VersionTLS13\"",
+		},
+		{
+			name:     "object replaced by a value",
+			before:   `{"ingress": {"ingressIPNetworkCIDR": ""}}`,
+			after:    `{"ingress": "none"}`,
+			expected: `~ ingress: {"ingressIPNetworkCIDR":""} -> "none"`,
+		},
+		{
+			name:     "invalid blob",
+			before:   `{`,
+			after:    `{}`,
+			expected: "the observed config before is invalid",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			diff := DiffObservedConfig([]byte(tc.before), []byte(tc.after))
+			if tc.name == "invalid blob" {
+				if !strings.HasPrefix(diff, tc.expected) {
+					t.Errorf("expected a diff starting with %q, got %q", tc.expected, diff)
+				}
+				return
+			}
+			if diff != tc.expected {
+				t.Errorf("expected diff:\n%s\ngot:\n%s", tc.expected, diff)
+			}
+			// the order does not depend on the map iteration order
+			for i := 0; i < 10; i++ {
+				if again := DiffObservedConfig([]byte(tc.before), []byte(tc.after)); again != diff {
+					t.Fatalf("expected a deterministic diff, got:\n%s\nthen:\n%s", diff, again)
+				}
+			}
+		})
+	}
+}
+
+func TestWaitForObservedConfig(t *testing.T) {
+	expected := []byte(`{"servingInfo": {"minTLSVersion": "VersionTLS12"}, "build": {"buildDefaults": {"gitNoProxy": ".example.com"}}}`)
+	regenerated := []byte(`{"build":{"buildDefaults":{"gitNoProxy":".example.com"}},"servingInfo":{"minTLSVersion":"VersionTLS12"}}`)
+	drifted := []byte(`{"servingInfo": {"minTLSVersion": "VersionTLS13"}}`)
+
+	tests := []struct {
+		name        string
+		observed    [][]byte
+		expectedErr string
+	}{
+		{
+			name:     "regenerated in another key order",
+			observed: [][]byte{regenerated},
+		},
+		{
+			name:     "converges after regenerating",
+			observed: [][]byte{nil, drifted, regenerated},
+		},
+		{
+			name:        "drifts",
+			observed:    [][]byte{drifted},
+			expectedErr: "~ servingInfo.minTLSVersion: \"VersionTLS12\" -> \"VersionTLS13\"",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			client := operatorfake.NewSimpleClientset(&operatorv1.OpenShiftControllerManager{ObjectMeta: metav1.ObjectMeta{Name: "cluster"}})
+			gets := 0
+			client.PrependReactor("get", "openshiftcontrollermanagers", func(clienttesting.Action) (bool, runtime.Object, error) {
+				// the last observed config stays
+				observed := tc.observed[min(gets, len(tc.observed)-1)]
+				gets++
+				return true, &operatorv1.OpenShiftControllerManager{
+					ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+					Spec: operatorv1.OpenShiftControllerManagerSpec{OperatorSpec: operatorv1.OperatorSpec{
+						ObservedConfig: runtime.RawExtension{Raw: observed},
+					}},
+				}, nil
+			})
+			logger := &recordingLogger{}
+
+			err := waitForObservedConfig(context.Background(), logger, client.OperatorV1(), expected, time.Millisecond, 50*time.Millisecond)
+			if len(tc.expectedErr) > 0 {
+				if err == nil || !strings.Contains(err.Error(), tc.expectedErr) {
+					t.Fatalf("expected an error containing %q, got %v", tc.expectedErr, err)
+				}
+				// the same differences are logged once
+				if len(logger.lines) != 1 {
+					t.Errorf("expected the differences logged once, got %q", logger.lines)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package framework
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	clientoperatorv1 "github.com/openshift/client-go/operator/clientset/versioned/typed/operator/v1"
+
+	"github.com/openshift/cluster-openshift-controller-manager-operator/pkg/util"
+)
+
+// observedConfigOwners are the dotted paths of the observed config each observer of the config observer
+// controller writes, in the order the controller runs them. It mirrors the paths of their registrations
+// by hand, a path missing here shows up as written by no observer.
+var observedConfigOwners = []struct {
+	observer string
+	paths    []string
+}{
+	{observer: "InternalRegistryHostname", paths: []string{"dockerPullSecret.internalRegistryHostname"}},
+	{observer: "ExternalRegistryHostnames", paths: []string{"dockerPullSecret.registryURLs"}},
+	{observer: "AdditionalTrustedCA", paths: []string{"build.additionalTrustedCA"}},
+	{observer: "ExternalIPAutoAssignCIDRs", paths: []string{"ingress.ingressIPNetworkCIDR"}},
+	// only registered when the operator runs with OCM_OPERATOR_ENABLE_NETWORK_OBSERVER=true
+	{observer: "ClusterNetworks", paths: []string{"network.clusterNetworks", "network.serviceNetworkCIDR"}},
+	{observer: "ControllerManagerImagesConfig", paths: []string{"build.imageTemplateFormat", "deployer.imageTemplateFormat"}},
+	{observer: "LeaderElection", paths: []string{"leaderElection"}},
+	{observer: "Controllers", paths: []string{"controllers"}},
+	{observer: "FeatureFlags", paths: []string{"featureGates"}},
+	{observer: "TLSSecurityProfile", paths: []string{"servingInfo.minTLSVersion", "servingInfo.cipherSuites"}},
+	{observer: "NamedCertificates", paths: []string{"servingInfo.namedCertificates"}},
+	{observer: "BuildControllerConfig", paths: []string{
+		"build.buildDefaults.env", "build.buildDefaults.imageLabels",
+		"build.buildOverrides.imageLabels", "build.buildOverrides.nodeSelector", "build.buildOverrides.tolerations", "build.buildOverrides.forcePull",
+	}},
+	{observer: "BuildDefaultResources", paths: []string{"build.buildDefaults.resources"}},
+	{observer: "GitProxy", paths: []string{"build.buildDefaults.gitHTTPProxy", "build.buildDefaults.gitHTTPSProxy"}},
+	{observer: "GitNoProxy", paths: []string{"build.buildDefaults.gitNoProxy"}},
+}
+
+// ObservedConfigSection is a top-level key of the observed config with the observers which wrote it.
+type ObservedConfigSection struct {
+	Key   string
+	Value interface{}
+	// Observers are the names of the observers which wrote any of the section, in the order they run.
+	// It is empty for a section no observer writes, e.g. one left behind by an older operator.
+	Observers []string
+}
+
+// ExplainObservedConfig returns the top-level sections of the observed config of the operator in the
+// order of their keys, each attributed to the observers which wrote it.
+func ExplainObservedConfig(ctx context.Context, client *Clientset) ([]ObservedConfigSection, error) {
+	raw, err := getObservedConfigRaw(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+	return explainObservedConfig(raw)
+}
+
+func explainObservedConfig(raw []byte) ([]ObservedConfigSection, error) {
+	observedConfig, err := unmarshalObservedConfig(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, 0, len(observedConfig))
+	for key := range observedConfig {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	sections := make([]ObservedConfigSection, 0, len(keys))
+	for _, key := range keys {
+		section := ObservedConfigSection{Key: key, Value: observedConfig[key]}
+		for _, owner := range observedConfigOwners {
+			for _, path := range owner.paths {
+				fields := strings.Split(path, ".")
+				if fields[0] != key {
+					continue
+				}
+				if _, found, _ := unstructured.NestedFieldNoCopy(observedConfig, fields...); found {
+					section.Observers = append(section.Observers, owner.observer)
+					break
+				}
+			}
+		}
+		sections = append(sections, section)
+	}
+	return sections, nil
+}
+
+// ObservedConfigRevision is a recent change of the observed config recorded by the operator.
+type ObservedConfigRevision struct {
+	Time metav1.Time `json:"time"`
+	// Reason names the dotted paths of the observed config the change added, changed or removed.
+	Reason string `json:"reason"`
+}
+
+// ObservedConfigHistory returns the recent changes of the observed config the operator recorded in the
+// operator config, oldest first. It is empty until the operator changed the observed config.
+func ObservedConfigHistory(ctx context.Context, client *Clientset) ([]ObservedConfigRevision, error) {
+	return getObservedConfigHistory(ctx, client)
+}
+
+func getObservedConfigHistory(ctx context.Context, client clientoperatorv1.OpenShiftControllerManagersGetter) ([]ObservedConfigRevision, error) {
+	cfg, err := client.OpenShiftControllerManagers().Get(ctx, "cluster", metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get openshiftcontrollermanagers.operator.openshift.io/cluster: %w", err)
+	}
+	value, ok := cfg.Annotations[util.ObservedConfigHistoryAnnotation]
+	if !ok {
+		return nil, nil
+	}
+	var revisions []ObservedConfigRevision
+	if err := json.Unmarshal([]byte(value), &revisions); err != nil {
+		return nil, fmt.Errorf("failed to decode the %s annotation: %w", util.ObservedConfigHistoryAnnotation, err)
+	}
+	return revisions, nil
+}
@@ -0,0 +1,100 @@
+package framework
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	operatorv1 "github.com/openshift/api/operator/v1"
+	operatorfake "github.com/openshift/client-go/operator/clientset/versioned/fake"
+
+	"github.com/openshift/cluster-openshift-controller-manager-operator/pkg/util"
+)
+
+func TestExplainObservedConfig(t *testing.T) {
+	raw := []byte(`{
+		"build": {
+			"additionalTrustedCA": "/var/run/configmaps/config/additional-ca",
+			"imageTemplateFormat": {"format": "quay.io/openshift/origin-${component}:latest"},
+			"buildDefaults": {"gitNoProxy": ".example.com", "resources": {"limits": {"cpu": "1"}}},
+			"buildOverrides": {"forcePull": true}
+		},
+		"controllers": ["*", "-openshift.io/build"],
+		"dockerPullSecret": {"internalRegistryHostname": "image-registry.openshift-image-registry.svc:5000"},
+		"leaderElection": {"leaseDuration": "137s"},
+		"servingInfo": {"minTLSVersion": "VersionTLS12", "namedCertificates": []},
+		"legacy": {"key": "value"}
+	}`)
+
+	sections, err := explainObservedConfig(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	attribution := map[string][]string{}
+	var keys []string
+	for _, section := range sections {
+		keys = append(keys, section.Key)
+		attribution[section.Key] = section.Observers
+	}
+
+	expectedKeys := []string{"build", "controllers", "dockerPullSecret", "leaderElection", "legacy", "servingInfo"}
+	if diff := cmp.Diff(expectedKeys, keys); len(diff) > 0 {
+		t.Errorf("unexpected sections (-want +got):\n%s", diff)
+	}
+	expected := map[string][]string{
+		"build":            {"AdditionalTrustedCA", "ControllerManagerImagesConfig", "BuildControllerConfig", "BuildDefaultResources", "GitNoProxy"},
+		"controllers":      {"Controllers"},
+		"dockerPullSecret": {"InternalRegistryHostname"},
+		"leaderElection":   {"LeaderElection"},
+		"legacy":           nil,
+		"servingInfo":      {"TLSSecurityProfile", "NamedCertificates"},
+	}
+	if diff := cmp.Diff(expected, attribution); len(diff) > 0 {
+		t.Errorf("unexpected attribution (-want +got):\n%s", diff)
+	}
+}
+
+func TestExplainObservedConfigEmpty(t *testing.T) {
+	sections, err := explainObservedConfig(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sections) != 0 {
+		t.Errorf("expected no sections, got %v", sections)
+	}
+}
+
+func TestObservedConfigHistory(t *testing.T) {
+	client := operatorfake.NewSimpleClientset(&operatorv1.OpenShiftControllerManager{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster", Annotations: map[string]string{
+			util.ObservedConfigHistoryAnnotation: `[{"time":"2026-01-01T00:00:00Z","reason":"added controllers"},{"time":"2026-01-01T00:05:00Z","reason":"changed servingInfo.minTLSVersion"}]`,
+		}},
+	})
+
+	history, err := getObservedConfigHistory(context.TODO(), client.OperatorV1())
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	expected := []ObservedConfigRevision{
+		{Time: metav1.NewTime(start), Reason: "added controllers"},
+		{Time: metav1.NewTime(start.Add(5 * time.Minute)), Reason: "changed servingInfo.minTLSVersion"},
+	}
+	if diff := cmp.Diff(expected, history); len(diff) > 0 {
+		t.Errorf("unexpected history (-want +got):\n%s", diff)
+	}
+
+	// nothing is recorded before the operator changed the observed config
+	history, err = getObservedConfigHistory(context.TODO(), operatorfake.NewSimpleClientset(&operatorv1.OpenShiftControllerManager{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+	}).OperatorV1())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) > 0 {
+		t.Errorf("expected no history, got %v", history)
+	}
+}
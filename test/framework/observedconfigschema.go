@@ -0,0 +1,151 @@
+package framework
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
+)
+
+// observedConfigSchemaJSON is the JSON schema of the observed config. It is checked in next to this
+// file and has to be extended together with the config observers.
+//
+//go:embed observedconfig.schema.json
+var observedConfigSchemaJSON []byte
+
+// jsonSchema is the subset of JSON schema the observed config schema is written in.
+type jsonSchema struct {
+	Type                 string                 `json:"type,omitempty"`
+	Properties           map[string]*jsonSchema `json:"properties,omitempty"`
+	AdditionalProperties *additionalProperties  `json:"additionalProperties,omitempty"`
+	Items                *jsonSchema            `json:"items,omitempty"`
+	Enum                 []interface{}          `json:"enum,omitempty"`
+}
+
+// additionalProperties is either a boolean or a schema of the values of the undeclared properties.
+type additionalProperties struct {
+	Allowed bool
+	Schema  *jsonSchema
+}
+
+func (a *additionalProperties) UnmarshalJSON(data []byte) error {
+	if err := json.Unmarshal(data, &a.Allowed); err == nil {
+		return nil
+	}
+	a.Allowed = true
+	return json.Unmarshal(data, &a.Schema)
+}
+
+// ValidateObservedConfigSchema validates the raw observed config against the checked-in JSON schema of
+// the keys the config observers write. Every violation is reported with the dotted path of the offending
+// value, e.g. "servingInfo.minTLSVersion: expected string, got number". An empty config is valid.
+func ValidateObservedConfigSchema(raw []byte) error {
+	schema := &jsonSchema{}
+	if err := json.Unmarshal(observedConfigSchemaJSON, schema); err != nil {
+		return fmt.Errorf("failed to parse the observed config schema: %w", err)
+	}
+	if len(raw) == 0 {
+		return nil
+	}
+	var observedConfig interface{}
+	if err := json.Unmarshal(raw, &observedConfig); err != nil {
+		return fmt.Errorf("failed to unmarshal observed config: %w", err)
+	}
+	return utilerrors.NewAggregate(schema.validate(observedConfig, nil))
+}
+
+// validate returns the violations of value against the schema, ordered by path.
+func (s *jsonSchema) validate(value interface{}, path []string) []error {
+	if actual := jsonType(value); len(s.Type) > 0 && !(s.Type == actual || s.Type == "number" && actual == "integer") {
+		return []error{fmt.Errorf("%s: expected %s, got %s", schemaPath(path), s.Type, jsonTypeName(actual))}
+	}
+	if len(s.Enum) > 0 && !containsValue(s.Enum, value) {
+		return []error{fmt.Errorf("%s: %v is not one of %v", schemaPath(path), value, s.Enum)}
+	}
+
+	var errs []error
+	switch v := value.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			keyPath := append(append([]string{}, path...), key)
+			switch property, declared := s.Properties[key]; {
+			case declared:
+				errs = append(errs, property.validate(v[key], keyPath)...)
+			case s.AdditionalProperties == nil:
+			case !s.AdditionalProperties.Allowed:
+				errs = append(errs, fmt.Errorf("%s: unknown key", schemaPath(keyPath)))
+			case s.AdditionalProperties.Schema != nil:
+				errs = append(errs, s.AdditionalProperties.Schema.validate(v[key], keyPath)...)
+			}
+		}
+	case []interface{}:
+		if s.Items == nil {
+			break
+		}
+		for i, item := range v {
+			itemPath := append([]string{}, path...)
+			if len(itemPath) == 0 {
+				itemPath = []string{""}
+			}
+			itemPath[len(itemPath)-1] += fmt.Sprintf("[%d]", i)
+			errs = append(errs, s.Items.validate(item, itemPath)...)
+		}
+	}
+	return errs
+}
+
+// jsonType returns the JSON schema type of a value decoded by encoding/json.
+func jsonType(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case float64:
+		if v == float64(int64(v)) {
+			return "integer"
+		}
+		return "number"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+// jsonTypeName names an integer a number in the violations, JSON itself does not tell them apart.
+func jsonTypeName(jsonType string) string {
+	if jsonType == "integer" {
+		return "number"
+	}
+	return jsonType
+}
+
+func containsValue(values []interface{}, value interface{}) bool {
+	for _, v := range values {
+		if reflect.DeepEqual(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func schemaPath(path []string) string {
+	if len(path) == 0 {
+		return "<root>"
+	}
+	return strings.Join(path, ".")
+}
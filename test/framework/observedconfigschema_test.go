@@ -0,0 +1,71 @@
+package framework
+
+import (
+	"testing"
+)
+
+func TestValidateObservedConfigSchema(t *testing.T) {
+	tests := []struct {
+		name          string
+		raw           string
+		expectedError string
+	}{
+		{
+			name: "valid",
+			raw: `{
+				"servingInfo": {
+					"minTLSVersion": "VersionTLS12",
+					"cipherSuites": ["TLS_AES_128_GCM_SHA256"],
+					"namedCertificates": [{"names": ["*.apps.example.com"], "certFile": "/tls.crt", "keyFile": "/tls.key"}]
+				},
+				"build": {
+					"additionalTrustedCA": "/var/run/configmaps/additional-trusted-ca/ca-bundle.crt",
+					"imageTemplateFormat": {"format": "quay.io/openshift/origin-${component}:${version}"},
+					"buildDefaults": {"gitHTTPProxy": "http://proxy:3128", "gitNoProxy": ".cluster.local"},
+					"buildOverrides": {"forcePull": true, "nodeSelector": {"kubernetes.io/os": "linux"}}
+				},
+				"dockerPullSecret": {"internalRegistryHostname": "image-registry.openshift-image-registry.svc:5000"},
+				"network": {"clusterNetworks": [{"cidr": "10.128.0.0/14", "hostSubnetLength": 9}], "serviceNetworkCIDR": "172.30.0.0/16"},
+				"controllers": ["*", "-openshift.io/build"],
+				"featureGates": ["BuildCSIVolumes=true"]
+			}`,
+		},
+		{
+			name: "empty",
+		},
+		{
+			name:          "wrong type of minTLSVersion",
+			raw:           `{"servingInfo": {"minTLSVersion": 12}}`,
+			expectedError: "servingInfo.minTLSVersion: expected string, got number",
+		},
+		{
+			name:          "unknown minTLSVersion",
+			raw:           `{"servingInfo": {"minTLSVersion": "VersionTLS14"}}`,
+			expectedError: "servingInfo.minTLSVersion: VersionTLS14 is not one of [VersionTLS10 VersionTLS11 VersionTLS12 VersionTLS13]",
+		},
+		{
+			name:          "wrong type of an array item",
+			raw:           `{"network": {"clusterNetworks": [{"cidr": "10.128.0.0/14", "hostSubnetLength": "9"}]}}`,
+			expectedError: "network.clusterNetworks[0].hostSubnetLength: expected integer, got string",
+		},
+		{
+			name:          "unknown top-level key",
+			raw:           `{"removedSection": {}, "controllers": "*"}`,
+			expectedError: "[controllers: expected array, got string, removedSection: unknown key]",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateObservedConfigSchema([]byte(tc.raw))
+			switch {
+			case len(tc.expectedError) == 0 && err != nil:
+				t.Errorf("unexpected error: %v", err)
+			case len(tc.expectedError) > 0 && err == nil:
+				t.Errorf("expected error %q, got none", tc.expectedError)
+			case len(tc.expectedError) > 0 && err.Error() != tc.expectedError:
+				t.Errorf("expected error %q, got %q", tc.expectedError, err.Error())
+			}
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package framework
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"sigs.k8s.io/yaml"
+
+	"github.com/openshift/cluster-openshift-controller-manager-operator/pkg/util"
+)
+
+const (
+	operandConfigMapName = "config"
+	operandConfigKey     = "config.yaml"
+)
+
+// GetOperandConfig returns the config the operand runs with as JSON, the observed config merged over the
+// defaults as the operator writes it to the config ConfigMap of the operand. HaveObservedConfigValue
+// matches its values like those of the observed config.
+func GetOperandConfig(ctx context.Context, t testing.TB, client *Clientset) ([]byte, error) {
+	t.Helper()
+	return getOperandConfig(ctx, client)
+}
+
+func getOperandConfig(ctx context.Context, client clientcorev1.ConfigMapsGetter) ([]byte, error) {
+	configMap, err := client.ConfigMaps(util.TargetNamespace).Get(ctx, operandConfigMapName, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get configmap/%s -n %s: %w", operandConfigMapName, util.TargetNamespace, err)
+	}
+	config, ok := configMap.Data[operandConfigKey]
+	if !ok {
+		return nil, fmt.Errorf("configmap/%s -n %s has no %s", operandConfigMapName, util.TargetNamespace, operandConfigKey)
+	}
+	raw, err := yaml.YAMLToJSON([]byte(config))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode %s of configmap/%s -n %s: %w", operandConfigKey, operandConfigMapName, util.TargetNamespace, err)
+	}
+	return raw, nil
+}
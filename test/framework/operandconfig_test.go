@@ -0,0 +1,58 @@
+package framework
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/openshift/cluster-openshift-controller-manager-operator/pkg/util"
+)
+
+func TestGetOperandConfig(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        map[string]string
+		expectedErr string
+	}{
+		{
+			name: "config",
+			data: map[string]string{"config.yaml": "apiVersion: openshiftcontrolplane.config.openshift.io/v1\nbuild:\n  buildDefaults:\n    gitNoProxy: .e2e.example.com\n"},
+		},
+		{
+			name:        "no config key",
+			data:        map[string]string{"other.yaml": "{}"},
+			expectedErr: "has no config.yaml",
+		},
+		{
+			name:        "invalid yaml",
+			data:        map[string]string{"config.yaml": "build: [unclosed"},
+			expectedErr: "failed to decode config.yaml",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			client := fake.NewSimpleClientset(&corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: util.TargetNamespace},
+				Data:       tc.data,
+			})
+			raw, err := getOperandConfig(context.TODO(), client.CoreV1())
+			if len(tc.expectedErr) > 0 {
+				if err == nil || !strings.Contains(err.Error(), tc.expectedErr) {
+					t.Fatalf("expected an error containing %q, got %v", tc.expectedErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			gomega.NewGomega(func(message string, _ ...int) { t.Error(message) }).Expect(raw).To(
+				HaveObservedConfigValue("build.buildDefaults.gitNoProxy", ".e2e.example.com"))
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package framework
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+
+	"github.com/openshift/cluster-openshift-controller-manager-operator/pkg/util"
+)
+
+const (
+	// operandHealthzPort is the HTTPS port the operand serves its health endpoints on.
+	operandHealthzPort = "8443"
+	// operandHealthzPollInterval is how often AssertOperandHealthz checks the operand pods.
+	operandHealthzPollInterval = 5 * time.Second
+	// operandHealthzTimeout is how long AssertOperandHealthz waits for all operand pods to be healthy.
+	operandHealthzTimeout = 2 * time.Minute
+	// operandHealthzRequestTimeout bounds a single request, so that a hanging pod does not use up the
+	// whole wait.
+	operandHealthzRequestTimeout = 10 * time.Second
+)
+
+// AssertOperandHealthz fails the test unless every running operand pod serves /healthz with a 200, and
+// /readyz as well where the operand exposes it. The pods are reached through the pod proxy of the API
+// server, so no port-forward has to be set up or torn down. Pods that are not healthy yet, e.g. right
+// after a config change rolled them out, are waited for up to two minutes.
+func AssertOperandHealthz(ctx context.Context, t testing.TB, client *Clientset) {
+	t.Helper()
+	if err := assertOperandHealthz(ctx, t, client, operandHealthzPollInterval, operandHealthzTimeout); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func assertOperandHealthz(ctx context.Context, logger Logger, client clientcorev1.PodsGetter, interval, timeout time.Duration) error {
+	var unhealthy []string
+	var lastErr error
+	err := poll(ctx, interval, timeout, func(ctx context.Context) (bool, error) {
+		pods, err := client.Pods(util.TargetNamespace).List(ctx, metav1.ListOptions{LabelSelector: operandPodSelector})
+		if err != nil {
+			logger.Logf("error listing the operand pods in %s: %v", util.TargetNamespace, err)
+			lastErr = err
+			return false, nil
+		}
+		lastErr = nil
+		unhealthy = nil
+		checked := 0
+		for _, pod := range pods.Items {
+			if pod.Status.Phase != corev1.PodRunning || pod.DeletionTimestamp != nil {
+				continue
+			}
+			checked++
+			if err := checkPodHealthz(ctx, client, pod.Name); err != nil {
+				unhealthy = append(unhealthy, fmt.Sprintf("%s: %v", pod.Name, err))
+			}
+		}
+		if checked == 0 {
+			lastErr = fmt.Errorf("found no running operand pods with %q in %s", operandPodSelector, util.TargetNamespace)
+			logger.Logf("%v", lastErr)
+			return false, nil
+		}
+		if len(unhealthy) > 0 {
+			logger.Logf("waiting for the operand pods in %s to be healthy: %s", util.TargetNamespace, strings.Join(unhealthy, "; "))
+			return false, nil
+		}
+		return true, nil
+	})
+	if err == nil {
+		return nil
+	}
+	if lastErr != nil {
+		return fmt.Errorf("the operand in %s is not healthy, last error: %v: %w", util.TargetNamespace, lastErr, err)
+	}
+	sort.Strings(unhealthy)
+	return fmt.Errorf("the operand pods in %s are not healthy: %s: %w", util.TargetNamespace, strings.Join(unhealthy, "; "), err)
+}
+
+// checkPodHealthz returns an error unless the pod answers /healthz, and /readyz if it is exposed, with
+// a 200.
+func checkPodHealthz(ctx context.Context, client clientcorev1.PodsGetter, podName string) error {
+	for _, endpoint := range []struct {
+		path     string
+		optional bool
+	}{
+		{path: "healthz"},
+		{path: "readyz", optional: true},
+	} {
+		requestCtx, cancel := context.WithTimeout(ctx, operandHealthzRequestTimeout)
+		_, err := client.Pods(util.TargetNamespace).ProxyGet("https", podName, operandHealthzPort, endpoint.path, nil).DoRaw(requestCtx)
+		cancel()
+		if endpoint.optional && errors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("/%s: %v", endpoint.path, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,172 @@
+package framework
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/fake"
+	restclient "k8s.io/client-go/rest"
+	clienttesting "k8s.io/client-go/testing"
+)
+
+// fakeResponse is the answer of a pod to a request through the pod proxy.
+type fakeResponse struct {
+	err error
+}
+
+func (r fakeResponse) DoRaw(context.Context) ([]byte, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	return []byte("ok"), nil
+}
+
+func (r fakeResponse) Stream(context.Context) (io.ReadCloser, error) {
+	return io.NopCloser(strings.NewReader("ok")), r.err
+}
+
+func runningOperandPod(name string, phase corev1.PodPhase) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "openshift-controller-manager",
+			Labels:    map[string]string{"app": "openshift-controller-manager-a"},
+		},
+		Status: corev1.PodStatus{Phase: phase},
+	}
+}
+
+func TestAssertOperandHealthz(t *testing.T) {
+	podsResource := schema.GroupResource{Resource: "pods"}
+	notFound := errors.NewNotFound(podsResource, "readyz")
+	unavailable := errors.NewServiceUnavailable("not serving")
+
+	tests := []struct {
+		name string
+		pods []*corev1.Pod
+		// respond answers a request of the pod to the path, calls counts the requests to the pod so far
+		respond   func(pod, path string, calls int) error
+		cancel    bool
+		expectErr []string
+	}{
+		{
+			name: "healthy without readyz",
+			pods: []*corev1.Pod{runningOperandPod("controller-manager-a", corev1.PodRunning), runningOperandPod("controller-manager-b", corev1.PodRunning)},
+			respond: func(_, path string, _ int) error {
+				if path == "readyz" {
+					return notFound
+				}
+				return nil
+			},
+		},
+		{
+			name:    "healthy with readyz",
+			pods:    []*corev1.Pod{runningOperandPod("controller-manager-a", corev1.PodRunning)},
+			respond: func(string, string, int) error { return nil },
+		},
+		{
+			name: "pending pods are not checked",
+			pods: []*corev1.Pod{runningOperandPod("controller-manager-a", corev1.PodRunning), runningOperandPod("controller-manager-b", corev1.PodPending)},
+			respond: func(pod, _ string, _ int) error {
+				if pod == "controller-manager-b" {
+					return unavailable
+				}
+				return nil
+			},
+		},
+		{
+			name: "pod becomes healthy",
+			pods: []*corev1.Pod{runningOperandPod("controller-manager-a", corev1.PodRunning)},
+			respond: func(_, _ string, calls int) error {
+				if calls < 3 {
+					return unavailable
+				}
+				return nil
+			},
+		},
+		{
+			name: "pod never healthy",
+			pods: []*corev1.Pod{runningOperandPod("controller-manager-a", corev1.PodRunning), runningOperandPod("controller-manager-b", corev1.PodRunning)},
+			respond: func(pod, path string, _ int) error {
+				if pod == "controller-manager-b" && path == "healthz" {
+					return unavailable
+				}
+				return nil
+			},
+			expectErr: []string{"controller-manager-b: /healthz", "not serving"},
+		},
+		{
+			name: "readyz failing",
+			pods: []*corev1.Pod{runningOperandPod("controller-manager-a", corev1.PodRunning)},
+			respond: func(_, path string, _ int) error {
+				if path == "readyz" {
+					return errors.NewInternalError(io.ErrUnexpectedEOF)
+				}
+				return nil
+			},
+			expectErr: []string{"controller-manager-a: /readyz"},
+		},
+		{
+			name:      "no running pods",
+			pods:      []*corev1.Pod{runningOperandPod("controller-manager-a", corev1.PodPending)},
+			respond:   func(string, string, int) error { return nil },
+			expectErr: []string{"found no running operand pods"},
+		},
+		{
+			name:      "cancelled context",
+			pods:      []*corev1.Pod{runningOperandPod("controller-manager-a", corev1.PodRunning)},
+			respond:   func(string, string, int) error { return nil },
+			cancel:    true,
+			expectErr: []string{"context canceled"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			client := fake.NewSimpleClientset()
+			for _, pod := range tc.pods {
+				if err := client.Tracker().Add(pod); err != nil {
+					t.Fatal(err)
+				}
+			}
+			calls := map[string]int{}
+			client.AddProxyReactor("pods", func(action clienttesting.Action) (bool, restclient.ResponseWrapper, error) {
+				proxy := action.(clienttesting.ProxyGetAction)
+				if proxy.GetScheme() != "https" || proxy.GetPort() != "8443" {
+					t.Errorf("unexpected proxy request %s://%s:%s", proxy.GetScheme(), proxy.GetName(), proxy.GetPort())
+				}
+				calls[proxy.GetName()]++
+				return true, fakeResponse{err: tc.respond(proxy.GetName(), proxy.GetPath(), calls[proxy.GetName()])}, nil
+			})
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			if tc.cancel {
+				cancel()
+			}
+
+			err := assertOperandHealthz(ctx, t, client.CoreV1(), time.Millisecond, 100*time.Millisecond)
+			if len(tc.expectErr) == 0 {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			for _, s := range tc.expectErr {
+				if !strings.Contains(err.Error(), s) {
+					t.Errorf("expected error to contain %q, got %q", s, err)
+				}
+			}
+		})
+	}
+}
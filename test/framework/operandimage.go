@@ -0,0 +1,109 @@
+package framework
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientappsv1 "k8s.io/client-go/kubernetes/typed/apps/v1"
+	clientcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+
+	"github.com/openshift/cluster-openshift-controller-manager-operator/pkg/util"
+)
+
+const (
+	operandContainerName   = "controller-manager"
+	operandPodSelector     = "app=openshift-controller-manager-a"
+	operatorDeploymentName = "openshift-controller-manager-operator"
+)
+
+// ImageMatchMode is how the image of the operand is compared to the expected image.
+type ImageMatchMode int
+
+const (
+	// ImageMatchExact requires the image pull spec to equal the expected one.
+	ImageMatchExact ImageMatchMode = iota
+	// ImageMatchSubstring requires the image pull spec to contain the expected one.
+	ImageMatchSubstring
+	// ImageMatchDigest requires the image pull spec to have the digest of the expected one, which is either a
+	// pull spec by digest or a digest.
+	ImageMatchDigest
+)
+
+// AssertOperandImage fails the test unless the controller-manager container of all the operand pods
+// runs the expected image.
+func AssertOperandImage(ctx context.Context, t testing.TB, client *Clientset, expectedImage string, match ImageMatchMode) {
+	t.Helper()
+	if err := checkOperandImage(ctx, client, expectedImage, match); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// ExpectedOperandImage returns the operand image the operator deploys, as set in its deployment.
+func ExpectedOperandImage(ctx context.Context, client clientappsv1.DeploymentsGetter) (string, error) {
+	deployment, err := client.Deployments(util.OperatorNamespace).Get(ctx, operatorDeploymentName, metav1.GetOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to get deployment/%s -n %s: %w", operatorDeploymentName, util.OperatorNamespace, err)
+	}
+	for _, container := range deployment.Spec.Template.Spec.Containers {
+		for _, env := range container.Env {
+			if env.Name == "IMAGE" {
+				return env.Value, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("deployment/%s -n %s has no IMAGE env var", operatorDeploymentName, util.OperatorNamespace)
+}
+
+func checkOperandImage(ctx context.Context, client clientcorev1.PodsGetter, expectedImage string, match ImageMatchMode) error {
+	pods, err := client.Pods(util.TargetNamespace).List(ctx, metav1.ListOptions{LabelSelector: operandPodSelector})
+	if err != nil {
+		return fmt.Errorf("failed to list the operand pods in %s: %w", util.TargetNamespace, err)
+	}
+	if len(pods.Items) == 0 {
+		return fmt.Errorf("found no operand pods with %q in %s", operandPodSelector, util.TargetNamespace)
+	}
+
+	var actual, mismatched []string
+	for _, pod := range pods.Items {
+		found := false
+		for _, container := range pod.Spec.Containers {
+			if container.Name != operandContainerName {
+				continue
+			}
+			found = true
+			image := fmt.Sprintf("pod/%s: %s", pod.Name, container.Image)
+			actual = append(actual, image)
+			if !imageMatches(container.Image, expectedImage, match) {
+				mismatched = append(mismatched, image)
+			}
+		}
+		if !found {
+			actual = append(actual, fmt.Sprintf("pod/%s: no %s container", pod.Name, operandContainerName))
+			mismatched = append(mismatched, pod.Name)
+		}
+	}
+	if len(mismatched) > 0 {
+		sort.Strings(actual)
+		return fmt.Errorf("%d of %d operand pods in %s do not run image %q, found:\n%s", len(mismatched), len(pods.Items), util.TargetNamespace, expectedImage, strings.Join(actual, "\n"))
+	}
+	return nil
+}
+
+func imageMatches(image, expectedImage string, match ImageMatchMode) bool {
+	switch match {
+	case ImageMatchSubstring:
+		return strings.Contains(image, expectedImage)
+	case ImageMatchDigest:
+		digest := expectedImage
+		if i := strings.LastIndex(expectedImage, "@"); i >= 0 {
+			digest = expectedImage[i+1:]
+		}
+		return len(digest) > 0 && strings.HasSuffix(image, "@"+digest)
+	default:
+		return image == expectedImage
+	}
+}
@@ -0,0 +1,105 @@
+package framework
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+const (
+	testDigest        = "sha256:4f53cda18c2baa0c0354bb5f9a3ecbe5ed12ab4d8e11ba873c2f11161202b945"
+	testOperandImage  = "quay.io/openshift/controller-manager@" + testDigest
+	testMirroredImage = "mirror.example.com/openshift/controller-manager@" + testDigest
+)
+
+func operandPod(name, image string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "openshift-controller-manager",
+			Labels:    map[string]string{"app": "openshift-controller-manager-a"},
+		},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "controller-manager", Image: image}},
+		},
+	}
+}
+
+func TestCheckOperandImage(t *testing.T) {
+	tests := []struct {
+		name           string
+		pods           []runtime.Object
+		expectedImage  string
+		match          ImageMatchMode
+		expectedErrors []string
+	}{
+		{
+			name:          "exact match",
+			pods:          []runtime.Object{operandPod("a", testOperandImage), operandPod("b", testOperandImage)},
+			expectedImage: testOperandImage,
+			match:         ImageMatchExact,
+		},
+		{
+			name:           "stale pod",
+			pods:           []runtime.Object{operandPod("a", testOperandImage), operandPod("b", "quay.io/openshift/controller-manager:old")},
+			expectedImage:  testOperandImage,
+			match:          ImageMatchExact,
+			expectedErrors: []string{"1 of 2 operand pods", "pod/b: quay.io/openshift/controller-manager:old", "pod/a: " + testOperandImage},
+		},
+		{
+			name:          "digest match of a mirrored image",
+			pods:          []runtime.Object{operandPod("a", testMirroredImage)},
+			expectedImage: testOperandImage,
+			match:         ImageMatchDigest,
+		},
+		{
+			name:          "digest match of a bare digest",
+			pods:          []runtime.Object{operandPod("a", testMirroredImage)},
+			expectedImage: testDigest,
+			match:         ImageMatchDigest,
+		},
+		{
+			name:           "digest mismatch",
+			pods:           []runtime.Object{operandPod("a", "quay.io/openshift/controller-manager@sha256:0000")},
+			expectedImage:  testOperandImage,
+			match:          ImageMatchDigest,
+			expectedErrors: []string{"pod/a: quay.io/openshift/controller-manager@sha256:0000"},
+		},
+		{
+			name:          "substring match",
+			pods:          []runtime.Object{operandPod("a", testMirroredImage)},
+			expectedImage: "openshift/controller-manager",
+			match:         ImageMatchSubstring,
+		},
+		{
+			name:           "no operand pods",
+			expectedImage:  testOperandImage,
+			expectedErrors: []string{"found no operand pods"},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			client := fake.NewSimpleClientset(tc.pods...)
+			err := checkOperandImage(context.TODO(), client.CoreV1(), tc.expectedImage, tc.match)
+			if len(tc.expectedErrors) == 0 {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			for _, expected := range tc.expectedErrors {
+				if !strings.Contains(err.Error(), expected) {
+					t.Errorf("expected the error to contain %q, got %q", expected, err.Error())
+				}
+			}
+		})
+	}
+}
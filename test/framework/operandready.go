@@ -0,0 +1,61 @@
+package framework
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientappsv1 "k8s.io/client-go/kubernetes/typed/apps/v1"
+
+	"github.com/openshift/cluster-openshift-controller-manager-operator/pkg/util"
+)
+
+const (
+	operandDeploymentName = "controller-manager"
+	// operandReadyPollInterval is how often WaitForOperandReady checks the operand deployment.
+	operandReadyPollInterval = 5 * time.Second
+)
+
+// WaitForOperandReady waits for at least minReady pods of the operand deployment to be available. The
+// deployment missing, e.g. while it is recreated, is waited out. The error on timeout has the last
+// status of the deployment.
+func WaitForOperandReady(ctx context.Context, t testing.TB, client *Clientset, minReady int32, timeout time.Duration) error {
+	t.Helper()
+	return waitForOperandReady(ctx, t, client, minReady, operandReadyPollInterval, timeout)
+}
+
+func waitForOperandReady(ctx context.Context, logger Logger, client clientappsv1.DeploymentsGetter, minReady int32, interval, timeout time.Duration) error {
+	var deployment *appsv1.Deployment
+	var lastErr error
+	err := poll(ctx, interval, timeout, func(ctx context.Context) (bool, error) {
+		var err error
+		deployment, err = client.Deployments(util.TargetNamespace).Get(ctx, operandDeploymentName, metav1.GetOptions{})
+		if errors.IsNotFound(err) {
+			logger.Logf("waiting for deployment/%s -n %s: the deployment does not exist", operandDeploymentName, util.TargetNamespace)
+			deployment, lastErr = nil, err
+			return false, nil
+		}
+		if err != nil {
+			logger.Logf("error getting deployment/%s -n %s: %v", operandDeploymentName, util.TargetNamespace, err)
+			deployment, lastErr = nil, err
+			return false, nil
+		}
+		lastErr = nil
+		return deployment.Status.AvailableReplicas >= minReady, nil
+	})
+	if err == nil {
+		return nil
+	}
+	if deployment == nil {
+		return fmt.Errorf("deployment/%s -n %s did not get %d available replicas, last error: %v: %w", operandDeploymentName, util.TargetNamespace, minReady, lastErr, err)
+	}
+	status := deployment.Status
+	return fmt.Errorf("deployment/%s -n %s did not get %d available replicas, has replicas=%d updated=%d ready=%d available=%d unavailable=%d observedGeneration=%d generation=%d: %w",
+		operandDeploymentName, util.TargetNamespace, minReady,
+		status.Replicas, status.UpdatedReplicas, status.ReadyReplicas, status.AvailableReplicas, status.UnavailableReplicas,
+		status.ObservedGeneration, deployment.Generation, err)
+}
@@ -0,0 +1,81 @@
+package framework
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+)
+
+func operandDeployment(available int32) *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "controller-manager", Namespace: "openshift-controller-manager"},
+		Status:     appsv1.DeploymentStatus{Replicas: 3, ReadyReplicas: available, AvailableReplicas: available, UnavailableReplicas: 3 - available},
+	}
+}
+
+func TestWaitForOperandReady(t *testing.T) {
+	client := fake.NewSimpleClientset(operandDeployment(3))
+	if err := waitForOperandReady(context.Background(), t, client.AppsV1(), 2, time.Millisecond, time.Second); err != nil {
+		t.Errorf("expected the operand to be ready, got %v", err)
+	}
+}
+
+func TestWaitForOperandReadyRetriesMissingDeployment(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	gets := 0
+	client.PrependReactor("get", "deployments", func(clienttesting.Action) (bool, runtime.Object, error) {
+		gets++
+		if gets < 3 {
+			// fall through to the tracker, which does not have the deployment yet
+			return false, nil, nil
+		}
+		return true, operandDeployment(3), nil
+	})
+
+	if err := waitForOperandReady(context.Background(), t, client.AppsV1(), 3, time.Millisecond, time.Second); err != nil {
+		t.Errorf("expected the deployment to be waited for, got %v", err)
+	}
+	if gets < 3 {
+		t.Errorf("expected the missing deployment to be retried, got %d gets", gets)
+	}
+}
+
+func TestWaitForOperandReadyTimeout(t *testing.T) {
+	tests := []struct {
+		name           string
+		objects        []runtime.Object
+		expectedErrors []string
+	}{
+		{
+			name:           "too few available replicas",
+			objects:        []runtime.Object{operandDeployment(1)},
+			expectedErrors: []string{"did not get 2 available replicas", "replicas=3", "available=1", "unavailable=2"},
+		},
+		{
+			name:           "missing deployment",
+			expectedErrors: []string{"did not get 2 available replicas", "not found"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			client := fake.NewSimpleClientset(tc.objects...)
+			err := waitForOperandReady(context.Background(), t, client.AppsV1(), 2, time.Millisecond, 20*time.Millisecond)
+			if err == nil {
+				t.Fatal("expected a timeout")
+			}
+			for _, expected := range tc.expectedErrors {
+				if !strings.Contains(err.Error(), expected) {
+					t.Errorf("expected %q in error %q", expected, err.Error())
+				}
+			}
+		})
+	}
+}
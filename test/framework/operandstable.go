@@ -0,0 +1,91 @@
+package framework
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	clientappsv1 "k8s.io/client-go/kubernetes/typed/apps/v1"
+	clientcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+
+	"github.com/openshift/cluster-openshift-controller-manager-operator/pkg/util"
+)
+
+// operandStablePollInterval is how often AssertOperandStableFor checks the operand.
+const operandStablePollInterval = 5 * time.Second
+
+// OperandSnapshot is what changes when the operand deployment rolls out or its pods are restarted.
+type OperandSnapshot struct {
+	// Generation is the generation of the operand deployment.
+	Generation int64
+	// Pods are the operand pods as sorted "name/uid" pairs.
+	Pods []string
+}
+
+type operandSnapshotClient interface {
+	clientappsv1.DeploymentsGetter
+	clientcorev1.PodsGetter
+}
+
+// GetOperandSnapshot returns the generation of the operand deployment and its pods.
+func GetOperandSnapshot(ctx context.Context, client *Clientset) (OperandSnapshot, error) {
+	return getOperandSnapshot(ctx, client)
+}
+
+func getOperandSnapshot(ctx context.Context, client operandSnapshotClient) (OperandSnapshot, error) {
+	deployment, err := client.Deployments(util.TargetNamespace).Get(ctx, operandDeploymentName, metav1.GetOptions{})
+	if err != nil {
+		return OperandSnapshot{}, fmt.Errorf("failed to get deployment/%s -n %s: %w", operandDeploymentName, util.TargetNamespace, err)
+	}
+	pods, err := client.Pods(util.TargetNamespace).List(ctx, metav1.ListOptions{LabelSelector: operandPodSelector})
+	if err != nil {
+		return OperandSnapshot{}, fmt.Errorf("failed to list the operand pods in %s: %w", util.TargetNamespace, err)
+	}
+	snapshot := OperandSnapshot{Generation: deployment.Generation}
+	for _, pod := range pods.Items {
+		snapshot.Pods = append(snapshot.Pods, fmt.Sprintf("%s/%s", pod.Name, pod.UID))
+	}
+	sort.Strings(snapshot.Pods)
+	return snapshot, nil
+}
+
+// assertOperandStableFor polls the operand every interval until duration elapsed and returns an error
+// as soon as it differs from before.
+func assertOperandStableFor(ctx context.Context, logger Logger, client operandSnapshotClient, before OperandSnapshot, duration, interval time.Duration) error {
+	err := poll(ctx, interval, duration, func(ctx context.Context) (bool, error) {
+		snapshot, err := getOperandSnapshot(ctx, client)
+		if err != nil {
+			logger.Logf("%v", err)
+			return false, nil
+		}
+		if snapshot.Generation != before.Generation {
+			return false, fmt.Errorf("deployment/%s -n %s rolled out, generation %d became %d", operandDeploymentName, util.TargetNamespace, before.Generation, snapshot.Generation)
+		}
+		if fmt.Sprint(snapshot.Pods) != fmt.Sprint(before.Pods) {
+			return false, fmt.Errorf("the operand pods in %s changed from %v to %v", util.TargetNamespace, before.Pods, snapshot.Pods)
+		}
+		return false, nil
+	})
+	// the condition never finishes the poll, so running into the timeout means the window passed without a change
+	if ctx.Err() != nil {
+		return err
+	}
+	if wait.Interrupted(err) {
+		return nil
+	}
+	return err
+}
+
+// AssertOperandStableFor fails the test if the operand deployment rolls out or its pods change at any
+// point during the given duration, compared to before. Stopping short of the full window, e.g. because
+// ctx is cancelled, fails the test as well.
+func AssertOperandStableFor(ctx context.Context, t testing.TB, client *Clientset, before OperandSnapshot, duration time.Duration) {
+	t.Helper()
+	if err := assertOperandStableFor(ctx, t, client, before, duration, operandStablePollInterval); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,139 @@
+package framework
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+)
+
+func operandDeploymentWithGeneration(generation int64) *appsv1.Deployment {
+	return &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{
+		Name:       "controller-manager",
+		Namespace:  "openshift-controller-manager",
+		Generation: generation,
+	}}
+}
+
+func operandPodWithUID(name string, uid types.UID) *corev1.Pod {
+	return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+		Name:      name,
+		Namespace: "openshift-controller-manager",
+		UID:       uid,
+		Labels:    map[string]string{"app": "openshift-controller-manager-a"},
+	}}
+}
+
+func TestGetOperandSnapshot(t *testing.T) {
+	kubeClient := fake.NewSimpleClientset(
+		operandDeploymentWithGeneration(3),
+		operandPodWithUID("controller-manager-b", "uid-b"),
+		operandPodWithUID("controller-manager-a", "uid-a"),
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "openshift-controller-manager", UID: "uid-other"}},
+	)
+	client := &Clientset{CoreV1Interface: kubeClient.CoreV1(), AppsV1Interface: kubeClient.AppsV1()}
+
+	snapshot, err := GetOperandSnapshot(context.TODO(), client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if snapshot.Generation != 3 {
+		t.Errorf("expected generation 3, got %d", snapshot.Generation)
+	}
+	if pods := strings.Join(snapshot.Pods, ","); pods != "controller-manager-a/uid-a,controller-manager-b/uid-b" {
+		t.Errorf("expected the sorted operand pods, got %s", pods)
+	}
+}
+
+func TestAssertOperandStableFor(t *testing.T) {
+	restartedPods := &corev1.PodList{Items: []corev1.Pod{*operandPodWithUID("controller-manager-c", "uid-c")}}
+	tests := []struct {
+		name string
+		// changeAfter is the number of deployment gets after which the operand changes, 0 never
+		changeAfter int
+		generation  int64
+		pods        *corev1.PodList
+		cancel      bool
+		expectErr   []string
+	}{
+		{
+			name: "stable for the whole window",
+		},
+		{
+			name:        "rolled out within the window",
+			changeAfter: 3,
+			generation:  4,
+			expectErr:   []string{"rolled out", "generation 3 became 4"},
+		},
+		{
+			name:        "pod restarted within the window",
+			changeAfter: 3,
+			pods:        restartedPods,
+			expectErr:   []string{"pods", "controller-manager-a/uid-a", "controller-manager-c/uid-c"},
+		},
+		{
+			name:      "cancelled context",
+			cancel:    true,
+			expectErr: []string{"context canceled"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			kubeClient := fake.NewSimpleClientset(operandDeploymentWithGeneration(3), operandPodWithUID("controller-manager-a", "uid-a"))
+			client := &Clientset{CoreV1Interface: kubeClient.CoreV1(), AppsV1Interface: kubeClient.AppsV1()}
+			before, err := getOperandSnapshot(context.TODO(), client)
+			if err != nil {
+				t.Fatal(err)
+			}
+			gets := 0
+			changed := func() bool { return tc.changeAfter > 0 && gets >= tc.changeAfter }
+			kubeClient.PrependReactor("get", "deployments", func(clienttesting.Action) (bool, runtime.Object, error) {
+				gets++
+				if changed() && tc.generation > 0 {
+					return true, operandDeploymentWithGeneration(tc.generation), nil
+				}
+				return false, nil, nil
+			})
+			kubeClient.PrependReactor("list", "pods", func(clienttesting.Action) (bool, runtime.Object, error) {
+				if changed() && tc.pods != nil {
+					return true, tc.pods, nil
+				}
+				return false, nil, nil
+			})
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			if tc.cancel {
+				cancel()
+			}
+
+			err = assertOperandStableFor(ctx, t, client, before, 200*time.Millisecond, 10*time.Millisecond)
+			if len(tc.expectErr) == 0 {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if gets < 2 {
+					t.Errorf("expected the operand to be polled repeatedly, got %d gets", gets)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			for _, s := range tc.expectErr {
+				if !strings.Contains(err.Error(), s) {
+					t.Errorf("expected error to contain %q, got %q", s, err)
+				}
+			}
+		})
+	}
+}
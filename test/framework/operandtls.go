@@ -0,0 +1,207 @@
+package framework
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientappsv1 "k8s.io/client-go/kubernetes/typed/apps/v1"
+	clientcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+
+	"github.com/openshift/cluster-openshift-controller-manager-operator/pkg/util"
+)
+
+const (
+	// operandServiceName is the service in front of the serving endpoint of the operand pods.
+	operandServiceName = "controller-manager"
+	// operandTLSProbeInterval is how often AssertOperandTLSVersion probes the operand.
+	operandTLSProbeInterval = 10 * time.Second
+	// operandTLSProbeTimeout is how long AssertOperandTLSVersion waits for the operand to serve the
+	// expected TLS versions, it outlasts a rollout of the operand for a changed TLS profile.
+	operandTLSProbeTimeout = 10 * time.Minute
+	// curlSSLConnectError is the exit code of curl for a failed TLS handshake.
+	curlSSLConnectError = 35
+	// minTLSVersionProbedAccepted is the lowest TLS version the probe expects the operand to accept. The
+	// crypto policy of the probing image refuses older versions itself, so they are only probed to be
+	// refused.
+	minTLSVersionProbedAccepted = "VersionTLS12"
+)
+
+// tlsVersions are the TLS versions by their name in the observed config, in ascending order, with the
+// version curl takes for them.
+var tlsVersions = []struct {
+	name string
+	curl string
+}{
+	{name: "VersionTLS10", curl: "1.0"},
+	{name: "VersionTLS11", curl: "1.1"},
+	{name: "VersionTLS12", curl: "1.2"},
+	{name: "VersionTLS13", curl: "1.3"},
+}
+
+type operandTLSClient interface {
+	clientcorev1.PodsGetter
+	clientcorev1.ServicesGetter
+	clientappsv1.DeploymentsGetter
+}
+
+// tlsProbe is a TLS version the operand is probed with and whether it is expected to complete the
+// handshake.
+type tlsProbe struct {
+	version  string
+	curl     string
+	accepted bool
+}
+
+// AssertOperandTLSVersion fails the test unless the serving endpoint of the operand refuses TLS handshakes
+// below expectedMin, a TLS version name like VersionTLS13, and completes them at expectedMin and above.
+// The API server proxy terminates TLS itself and port forwarding needs a client the framework does not
+// have, so the endpoint is probed from a pod in a test namespace running curl from the operator image
+// against the operand service, restricted to a single TLS version per handshake. A rollout of the operand
+// for a changed TLS profile is waited out.
+func AssertOperandTLSVersion(ctx context.Context, t testing.TB, client *Clientset, expectedMin string) {
+	t.Helper()
+	namespace := CreateTestNamespace(ctx, t, client)
+	if err := assertOperandTLSVersion(ctx, t, client, namespace, expectedMin, operandTLSProbeInterval, operandTLSProbeTimeout, metricsScrapePodTimeout); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func assertOperandTLSVersion(ctx context.Context, logger Logger, client operandTLSClient, namespace, expectedMin string, interval, timeout, podTimeout time.Duration) error {
+	probes, err := tlsProbes(expectedMin)
+	if err != nil {
+		return err
+	}
+	url, err := operandServingURL(ctx, client)
+	if err != nil {
+		return err
+	}
+	image, err := operatorImage(ctx, client)
+	if err != nil {
+		return err
+	}
+
+	var lastErr error
+	err = poll(ctx, interval, timeout, func(ctx context.Context) (bool, error) {
+		var logs []byte
+		logs, lastErr = runPod(ctx, client, tlsProbePod(namespace, image, url, probes), podTimeout)
+		if lastErr == nil {
+			lastErr = checkTLSProbes(probes, logs)
+		}
+		if lastErr != nil {
+			logger.Logf("waiting for %s to serve TLS from %s up: %v", url, expectedMin, lastErr)
+			return false, nil
+		}
+		return true, nil
+	})
+	if err != nil {
+		return fmt.Errorf("%s does not serve TLS from %s up, last error: %v: %w", url, expectedMin, lastErr, err)
+	}
+	return nil
+}
+
+// tlsProbes returns the TLS versions to probe the operand with for the expected minimum version.
+func tlsProbes(expectedMin string) ([]tlsProbe, error) {
+	minIndex, probedIndex := -1, -1
+	for i, version := range tlsVersions {
+		if version.name == expectedMin {
+			minIndex = i
+		}
+		if version.name == minTLSVersionProbedAccepted {
+			probedIndex = i
+		}
+	}
+	if minIndex < 0 {
+		return nil, fmt.Errorf("unknown TLS version %q", expectedMin)
+	}
+	var probes []tlsProbe
+	for i, version := range tlsVersions {
+		switch {
+		case i < minIndex:
+			probes = append(probes, tlsProbe{version: version.name, curl: version.curl})
+		case i >= probedIndex:
+			probes = append(probes, tlsProbe{version: version.name, curl: version.curl, accepted: true})
+		}
+	}
+	return probes, nil
+}
+
+// operandServingURL returns the URL of the healthz endpoint of the operand behind the https port of its
+// service.
+func operandServingURL(ctx context.Context, client clientcorev1.ServicesGetter) (string, error) {
+	service, err := client.Services(util.TargetNamespace).Get(ctx, operandServiceName, metav1.GetOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to get service/%s -n %s: %w", operandServiceName, util.TargetNamespace, err)
+	}
+	for _, port := range service.Spec.Ports {
+		if port.Name == "https" {
+			return fmt.Sprintf("https://%s.%s.svc:%d/healthz", service.Name, service.Namespace, port.Port), nil
+		}
+	}
+	return "", fmt.Errorf("service/%s -n %s has no https port", operandServiceName, util.TargetNamespace)
+}
+
+// tlsProbePod returns a pod handshaking with url once per probe, each restricted to the TLS version of the
+// probe, and printing the TLS version followed by the exit code of curl per probe.
+func tlsProbePod(namespace, image, url string, probes []tlsProbe) *corev1.Pod {
+	var script []string
+	for _, probe := range probes {
+		script = append(script, fmt.Sprintf(`curl --silent --output /dev/null --max-time 30 --cacert %s --tlsv%s --tls-max %s %s; echo "%s $?"`,
+			serviceCAFile, probe.curl, probe.curl, url, probe.version))
+	}
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: "tls-probe-",
+			Namespace:    namespace,
+		},
+		Spec: corev1.PodSpec{
+			RestartPolicy: corev1.RestartPolicyNever,
+			Containers: []corev1.Container{{
+				Name:    "probe",
+				Image:   image,
+				Command: []string{"/bin/bash", "-c"},
+				Args:    []string{strings.Join(script, "\n")},
+			}},
+		},
+	}
+}
+
+// checkTLSProbes returns an error listing the probes whose handshake did not end as expected in the logs
+// of a probe pod. A handshake failing other than at TLS, e.g. as the operand is not reachable, fails the
+// check as well.
+func checkTLSProbes(probes []tlsProbe, logs []byte) error {
+	exitCodes := map[string]int{}
+	scanner := bufio.NewScanner(bytes.NewReader(logs))
+	for scanner.Scan() {
+		var version string
+		var exitCode int
+		if _, err := fmt.Sscanf(scanner.Text(), "%s %d", &version, &exitCode); err == nil {
+			exitCodes[version] = exitCode
+		}
+	}
+
+	var mismatches []string
+	for _, probe := range probes {
+		exitCode, ok := exitCodes[probe.version]
+		switch {
+		case !ok:
+			mismatches = append(mismatches, fmt.Sprintf("%s was not probed", probe.version))
+		case probe.accepted && exitCode != 0:
+			mismatches = append(mismatches, fmt.Sprintf("%s was not accepted, curl exited with %d", probe.version, exitCode))
+		case !probe.accepted && exitCode == 0:
+			mismatches = append(mismatches, fmt.Sprintf("%s was accepted", probe.version))
+		case !probe.accepted && exitCode != curlSSLConnectError:
+			mismatches = append(mismatches, fmt.Sprintf("%s failed other than at the handshake, curl exited with %d", probe.version, exitCode))
+		}
+	}
+	if len(mismatches) > 0 {
+		return fmt.Errorf("%s", strings.Join(mismatches, ", "))
+	}
+	return nil
+}
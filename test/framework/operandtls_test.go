@@ -0,0 +1,157 @@
+package framework
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestTLSProbes(t *testing.T) {
+	tests := []struct {
+		expectedMin   string
+		expected      []tlsProbe
+		expectedError string
+	}{
+		{
+			expectedMin: "VersionTLS13",
+			expected: []tlsProbe{
+				{version: "VersionTLS10", curl: "1.0"},
+				{version: "VersionTLS11", curl: "1.1"},
+				{version: "VersionTLS12", curl: "1.2"},
+				{version: "VersionTLS13", curl: "1.3", accepted: true},
+			},
+		},
+		{
+			expectedMin: "VersionTLS12",
+			expected: []tlsProbe{
+				{version: "VersionTLS10", curl: "1.0"},
+				{version: "VersionTLS11", curl: "1.1"},
+				{version: "VersionTLS12", curl: "1.2", accepted: true},
+				{version: "VersionTLS13", curl: "1.3", accepted: true},
+			},
+		},
+		{
+			// the probing image refuses the older versions itself
+			expectedMin: "VersionTLS10",
+			expected: []tlsProbe{
+				{version: "VersionTLS12", curl: "1.2", accepted: true},
+				{version: "VersionTLS13", curl: "1.3", accepted: true},
+			},
+		},
+		{
+			expectedMin:   "TLSv1.3",
+			expectedError: `unknown TLS version "TLSv1.3"`,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.expectedMin, func(t *testing.T) {
+			probes, err := tlsProbes(tc.expectedMin)
+			if len(tc.expectedError) > 0 {
+				if err == nil || err.Error() != tc.expectedError {
+					t.Errorf("expected the error %q, got %v", tc.expectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.expected, probes, cmp.AllowUnexported(tlsProbe{})); len(diff) > 0 {
+				t.Errorf("unexpected probes (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCheckTLSProbes(t *testing.T) {
+	probes, err := tlsProbes("VersionTLS13")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name          string
+		logs          string
+		expectedError string
+	}{
+		{
+			name: "Modern profile in effect",
+			logs: "VersionTLS10 35\nVersionTLS11 35\nVersionTLS12 35\nVersionTLS13 0\n",
+		},
+		{
+			name:          "TLS 1.2 still accepted",
+			logs:          "VersionTLS10 35\nVersionTLS11 35\nVersionTLS12 0\nVersionTLS13 0\n",
+			expectedError: "VersionTLS12 was accepted",
+		},
+		{
+			name:          "operand not reachable",
+			logs:          "VersionTLS10 7\nVersionTLS11 7\nVersionTLS12 7\nVersionTLS13 7\n",
+			expectedError: "VersionTLS10 failed other than at the handshake, curl exited with 7, VersionTLS11 failed other than at the handshake, curl exited with 7, VersionTLS12 failed other than at the handshake, curl exited with 7, VersionTLS13 was not accepted, curl exited with 7",
+		},
+		{
+			name:          "truncated logs",
+			logs:          "VersionTLS10 35\n",
+			expectedError: "VersionTLS11 was not probed, VersionTLS12 was not probed, VersionTLS13 was not probed",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := checkTLSProbes(probes, []byte(tc.logs))
+			if len(tc.expectedError) == 0 {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tc.expectedError {
+				t.Errorf("expected the error %q, got %v", tc.expectedError, err)
+			}
+		})
+	}
+}
+
+func TestTLSProbePod(t *testing.T) {
+	pod := tlsProbePod("e2e-test", "quay.io/openshift/operator:latest", "https://controller-manager.openshift-controller-manager.svc:443/healthz", []tlsProbe{
+		{version: "VersionTLS12", curl: "1.2"},
+		{version: "VersionTLS13", curl: "1.3", accepted: true},
+	})
+	if pod.Spec.RestartPolicy != corev1.RestartPolicyNever {
+		t.Errorf("expected the pod not to be restarted, got %s", pod.Spec.RestartPolicy)
+	}
+	container := pod.Spec.Containers[0]
+	command := strings.Join(append(container.Command, container.Args...), " ")
+	for _, expected := range []string{
+		"--cacert " + serviceCAFile,
+		`--tlsv1.2 --tls-max 1.2 https://controller-manager.openshift-controller-manager.svc:443/healthz; echo "VersionTLS12 $?"`,
+		`--tlsv1.3 --tls-max 1.3 https://controller-manager.openshift-controller-manager.svc:443/healthz; echo "VersionTLS13 $?"`,
+	} {
+		if !strings.Contains(command, expected) {
+			t.Errorf("expected the command to contain %q, got %q", expected, command)
+		}
+	}
+}
+
+func TestOperandServingURL(t *testing.T) {
+	client := fake.NewSimpleClientset(&corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "controller-manager", Namespace: "openshift-controller-manager"},
+		Spec: corev1.ServiceSpec{Ports: []corev1.ServicePort{
+			{Name: "metrics", Port: 9090},
+			{Name: "https", Port: 443},
+		}},
+	}).CoreV1()
+
+	url, err := operandServingURL(context.TODO(), client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := "https://controller-manager.openshift-controller-manager.svc:443/healthz"; url != expected {
+		t.Errorf("expected %q, got %q", expected, url)
+	}
+
+	if _, err := operandServingURL(context.TODO(), fake.NewSimpleClientset().CoreV1()); err == nil || !strings.Contains(err.Error(), "service/controller-manager -n openshift-controller-manager") {
+		t.Errorf("expected an error naming the service, got %v", err)
+	}
+}
@@ -0,0 +1,195 @@
+package framework
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientappsv1 "k8s.io/client-go/kubernetes/typed/apps/v1"
+	"k8s.io/client-go/util/retry"
+
+	configv1 "github.com/openshift/api/config/v1"
+	clientconfigv1 "github.com/openshift/client-go/config/clientset/versioned/typed/config/v1"
+
+	"github.com/openshift/cluster-openshift-controller-manager-operator/pkg/util"
+)
+
+const (
+	// operatorScalePollInterval is how often WithOperatorScaledDown checks the operator deployment.
+	operatorScalePollInterval = 5 * time.Second
+	// operatorScaleTimeout is how long WithOperatorScaledDown waits for the operator to scale down or
+	// to be ready again.
+	operatorScaleTimeout = 5 * time.Minute
+)
+
+type operatorScaleClient interface {
+	clientappsv1.DeploymentsGetter
+	clientconfigv1.ClusterVersionsGetter
+}
+
+// WithOperatorScaledDown is a DISRUPTIVE helper for [Disruptive] or [Serial] suites only. It scales the
+// operator deployment to zero and waits for its pods to be gone, so that a test can watch the operand
+// while nothing reconciles it. The cluster version operator is told to leave the operator deployment
+// alone until then, it would scale it up again otherwise.
+//
+// The returned restore function scales the operator back to its original replicas, hands the deployment
+// back to the cluster version operator and waits for the operator to be ready. It is also registered as
+// a cleanup, which runs when the test fails or panics, so the cluster is never left without the
+// operator; calling it earlier is fine, it only restores once.
+func WithOperatorScaledDown(ctx context.Context, t testing.TB, client *Clientset) func() {
+	t.Helper()
+	restore, err := scaleOperatorDown(ctx, t, client, operatorScalePollInterval, operatorScaleTimeout)
+	var once sync.Once
+	restoreOnce := func() {
+		once.Do(func() {
+			if restore == nil || CleanupSkipped(t, "scale deployment/%s -n %s back up and hand it back to the cluster version operator", operatorDeploymentName, util.OperatorNamespace) {
+				return
+			}
+			// the test context may be done by the time the cleanup runs, restoring must not be skipped
+			if err := restore(context.WithoutCancel(ctx)); err != nil {
+				t.Errorf("failed to restore the operator: %v", err)
+			}
+		})
+	}
+	t.Cleanup(restoreOnce)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return restoreOnce
+}
+
+// scaleOperatorDown scales the operator deployment to zero and waits for its pods to be gone. The restore
+// function it returns is nil when nothing was changed, it is returned along with an error once the
+// cluster was changed so that the change is reverted either way.
+func scaleOperatorDown(ctx context.Context, logger Logger, client operatorScaleClient, interval, timeout time.Duration) (func(ctx context.Context) error, error) {
+	addedOverride, err := setOperatorUnmanaged(ctx, client, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hand deployment/%s -n %s over from the cluster version operator: %w", operatorDeploymentName, util.OperatorNamespace, err)
+	}
+	var originalReplicas int32
+	restore := func(ctx context.Context) error {
+		if err := setOperatorReplicas(ctx, client, originalReplicas); err != nil {
+			return err
+		}
+		logger.Logf("scaled deployment/%s -n %s back to %d replicas", operatorDeploymentName, util.OperatorNamespace, originalReplicas)
+		if addedOverride {
+			if _, err := setOperatorUnmanaged(ctx, client, false); err != nil {
+				return fmt.Errorf("failed to hand deployment/%s -n %s back to the cluster version operator: %w", operatorDeploymentName, util.OperatorNamespace, err)
+			}
+		}
+		return waitForOperatorReplicas(ctx, client, originalReplicas, interval, timeout)
+	}
+	restoreOverride := func(ctx context.Context) error {
+		if !addedOverride {
+			return nil
+		}
+		_, err := setOperatorUnmanaged(ctx, client, false)
+		return err
+	}
+
+	deployment, err := client.Deployments(util.OperatorNamespace).Get(ctx, operatorDeploymentName, metav1.GetOptions{})
+	if err != nil {
+		return restoreOverride, fmt.Errorf("failed to get deployment/%s -n %s: %w", operatorDeploymentName, util.OperatorNamespace, err)
+	}
+	originalReplicas = 1
+	if deployment.Spec.Replicas != nil {
+		originalReplicas = *deployment.Spec.Replicas
+	}
+	if err := setOperatorReplicas(ctx, client, 0); err != nil {
+		return restoreOverride, err
+	}
+	logger.Logf("scaled deployment/%s -n %s down from %d replicas", operatorDeploymentName, util.OperatorNamespace, originalReplicas)
+	if err := waitForOperatorReplicas(ctx, client, 0, interval, timeout); err != nil {
+		return restore, err
+	}
+	return restore, nil
+}
+
+func setOperatorReplicas(ctx context.Context, client clientappsv1.DeploymentsGetter, replicas int32) error {
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		deployment, err := client.Deployments(util.OperatorNamespace).Get(ctx, operatorDeploymentName, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		deployment.Spec.Replicas = &replicas
+		_, err = client.Deployments(util.OperatorNamespace).Update(ctx, deployment, metav1.UpdateOptions{})
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to scale deployment/%s -n %s to %d replicas: %w", operatorDeploymentName, util.OperatorNamespace, replicas, err)
+	}
+	return nil
+}
+
+// waitForOperatorReplicas waits for the operator deployment to run exactly the given replicas, all of
+// them available.
+func waitForOperatorReplicas(ctx context.Context, client clientappsv1.DeploymentsGetter, replicas int32, interval, timeout time.Duration) error {
+	var lastErr error
+	err := poll(ctx, interval, timeout, func(ctx context.Context) (bool, error) {
+		deployment, err := client.Deployments(util.OperatorNamespace).Get(ctx, operatorDeploymentName, metav1.GetOptions{})
+		if err != nil {
+			lastErr = err
+			return false, nil
+		}
+		status := deployment.Status
+		lastErr = fmt.Errorf("has replicas=%d available=%d", status.Replicas, status.AvailableReplicas)
+		return status.Replicas == replicas && status.AvailableReplicas == replicas, nil
+	})
+	if err != nil {
+		return fmt.Errorf("deployment/%s -n %s did not get to %d replicas, last error: %v: %w", operatorDeploymentName, util.OperatorNamespace, replicas, lastErr, err)
+	}
+	return nil
+}
+
+// setOperatorUnmanaged adds, or removes, the override which stops the cluster version operator from
+// managing the operator deployment. It returns whether the overrides were changed, an override which
+// existed before is neither added nor removed.
+func setOperatorUnmanaged(ctx context.Context, client clientconfigv1.ClusterVersionsGetter, unmanaged bool) (bool, error) {
+	return setComponentUnmanaged(ctx, client, configv1.ComponentOverride{
+		Kind:      "Deployment",
+		Group:     "apps",
+		Namespace: util.OperatorNamespace,
+		Name:      operatorDeploymentName,
+		Unmanaged: true,
+	}, unmanaged)
+}
+
+// setComponentUnmanaged adds, or removes, the given override of the cluster version operator like
+// setOperatorUnmanaged.
+func setComponentUnmanaged(ctx context.Context, client clientconfigv1.ClusterVersionsGetter, override configv1.ComponentOverride, unmanaged bool) (bool, error) {
+	changed := false
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		clusterVersion, err := client.ClusterVersions().Get(ctx, "version", metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		var overrides []configv1.ComponentOverride
+		found := false
+		for _, existing := range clusterVersion.Spec.Overrides {
+			if existing == override {
+				found = true
+				if !unmanaged {
+					continue
+				}
+			}
+			overrides = append(overrides, existing)
+		}
+		if found == unmanaged {
+			changed = false
+			return nil
+		}
+		if unmanaged {
+			overrides = append(overrides, override)
+		}
+		clusterVersion.Spec.Overrides = overrides
+		if _, err := client.ClusterVersions().Update(ctx, clusterVersion, metav1.UpdateOptions{}); err != nil {
+			return err
+		}
+		changed = true
+		return nil
+	})
+	return changed, err
+}
@@ -0,0 +1,180 @@
+package framework
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+	"k8s.io/utils/ptr"
+
+	configv1 "github.com/openshift/api/config/v1"
+	configfake "github.com/openshift/client-go/config/clientset/versioned/fake"
+)
+
+var otherOverride = configv1.ComponentOverride{Kind: "Deployment", Group: "apps", Namespace: "other", Name: "other", Unmanaged: true}
+
+// newOperatorScaleClient returns a client with the operator deployment, whose pods follow its replicas
+// right away.
+func newOperatorScaleClient(t *testing.T, deployment *appsv1.Deployment, overrides ...configv1.ComponentOverride) (*Clientset, *fake.Clientset, *configfake.Clientset) {
+	t.Helper()
+	var kubeObjects []runtime.Object
+	if deployment != nil {
+		kubeObjects = append(kubeObjects, deployment)
+	}
+	kubeClient := fake.NewSimpleClientset(kubeObjects...)
+	kubeClient.PrependReactor("update", "deployments", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		updated := action.(clienttesting.UpdateAction).GetObject().(*appsv1.Deployment)
+		updated.Status.Replicas = *updated.Spec.Replicas
+		updated.Status.AvailableReplicas = *updated.Spec.Replicas
+		// fall through to the tracker, which stores the deployment with the status
+		return false, nil, nil
+	})
+	configClient := configfake.NewSimpleClientset(&configv1.ClusterVersion{
+		ObjectMeta: metav1.ObjectMeta{Name: "version"},
+		Spec:       configv1.ClusterVersionSpec{Overrides: overrides},
+	})
+	return &Clientset{AppsV1Interface: kubeClient.AppsV1(), ConfigV1Interface: configClient.ConfigV1()}, kubeClient, configClient
+}
+
+func operatorDeployment(replicas int32) *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "openshift-controller-manager-operator", Namespace: "openshift-controller-manager-operator"},
+		Spec:       appsv1.DeploymentSpec{Replicas: ptr.To(replicas)},
+		Status:     appsv1.DeploymentStatus{Replicas: replicas, AvailableReplicas: replicas},
+	}
+}
+
+func operatorReplicas(t *testing.T, client *Clientset) int32 {
+	t.Helper()
+	deployment, err := client.Deployments("openshift-controller-manager-operator").Get(context.TODO(), "openshift-controller-manager-operator", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return *deployment.Spec.Replicas
+}
+
+func clusterVersionOverrides(t *testing.T, client *Clientset) []configv1.ComponentOverride {
+	t.Helper()
+	clusterVersion, err := client.ClusterVersions().Get(context.TODO(), "version", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return clusterVersion.Spec.Overrides
+}
+
+func TestScaleOperatorDown(t *testing.T) {
+	operatorOverride := configv1.ComponentOverride{
+		Kind: "Deployment", Group: "apps", Namespace: "openshift-controller-manager-operator", Name: "openshift-controller-manager-operator", Unmanaged: true,
+	}
+
+	t.Run("scales down and restores", func(t *testing.T) {
+		client, _, _ := newOperatorScaleClient(t, operatorDeployment(2), otherOverride)
+		restore, err := scaleOperatorDown(context.TODO(), t, client, time.Millisecond, time.Second)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if replicas := operatorReplicas(t, client); replicas != 0 {
+			t.Errorf("expected the operator to be scaled to zero, got %d replicas", replicas)
+		}
+		if overrides := clusterVersionOverrides(t, client); len(overrides) != 2 || overrides[1] != operatorOverride {
+			t.Errorf("expected the operator deployment to be unmanaged by the cluster version operator, got %v", overrides)
+		}
+
+		if err := restore(context.TODO()); err != nil {
+			t.Fatal(err)
+		}
+		if replicas := operatorReplicas(t, client); replicas != 2 {
+			t.Errorf("expected the operator to be scaled back to 2 replicas, got %d", replicas)
+		}
+		if overrides := clusterVersionOverrides(t, client); len(overrides) != 1 || overrides[0] != otherOverride {
+			t.Errorf("expected only the other override to be left, got %v", overrides)
+		}
+	})
+
+	t.Run("keeps an existing override", func(t *testing.T) {
+		client, _, _ := newOperatorScaleClient(t, operatorDeployment(1), operatorOverride)
+		restore, err := scaleOperatorDown(context.TODO(), t, client, time.Millisecond, time.Second)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := restore(context.TODO()); err != nil {
+			t.Fatal(err)
+		}
+		if overrides := clusterVersionOverrides(t, client); len(overrides) != 1 || overrides[0] != operatorOverride {
+			t.Errorf("expected the existing override to be kept, got %v", overrides)
+		}
+	})
+
+	t.Run("missing operator deployment", func(t *testing.T) {
+		client, _, _ := newOperatorScaleClient(t, nil, otherOverride)
+		restore, err := scaleOperatorDown(context.TODO(), t, client, time.Millisecond, time.Second)
+		if err == nil || !strings.Contains(err.Error(), "failed to get deployment/openshift-controller-manager-operator") {
+			t.Fatalf("expected an error getting the operator deployment, got %v", err)
+		}
+		if restore == nil {
+			t.Fatal("expected a restore function for the added override")
+		}
+		if err := restore(context.TODO()); err != nil {
+			t.Fatal(err)
+		}
+		if overrides := clusterVersionOverrides(t, client); len(overrides) != 1 || overrides[0] != otherOverride {
+			t.Errorf("expected the added override to be removed, got %v", overrides)
+		}
+	})
+
+	t.Run("operator does not scale down", func(t *testing.T) {
+		client, kubeClient, _ := newOperatorScaleClient(t, operatorDeployment(1))
+		// the pods of the operator never go away
+		kubeClient.PrependReactor("get", "deployments", func(clienttesting.Action) (bool, runtime.Object, error) {
+			return true, operatorDeployment(1), nil
+		})
+		restore, err := scaleOperatorDown(context.TODO(), t, client, time.Millisecond, 50*time.Millisecond)
+		if err == nil || !strings.Contains(err.Error(), "did not get to 0 replicas") {
+			t.Fatalf("expected a timeout scaling down, got %v", err)
+		}
+		if restore == nil {
+			t.Fatal("expected a restore function once the operator was scaled")
+		}
+	})
+}
+
+func TestWithOperatorScaledDownRestoresOnCleanup(t *testing.T) {
+	client, _, _ := newOperatorScaleClient(t, operatorDeployment(1))
+	t.Run("disruptive test", func(t *testing.T) {
+		WithOperatorScaledDown(context.TODO(), t, client)
+		if replicas := operatorReplicas(t, client); replicas != 0 {
+			t.Errorf("expected the operator to be scaled to zero, got %d replicas", replicas)
+		}
+		// the test ends without restoring the operator
+	})
+	if replicas := operatorReplicas(t, client); replicas != 1 {
+		t.Errorf("expected the cleanup to restore the operator to 1 replica, got %d", replicas)
+	}
+	if overrides := clusterVersionOverrides(t, client); len(overrides) != 0 {
+		t.Errorf("expected the override to be removed, got %v", overrides)
+	}
+}
+
+func TestWithOperatorScaledDownRestoresOnce(t *testing.T) {
+	client, kubeClient, _ := newOperatorScaleClient(t, operatorDeployment(1))
+	t.Run("disruptive test", func(t *testing.T) {
+		restore := WithOperatorScaledDown(context.TODO(), t, client)
+		restore()
+		kubeClient.ClearActions()
+		// the cleanup restores again
+	})
+	for _, action := range kubeClient.Actions() {
+		if action.GetVerb() == "update" {
+			t.Errorf("expected the operator to be restored once, got %v", action)
+		}
+	}
+	if replicas := operatorReplicas(t, client); replicas != 1 {
+		t.Errorf("expected the operator to be restored to 1 replica, got %d", replicas)
+	}
+}
@@ -0,0 +1,54 @@
+package framework
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+// PodSecurityLevel is a level of the Pod Security Standards a namespace admits pods at.
+type PodSecurityLevel string
+
+const (
+	PodSecurityPrivileged PodSecurityLevel = "privileged"
+	PodSecurityBaseline   PodSecurityLevel = "baseline"
+	PodSecurityRestricted PodSecurityLevel = "restricted"
+)
+
+// podSecurityModes are the modes of Pod Security Admission, each set by a namespace label.
+var podSecurityModes = []string{"enforce", "audit", "warn"}
+
+// AssertNamespacePSALabels fails the test unless the namespace enforces, audits and warns at
+// expectedLevel with its pod-security.kubernetes.io labels, listing every label which does not.
+func AssertNamespacePSALabels(ctx context.Context, t testing.TB, client *Clientset, namespace string, expectedLevel PodSecurityLevel) {
+	t.Helper()
+	if err := checkNamespacePSALabels(ctx, client, namespace, expectedLevel); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func checkNamespacePSALabels(ctx context.Context, client clientcorev1.NamespacesGetter, namespace string, expectedLevel PodSecurityLevel) error {
+	ns, err := client.Namespaces().Get(ctx, namespace, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get namespace/%s: %w", namespace, err)
+	}
+	var mismatches []string
+	for _, mode := range podSecurityModes {
+		label := "pod-security.kubernetes.io/" + mode
+		level, ok := ns.Labels[label]
+		switch {
+		case !ok:
+			mismatches = append(mismatches, fmt.Sprintf("%s is missing", label))
+		case PodSecurityLevel(level) != expectedLevel:
+			mismatches = append(mismatches, fmt.Sprintf("%s is %q", label, level))
+		}
+	}
+	if len(mismatches) > 0 {
+		return fmt.Errorf("namespace/%s does not admit pods at the %s pod security level: %s", namespace, expectedLevel, strings.Join(mismatches, ", "))
+	}
+	return nil
+}
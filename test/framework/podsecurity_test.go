@@ -0,0 +1,56 @@
+package framework
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestCheckNamespacePSALabels(t *testing.T) {
+	tests := []struct {
+		name          string
+		labels        map[string]string
+		expectedError string
+	}{
+		{
+			name: "expected level",
+			labels: map[string]string{
+				"pod-security.kubernetes.io/enforce": "privileged",
+				"pod-security.kubernetes.io/audit":   "privileged",
+				"pod-security.kubernetes.io/warn":    "privileged",
+			},
+		},
+		{
+			name: "other level and missing labels",
+			labels: map[string]string{
+				"pod-security.kubernetes.io/enforce": "restricted",
+				"pod-security.kubernetes.io/audit":   "privileged",
+			},
+			expectedError: `namespace/openshift-controller-manager does not admit pods at the privileged pod security level: pod-security.kubernetes.io/enforce is "restricted", pod-security.kubernetes.io/warn is missing`,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			client := fake.NewSimpleClientset(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "openshift-controller-manager", Labels: tc.labels}}).CoreV1()
+			err := checkNamespacePSALabels(context.TODO(), client, "openshift-controller-manager", PodSecurityPrivileged)
+			if len(tc.expectedError) == 0 {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tc.expectedError {
+				t.Errorf("expected the error %q, got %v", tc.expectedError, err)
+			}
+		})
+	}
+
+	err := checkNamespacePSALabels(context.TODO(), fake.NewSimpleClientset().CoreV1(), "openshift-controller-manager", PodSecurityPrivileged)
+	if err == nil || !strings.Contains(err.Error(), "failed to get namespace/openshift-controller-manager") {
+		t.Errorf("expected an error for a missing namespace, got %v", err)
+	}
+}
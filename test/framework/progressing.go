@@ -0,0 +1,71 @@
+package framework
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	configv1 "github.com/openshift/api/config/v1"
+	clientconfigv1 "github.com/openshift/client-go/config/clientset/versioned/typed/config/v1"
+	"github.com/openshift/library-go/pkg/config/clusteroperator/v1helpers"
+)
+
+// progressingPollInterval is how often AssertProgressingClearsWithin checks the ClusterOperator.
+const progressingPollInterval = 10 * time.Second
+
+// AssertProgressingClearsWithin fails the test unless the operator reports Progressing=False within the
+// timeout, which is meant as a hard ceiling on how long reconciling a known change may take. Every new
+// reason or message of the Progressing condition is logged while waiting, and the failure names the
+// reason the operator got stuck with, so that a stuck rollout can be told from a slow one from the test
+// output alone.
+func AssertProgressingClearsWithin(ctx context.Context, t testing.TB, client *Clientset, timeout time.Duration) {
+	t.Helper()
+	if err := waitForProgressingCleared(ctx, t, client, progressingPollInterval, timeout); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func waitForProgressingCleared(ctx context.Context, logger Logger, client clientconfigv1.ClusterOperatorsGetter, interval, timeout time.Duration) error {
+	start := time.Now()
+	var last *configv1.ClusterOperatorStatusCondition
+	// reasons are the distinct reasons the operator progressed with, in the order they were seen
+	var reasons []string
+	err := poll(ctx, interval, timeout, func(ctx context.Context) (bool, error) {
+		co, err := client.ClusterOperators().Get(ctx, clusterOperatorName, metav1.GetOptions{})
+		if err != nil {
+			logger.Logf("error getting clusteroperator/%s: %v", clusterOperatorName, err)
+			return false, nil
+		}
+		progressing := v1helpers.FindStatusCondition(co.Status.Conditions, configv1.OperatorProgressing)
+		if progressing == nil {
+			logger.Logf("clusteroperator/%s has no %s condition yet", clusterOperatorName, configv1.OperatorProgressing)
+			return false, nil
+		}
+		if progressing.Status == configv1.ConditionFalse {
+			logger.Logf("clusteroperator/%s stopped progressing after %s", clusterOperatorName, time.Since(start).Round(time.Second))
+			return true, nil
+		}
+		if last == nil || last.Status != progressing.Status || last.Reason != progressing.Reason || last.Message != progressing.Message {
+			logger.Logf("clusteroperator/%s is %s=%s after %s: %s: %s", clusterOperatorName, progressing.Type, progressing.Status,
+				time.Since(start).Round(time.Second), progressing.Reason, progressing.Message)
+		}
+		if len(progressing.Reason) > 0 && (len(reasons) == 0 || reasons[len(reasons)-1] != progressing.Reason) {
+			reasons = append(reasons, progressing.Reason)
+		}
+		last = progressing.DeepCopy()
+		return false, nil
+	})
+	if err == nil {
+		return nil
+	}
+	if last == nil {
+		return fmt.Errorf("clusteroperator/%s did not report %s within %s: %w", clusterOperatorName, configv1.OperatorProgressing, timeout, err)
+	}
+	return fmt.Errorf("clusteroperator/%s is still %s=%s after %s, stuck with %s: %s (since %s, reasons seen: %s): %w",
+		clusterOperatorName, last.Type, last.Status, timeout, last.Reason, last.Message,
+		last.LastTransitionTime.UTC().Format(time.RFC3339), strings.Join(reasons, ", "), err)
+}
@@ -0,0 +1,106 @@
+package framework
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clienttesting "k8s.io/client-go/testing"
+
+	configv1 "github.com/openshift/api/config/v1"
+	configfake "github.com/openshift/client-go/config/clientset/versioned/fake"
+)
+
+// recordingLogger keeps the lines logged.
+type recordingLogger struct {
+	lines []string
+}
+
+func (l *recordingLogger) Logf(format string, args ...interface{}) {
+	l.lines = append(l.lines, fmt.Sprintf(format, args...))
+}
+
+func clusterOperatorProgressing(status configv1.ConditionStatus, reason, message string) *configv1.ClusterOperator {
+	return &configv1.ClusterOperator{
+		ObjectMeta: metav1.ObjectMeta{Name: "openshift-controller-manager"},
+		Status: configv1.ClusterOperatorStatus{Conditions: []configv1.ClusterOperatorStatusCondition{
+			{Type: configv1.OperatorProgressing, Status: status, Reason: reason, Message: message},
+		}},
+	}
+}
+
+func TestWaitForProgressingCleared(t *testing.T) {
+	rollingOut := clusterOperatorProgressing(configv1.ConditionTrue, "RolloutInProgress", "deployment/controller-manager: 1 of 3 pods updated")
+	podsNotReady := clusterOperatorProgressing(configv1.ConditionTrue, "PodsNotReady", "deployment/controller-manager: 2 of 3 pods ready")
+	done := clusterOperatorProgressing(configv1.ConditionFalse, "AsExpected", "")
+
+	tests := []struct {
+		name string
+		// states are what the gets return in turn, the last one sticks
+		states       []*configv1.ClusterOperator
+		expectedErr  []string
+		expectedLogs []string
+	}{
+		{
+			name:   "not progressing",
+			states: []*configv1.ClusterOperator{done},
+		},
+		{
+			name:         "clears after progressing",
+			states:       []*configv1.ClusterOperator{rollingOut, rollingOut, podsNotReady, done},
+			expectedLogs: []string{"RolloutInProgress: deployment/controller-manager: 1 of 3 pods updated", "PodsNotReady: deployment/controller-manager: 2 of 3 pods ready", "stopped progressing"},
+		},
+		{
+			name:   "stuck",
+			states: []*configv1.ClusterOperator{rollingOut, podsNotReady},
+			expectedErr: []string{
+				"still Progressing=True after 50ms, stuck with PodsNotReady: deployment/controller-manager: 2 of 3 pods ready",
+				"reasons seen: RolloutInProgress, PodsNotReady",
+			},
+		},
+		{
+			name:        "no condition",
+			states:      []*configv1.ClusterOperator{{ObjectMeta: metav1.ObjectMeta{Name: "openshift-controller-manager"}}},
+			expectedErr: []string{"did not report Progressing within 50ms"},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			client := configfake.NewSimpleClientset()
+			gets := 0
+			client.PrependReactor("get", "clusteroperators", func(clienttesting.Action) (bool, runtime.Object, error) {
+				state := tc.states[min(gets, len(tc.states)-1)]
+				gets++
+				return true, state.DeepCopy(), nil
+			})
+			logger := &recordingLogger{}
+
+			err := waitForProgressingCleared(context.TODO(), logger, client.ConfigV1(), time.Millisecond, 50*time.Millisecond)
+			logs := strings.Join(logger.lines, "\n")
+			if len(tc.expectedErr) == 0 && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(tc.expectedErr) > 0 && err == nil {
+				t.Fatal("expected an error")
+			}
+			for _, expected := range tc.expectedErr {
+				if !strings.Contains(err.Error(), expected) {
+					t.Errorf("expected the error to contain %q, got %q", expected, err)
+				}
+			}
+			for _, expected := range tc.expectedLogs {
+				if !strings.Contains(logs, expected) {
+					t.Errorf("expected the logs to contain %q, got:\n%s", expected, logs)
+				}
+			}
+			// an unchanged condition is logged once
+			if count := strings.Count(logs, "RolloutInProgress"); count > 1 {
+				t.Errorf("expected an unchanged reason to be logged once, got it %d times:\n%s", count, logs)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package framework
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	configv1 "github.com/openshift/api/config/v1"
+	clientconfigv1 "github.com/openshift/client-go/config/clientset/versioned/typed/config/v1"
+)
+
+// AssertRelatedObjects fails the test unless the ClusterOperator lists related objects and every expected
+// one is among them. must-gather collects the related objects, one missing from them is missing from the
+// gathered data.
+func AssertRelatedObjects(ctx context.Context, t testing.TB, client *Clientset, expected ...configv1.ObjectReference) {
+	t.Helper()
+	if err := checkRelatedObjects(ctx, client, expected); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func checkRelatedObjects(ctx context.Context, client clientconfigv1.ClusterOperatorsGetter, expected []configv1.ObjectReference) error {
+	clusterOperator, err := client.ClusterOperators().Get(ctx, clusterOperatorName, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("unable to get clusteroperator/%s: %w", clusterOperatorName, err)
+	}
+	relatedObjects := clusterOperator.Status.RelatedObjects
+	if len(relatedObjects) == 0 {
+		return fmt.Errorf("clusteroperator/%s has no related objects", clusterOperatorName)
+	}
+
+	listed := map[configv1.ObjectReference]bool{}
+	for _, relatedObject := range relatedObjects {
+		listed[relatedObject] = true
+	}
+	var missing []string
+	for _, object := range expected {
+		if !listed[object] {
+			missing = append(missing, relatedObjectString(object))
+		}
+	}
+	if len(missing) > 0 {
+		var all []string
+		for _, relatedObject := range relatedObjects {
+			all = append(all, relatedObjectString(relatedObject))
+		}
+		return fmt.Errorf("clusteroperator/%s does not list the related objects %s, it lists %s",
+			clusterOperatorName, strings.Join(missing, ", "), strings.Join(all, ", "))
+	}
+	return nil
+}
+
+// relatedObjectString returns the related object as resource.group/name -n namespace.
+func relatedObjectString(object configv1.ObjectReference) string {
+	s := object.Resource
+	if len(object.Group) > 0 {
+		s += "." + object.Group
+	}
+	s += "/" + object.Name
+	if len(object.Namespace) > 0 {
+		s += " -n " + object.Namespace
+	}
+	return s
+}
@@ -0,0 +1,63 @@
+package framework
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	configv1 "github.com/openshift/api/config/v1"
+	configfake "github.com/openshift/client-go/config/clientset/versioned/fake"
+)
+
+func TestCheckRelatedObjects(t *testing.T) {
+	operatorConfig := configv1.ObjectReference{Group: "operator.openshift.io", Resource: "openshiftcontrollermanagers", Name: "cluster"}
+	operandNamespace := configv1.ObjectReference{Resource: "namespaces", Name: "openshift-controller-manager"}
+	tests := []struct {
+		name           string
+		relatedObjects []configv1.ObjectReference
+		expectedErrors []string
+	}{
+		{
+			name:           "expected objects listed",
+			relatedObjects: []configv1.ObjectReference{operatorConfig, {Resource: "namespaces", Name: "openshift-config"}, operandNamespace},
+		},
+		{
+			name:           "no related objects",
+			expectedErrors: []string{"clusteroperator/openshift-controller-manager has no related objects"},
+		},
+		{
+			name:           "operand namespace missing",
+			relatedObjects: []configv1.ObjectReference{operatorConfig},
+			expectedErrors: []string{
+				"does not list the related objects namespaces/openshift-controller-manager",
+				"it lists openshiftcontrollermanagers.operator.openshift.io/cluster",
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			client := configfake.NewSimpleClientset(&configv1.ClusterOperator{
+				ObjectMeta: metav1.ObjectMeta{Name: "openshift-controller-manager"},
+				Status:     configv1.ClusterOperatorStatus{RelatedObjects: tc.relatedObjects},
+			})
+
+			err := checkRelatedObjects(context.TODO(), client.ConfigV1(), []configv1.ObjectReference{operatorConfig, operandNamespace})
+			if len(tc.expectedErrors) == 0 {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			for _, expected := range tc.expectedErrors {
+				if !strings.Contains(err.Error(), expected) {
+					t.Errorf("expected the error to contain %q, got %v", expected, err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package framework
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	clientcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+// serviceMonitorsResource has no typed client in the clientset, ServiceMonitors are read dynamically.
+var serviceMonitorsResource = schema.GroupVersionResource{Group: "monitoring.coreos.com", Version: "v1", Resource: "servicemonitors"}
+
+type serviceMonitorClient interface {
+	dynamic.Interface
+	clientcorev1.ServicesGetter
+}
+
+// AssertServiceMonitor fails the test unless the named ServiceMonitor exists and each of its endpoints
+// scrapes a service it selects the way the metrics are served: over https, from a port the service
+// names, verifying the serving certificate of the service. A ServiceMonitor which no longer matches its
+// service is not an error anywhere, the metrics are just silently missing from Prometheus.
+func AssertServiceMonitor(ctx context.Context, t testing.TB, client *Clientset, namespace, name string) {
+	t.Helper()
+	if err := checkServiceMonitor(ctx, client, namespace, name); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func checkServiceMonitor(ctx context.Context, client serviceMonitorClient, namespace, name string) error {
+	serviceMonitor, err := client.Resource(serviceMonitorsResource).Namespace(namespace).Get(ctx, name, metav1.GetOptions{})
+	if errors.IsNotFound(err) {
+		return fmt.Errorf("servicemonitor/%s -n %s does not exist", name, namespace)
+	}
+	if err != nil {
+		return fmt.Errorf("unable to get servicemonitor/%s -n %s: %w", name, namespace, err)
+	}
+
+	matchLabels, _, err := unstructured.NestedStringMap(serviceMonitor.Object, "spec", "selector", "matchLabels")
+	if err != nil {
+		return fmt.Errorf("servicemonitor/%s -n %s has an invalid selector: %w", name, namespace, err)
+	}
+	if len(matchLabels) == 0 {
+		return fmt.Errorf("servicemonitor/%s -n %s selects no services", name, namespace)
+	}
+	selector := labels.SelectorFromSet(matchLabels)
+	services, err := client.Services(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return fmt.Errorf("unable to list the services of servicemonitor/%s -n %s: %w", name, namespace, err)
+	}
+	if len(services.Items) == 0 {
+		return fmt.Errorf("servicemonitor/%s -n %s selects no services with %s", name, namespace, selector)
+	}
+	ports := map[string]bool{}
+	serverNames := map[string]bool{}
+	for _, service := range services.Items {
+		for _, port := range service.Spec.Ports {
+			ports[port.Name] = true
+		}
+		serverNames[fmt.Sprintf("%s.%s.svc", service.Name, service.Namespace)] = true
+	}
+
+	endpoints, _, err := unstructured.NestedSlice(serviceMonitor.Object, "spec", "endpoints")
+	if err != nil {
+		return fmt.Errorf("servicemonitor/%s -n %s has invalid endpoints: %w", name, namespace, err)
+	}
+	if len(endpoints) == 0 {
+		return fmt.Errorf("servicemonitor/%s -n %s has no endpoints", name, namespace)
+	}
+	var problems []string
+	for i, endpoint := range endpoints {
+		endpoint, ok := endpoint.(map[string]interface{})
+		if !ok {
+			problems = append(problems, fmt.Sprintf("endpoint %d is not an object", i))
+			continue
+		}
+		port, _, _ := unstructured.NestedString(endpoint, "port")
+		if !ports[port] {
+			problems = append(problems, fmt.Sprintf("endpoint %d scrapes the port %q no selected service names", i, port))
+		}
+		if scheme, _, _ := unstructured.NestedString(endpoint, "scheme"); scheme != "https" {
+			problems = append(problems, fmt.Sprintf("endpoint %d scrapes with the scheme %q instead of https", i, scheme))
+		}
+		if serverName, _, _ := unstructured.NestedString(endpoint, "tlsConfig", "serverName"); !serverNames[serverName] {
+			problems = append(problems, fmt.Sprintf("endpoint %d verifies the server name %q of no selected service", i, serverName))
+		}
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("servicemonitor/%s -n %s does not match the services it selects with %s: %s", name, namespace, selector, strings.Join(problems, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package framework
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/dynamic"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	clientcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+type fakeServiceMonitorClient struct {
+	dynamic.Interface
+	clientcorev1.CoreV1Interface
+}
+
+func TestCheckServiceMonitor(t *testing.T) {
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "operand", Name: "controller-manager", Labels: map[string]string{"prometheus": "operand"}},
+		Spec:       corev1.ServiceSpec{Ports: []corev1.ServicePort{{Name: "https", Port: 443}}},
+	}
+	serviceMonitor := func(matchLabels map[string]interface{}, endpoints ...interface{}) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "monitoring.coreos.com/v1",
+			"kind":       "ServiceMonitor",
+			"metadata":   map[string]interface{}{"namespace": "operand", "name": "operand"},
+			"spec": map[string]interface{}{
+				"selector":  map[string]interface{}{"matchLabels": matchLabels},
+				"endpoints": endpoints,
+			},
+		}}
+	}
+	selectingOperand := map[string]interface{}{"prometheus": "operand"}
+	endpoint := func(port, scheme, serverName string) map[string]interface{} {
+		return map[string]interface{}{"port": port, "scheme": scheme, "tlsConfig": map[string]interface{}{"serverName": serverName}}
+	}
+
+	tests := []struct {
+		name           string
+		serviceMonitor *unstructured.Unstructured
+		expectedErr    string
+	}{
+		{
+			name:           "matching the service",
+			serviceMonitor: serviceMonitor(selectingOperand, endpoint("https", "https", "controller-manager.operand.svc")),
+		},
+		{
+			name:        "missing",
+			expectedErr: "servicemonitor/operand -n operand does not exist",
+		},
+		{
+			name:           "selecting no services",
+			serviceMonitor: serviceMonitor(map[string]interface{}{"prometheus": "renamed"}, endpoint("https", "https", "controller-manager.operand.svc")),
+			expectedErr:    "selects no services with prometheus=renamed",
+		},
+		{
+			name:           "without endpoints",
+			serviceMonitor: serviceMonitor(selectingOperand),
+			expectedErr:    "has no endpoints",
+		},
+		{
+			name:           "scraping an unknown port",
+			serviceMonitor: serviceMonitor(selectingOperand, endpoint("metrics", "https", "controller-manager.operand.svc")),
+			expectedErr:    `endpoint 0 scrapes the port "metrics" no selected service names`,
+		},
+		{
+			name:           "scraping over http",
+			serviceMonitor: serviceMonitor(selectingOperand, endpoint("https", "http", "controller-manager.operand.svc")),
+			expectedErr:    `endpoint 0 scrapes with the scheme "http" instead of https`,
+		},
+		{
+			name:           "verifying another server name",
+			serviceMonitor: serviceMonitor(selectingOperand, endpoint("https", "https", "metrics.operand.svc")),
+			expectedErr:    `endpoint 0 verifies the server name "metrics.operand.svc" of no selected service`,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var objects []runtime.Object
+			if tc.serviceMonitor != nil {
+				objects = append(objects, tc.serviceMonitor)
+			}
+			client := fakeServiceMonitorClient{
+				Interface:       dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), objects...),
+				CoreV1Interface: fake.NewSimpleClientset(service).CoreV1(),
+			}
+
+			err := checkServiceMonitor(context.Background(), client, "operand", "operand")
+			if len(tc.expectedErr) == 0 {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.expectedErr) {
+				t.Errorf("expected an error containing %q, got %v", tc.expectedErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package framework
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/spf13/pflag"
+)
+
+// skipCleanupEnv sets the default of --skip-cleanup, the test binary is run once per spec by
+// openshift-tests, which passes no flags.
+const skipCleanupEnv = "OCM_E2E_SKIP_CLEANUP"
+
+// SkipCleanup makes the cleanups of the tests log what they would revert instead of reverting it, so
+// that the cluster is left as a failed test left it for debugging. The cluster is dirty afterwards, the
+// changed cluster config, the scaled down operator and the test namespaces have to be reverted by hand.
+var SkipCleanup = skipCleanupFromEnv(os.LookupEnv)
+
+func skipCleanupFromEnv(lookupEnv func(string) (string, bool)) bool {
+	value, ok := lookupEnv(skipCleanupEnv)
+	if !ok {
+		return false
+	}
+	skip, err := strconv.ParseBool(value)
+	return err == nil && skip
+}
+
+// AddSkipCleanupFlag registers a --skip-cleanup flag setting SkipCleanup.
+func AddSkipCleanupFlag(fs *pflag.FlagSet) {
+	fs.BoolVar(&SkipCleanup, "skip-cleanup", SkipCleanup, fmt.Sprintf("Log what the cleanups of the tests would revert instead of reverting it, for debugging a failed test on the cluster it left behind. The cluster is left dirty. Defaults to the %s environment variable.", skipCleanupEnv))
+}
+
+// SkipCleanupWarning is logged at the start of a run with SkipCleanup set.
+const SkipCleanupWarning = "!!! --skip-cleanup is set: the tests will NOT revert their changes to the cluster, it is left dirty and has to be cleaned up by hand !!!"
+
+// CleanupSkipped returns whether SkipCleanup is set, after logging the cleanup it skips, which reads
+// "would <what>", e.g. CleanupSkipped(t, "delete the test namespace %s", name). A cleanup returns right
+// away when it returns true.
+func CleanupSkipped(logger Logger, format string, args ...interface{}) bool {
+	if !SkipCleanup {
+		return false
+	}
+	logger.Logf("skipping cleanup as --skip-cleanup is set, it would %s", fmt.Sprintf(format, args...))
+	return true
+}
@@ -0,0 +1,65 @@
+package framework
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSkipCleanupFromEnv(t *testing.T) {
+	tests := []struct {
+		name     string
+		env      map[string]string
+		expected bool
+	}{
+		{name: "unset"},
+		{name: "true", env: map[string]string{skipCleanupEnv: "true"}, expected: true},
+		{name: "false", env: map[string]string{skipCleanupEnv: "false"}},
+		{name: "invalid", env: map[string]string{skipCleanupEnv: "yes please"}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			lookupEnv := func(key string) (string, bool) {
+				value, ok := tc.env[key]
+				return value, ok
+			}
+			if skip := skipCleanupFromEnv(lookupEnv); skip != tc.expected {
+				t.Errorf("expected %t, got %t", tc.expected, skip)
+			}
+		})
+	}
+}
+
+func TestClusterConfigMutationSkipCleanup(t *testing.T) {
+	skipCleanup := SkipCleanup
+	defer func() { SkipCleanup = skipCleanup }()
+	SkipCleanup = true
+
+	config := &fakeConfig{value: "original"}
+	verified := false
+	mutation := config.mutation()
+	mutation.Verify = func(context.Context, string) error {
+		verified = true
+		return nil
+	}
+	logger := &recordingLogger{}
+	restore, err := mutation.Apply(context.Background(), logger, "changed")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := restore(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := []string{"changed"}; !reflect.DeepEqual(config.writes, expected) {
+		t.Errorf("expected the original value not to be restored, got the writes %v", config.writes)
+	}
+	if config.settles != 1 || verified {
+		t.Errorf("expected no settling or verification after the skipped restore, settled %d times, verified %t", config.settles, verified)
+	}
+	expected := "skipping cleanup as --skip-cleanup is set, it would restore the original fake value original"
+	if !strings.Contains(strings.Join(logger.lines, "\n"), expected) {
+		t.Errorf("expected the skipped restore to be logged as %q, got %v", expected, logger.lines)
+	}
+}
@@ -0,0 +1,157 @@
+package framework
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	configv1 "github.com/openshift/api/config/v1"
+	clientconfigv1 "github.com/openshift/client-go/config/clientset/versioned/typed/config/v1"
+)
+
+// OperatorStatusSnapshot is the status of the ClusterOperator at a point in time, reduced to what a change
+// of the cluster config may move: the conditions without their transition times, the versions by name and
+// the related objects. Two snapshots are equal when DiffStatus returns no changes.
+type OperatorStatusSnapshot struct {
+	Conditions map[configv1.ClusterStatusConditionType]OperatorConditionSnapshot
+	Versions   map[string]string
+	// RelatedObjects are sorted, as resource.group/name -n namespace.
+	RelatedObjects []string
+}
+
+// OperatorConditionSnapshot is a ClusterOperator condition without its transition time.
+type OperatorConditionSnapshot struct {
+	Status  configv1.ConditionStatus
+	Reason  string
+	Message string
+}
+
+// StatusChange is a field which differs between two snapshots, by its dotted path like
+// conditions.Progressing.status, versions.operator or relatedObjects. Before is empty for an added field,
+// After for a removed one.
+type StatusChange struct {
+	Path   string
+	Before string
+	After  string
+}
+
+// SnapshotOperatorStatus returns the current status of the ClusterOperator, to be compared with DiffStatus
+// to a snapshot taken later.
+func SnapshotOperatorStatus(ctx context.Context, t testing.TB, client *Clientset) OperatorStatusSnapshot {
+	t.Helper()
+	snapshot, err := snapshotOperatorStatus(ctx, client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return snapshot
+}
+
+func snapshotOperatorStatus(ctx context.Context, client clientconfigv1.ClusterOperatorsGetter) (OperatorStatusSnapshot, error) {
+	clusterOperator, err := client.ClusterOperators().Get(ctx, clusterOperatorName, metav1.GetOptions{})
+	if err != nil {
+		return OperatorStatusSnapshot{}, fmt.Errorf("unable to get clusteroperator/%s: %w", clusterOperatorName, err)
+	}
+	return newOperatorStatusSnapshot(clusterOperator.Status), nil
+}
+
+func newOperatorStatusSnapshot(status configv1.ClusterOperatorStatus) OperatorStatusSnapshot {
+	snapshot := OperatorStatusSnapshot{
+		Conditions: map[configv1.ClusterStatusConditionType]OperatorConditionSnapshot{},
+		Versions:   map[string]string{},
+	}
+	for _, condition := range status.Conditions {
+		snapshot.Conditions[condition.Type] = OperatorConditionSnapshot{Status: condition.Status, Reason: condition.Reason, Message: condition.Message}
+	}
+	for _, version := range status.Versions {
+		snapshot.Versions[version.Name] = version.Version
+	}
+	for _, relatedObject := range status.RelatedObjects {
+		snapshot.RelatedObjects = append(snapshot.RelatedObjects, relatedObjectString(relatedObject))
+	}
+	sort.Strings(snapshot.RelatedObjects)
+	return snapshot
+}
+
+// StatusChanges returns the fields which differ between the snapshots, sorted by path, those of a
+// condition one by one and a related object as a relatedObjects field of its own.
+func StatusChanges(before, after OperatorStatusSnapshot) []StatusChange {
+	beforeFields, afterFields := before.fields(), after.fields()
+	var changes []StatusChange
+	for path, beforeValue := range beforeFields {
+		if afterValue := afterFields[path]; afterValue != beforeValue {
+			changes = append(changes, StatusChange{Path: path, Before: beforeValue, After: afterValue})
+		}
+	}
+	for path, afterValue := range afterFields {
+		if _, ok := beforeFields[path]; !ok {
+			changes = append(changes, StatusChange{Path: path, After: afterValue})
+		}
+	}
+
+	beforeObjects := map[string]bool{}
+	for _, relatedObject := range before.RelatedObjects {
+		beforeObjects[relatedObject] = true
+	}
+	afterObjects := map[string]bool{}
+	for _, relatedObject := range after.RelatedObjects {
+		afterObjects[relatedObject] = true
+		if !beforeObjects[relatedObject] {
+			changes = append(changes, StatusChange{Path: "relatedObjects", After: relatedObject})
+		}
+	}
+	for _, relatedObject := range before.RelatedObjects {
+		if !afterObjects[relatedObject] {
+			changes = append(changes, StatusChange{Path: "relatedObjects", Before: relatedObject})
+		}
+	}
+
+	sort.SliceStable(changes, func(i, j int) bool {
+		if changes[i].Path != changes[j].Path {
+			return changes[i].Path < changes[j].Path
+		}
+		return changes[i].Before+changes[i].After < changes[j].Before+changes[j].After
+	})
+	return changes
+}
+
+// fields returns the conditions and versions of the snapshot by their path, leaving out empty ones.
+func (s OperatorStatusSnapshot) fields() map[string]string {
+	fields := map[string]string{}
+	set := func(path, value string) {
+		if len(value) > 0 {
+			fields[path] = value
+		}
+	}
+	for conditionType, condition := range s.Conditions {
+		set(fmt.Sprintf("conditions.%s.status", conditionType), string(condition.Status))
+		set(fmt.Sprintf("conditions.%s.reason", conditionType), condition.Reason)
+		set(fmt.Sprintf("conditions.%s.message", conditionType), condition.Message)
+	}
+	for name, version := range s.Versions {
+		set("versions."+name, version)
+	}
+	return fields
+}
+
+// DiffStatus returns the changes between the snapshots, one line per change in the format of
+// DiffObservedConfig: "+ path: value" for an added field, "- path: value" for a removed one and
+// "~ path: before -> after" for a changed one, values quoted. It returns an empty string when the
+// snapshots are equal.
+func DiffStatus(before, after OperatorStatusSnapshot) string {
+	var lines []string
+	for _, change := range StatusChanges(before, after) {
+		switch {
+		case len(change.Before) == 0:
+			lines = append(lines, fmt.Sprintf("+ %s: %q", change.Path, change.After))
+		case len(change.After) == 0:
+			lines = append(lines, fmt.Sprintf("- %s: %q", change.Path, change.Before))
+		default:
+			lines = append(lines, fmt.Sprintf("~ %s: %q -> %q", change.Path, change.Before, change.After))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
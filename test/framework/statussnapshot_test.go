@@ -0,0 +1,128 @@
+package framework
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	configv1 "github.com/openshift/api/config/v1"
+	configfake "github.com/openshift/client-go/config/clientset/versioned/fake"
+)
+
+func TestDiffStatus(t *testing.T) {
+	now := metav1.NewTime(time.Now())
+	status := func(progressing configv1.ConditionStatus, reason string, operatorVersion string, relatedObjects ...configv1.ObjectReference) configv1.ClusterOperatorStatus {
+		return configv1.ClusterOperatorStatus{
+			Conditions: []configv1.ClusterOperatorStatusCondition{
+				{Type: configv1.OperatorAvailable, Status: configv1.ConditionTrue, Reason: "AsExpected", LastTransitionTime: now},
+				{Type: configv1.OperatorProgressing, Status: progressing, Reason: reason, LastTransitionTime: now},
+			},
+			Versions:       []configv1.OperandVersion{{Name: "operator", Version: operatorVersion}},
+			RelatedObjects: relatedObjects,
+		}
+	}
+	operandNamespace := configv1.ObjectReference{Resource: "namespaces", Name: "openshift-controller-manager"}
+	operatorConfig := configv1.ObjectReference{Group: "operator.openshift.io", Resource: "openshiftcontrollermanagers", Name: "cluster"}
+
+	tests := []struct {
+		name            string
+		before, after   configv1.ClusterOperatorStatus
+		expectedChanges []StatusChange
+		expectedDiff    string
+	}{
+		{
+			name:   "unchanged but for the transition times",
+			before: status(configv1.ConditionFalse, "AsExpected", "4.18.0", operandNamespace),
+			after: func() configv1.ClusterOperatorStatus {
+				s := status(configv1.ConditionFalse, "AsExpected", "4.18.0", operandNamespace)
+				s.Conditions[0].LastTransitionTime = metav1.NewTime(now.Add(time.Minute))
+				return s
+			}(),
+		},
+		{
+			name:   "Progressing flips",
+			before: status(configv1.ConditionFalse, "AsExpected", "4.18.0", operandNamespace),
+			after:  status(configv1.ConditionTrue, "", "4.18.0", operandNamespace),
+			expectedChanges: []StatusChange{
+				{Path: "conditions.Progressing.reason", Before: "AsExpected"},
+				{Path: "conditions.Progressing.status", Before: "False", After: "True"},
+			},
+			expectedDiff: `- conditions.Progressing.reason: "AsExpected"
+~ conditions.Progressing.status: "False" -> "True"`,
+		},
+		{
+			name:   "version and related objects move",
+			before: status(configv1.ConditionFalse, "AsExpected", "4.18.0", operandNamespace),
+			after:  status(configv1.ConditionFalse, "AsExpected", "4.18.1", operatorConfig),
+			expectedChanges: []StatusChange{
+				{Path: "relatedObjects", Before: "namespaces/openshift-controller-manager"},
+				{Path: "relatedObjects", After: "openshiftcontrollermanagers.operator.openshift.io/cluster"},
+				{Path: "versions.operator", Before: "4.18.0", After: "4.18.1"},
+			},
+			expectedDiff: `- relatedObjects: "namespaces/openshift-controller-manager"
++ relatedObjects: "openshiftcontrollermanagers.operator.openshift.io/cluster"
+~ versions.operator: "4.18.0" -> "4.18.1"`,
+		},
+		{
+			name:   "condition added",
+			before: configv1.ClusterOperatorStatus{},
+			after: configv1.ClusterOperatorStatus{Conditions: []configv1.ClusterOperatorStatusCondition{
+				{Type: configv1.OperatorDegraded, Status: configv1.ConditionTrue, Reason: "Failing", Message: "it broke"},
+			}},
+			expectedChanges: []StatusChange{
+				{Path: "conditions.Degraded.message", After: "it broke"},
+				{Path: "conditions.Degraded.reason", After: "Failing"},
+				{Path: "conditions.Degraded.status", After: "True"},
+			},
+			expectedDiff: `+ conditions.Degraded.message: "it broke"
++ conditions.Degraded.reason: "Failing"
++ conditions.Degraded.status: "True"`,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			before, after := newOperatorStatusSnapshot(tc.before), newOperatorStatusSnapshot(tc.after)
+			if diff := cmp.Diff(tc.expectedChanges, StatusChanges(before, after)); len(diff) > 0 {
+				t.Errorf("unexpected changes (-want +got):\n%s", diff)
+			}
+			if diff := DiffStatus(before, after); diff != tc.expectedDiff {
+				t.Errorf("expected the diff\n%s\ngot\n%s", tc.expectedDiff, diff)
+			}
+		})
+	}
+}
+
+func TestSnapshotOperatorStatus(t *testing.T) {
+	client := configfake.NewSimpleClientset(&configv1.ClusterOperator{
+		ObjectMeta: metav1.ObjectMeta{Name: "openshift-controller-manager"},
+		Status: configv1.ClusterOperatorStatus{
+			Conditions: []configv1.ClusterOperatorStatusCondition{{Type: configv1.OperatorAvailable, Status: configv1.ConditionTrue}},
+			Versions:   []configv1.OperandVersion{{Name: "operator", Version: "4.18.0"}},
+			RelatedObjects: []configv1.ObjectReference{
+				{Resource: "namespaces", Name: "openshift-controller-manager"},
+				{Resource: "namespaces", Name: "openshift-config"},
+			},
+		},
+	})
+	snapshot, err := snapshotOperatorStatus(context.TODO(), client.ConfigV1())
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := OperatorStatusSnapshot{
+		Conditions:     map[configv1.ClusterStatusConditionType]OperatorConditionSnapshot{configv1.OperatorAvailable: {Status: configv1.ConditionTrue}},
+		Versions:       map[string]string{"operator": "4.18.0"},
+		RelatedObjects: []string{"namespaces/openshift-config", "namespaces/openshift-controller-manager"},
+	}
+	if diff := cmp.Diff(expected, snapshot); len(diff) > 0 {
+		t.Errorf("unexpected snapshot (-want +got):\n%s", diff)
+	}
+
+	_, err = snapshotOperatorStatus(context.TODO(), configfake.NewSimpleClientset().ConfigV1())
+	if err == nil || !strings.Contains(err.Error(), "unable to get clusteroperator/openshift-controller-manager") {
+		t.Errorf("expected an error for a missing ClusterOperator, got %v", err)
+	}
+}
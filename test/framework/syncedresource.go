@@ -0,0 +1,119 @@
+package framework
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+// SyncedResourceKind is the kind of an object the operator syncs into an operand namespace.
+type SyncedResourceKind string
+
+const (
+	SyncedConfigMap SyncedResourceKind = "configmap"
+	SyncedSecret    SyncedResourceKind = "secret"
+
+	// syncedResourcePollInterval is how often WaitForSyncedResource checks the synced object.
+	syncedResourcePollInterval = 5 * time.Second
+)
+
+// SyncSource is the object a synced object is copied from.
+type SyncSource struct {
+	Namespace string
+	Name      string
+}
+
+// syncedResourceGetter gets the configmaps and secrets the operator syncs.
+type syncedResourceGetter interface {
+	clientcorev1.ConfigMapsGetter
+	clientcorev1.SecretsGetter
+}
+
+// WaitForSyncedResource waits for the configmap or secret of the given kind to exist in the namespace. If
+// source is not nil, it also waits for the content of the object to match the one of the source, which
+// the operator copies it from. The error on timeout says whether the object or the source was missing
+// or how their content hashes differed.
+func WaitForSyncedResource(ctx context.Context, t testing.TB, client *Clientset, namespace, name string, kind SyncedResourceKind, source *SyncSource, timeout time.Duration) error {
+	t.Helper()
+	return waitForSyncedResource(ctx, t, client, namespace, name, kind, source, syncedResourcePollInterval, timeout)
+}
+
+func waitForSyncedResource(ctx context.Context, logger Logger, client syncedResourceGetter, namespace, name string, kind SyncedResourceKind, source *SyncSource, interval, timeout time.Duration) error {
+	if kind != SyncedConfigMap && kind != SyncedSecret {
+		return fmt.Errorf("unsupported kind %q", kind)
+	}
+	object := fmt.Sprintf("%s/%s -n %s", kind, name, namespace)
+	var lastState string
+	err := poll(ctx, interval, timeout, func(ctx context.Context) (bool, error) {
+		hash, err := syncedResourceHash(ctx, client, kind, namespace, name)
+		if errors.IsNotFound(err) {
+			lastState = "the object does not exist"
+			return false, nil
+		}
+		if err != nil {
+			logger.Logf("error getting %s: %v", object, err)
+			lastState = fmt.Sprintf("last error: %v", err)
+			return false, nil
+		}
+		if source == nil {
+			return true, nil
+		}
+		sourceHash, err := syncedResourceHash(ctx, client, kind, source.Namespace, source.Name)
+		if errors.IsNotFound(err) {
+			lastState = fmt.Sprintf("the source %s/%s -n %s does not exist", kind, source.Name, source.Namespace)
+			return false, nil
+		}
+		if err != nil {
+			logger.Logf("error getting the source %s/%s -n %s: %v", kind, source.Name, source.Namespace, err)
+			lastState = fmt.Sprintf("last error: %v", err)
+			return false, nil
+		}
+		if hash != sourceHash {
+			state := fmt.Sprintf("its content hash %s differs from the hash %s of the source %s/%s -n %s", hash, sourceHash, kind, source.Name, source.Namespace)
+			if state != lastState {
+				logger.Logf("waiting for %s to be synced: %s", object, state)
+			}
+			lastState = state
+			return false, nil
+		}
+		return true, nil
+	})
+	if err == nil {
+		return nil
+	}
+	return fmt.Errorf("%s was not synced, %s: %w", object, lastState, err)
+}
+
+// syncedResourceHash returns a hash of the content of the configmap or secret, the fields the resource
+// syncer of the operator copies.
+func syncedResourceHash(ctx context.Context, client syncedResourceGetter, kind SyncedResourceKind, namespace, name string) (string, error) {
+	var content interface{}
+	switch kind {
+	case SyncedConfigMap:
+		configMap, err := client.ConfigMaps(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return "", err
+		}
+		content = []interface{}{configMap.Data, configMap.BinaryData}
+	case SyncedSecret:
+		secret, err := client.Secrets(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return "", err
+		}
+		content = []interface{}{secret.Type, secret.Data}
+	default:
+		return "", fmt.Errorf("unsupported kind %q", kind)
+	}
+	raw, err := json.Marshal(content)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%x", sha256.Sum256(raw))[:16], nil
+}
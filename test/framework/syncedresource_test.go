@@ -0,0 +1,134 @@
+package framework
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	clienttesting "k8s.io/client-go/testing"
+)
+
+func syncedConfigMap(namespace, name, ca string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Data:       map[string]string{"ca-bundle.crt": ca},
+	}
+}
+
+func TestWaitForSyncedResource(t *testing.T) {
+	source := &SyncSource{Namespace: "openshift-config", Name: "user-ca"}
+	tests := []struct {
+		name           string
+		objects        []runtime.Object
+		kind           SyncedResourceKind
+		source         *SyncSource
+		expectedErrors []string
+	}{
+		{
+			name:    "existing object",
+			objects: []runtime.Object{syncedConfigMap("openshift-controller-manager", "openshift-user-ca", "ca-1")},
+			kind:    SyncedConfigMap,
+		},
+		{
+			name: "content matching the source",
+			objects: []runtime.Object{
+				syncedConfigMap("openshift-controller-manager", "openshift-user-ca", "ca-1"),
+				syncedConfigMap("openshift-config", "user-ca", "ca-1"),
+			},
+			kind:   SyncedConfigMap,
+			source: source,
+		},
+		{
+			name: "secret matching the source",
+			objects: []runtime.Object{
+				&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "openshift-user-ca", Namespace: "openshift-controller-manager"}, Data: map[string][]byte{"key": []byte("value")}},
+				&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "user-ca", Namespace: "openshift-config"}, Data: map[string][]byte{"key": []byte("value")}},
+			},
+			kind:   SyncedSecret,
+			source: source,
+		},
+		{
+			name:           "missing object",
+			kind:           SyncedConfigMap,
+			expectedErrors: []string{"configmap/openshift-user-ca -n openshift-controller-manager was not synced", "the object does not exist"},
+		},
+		{
+			name:           "missing source",
+			objects:        []runtime.Object{syncedConfigMap("openshift-controller-manager", "openshift-user-ca", "ca-1")},
+			kind:           SyncedConfigMap,
+			source:         source,
+			expectedErrors: []string{"the source configmap/user-ca -n openshift-config does not exist"},
+		},
+		{
+			name: "content differing from the source",
+			objects: []runtime.Object{
+				syncedConfigMap("openshift-controller-manager", "openshift-user-ca", "ca-1"),
+				syncedConfigMap("openshift-config", "user-ca", "ca-2"),
+			},
+			kind:           SyncedConfigMap,
+			source:         source,
+			expectedErrors: []string{"its content hash", "differs from the hash", "of the source configmap/user-ca -n openshift-config"},
+		},
+		{
+			name:           "unsupported kind",
+			kind:           "deployment",
+			expectedErrors: []string{`unsupported kind "deployment"`},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			client := fake.NewSimpleClientset(tc.objects...)
+
+			err := waitForSyncedResource(context.TODO(), t, client.CoreV1(), "openshift-controller-manager", "openshift-user-ca", tc.kind, tc.source, time.Millisecond, 20*time.Millisecond)
+			if len(tc.expectedErrors) == 0 {
+				if err != nil {
+					t.Errorf("expected the object to be synced, got %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			for _, expected := range tc.expectedErrors {
+				if !strings.Contains(err.Error(), expected) {
+					t.Errorf("expected the error to contain %q, got %v", expected, err)
+				}
+			}
+		})
+	}
+}
+
+func TestWaitForSyncedResourceWaitsForSync(t *testing.T) {
+	client := fake.NewSimpleClientset(
+		syncedConfigMap("openshift-controller-manager", "openshift-user-ca", "ca-1"),
+		syncedConfigMap("openshift-config", "user-ca", "ca-2"),
+	)
+	gets := 0
+	client.PrependReactor("get", "configmaps", func(action clienttesting.Action) (bool, runtime.Object, error) {
+		if action.GetNamespace() != "openshift-controller-manager" {
+			return false, nil, nil
+		}
+		gets++
+		if gets < 3 {
+			// the resource syncer has not copied the rotated source yet
+			return false, nil, nil
+		}
+		return true, syncedConfigMap("openshift-controller-manager", "openshift-user-ca", "ca-2"), nil
+	})
+	logger := &recordingLogger{}
+
+	err := waitForSyncedResource(context.TODO(), logger, client.CoreV1(), "openshift-controller-manager", "openshift-user-ca", SyncedConfigMap, &SyncSource{Namespace: "openshift-config", Name: "user-ca"}, time.Millisecond, time.Second)
+	if err != nil {
+		t.Fatalf("expected the object to be synced, got %v", err)
+	}
+	// the same difference is logged once
+	if len(logger.lines) != 1 || !strings.Contains(logger.lines[0], "waiting for configmap/openshift-user-ca -n openshift-controller-manager to be synced") {
+		t.Errorf("unexpected logs: %v", logger.lines)
+	}
+}
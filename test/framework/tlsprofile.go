@@ -0,0 +1,171 @@
+package framework
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+
+	configv1 "github.com/openshift/api/config/v1"
+	clientconfigv1 "github.com/openshift/client-go/config/clientset/versioned/typed/config/v1"
+	clientoperatorv1 "github.com/openshift/client-go/operator/clientset/versioned/typed/operator/v1"
+)
+
+const (
+	// tlsProfileProgressingTimeout is how long a TLS profile change is waited for to make the operator
+	// progress, a change the operator already reconciled does not make it progress at all.
+	tlsProfileProgressingTimeout = 5 * time.Minute
+	// tlsProfileReconciledTimeout is how long the operator is waited for to finish rolling out a TLS
+	// profile change, it typically takes 12-15 minutes.
+	tlsProfileReconciledTimeout = 15 * time.Minute
+)
+
+type tlsProfileClient interface {
+	clientconfigv1.APIServersGetter
+	clientconfigv1.ClusterOperatorsGetter
+	clientoperatorv1.OpenShiftControllerManagersGetter
+}
+
+// WithAPIServerTLSProfile sets the TLS security profile of the APIServer config, waits for the operator to
+// reconcile it and runs body with a context ending at the deadline of the test. The original profile is
+// restored on cleanup, which waits for the operator to reconcile it again and verifies the observed config
+// reverted. Set the profile this way for the "set profile, assert something, revert" pattern.
+func WithAPIServerTLSProfile(ctx context.Context, t testing.TB, client *Clientset, profile *configv1.TLSSecurityProfile, body func(ctx context.Context)) {
+	t.Helper()
+	withClusterConfigMutation(ctx, t, APIServerTLSProfileMutation(t, client), profile, body)
+}
+
+// APIServerTLSProfileMutation changes the TLS security profile of the APIServer config. A restored profile
+// is verified in the observed config: without a profile the TLS keys must be back to their defaults,
+// else the minimum TLS version must be the one of the restored profile.
+func APIServerTLSProfileMutation(logger Logger, client *Clientset) ClusterConfigMutation[*configv1.TLSSecurityProfile] {
+	return apiServerTLSProfileMutation(logger, client, 5*time.Second, tlsProfileProgressingTimeout, 10*time.Second, tlsProfileReconciledTimeout)
+}
+
+func apiServerTLSProfileMutation(logger Logger, client tlsProfileClient, progressingInterval, progressingTimeout, reconciledInterval, reconciledTimeout time.Duration) ClusterConfigMutation[*configv1.TLSSecurityProfile] {
+	return ClusterConfigMutation[*configv1.TLSSecurityProfile]{
+		Name: "APIServer TLS security profile",
+		Get: func(ctx context.Context) (*configv1.TLSSecurityProfile, error) {
+			apiServer, err := client.APIServers().Get(ctx, "cluster", metav1.GetOptions{})
+			if err != nil {
+				return nil, err
+			}
+			return apiServer.Spec.TLSSecurityProfile, nil
+		},
+		Set: func(ctx context.Context, profile *configv1.TLSSecurityProfile) error {
+			return setAPIServerTLSProfile(ctx, client, profile)
+		},
+		Settle: func(ctx context.Context) error {
+			return waitForTLSProfileReconciled(ctx, logger, client, progressingInterval, progressingTimeout, reconciledInterval, reconciledTimeout)
+		},
+		Verify: func(ctx context.Context, original *configv1.TLSSecurityProfile) error {
+			raw, err := getObservedConfigRaw(ctx, client)
+			if err != nil {
+				return err
+			}
+			if original == nil {
+				return checkObservedConfigClean(raw, []string{"servingInfo.minTLSVersion", "servingInfo.cipherSuites"})
+			}
+			minTLSVersion, _, err := parseServingInfo(raw)
+			if err != nil {
+				return err
+			}
+			if expected := tlsProfileMinTLSVersion(original); minTLSVersion != expected {
+				return fmt.Errorf("servingInfo.minTLSVersion is %q, expected %q", minTLSVersion, expected)
+			}
+			return nil
+		},
+	}
+}
+
+// SetAPIServerTLSProfile sets the TLS security profile of the APIServer config, retrying on conflicts. It
+// does not wait for the operator, nor restore the original profile.
+func SetAPIServerTLSProfile(ctx context.Context, client *Clientset, profile *configv1.TLSSecurityProfile) error {
+	return setAPIServerTLSProfile(ctx, client, profile)
+}
+
+func setAPIServerTLSProfile(ctx context.Context, client clientconfigv1.APIServersGetter, profile *configv1.TLSSecurityProfile) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		apiServer, err := client.APIServers().Get(ctx, "cluster", metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		apiServer.Spec.TLSSecurityProfile = profile
+		_, err = client.APIServers().Update(ctx, apiServer, metav1.UpdateOptions{})
+		return err
+	})
+}
+
+// tlsProfileMinTLSVersion returns the minimum TLS version of profile, unknown profile types fall back to
+// the Intermediate profile like the operator does.
+func tlsProfileMinTLSVersion(profile *configv1.TLSSecurityProfile) string {
+	if profile.Type == configv1.TLSProfileCustomType && profile.Custom != nil {
+		return string(profile.Custom.MinTLSVersion)
+	}
+	if spec, ok := configv1.TLSProfiles[profile.Type]; ok {
+		return string(spec.MinTLSVersion)
+	}
+	return string(configv1.TLSProfiles[configv1.TLSProfileIntermediateType].MinTLSVersion)
+}
+
+// waitForTLSProfileReconciled waits for the operator to pick up a TLS profile change and to finish
+// rolling it out without being degraded. Not seeing the operator progress is logged only, the change
+// may have been reconciled already, while still progressing after reconciledTimeout fails with the
+// reason the rollout is stuck with.
+func waitForTLSProfileReconciled(ctx context.Context, logger Logger, client clientconfigv1.ClusterOperatorsGetter, progressingInterval, progressingTimeout, reconciledInterval, reconciledTimeout time.Duration) error {
+	err := poll(ctx, progressingInterval, progressingTimeout, func(ctx context.Context) (bool, error) {
+		co, err := client.ClusterOperators().Get(ctx, clusterOperatorName, metav1.GetOptions{})
+		if err != nil {
+			logger.Logf("error getting clusteroperator/%s: %v", clusterOperatorName, err)
+			return false, nil
+		}
+		for _, c := range co.Status.Conditions {
+			if c.Type == configv1.OperatorProgressing && c.Status == configv1.ConditionTrue {
+				logger.Logf("clusteroperator/%s is progressing: %s", clusterOperatorName, c.Reason)
+				return true, nil
+			}
+		}
+		return false, nil
+	})
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if err != nil {
+		logger.Logf("clusteroperator/%s did not start progressing within %v, continuing anyway: %v", clusterOperatorName, progressingTimeout, err)
+	}
+
+	// the rollout is slow but bounded, still progressing at the ceiling means it is stuck
+	if err := waitForProgressingCleared(ctx, logger, client, reconciledInterval, reconciledTimeout); err != nil {
+		return err
+	}
+	return poll(ctx, reconciledInterval, reconciledTimeout, func(ctx context.Context) (bool, error) {
+		co, err := client.ClusterOperators().Get(ctx, clusterOperatorName, metav1.GetOptions{})
+		if err != nil {
+			logger.Logf("error getting clusteroperator/%s: %v", clusterOperatorName, err)
+			return false, nil
+		}
+		available, progressing, degraded := false, true, false
+		for _, c := range co.Status.Conditions {
+			switch {
+			case c.Type == configv1.OperatorAvailable && c.Status == configv1.ConditionTrue:
+				available = true
+			case c.Type == configv1.OperatorProgressing && c.Status == configv1.ConditionFalse:
+				progressing = false
+			case c.Type == configv1.OperatorDegraded && c.Status == configv1.ConditionTrue:
+				degraded = true
+			}
+		}
+		if degraded {
+			logger.Logf("clusteroperator/%s is degraded", clusterOperatorName)
+			return false, nil
+		}
+		if available && !progressing {
+			return true, nil
+		}
+		logger.Logf("clusteroperator/%s is still reconciling: available=%t progressing=%t", clusterOperatorName, available, progressing)
+		return false, nil
+	})
+}
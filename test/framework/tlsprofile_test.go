@@ -0,0 +1,113 @@
+package framework
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	configv1 "github.com/openshift/api/config/v1"
+	operatorv1 "github.com/openshift/api/operator/v1"
+	configfake "github.com/openshift/client-go/config/clientset/versioned/fake"
+	operatorfake "github.com/openshift/client-go/operator/clientset/versioned/fake"
+)
+
+func reconciledClusterOperator() *configv1.ClusterOperator {
+	return &configv1.ClusterOperator{
+		ObjectMeta: metav1.ObjectMeta{Name: "openshift-controller-manager"},
+		Status: configv1.ClusterOperatorStatus{Conditions: []configv1.ClusterOperatorStatusCondition{
+			{Type: configv1.OperatorAvailable, Status: configv1.ConditionTrue},
+			{Type: configv1.OperatorProgressing, Status: configv1.ConditionFalse},
+			{Type: configv1.OperatorDegraded, Status: configv1.ConditionFalse},
+		}},
+	}
+}
+
+func TestAPIServerTLSProfileMutation(t *testing.T) {
+	configClient := configfake.NewSimpleClientset(
+		&configv1.APIServer{ObjectMeta: metav1.ObjectMeta{Name: "cluster"}},
+		reconciledClusterOperator(),
+	)
+	operatorClient := operatorfake.NewSimpleClientset(&operatorv1.OpenShiftControllerManager{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+		Spec: operatorv1.OpenShiftControllerManagerSpec{OperatorSpec: operatorv1.OperatorSpec{
+			ObservedConfig: runtime.RawExtension{Raw: []byte(`{"servingInfo":{"minTLSVersion":"VersionTLS13"}}`)},
+		}},
+	})
+	client := &Clientset{ConfigV1Interface: configClient.ConfigV1(), OperatorV1Interface: operatorClient.OperatorV1()}
+	mutation := apiServerTLSProfileMutation(t, client, time.Millisecond, 10*time.Millisecond, time.Millisecond, time.Second)
+	mutation.VerifyTimeout = 20 * time.Millisecond
+	mutation.verifyPollInterval = time.Millisecond
+
+	modern := &configv1.TLSSecurityProfile{Type: configv1.TLSProfileModernType, Modern: &configv1.ModernTLSProfile{}}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	// the operator never progresses in the fake, settling only logs that
+	restore, err := mutation.Apply(ctx, t, modern)
+	if err != nil {
+		t.Fatal(err)
+	}
+	apiServer, err := client.APIServers().Get(ctx, "cluster", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if profile := apiServer.Spec.TLSSecurityProfile; profile == nil || profile.Type != configv1.TLSProfileModernType {
+		t.Fatalf("expected the Modern profile to be set, got %v", profile)
+	}
+
+	// the observed config still has the minimum TLS version of the Modern profile
+	err = restore(ctx)
+	if err == nil || !strings.Contains(err.Error(), "servingInfo.minTLSVersion") {
+		t.Fatalf("expected the restore to fail verifying the observed config, got %v", err)
+	}
+	apiServer, err = client.APIServers().Get(ctx, "cluster", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if apiServer.Spec.TLSSecurityProfile != nil {
+		t.Errorf("expected the original profile to be restored, got %v", apiServer.Spec.TLSSecurityProfile)
+	}
+}
+
+func TestWaitForTLSProfileReconciled(t *testing.T) {
+	tests := []struct {
+		name      string
+		operator  *configv1.ClusterOperator
+		expectErr bool
+	}{
+		{
+			name:     "reconciled",
+			operator: reconciledClusterOperator(),
+		},
+		{
+			name: "degraded",
+			operator: func() *configv1.ClusterOperator {
+				co := reconciledClusterOperator()
+				co.Status.Conditions[2].Status = configv1.ConditionTrue
+				return co
+			}(),
+			expectErr: true,
+		},
+		{
+			name: "still progressing",
+			operator: func() *configv1.ClusterOperator {
+				co := reconciledClusterOperator()
+				co.Status.Conditions[1].Status = configv1.ConditionTrue
+				return co
+			}(),
+			expectErr: true,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			client := configfake.NewSimpleClientset(tc.operator).ConfigV1()
+			err := waitForTLSProfileReconciled(context.Background(), t, client, time.Millisecond, 10*time.Millisecond, time.Millisecond, 20*time.Millisecond)
+			if tc.expectErr != (err != nil) {
+				t.Errorf("expected error %t, got %v", tc.expectErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package framework
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	configv1 "github.com/openshift/api/config/v1"
+	clientconfigv1 "github.com/openshift/client-go/config/clientset/versioned/typed/config/v1"
+	clientoperatorv1 "github.com/openshift/client-go/operator/clientset/versioned/typed/operator/v1"
+)
+
+const (
+	// DefaultTLSPropagationSLO is how long a TLS profile change may take to reach the observed config of
+	// the operator.
+	DefaultTLSPropagationSLO = 15 * time.Minute
+	// tlsPropagationPollInterval is how often the observed config is checked for a TLS profile change.
+	tlsPropagationPollInterval = 5 * time.Second
+)
+
+type tlsPropagationClient interface {
+	clientconfigv1.APIServersGetter
+	clientoperatorv1.OpenShiftControllerManagersGetter
+}
+
+// AssertTLSPropagationWithin sets the TLS security profile of the APIServer config and measures how long
+// it takes until the observed config has the minimum TLS version of profile, which has to differ from the
+// current one. It fails the test if that takes longer than slo, e.g. DefaultTLSPropagationSLO, and returns
+// the measured duration, which is logged for trending. The original profile is restored on cleanup, which
+// waits for the operator to reconcile it again.
+func AssertTLSPropagationWithin(ctx context.Context, t testing.TB, client *Clientset, profile *configv1.TLSSecurityProfile, slo time.Duration) time.Duration {
+	t.Helper()
+	mutation := APIServerTLSProfileMutation(t, client)
+	original, err := mutation.Get(ctx)
+	if err != nil {
+		t.Fatalf("failed to get the original %s: %v", mutation.Name, err)
+	}
+	t.Cleanup(func() {
+		// the test context may be done by the time the cleanup runs, restoring must not be skipped
+		if err := mutation.restore(context.WithoutCancel(ctx), t, original); err != nil {
+			t.Errorf("%v", err)
+		}
+	})
+
+	latency, err := measureTLSPropagation(ctx, t, client, profile, tlsPropagationPollInterval, slo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return latency
+}
+
+// measureTLSPropagation sets profile and returns how long after the update the observed config had its
+// minimum TLS version, or an error once that took longer than slo.
+func measureTLSPropagation(ctx context.Context, logger Logger, client tlsPropagationClient, profile *configv1.TLSSecurityProfile, interval, slo time.Duration) (time.Duration, error) {
+	expected := tlsProfileMinTLSVersion(profile)
+	minTLSVersion, err := observedMinTLSVersion(ctx, client)
+	if err != nil {
+		return 0, err
+	}
+	if minTLSVersion == expected {
+		return 0, fmt.Errorf("servingInfo.minTLSVersion is %q already, the propagation of the %s TLS profile cannot be measured", minTLSVersion, profile.Type)
+	}
+
+	if err := setAPIServerTLSProfile(ctx, client, profile); err != nil {
+		return 0, fmt.Errorf("failed to set the APIServer TLS security profile to %s: %w", profile.Type, err)
+	}
+	start := time.Now()
+	err = poll(ctx, interval, slo, func(ctx context.Context) (bool, error) {
+		current, err := observedMinTLSVersion(ctx, client)
+		if err != nil {
+			logger.Logf("waiting for the %s TLS profile to be observed: %v", profile.Type, err)
+			return false, nil
+		}
+		minTLSVersion = current
+		return minTLSVersion == expected, nil
+	})
+	latency := time.Since(start)
+	if err != nil {
+		return latency, fmt.Errorf("servingInfo.minTLSVersion was still %q instead of %q %s after setting the %s TLS profile, exceeding the SLO of %s: %w",
+			minTLSVersion, expected, latency.Round(time.Second), profile.Type, slo, err)
+	}
+	logger.Logf("TLS propagation latency: the %s TLS profile was observed %s after the APIServer update (SLO %s)", profile.Type, latency.Round(time.Millisecond), slo)
+	return latency, nil
+}
+
+func observedMinTLSVersion(ctx context.Context, client clientoperatorv1.OpenShiftControllerManagersGetter) (string, error) {
+	raw, err := getObservedConfigRaw(ctx, client)
+	if err != nil {
+		return "", err
+	}
+	minTLSVersion, _, err := parseServingInfo(raw)
+	return minTLSVersion, err
+}
@@ -0,0 +1,85 @@
+package framework
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clienttesting "k8s.io/client-go/testing"
+
+	configv1 "github.com/openshift/api/config/v1"
+	operatorv1 "github.com/openshift/api/operator/v1"
+	configfake "github.com/openshift/client-go/config/clientset/versioned/fake"
+	operatorfake "github.com/openshift/client-go/operator/clientset/versioned/fake"
+)
+
+func TestMeasureTLSPropagation(t *testing.T) {
+	modern := &configv1.TLSSecurityProfile{Type: configv1.TLSProfileModernType, Modern: &configv1.ModernTLSProfile{}}
+	tests := []struct {
+		name             string
+		minTLSVersion    string
+		operatorObserves bool
+		expectedError    string
+	}{
+		{
+			name:             "propagated within the SLO",
+			minTLSVersion:    "VersionTLS12",
+			operatorObserves: true,
+		},
+		{
+			name:          "SLO exceeded",
+			minTLSVersion: "VersionTLS12",
+			expectedError: `servingInfo.minTLSVersion was still "VersionTLS12" instead of "VersionTLS13"`,
+		},
+		{
+			name:          "profile observed already",
+			minTLSVersion: "VersionTLS13",
+			expectedError: `servingInfo.minTLSVersion is "VersionTLS13" already`,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			configClient := configfake.NewSimpleClientset(&configv1.APIServer{ObjectMeta: metav1.ObjectMeta{Name: "cluster"}})
+			operatorClient := operatorfake.NewSimpleClientset(&operatorv1.OpenShiftControllerManager{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+				Spec: operatorv1.OpenShiftControllerManagerSpec{OperatorSpec: operatorv1.OperatorSpec{
+					ObservedConfig: runtime.RawExtension{Raw: []byte(`{"servingInfo":{"minTLSVersion":"` + tc.minTLSVersion + `"}}`)},
+				}},
+			})
+			if tc.operatorObserves {
+				// the operator observes the profile right after the update
+				configClient.PrependReactor("update", "apiservers", func(clienttesting.Action) (bool, runtime.Object, error) {
+					config, err := operatorClient.OperatorV1().OpenShiftControllerManagers().Get(context.TODO(), "cluster", metav1.GetOptions{})
+					if err != nil {
+						return true, nil, err
+					}
+					config.Spec.ObservedConfig.Raw = []byte(`{"servingInfo":{"minTLSVersion":"VersionTLS13"}}`)
+					_, err = operatorClient.OperatorV1().OpenShiftControllerManagers().Update(context.TODO(), config, metav1.UpdateOptions{})
+					return false, nil, err
+				})
+			}
+			client := &Clientset{ConfigV1Interface: configClient.ConfigV1(), OperatorV1Interface: operatorClient.OperatorV1()}
+			logger := &recordingLogger{}
+
+			latency, err := measureTLSPropagation(context.TODO(), logger, client, modern, time.Millisecond, 20*time.Millisecond)
+			if len(tc.expectedError) > 0 {
+				if err == nil || !strings.Contains(err.Error(), tc.expectedError) {
+					t.Fatalf("expected an error containing %q, got %v", tc.expectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if latency <= 0 || latency > 20*time.Millisecond {
+				t.Errorf("expected a latency within the SLO, got %s", latency)
+			}
+			if !strings.Contains(strings.Join(logger.lines, "\n"), "TLS propagation latency: the Modern TLS profile was observed") {
+				t.Errorf("expected the latency to be logged, got %v", logger.lines)
+			}
+		})
+	}
+}
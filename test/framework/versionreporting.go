@@ -0,0 +1,87 @@
+package framework
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	utilversion "k8s.io/apimachinery/pkg/util/version"
+	"k8s.io/apimachinery/pkg/version"
+
+	configv1 "github.com/openshift/api/config/v1"
+)
+
+// operatorVersionName is the name of the version of the operator itself in the ClusterOperator status.
+const operatorVersionName = "operator"
+
+// OperatorVersion returns the version the ClusterOperator reports for the operator itself, empty when it
+// reports none.
+func OperatorVersion(co *configv1.ClusterOperator) string {
+	for _, v := range co.Status.Versions {
+		if v.Name == operatorVersionName {
+			return v.Version
+		}
+	}
+	return ""
+}
+
+// CheckVersionTransition returns an error unless the ClusterOperator versions after reports the operator
+// version, still reports every version of before, and none of them went back.
+func CheckVersionTransition(before, after []configv1.OperandVersion) error {
+	afterVersions := map[string]string{}
+	for _, v := range after {
+		afterVersions[v.Name] = v.Version
+	}
+	if len(afterVersions[operatorVersionName]) == 0 {
+		return fmt.Errorf("the %q version is not reported, got %v", operatorVersionName, after)
+	}
+
+	var problems []string
+	for _, v := range before {
+		afterVersion, ok := afterVersions[v.Name]
+		if !ok {
+			problems = append(problems, fmt.Sprintf("%s: version %s is not reported anymore", v.Name, v.Version))
+			continue
+		}
+		if afterVersion == v.Version {
+			continue
+		}
+		previous, err := utilversion.ParseGeneric(v.Version)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %q is not a version: %v", v.Name, v.Version, err))
+			continue
+		}
+		current, err := utilversion.ParseGeneric(afterVersion)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %q is not a version: %v", v.Name, afterVersion, err))
+			continue
+		}
+		if !current.AtLeast(previous) {
+			problems = append(problems, fmt.Sprintf("%s: went back from %s to %s", v.Name, v.Version, afterVersion))
+		}
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("the ClusterOperator versions did not move forward:\n%s", strings.Join(problems, "\n"))
+	}
+	return nil
+}
+
+// CheckVersionMatchesBuild returns an error unless the reported version has the major and minor version the
+// operator was built with. A build without them, e.g. a local build without the version ldflags, is not
+// checked.
+func CheckVersionMatchesBuild(reported string, build version.Info) error {
+	// the minor version of a build from a commit past the release tag carries a "+"
+	major, majorErr := strconv.ParseUint(strings.TrimSuffix(build.Major, "+"), 10, 0)
+	minor, minorErr := strconv.ParseUint(strings.TrimSuffix(build.Minor, "+"), 10, 0)
+	if majorErr != nil || minorErr != nil {
+		return nil
+	}
+	v, err := utilversion.ParseGeneric(reported)
+	if err != nil {
+		return fmt.Errorf("the reported version %q is not a version: %w", reported, err)
+	}
+	if uint64(v.Major()) != major || uint64(v.Minor()) != minor {
+		return fmt.Errorf("the reported version %s does not match the %d.%d version the operator was built with", reported, major, minor)
+	}
+	return nil
+}
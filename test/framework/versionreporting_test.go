@@ -0,0 +1,105 @@
+package framework
+
+import (
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/version"
+
+	configv1 "github.com/openshift/api/config/v1"
+)
+
+func versions(nameVersions ...string) []configv1.OperandVersion {
+	var result []configv1.OperandVersion
+	for i := 0; i < len(nameVersions); i += 2 {
+		result = append(result, configv1.OperandVersion{Name: nameVersions[i], Version: nameVersions[i+1]})
+	}
+	return result
+}
+
+func TestCheckVersionTransition(t *testing.T) {
+	tests := []struct {
+		name          string
+		before, after []configv1.OperandVersion
+		expectedError string
+	}{
+		{
+			name:   "unchanged",
+			before: versions("operator", "4.18.3"),
+			after:  versions("operator", "4.18.3"),
+		},
+		{
+			name:   "upgraded",
+			before: versions("operator", "4.17.9"),
+			after:  versions("operator", "4.18.0-0.nightly-2026-10-01-000000"),
+		},
+		{
+			name:   "first report",
+			before: nil,
+			after:  versions("operator", "4.18.3"),
+		},
+		{
+			name:          "went back",
+			before:        versions("operator", "4.18.3"),
+			after:         versions("operator", "4.17.9"),
+			expectedError: "operator: went back from 4.18.3 to 4.17.9",
+		},
+		{
+			name:          "operator version missing",
+			before:        versions("operator", "4.18.3"),
+			after:         nil,
+			expectedError: `the "operator" version is not reported`,
+		},
+		{
+			name:          "version dropped",
+			before:        versions("operator", "4.18.3", "openshift-controller-manager", "4.18.3"),
+			after:         versions("operator", "4.18.3"),
+			expectedError: "openshift-controller-manager: version 4.18.3 is not reported anymore",
+		},
+		{
+			name:          "not a version",
+			before:        versions("operator", "4.18.3"),
+			after:         versions("operator", "latest"),
+			expectedError: `operator: "latest" is not a version`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := CheckVersionTransition(tc.before, tc.after)
+			if len(tc.expectedError) == 0 {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.expectedError) {
+				t.Errorf("expected an error with %q, got %v", tc.expectedError, err)
+			}
+		})
+	}
+}
+
+func TestCheckVersionMatchesBuild(t *testing.T) {
+	tests := []struct {
+		name          string
+		reported      string
+		build         version.Info
+		expectedError bool
+	}{
+		{name: "matches", reported: "4.18.3", build: version.Info{Major: "4", Minor: "18"}},
+		{name: "matches a build past the tag", reported: "4.18.0-0.nightly-2026-10-01-000000", build: version.Info{Major: "4", Minor: "18+"}},
+		{name: "build without a version", reported: "4.18.3", build: version.Info{}},
+		{name: "other minor", reported: "4.17.9", build: version.Info{Major: "4", Minor: "18"}, expectedError: true},
+		{name: "not a version", reported: "latest", build: version.Info{Major: "4", Minor: "18"}, expectedError: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := CheckVersionMatchesBuild(tc.reported, tc.build)
+			if tc.expectedError != (err != nil) {
+				t.Errorf("expected an error %v, got %v", tc.expectedError, err)
+			}
+		})
+	}
+}
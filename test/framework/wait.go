@@ -0,0 +1,27 @@
+package framework
+
+import (
+	"context"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+// poll is like wait.PollUntilContextTimeout, but returns the error of ctx as soon as ctx is done, without
+// running condition again, so that a cancelled test aborts the poll instead of waiting out the timeout.
+// Conditions are expected to retry errors which are not caused by ctx.
+func poll(ctx context.Context, interval, timeout time.Duration, condition wait.ConditionWithContextFunc) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	err := wait.PollUntilContextTimeout(ctx, interval, timeout, true, func(pollCtx context.Context) (bool, error) {
+		if err := ctx.Err(); err != nil {
+			return false, err
+		}
+		return condition(pollCtx)
+	})
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return err
+}
@@ -0,0 +1,65 @@
+package framework
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	configfake "github.com/openshift/client-go/config/clientset/versioned/fake"
+)
+
+func TestPollCancelledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	calls := 0
+	start := time.Now()
+	err := poll(ctx, time.Hour, time.Hour, func(context.Context) (bool, error) {
+		calls++
+		return false, nil
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected %v, got %v", context.Canceled, err)
+	}
+	if calls > 0 {
+		t.Errorf("expected the condition not to run, ran %d times", calls)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("expected poll to return immediately, took %s", elapsed)
+	}
+}
+
+func TestPollContextCancelledWhilePolling(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	start := time.Now()
+	err := poll(ctx, time.Hour, time.Hour, func(context.Context) (bool, error) {
+		// errors are swallowed by the condition, the poll must still stop
+		cancel()
+		return false, nil
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected %v, got %v", context.Canceled, err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("expected poll to return once the context is cancelled, took %s", elapsed)
+	}
+}
+
+func TestEnsureClusterOperatorStatusIsSetCancelledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// the cluster operator does not exist, an ignored context would wait for it for 2 minutes
+	client := configfake.NewSimpleClientset()
+	start := time.Now()
+	err := ensureClusterOperatorStatusIsSet(ctx, t, client.ConfigV1())
+	if !errors.Is(err, ctx.Err()) {
+		t.Errorf("expected %v, got %v", ctx.Err(), err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("expected the wait to return immediately, took %s", elapsed)
+	}
+}